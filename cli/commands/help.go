@@ -26,10 +26,16 @@ func PrintHelp() {
 	fmt.Println("	object-bucket-create            Creates a bucket")
 	fmt.Println("	object-bucket-list              List buckets")
 	fmt.Println("	object-bucket-delete            Delete bucket")
+	fmt.Println("	object-bucket-versioning        Show or set bucket versioning")
+	fmt.Println("	object-bucket-lifecycle         Show or set bucket lifecycle rules")
+	fmt.Println("	object-bucket-notifications     Show or set bucket notification rules")
 	fmt.Println("	object-file-upload              Upload file")
 	fmt.Println("	object-file-download            File download")
 	fmt.Println("	object-file-list                List files")
 	fmt.Println("	object-file-delete              Delete file")
+	fmt.Println("	object-file-versions            List file versions")
+	fmt.Println("	object-file-restore             Restore file version")
+	fmt.Println("	object-file-sign                Sign file download or upload url")
 	fmt.Println("	topics-add                      Add topic")
 	fmt.Println("	topics-list                     List topics")
 	fmt.Println("	topics-update                   Update topic")
@@ -39,6 +45,14 @@ func PrintHelp() {
 	fmt.Println("	snippets-invoke-by-id           Invoke snippet by id")
 	fmt.Println("	snippets-list                   List snippets")
 	fmt.Println("	snippets-delete                 Delete snippet")
+	fmt.Println("	snippets-test                   Test snippet with mocked modules")
+	fmt.Println("	libraries-add                   Add library version")
+	fmt.Println("	libraries-get                   Get library version")
+	fmt.Println("	libraries-list                  List libraries")
+	fmt.Println("	libraries-delete                Delete library")
+	fmt.Println("	policies-set                    Set account or snippet policy")
+	fmt.Println("	policies-get                    Get account or snippet policy")
+	fmt.Println("	policies-delete                 Delete account or snippet policy")
 	fmt.Println("	cron-add                        Add cron job")
 	fmt.Println("	cron-update                     Update cron job")
 	fmt.Println("	cron-list                       List cron jobs")
@@ -18,27 +18,44 @@ package invoke
 
 import (
 	"flag"
+	"time"
 
 	"errors"
 	"fmt"
 	"github.com/lavaorg/northstar/cli/commands"
 	"github.com/lavaorg/northstar/cli/util"
 	"github.com/lavaorg/northstar/data/invocations/client"
+	"github.com/lavaorg/northstar/data/invocations/model"
+	"github.com/lavaorg/northstar/rte/rtepub"
 )
 
+const TAIL_INTERVAL = time.Second
+
 type InvocationGetCmd struct {
 	client       *client.InvocationClient
 	cmd          *flag.FlagSet
 	invocationId *string
+	logs         *bool
+	level        *string
+	since        *string
+	tail         *bool
 }
 
 func NewGetInvocation(client *client.InvocationClient) commands.Command {
 	cmd := flag.NewFlagSet("invoke-get", flag.ExitOnError)
 	invocationId := cmd.String("id", "", "The invocation id")
+	logs := cmd.Bool("logs", false, "Print the nsLog entries of the invocation")
+	level := cmd.String("level", model.LOG_DEBUG, "The minimum level of the log entries (debug, info, warn, error)")
+	since := cmd.String("since", "", "Print the log entries written since the time (RFC 3339)")
+	tail := cmd.Bool("tail", false, "Follow the log until the invocation completes")
 
 	return &InvocationGetCmd{client: client,
 		cmd:          cmd,
-		invocationId: invocationId}
+		invocationId: invocationId,
+		logs:         logs,
+		level:        level,
+		since:        since,
+		tail:         tail}
 }
 
 func (output *InvocationGetCmd) Run(args []string) error {
@@ -52,6 +69,10 @@ func (output *InvocationGetCmd) Run(args []string) error {
 		return errors.New("Please set a invocation id using -id.")
 	}
 
+	if *output.logs || *output.tail {
+		return output.printLogs()
+	}
+
 	result, err := output.client.GetInvocation(util.GetAccountID(), *output.invocationId)
 	if err != nil {
 		return err
@@ -60,3 +81,56 @@ func (output *InvocationGetCmd) Run(args []string) error {
 	fmt.Println(result.Print())
 	return nil
 }
+
+// printLogs prints the log entries of the invocation. When tailing, the log
+// is polled for the entries after the last one printed until the invocation
+// is no longer running and the log is caught up, so the entries flushed at the
+// end of the invocation are printed.
+func (output *InvocationGetCmd) printLogs() error {
+	if err := model.ValidateLogLevel(*output.level); err != nil {
+		return err
+	}
+
+	query := &model.LogQuery{Level: *output.level}
+	if *output.since != "" {
+		since, err := time.Parse(time.RFC3339, *output.since)
+		if err != nil {
+			return fmt.Errorf("Invalid -since time: %v", err)
+		}
+		query.Since = since
+	}
+
+	accountId := util.GetAccountID()
+	for {
+		running := false
+		if *output.tail {
+			invocation, err := output.client.GetInvocation(accountId, *output.invocationId)
+			if err != nil {
+				return err
+			}
+			running = invocation.Status == rtepub.SNIPPET_START_EVENT ||
+				invocation.Status == rtepub.SNIPPET_RUNNING_EVENT
+		}
+
+		entries, err := output.client.GetLogs(accountId, *output.invocationId, query)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			fmt.Println(entry.Print())
+			query.After = entry.Id
+		}
+
+		// The log is read in pages, so it is read on until caught up.
+		if len(entries) > 0 {
+			continue
+		}
+
+		if !running {
+			return nil
+		}
+
+		time.Sleep(TAIL_INTERVAL)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libraries
+
+import (
+	"flag"
+	"io/ioutil"
+
+	"errors"
+	"fmt"
+	"github.com/lavaorg/northstar/cli/commands"
+	"github.com/lavaorg/northstar/cli/util"
+	"github.com/lavaorg/northstar/data/libraries/client"
+	"github.com/lavaorg/northstar/data/libraries/model"
+)
+
+type AddLibraryCmd struct {
+	client      *client.LibrariesClient
+	cmd         *flag.FlagSet
+	name        *string
+	file        *string
+	description *string
+}
+
+func NewAddLibrary(client *client.LibrariesClient) commands.Command {
+	cmd := flag.NewFlagSet("libraries-add", flag.ExitOnError)
+	name := cmd.String("name", "", "The library name, required by snippets as lib.<name>@<version>")
+	file := cmd.String("file", "", "The library Lua file")
+	description := cmd.String("description", "", "The library description")
+
+	return &AddLibraryCmd{client: client,
+		cmd:         cmd,
+		name:        name,
+		file:        file,
+		description: description}
+}
+
+func (add *AddLibraryCmd) Run(args []string) error {
+	add.cmd.Parse(args)
+
+	if !add.cmd.Parsed() {
+		return errors.New("Failed to parse cmd")
+	}
+
+	if *add.name == "" {
+		return errors.New("Please set a name using -name.")
+	}
+
+	if *add.file == "" {
+		return errors.New("Please set a file using -file.")
+	}
+
+	code, err := ioutil.ReadFile(*add.file)
+	if err != nil {
+		return err
+	}
+
+	req := &model.LibraryData{Code: string(code), Description: *add.description}
+	version, mErr := add.client.AddLibrary(util.GetAccountID(), *add.name, req)
+	if mErr != nil {
+		return mErr
+	}
+
+	fmt.Printf("Library added, require it as lib.%s@%d\n", *add.name, version)
+	return nil
+}
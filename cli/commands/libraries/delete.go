@@ -0,0 +1,66 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libraries
+
+import (
+	"flag"
+
+	"errors"
+	"fmt"
+	"github.com/lavaorg/northstar/cli/commands"
+	"github.com/lavaorg/northstar/cli/util"
+	"github.com/lavaorg/northstar/data/libraries/client"
+	"github.com/lavaorg/northstar/data/libraries/model"
+)
+
+type DeleteLibraryCmd struct {
+	client  *client.LibrariesClient
+	cmd     *flag.FlagSet
+	name    *string
+	version *int
+}
+
+func NewDeleteLibrary(client *client.LibrariesClient) commands.Command {
+	cmd := flag.NewFlagSet("libraries-delete", flag.ExitOnError)
+	name := cmd.String("name", "", "The library name")
+	version := cmd.Int("version", model.LATEST, "The library version, all versions if not set")
+
+	return &DeleteLibraryCmd{client: client,
+		cmd:     cmd,
+		name:    name,
+		version: version}
+}
+
+func (d *DeleteLibraryCmd) Run(args []string) error {
+	d.cmd.Parse(args)
+
+	if !d.cmd.Parsed() {
+		return errors.New("Failed to parse cmd")
+	}
+
+	if *d.name == "" {
+		return errors.New("Please set a name using -name.")
+	}
+
+	err := d.client.DeleteLibrary(util.GetAccountID(), *d.name, *d.version)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Library deleted")
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/lavaorg/northstar/cli/commands"
+	"github.com/lavaorg/northstar/cli/util"
+	"github.com/lavaorg/northstar/object/client"
+	"github.com/lavaorg/northstar/object/model"
+)
+
+type LifecycleCMD struct {
+	client *client.ObjectClient
+	cmd    *flag.FlagSet
+	name   *string
+	rules  *string
+}
+
+func NewLifecycle(client *client.ObjectClient) commands.Command {
+	cmd := flag.NewFlagSet("object-bucket-lifecycle", flag.ExitOnError)
+	name := cmd.String("name", "testbucket", "The bucket name")
+	rules := cmd.String("rules", "", "The JSON file of the lifecycle rules to set, show them when empty")
+	return &LifecycleCMD{client: client, cmd: cmd, name: name, rules: rules}
+}
+
+func (lifecycle *LifecycleCMD) Run(args []string) error {
+	lifecycle.cmd.Parse(args)
+
+	if !lifecycle.cmd.Parsed() {
+		return errors.New("Failed to parse cmd")
+	}
+
+	if *lifecycle.rules == "" {
+		rules, mErr := lifecycle.client.GetLifecycle(util.GetAccountID(), *lifecycle.name)
+		if mErr != nil {
+			return mErr
+		}
+
+		if len(rules.Rules) == 0 {
+			fmt.Println("No lifecycle rules found")
+			return nil
+		}
+
+		printRules(rules.Rules)
+		return nil
+	}
+
+	byteArr, err := ioutil.ReadFile(*lifecycle.rules)
+	if err != nil {
+		return err
+	}
+
+	var rules model.Lifecycle
+	if err := json.Unmarshal(byteArr, &rules); err != nil {
+		return err
+	}
+
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+
+	if mErr := lifecycle.client.SetLifecycle(util.GetAccountID(), *lifecycle.name, &rules); mErr != nil {
+		return mErr
+	}
+
+	fmt.Printf("Bucket %s has %d lifecycle rules\n", *lifecycle.name, len(rules.Rules))
+	return nil
+}
+
+func printRules(rules []model.LifecycleRule) {
+	for _, rule := range rules {
+		fmt.Printf("Id: %v, prefix: %v, expireDays: %v, transitionDays: %v, transitionPrefix: %v\n",
+			rule.Id, rule.Prefix, rule.ExpireDays, rule.TransitionDays, rule.TransitionPrefix)
+	}
+}
@@ -28,15 +28,26 @@ import (
 )
 
 type ListFilesCMD struct {
-	client *client.ObjectClient
-	cmd    *flag.FlagSet
-	name   *string
+	client   *client.ObjectClient
+	cmd      *flag.FlagSet
+	name     *string
+	prefix   *string
+	tags     *string
+	metadata *string
 }
 
 func NewListFiles(client *client.ObjectClient) commands.Command {
 	cmd := flag.NewFlagSet("object-files-list", flag.ExitOnError)
 	name := cmd.String("name", "testbucket", "The bucket name")
-	return &ListFilesCMD{client: client, cmd: cmd, name: name}
+	prefix := cmd.String("prefix", "", "The prefix of the file names")
+	tags := cmd.String("tags", "", "The tags of the files, as key=value pairs separated by commas")
+	metadata := cmd.String("metadata", "", "The user metadata of the files, as key=value pairs separated by commas")
+	return &ListFilesCMD{client: client,
+		cmd:      cmd,
+		name:     name,
+		prefix:   prefix,
+		tags:     tags,
+		metadata: metadata}
 }
 
 func (listFiles *ListFilesCMD) Run(args []string) error {
@@ -46,11 +57,22 @@ func (listFiles *ListFilesCMD) Run(args []string) error {
 		return errors.New("Failed to parse cmd")
 	}
 
-	files, err := listFiles.client.ListFiles(util.GetAccountID(), *listFiles.name)
+	tags, err := parsePairs(*listFiles.tags)
 	if err != nil {
 		return err
 	}
 
+	metadata, err := parsePairs(*listFiles.metadata)
+	if err != nil {
+		return err
+	}
+
+	filter := &model.Filter{Prefix: *listFiles.prefix, Tags: tags, Metadata: metadata}
+	files, mErr := listFiles.client.FindFiles(util.GetAccountID(), *listFiles.name, filter)
+	if mErr != nil {
+		return mErr
+	}
+
 	if len(files) == 0 {
 		fmt.Println("No files found")
 		return nil
@@ -62,7 +84,14 @@ func (listFiles *ListFilesCMD) Run(args []string) error {
 
 func printFiles(results []model.Object) {
 	for _, data := range results {
-		fmt.Printf("Name: %v, size: %v, etag: %v, lastModified: %v, storageClass: %v\n",
+		fmt.Printf("Name: %v, size: %v, etag: %v, lastModified: %v, storageClass: %v",
 			data.Key, data.Size, data.Etag, data.LastModified, data.StorageClass)
+		if len(data.Tags) > 0 {
+			fmt.Printf(", tags: %v", data.Tags)
+		}
+		if len(data.Metadata) > 0 {
+			fmt.Printf(", metadata: %v", data.Metadata)
+		}
+		fmt.Println()
 	}
 }
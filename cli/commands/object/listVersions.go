@@ -0,0 +1,65 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/lavaorg/northstar/cli/commands"
+	"github.com/lavaorg/northstar/cli/util"
+	"github.com/lavaorg/northstar/object/client"
+)
+
+type ListVersionsCMD struct {
+	client *client.ObjectClient
+	cmd    *flag.FlagSet
+	bucket *string
+	file   *string
+}
+
+func NewListVersions(client *client.ObjectClient) commands.Command {
+	cmd := flag.NewFlagSet("object-file-versions", flag.ExitOnError)
+	bucket := cmd.String("bucket", "testbucket", "The bucket name")
+	file := cmd.String("file", "test.csv", "The file name")
+	return &ListVersionsCMD{client: client, cmd: cmd, bucket: bucket, file: file}
+}
+
+func (listVersions *ListVersionsCMD) Run(args []string) error {
+	listVersions.cmd.Parse(args)
+
+	if !listVersions.cmd.Parsed() {
+		return errors.New("Failed to parse cmd")
+	}
+
+	versions, mErr := listVersions.client.ListVersions(util.GetAccountID(), *listVersions.bucket, *listVersions.file)
+	if mErr != nil {
+		return mErr
+	}
+
+	if len(versions) == 0 {
+		fmt.Println("No versions found")
+		return nil
+	}
+
+	for _, data := range versions {
+		fmt.Printf("VersionId: %v, latest: %v, size: %v, etag: %v, lastModified: %v\n",
+			data.VersionId, data.IsLatest, data.Size, data.Etag, data.LastModified)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/lavaorg/northstar/cli/commands"
+	"github.com/lavaorg/northstar/cli/util"
+	"github.com/lavaorg/northstar/object/client"
+	"github.com/lavaorg/northstar/object/model"
+)
+
+type NotificationsCMD struct {
+	client *client.ObjectClient
+	cmd    *flag.FlagSet
+	name   *string
+	rules  *string
+}
+
+func NewNotifications(client *client.ObjectClient) commands.Command {
+	cmd := flag.NewFlagSet("object-bucket-notifications", flag.ExitOnError)
+	name := cmd.String("name", "testbucket", "The bucket name")
+	rules := cmd.String("rules", "", "The JSON file of the notification rules to set, show them when empty")
+	return &NotificationsCMD{client: client, cmd: cmd, name: name, rules: rules}
+}
+
+func (notifications *NotificationsCMD) Run(args []string) error {
+	notifications.cmd.Parse(args)
+
+	if !notifications.cmd.Parsed() {
+		return errors.New("Failed to parse cmd")
+	}
+
+	if *notifications.rules == "" {
+		rules, mErr := notifications.client.GetNotifications(util.GetAccountID(), *notifications.name)
+		if mErr != nil {
+			return mErr
+		}
+
+		if len(rules.Rules) == 0 {
+			fmt.Println("No notification rules found")
+			return nil
+		}
+
+		for _, rule := range rules.Rules {
+			fmt.Printf("Id: %v, eventId: %v, prefix: %v, types: %v\n", rule.Id, rule.EventId, rule.Prefix, rule.Types)
+		}
+		return nil
+	}
+
+	byteArr, err := ioutil.ReadFile(*notifications.rules)
+	if err != nil {
+		return err
+	}
+
+	var rules model.Notifications
+	if err := json.Unmarshal(byteArr, &rules); err != nil {
+		return err
+	}
+
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+
+	if mErr := notifications.client.SetNotifications(util.GetAccountID(), *notifications.name, &rules); mErr != nil {
+		return mErr
+	}
+
+	fmt.Printf("Bucket %s has %d notification rules\n", *notifications.name, len(rules.Rules))
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/lavaorg/northstar/cli/commands"
+	"github.com/lavaorg/northstar/cli/util"
+	"github.com/lavaorg/northstar/object/client"
+	"github.com/lavaorg/northstar/object/model"
+)
+
+type RestoreVersionCMD struct {
+	client  *client.ObjectClient
+	cmd     *flag.FlagSet
+	bucket  *string
+	file    *string
+	version *string
+}
+
+func NewRestoreVersion(client *client.ObjectClient) commands.Command {
+	cmd := flag.NewFlagSet("object-file-restore", flag.ExitOnError)
+	bucket := cmd.String("bucket", "testbucket", "The bucket name")
+	file := cmd.String("file", "test.csv", "The file name")
+	version := cmd.String("version", "", "The version to restore")
+	return &RestoreVersionCMD{client: client, cmd: cmd, bucket: bucket, file: file, version: version}
+}
+
+func (restore *RestoreVersionCMD) Run(args []string) error {
+	restore.cmd.Parse(args)
+
+	if !restore.cmd.Parsed() {
+		return errors.New("Failed to parse cmd")
+	}
+
+	data := &model.RestoreData{FileName: *restore.file, VersionId: *restore.version}
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	if mErr := restore.client.RestoreVersion(util.GetAccountID(), *restore.bucket, data); mErr != nil {
+		return mErr
+	}
+
+	fmt.Printf("Version %s of file %s restored\n", *restore.version, *restore.file)
+	return nil
+}
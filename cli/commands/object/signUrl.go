@@ -0,0 +1,67 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/lavaorg/northstar/cli/commands"
+	"github.com/lavaorg/northstar/cli/util"
+	"github.com/lavaorg/northstar/object/client"
+	"github.com/lavaorg/northstar/object/model"
+)
+
+type SignUrlCMD struct {
+	client  *client.ObjectClient
+	cmd     *flag.FlagSet
+	bucket  *string
+	file    *string
+	method  *string
+	expires *int64
+}
+
+func NewSignUrl(client *client.ObjectClient) commands.Command {
+	cmd := flag.NewFlagSet("object-file-sign", flag.ExitOnError)
+	bucket := cmd.String("bucket", "testbucket", "The bucket name")
+	file := cmd.String("file", "test.csv", "The file name")
+	method := cmd.String("method", "GET", "GET to download the file, PUT to upload it")
+	expires := cmd.Int64("expires", 3600, "The number of seconds the url is valid")
+	return &SignUrlCMD{client: client, cmd: cmd, bucket: bucket, file: file, method: method, expires: expires}
+}
+
+func (sign *SignUrlCMD) Run(args []string) error {
+	sign.cmd.Parse(args)
+
+	if !sign.cmd.Parsed() {
+		return errors.New("Failed to parse cmd")
+	}
+
+	request := &model.SignRequest{FileName: *sign.file, Method: *sign.method, ExpiresIn: *sign.expires}
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	signed, mErr := sign.client.SignUrl(util.GetAccountID(), *sign.bucket, request)
+	if mErr != nil {
+		return mErr
+	}
+
+	fmt.Printf("%s %s (expires %s)\n", signed.Method, signed.Url, signed.Expires)
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"strings"
 	"github.com/lavaorg/northstar/cli/commands"
 	"github.com/lavaorg/northstar/cli/util"
 	"github.com/lavaorg/northstar/object/client"
@@ -35,6 +36,8 @@ type UploadFileCMD struct {
 	contentType *string
 	localFile   *string
 	remoteFile  *string
+	metadata    *string
+	tags        *string
 }
 
 func NewUploadFile(client *client.ObjectClient) commands.Command {
@@ -43,13 +46,17 @@ func NewUploadFile(client *client.ObjectClient) commands.Command {
 	contentType := cmd.String("contentType", "text/plain", "The content type")
 	localFile := cmd.String("localFile", "test.csv", "The local file name")
 	remoteFile := cmd.String("remoteFile", "test.csv", "The local file name")
+	metadata := cmd.String("metadata", "", "The user metadata, as key=value pairs separated by commas")
+	tags := cmd.String("tags", "", "The tags, as key=value pairs separated by commas")
 
 	return &UploadFileCMD{client: client,
 		cmd:         cmd,
 		bucket:      bucket,
 		contentType: contentType,
 		localFile:   localFile,
-		remoteFile:  remoteFile}
+		remoteFile:  remoteFile,
+		metadata:    metadata,
+		tags:        tags}
 }
 
 func (upload *UploadFileCMD) Run(args []string) error {
@@ -64,9 +71,21 @@ func (upload *UploadFileCMD) Run(args []string) error {
 		return err
 	}
 
+	metadata, err := parsePairs(*upload.metadata)
+	if err != nil {
+		return err
+	}
+
+	tags, err := parsePairs(*upload.tags)
+	if err != nil {
+		return err
+	}
+
 	data := model.UploadData{FileName: *upload.remoteFile,
 		ContentType: *upload.contentType,
-		Payload:     byteArr}
+		Payload:     byteArr,
+		Metadata:    metadata,
+		Tags:        tags}
 	_, mErr := upload.client.UploadFile(util.GetAccountID(),
 		*upload.bucket,
 		&data)
@@ -77,3 +96,21 @@ func (upload *UploadFileCMD) Run(args []string) error {
 	fmt.Printf("File %s uploaded as %s\n", *upload.localFile, *upload.remoteFile)
 	return nil
 }
+
+// parsePairs parses the key=value pairs of a flag separated by commas.
+func parsePairs(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	pairs := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("Invalid key=value pair %s", pair)
+		}
+		pairs[kv[0]] = kv[1]
+	}
+
+	return pairs, nil
+}
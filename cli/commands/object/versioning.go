@@ -0,0 +1,68 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/lavaorg/northstar/cli/commands"
+	"github.com/lavaorg/northstar/cli/util"
+	"github.com/lavaorg/northstar/object/client"
+)
+
+type VersioningCMD struct {
+	client *client.ObjectClient
+	cmd    *flag.FlagSet
+	name   *string
+	status *string
+}
+
+func NewVersioning(client *client.ObjectClient) commands.Command {
+	cmd := flag.NewFlagSet("object-bucket-versioning", flag.ExitOnError)
+	name := cmd.String("name", "testbucket", "The bucket name")
+	status := cmd.String("status", "", "Set the versioning to enabled or suspended, show it when empty")
+	return &VersioningCMD{client: client, cmd: cmd, name: name, status: status}
+}
+
+func (versioning *VersioningCMD) Run(args []string) error {
+	versioning.cmd.Parse(args)
+
+	if !versioning.cmd.Parsed() {
+		return errors.New("Failed to parse cmd")
+	}
+
+	switch *versioning.status {
+	case "":
+		enabled, mErr := versioning.client.GetVersioning(util.GetAccountID(), *versioning.name)
+		if mErr != nil {
+			return mErr
+		}
+		fmt.Printf("Bucket %s versioning enabled: %v\n", *versioning.name, enabled)
+		return nil
+	case "enabled", "suspended":
+		enabled := *versioning.status == "enabled"
+		if mErr := versioning.client.SetVersioning(util.GetAccountID(), *versioning.name, enabled); mErr != nil {
+			return mErr
+		}
+		fmt.Printf("Bucket %s versioning %s\n", *versioning.name, *versioning.status)
+		return nil
+	}
+
+	return fmt.Errorf("Invalid versioning status %s", *versioning.status)
+}
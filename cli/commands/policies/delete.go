@@ -0,0 +1,58 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policies
+
+import (
+	"flag"
+
+	"errors"
+	"fmt"
+	"github.com/lavaorg/northstar/cli/commands"
+	"github.com/lavaorg/northstar/cli/util"
+	"github.com/lavaorg/northstar/data/policies/client"
+)
+
+type DeletePolicyCmd struct {
+	client    *client.PoliciesClient
+	cmd       *flag.FlagSet
+	snippetId *string
+}
+
+func NewDeletePolicy(client *client.PoliciesClient) commands.Command {
+	cmd := flag.NewFlagSet("policies-delete", flag.ExitOnError)
+	snippetId := cmd.String("snippetId", "", "The snippet id, the account policy is deleted if not set")
+
+	return &DeletePolicyCmd{client: client,
+		cmd:       cmd,
+		snippetId: snippetId}
+}
+
+func (delete *DeletePolicyCmd) Run(args []string) error {
+	delete.cmd.Parse(args)
+
+	if !delete.cmd.Parsed() {
+		return errors.New("Failed to parse cmd")
+	}
+
+	err := delete.client.DeletePolicy(util.GetAccountID(), getSnippetId(*delete.snippetId))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Policy deleted")
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policies
+
+import (
+	"flag"
+
+	"errors"
+	"fmt"
+	"github.com/lavaorg/northstar/cli/commands"
+	"github.com/lavaorg/northstar/cli/util"
+	"github.com/lavaorg/northstar/data/policies/client"
+)
+
+type GetPolicyCmd struct {
+	client    *client.PoliciesClient
+	cmd       *flag.FlagSet
+	snippetId *string
+}
+
+func NewGetPolicy(client *client.PoliciesClient) commands.Command {
+	cmd := flag.NewFlagSet("policies-get", flag.ExitOnError)
+	snippetId := cmd.String("snippetId", "", "The snippet id, the account policy is returned if not set")
+
+	return &GetPolicyCmd{client: client,
+		cmd:       cmd,
+		snippetId: snippetId}
+}
+
+func (get *GetPolicyCmd) Run(args []string) error {
+	get.cmd.Parse(args)
+
+	if !get.cmd.Parsed() {
+		return errors.New("Failed to parse cmd")
+	}
+
+	result, err := get.client.GetPolicy(util.GetAccountID(), getSnippetId(*get.snippetId))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(result.Print())
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policies
+
+import (
+	"flag"
+
+	"errors"
+	"fmt"
+	"github.com/lavaorg/northstar/cli/commands"
+	"github.com/lavaorg/northstar/cli/util"
+	"github.com/lavaorg/northstar/data/policies/client"
+	"github.com/lavaorg/northstar/data/policies/model"
+	"strings"
+	"time"
+)
+
+type SetPolicyCmd struct {
+	client    *client.PoliciesClient
+	cmd       *flag.FlagSet
+	snippetId *string
+	modules   *string
+	hosts     *string
+}
+
+func NewSetPolicy(client *client.PoliciesClient) commands.Command {
+	cmd := flag.NewFlagSet("policies-set", flag.ExitOnError)
+	snippetId := cmd.String("snippetId", "", "The snippet id, the account policy is set if not set")
+	modules := cmd.String("modules", "", "Comma separated modules the snippets may require, any module if not set")
+	hosts := cmd.String("hosts", "", "Comma separated hosts the network modules may connect to, any host if not set")
+
+	return &SetPolicyCmd{client: client,
+		cmd:       cmd,
+		snippetId: snippetId,
+		modules:   modules,
+		hosts:     hosts}
+}
+
+func (set *SetPolicyCmd) Run(args []string) error {
+	set.cmd.Parse(args)
+
+	if !set.cmd.Parsed() {
+		return errors.New("Failed to parse cmd")
+	}
+
+	data := &model.PolicyData{SnippetId: getSnippetId(*set.snippetId),
+		Modules:   splitList(*set.modules),
+		Hosts:     splitList(*set.hosts),
+		UpdatedOn: time.Now()}
+
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	err := set.client.SetPolicy(util.GetAccountID(), data.SnippetId, data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Policy set")
+	return nil
+}
+
+// Returns the snippet id the policy is stored under, the account policy
+// when no snippet id is given.
+func getSnippetId(snippetId string) string {
+	if snippetId == "" {
+		return model.ACCOUNT_POLICY
+	}
+
+	return snippetId
+}
+
+func splitList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
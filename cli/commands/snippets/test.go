@@ -0,0 +1,130 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snippets
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/lavaorg/northstar/cli/commands"
+	"github.com/lavaorg/northstar/rte-lua/snippettest"
+)
+
+type TestSnippetCmd struct {
+	cmd    *flag.FlagSet
+	file   *string
+	dir    *string
+	tests  *string
+	record *bool
+}
+
+func NewTestSnippet() commands.Command {
+	cmd := flag.NewFlagSet("snippets-test", flag.ExitOnError)
+	file := cmd.String("file", "", "The snippet file")
+	dir := cmd.String("dir", "", "The directory of the snippets with colocated tests")
+	tests := cmd.String("tests", "", "The test file (default <snippet>_test.json)")
+	record := cmd.Bool("record", false, "Send http requests without a fixture and record them in the test file")
+
+	return &TestSnippetCmd{cmd: cmd,
+		file:   file,
+		dir:    dir,
+		tests:  tests,
+		record: record,
+	}
+}
+
+func (test *TestSnippetCmd) Run(args []string) error {
+	test.cmd.Parse(args)
+
+	if !test.cmd.Parsed() {
+		return errors.New("Failed to parse cmd")
+	}
+
+	var snippets []string
+	switch {
+	case *test.file != "":
+		snippets = []string{*test.file}
+	case *test.dir != "":
+		var err error
+		if snippets, err = snippettest.Discover(*test.dir); err != nil {
+			return err
+		}
+	default:
+		return errors.New("Either file or dir is required")
+	}
+
+	runner := snippettest.NewRunner()
+	if *test.record {
+		runner.Recorder = http.DefaultClient
+	}
+
+	failed := 0
+	for _, snippet := range snippets {
+		testFile := snippettest.TestFile(snippet)
+		if *test.tests != "" && *test.file != "" {
+			testFile = *test.tests
+		}
+
+		n, err := test.run(runner, snippet, testFile)
+		if err != nil {
+			return err
+		}
+		failed += n
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d test case(s) failed", failed)
+	}
+
+	return nil
+}
+
+// run runs the suite of the snippet and returns the number of failed cases.
+func (test *TestSnippetCmd) run(runner *snippettest.Runner, snippet string, testFile string) (int, error) {
+	code, err := ioutil.ReadFile(snippet)
+	if err != nil {
+		return 0, err
+	}
+
+	suite, err := snippettest.LoadSuite(testFile)
+	if err != nil {
+		return 0, err
+	}
+
+	failed := 0
+	for _, result := range runner.Run(string(code), suite) {
+		if result.Passed() {
+			fmt.Printf("PASS %s: %s\n", snippet, result.Name)
+			continue
+		}
+
+		failed++
+		fmt.Printf("FAIL %s: %s\n", snippet, result.Name)
+		for _, failure := range result.Failures {
+			fmt.Printf("	%s\n", failure)
+		}
+	}
+
+	if *test.record {
+		return failed, suite.Save(testFile)
+	}
+
+	return failed, nil
+}
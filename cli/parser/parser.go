@@ -27,8 +27,10 @@ import (
 	"github.com/lavaorg/northstar/cli/commands/events"
 	"github.com/lavaorg/northstar/cli/commands/invoke"
 	kafkamgrCmd "github.com/lavaorg/northstar/cli/commands/kafkamgr"
+	"github.com/lavaorg/northstar/cli/commands/libraries"
 	"github.com/lavaorg/northstar/cli/commands/mappings"
 	"github.com/lavaorg/northstar/cli/commands/object"
+	"github.com/lavaorg/northstar/cli/commands/policies"
 	"github.com/lavaorg/northstar/cli/commands/snippets"
 	cronClient "github.com/lavaorg/northstar/cron/client"
 	cronDataClient "github.com/lavaorg/northstar/data/cron/client"
@@ -36,7 +38,9 @@ import (
 	datasourcesData "github.com/lavaorg/northstar/data/datasources/client"
 	eventsDataClient "github.com/lavaorg/northstar/data/events/client"
 	invocationsDataClient "github.com/lavaorg/northstar/data/invocations/client"
+	librariesDataClient "github.com/lavaorg/northstar/data/libraries/client"
 	mappingsDataClient "github.com/lavaorg/northstar/data/mappings/client"
+	policiesDataClient "github.com/lavaorg/northstar/data/policies/client"
 	snippetsDataClient "github.com/lavaorg/northstar/data/snippets/client"
 	kafkamgrClient "github.com/lavaorg/northstar/kafkamgr"
 	objectClient "github.com/lavaorg/northstar/object/client"
@@ -60,7 +64,9 @@ func InitParser(kafkaMngr *kafkamgrClient.KafkaMngrClient,
 	datasourcesData *datasourcesData.DatasourcesClient,
 	cronClient *cronClient.CronClient,
 	cronDataClient *cronDataClient.CronClient,
-	objectClient *objectClient.ObjectClient) {
+	objectClient *objectClient.ObjectClient,
+	librariesData *librariesDataClient.LibrariesClient,
+	policiesData *policiesDataClient.PoliciesClient) {
 	if len(os.Args) == 1 {
 		commands.PrintHelp()
 		return
@@ -80,6 +86,13 @@ func InitParser(kafkaMngr *kafkamgrClient.KafkaMngrClient,
 	listSnippet := snippets.NewListSnippets(snippetsData)
 	deleteSnippet := snippets.NewDeleteSnippet(snippetsData)
 	updateSnippet := snippets.NewUpdateSnippet(snippetsData)
+	testSnippet := snippets.NewTestSnippet()
+
+	// Libraries cmd
+	addLibrary := libraries.NewAddLibrary(librariesData)
+	getLibrary := libraries.NewGetLibrary(librariesData)
+	listLibraries := libraries.NewListLibraries(librariesData)
+	deleteLibrary := libraries.NewDeleteLibrary(librariesData)
 
 	// List cmd
 	getInvocation := invoke.NewGetInvocation(invocationData)
@@ -111,6 +124,11 @@ func InitParser(kafkaMngr *kafkamgrClient.KafkaMngrClient,
 	listDatasets := datasets.NewListDatasets(datasetsData)
 	deleteDataset := datasets.NewDeleteDataset(datasetsData)
 
+	// Policies cmd
+	setPolicy := policies.NewSetPolicy(policiesData)
+	getPolicy := policies.NewGetPolicy(policiesData)
+	deletePolicy := policies.NewDeletePolicy(policiesData)
+
 	// Cron cmd
 	addCron := cron.NewAddCronJob(cronClient)
 	deleteCron := cron.NewDeleteJob(cronClient)
@@ -121,12 +139,18 @@ func InitParser(kafkaMngr *kafkamgrClient.KafkaMngrClient,
 	createBucket := object.NewCreateBucket(objectClient)
 	listBuckets := object.NewListBucket(objectClient)
 	deleteBucket := object.NewDeleteBucket(objectClient)
+	bucketVersioning := object.NewVersioning(objectClient)
+	bucketLifecycle := object.NewLifecycle(objectClient)
+	bucketNotifications := object.NewNotifications(objectClient)
 
 	// Object file cmd
 	uploadFile := object.NewUploadFile(objectClient)
 	downloadFile := object.NewDownloadFile(objectClient)
 	listFiles := object.NewListFiles(objectClient)
 	deleteFile := object.NewDeleteFile(objectClient)
+	listVersions := object.NewListVersions(objectClient)
+	restoreVersion := object.NewRestoreVersion(objectClient)
+	signUrl := object.NewSignUrl(objectClient)
 
 	switch os.Args[1] {
 	case "object-bucket-create":
@@ -135,6 +159,12 @@ func InitParser(kafkaMngr *kafkamgrClient.KafkaMngrClient,
 		err = listBuckets.Run(os.Args[2:])
 	case "object-bucket-delete":
 		err = deleteBucket.Run(os.Args[2:])
+	case "object-bucket-versioning":
+		err = bucketVersioning.Run(os.Args[2:])
+	case "object-bucket-lifecycle":
+		err = bucketLifecycle.Run(os.Args[2:])
+	case "object-bucket-notifications":
+		err = bucketNotifications.Run(os.Args[2:])
 	case "object-file-upload":
 		err = uploadFile.Run(os.Args[2:])
 	case "object-file-download":
@@ -143,6 +173,12 @@ func InitParser(kafkaMngr *kafkamgrClient.KafkaMngrClient,
 		err = listFiles.Run(os.Args[2:])
 	case "object-file-delete":
 		err = deleteFile.Run(os.Args[2:])
+	case "object-file-versions":
+		err = listVersions.Run(os.Args[2:])
+	case "object-file-restore":
+		err = restoreVersion.Run(os.Args[2:])
+	case "object-file-sign":
+		err = signUrl.Run(os.Args[2:])
 	case "topics-add":
 		err = addTopic.Run(os.Args[2:])
 	case "topics-list":
@@ -161,6 +197,22 @@ func InitParser(kafkaMngr *kafkamgrClient.KafkaMngrClient,
 		err = listSnippet.Run(os.Args[2:])
 	case "snippets-delete":
 		err = deleteSnippet.Run(os.Args[2:])
+	case "snippets-test":
+		err = testSnippet.Run(os.Args[2:])
+	case "libraries-add":
+		err = addLibrary.Run(os.Args[2:])
+	case "libraries-get":
+		err = getLibrary.Run(os.Args[2:])
+	case "libraries-list":
+		err = listLibraries.Run(os.Args[2:])
+	case "libraries-delete":
+		err = deleteLibrary.Run(os.Args[2:])
+	case "policies-set":
+		err = setPolicy.Run(os.Args[2:])
+	case "policies-get":
+		err = getPolicy.Run(os.Args[2:])
+	case "policies-delete":
+		err = deletePolicy.Run(os.Args[2:])
 	case "cron-add":
 		err = addCron.Run(os.Args[2:])
 	case "cron-update":
@@ -27,7 +27,9 @@ import (
 	datasourcesData "github.com/lavaorg/northstar/data/datasources/client"
 	eventsData "github.com/lavaorg/northstar/data/events/client"
 	invocationData "github.com/lavaorg/northstar/data/invocations/client"
+	librariesData "github.com/lavaorg/northstar/data/libraries/client"
 	mappingData "github.com/lavaorg/northstar/data/mappings/client"
+	policiesData "github.com/lavaorg/northstar/data/policies/client"
 	snippetsData "github.com/lavaorg/northstar/data/snippets/client"
 	"github.com/lavaorg/northstar/kafkamgr"
 	object "github.com/lavaorg/northstar/object/client"
@@ -102,6 +104,18 @@ func main() {
 		os.Exit(-1)
 	}
 
+	librariesData, mErr := librariesData.NewLibrariesClient()
+	if mErr != nil {
+		mlog.Error("Failed to create libraries data client: %v", mErr)
+		os.Exit(-1)
+	}
+
+	policiesData, mErr := policiesData.NewPoliciesClient()
+	if mErr != nil {
+		mlog.Error("Failed to create policies data client: %v", mErr)
+		os.Exit(-1)
+	}
+
 	object, mErr := object.NewObjectClient()
 	if mErr != nil {
 		mlog.Error("Failed to create cron client: %v", mErr)
@@ -119,5 +133,7 @@ func main() {
 		datasourcesData,
 		cron,
 		cronData,
-		object)
+		object,
+		librariesData,
+		policiesData)
 }
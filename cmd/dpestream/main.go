@@ -40,12 +40,13 @@ func main() {
 	switch option {
 	case MASTER:
 		mlog.Info("Starting in master mode")
-		service, err := service.NewSteamService()
+		service, err := service.NewSteamService(worker.RunJob)
 		if err != nil {
 			mlog.Error("Failed to create stream service: %v", err)
 			os.Exit(-1)
 		}
 		service.AddRoutes()
+		service.StartAutoscaler()
 	case WORKER:
 		mlog.Info("Starting in worker mode")
 		err := worker.StartWorker()
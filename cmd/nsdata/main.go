@@ -27,10 +27,13 @@ import (
 	"github.com/lavaorg/northstar/data/env"
 	"github.com/lavaorg/northstar/data/events"
 	"github.com/lavaorg/northstar/data/invocations"
+	"github.com/lavaorg/northstar/data/libraries"
 	"github.com/lavaorg/northstar/data/mappings"
 	"github.com/lavaorg/northstar/data/notebooks"
+	"github.com/lavaorg/northstar/data/policies"
 	"github.com/lavaorg/northstar/data/snippets"
 	"github.com/lavaorg/northstar/data/stream"
+	"github.com/lavaorg/northstar/data/syncs"
 	"github.com/lavaorg/northstar/data/templates"
 )
 
@@ -77,6 +80,15 @@ func main() {
 	dataService = new(stream.StreamService)
 	dataService.AddRoutes()
 
+	dataService = new(policies.PoliciesService)
+	dataService.AddRoutes()
+
+	dataService = new(libraries.LibrariesService)
+	dataService.AddRoutes()
+
+	dataService = new(syncs.SyncsService)
+	dataService.AddRoutes()
+
 	port := ":" + dataPort
 	if err := management.Listen(port); err != nil {
 		mlog.Error("Error starting api service", err)
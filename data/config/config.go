@@ -35,6 +35,9 @@ var (
 	VaultHostPort, _                     = config.GetString("VAULT_HOST_PORT", "")
 	GatekeeperHostPort, _                = config.GetString("GATEKEEPER_HOST_PORT", "")
 	CassandraUsername, CassandraPassword = GetCassandraAuthCredentials(GatekeeperHostPort, VaultHostPort)
+
+	// Seconds the entries of invocation logs are retained.
+	InvocationLogRetention, _ = config.GetInt("INVOCATION_LOG_RETENTION", 604800)
 )
 
 func GetCassandraAuthCredentials(gatekeeperHostPort string, vaultHostPort string) (username string, password string) {
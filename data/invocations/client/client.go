@@ -19,6 +19,10 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
 	lb "github.com/lavaorg/lrtx/httpclientlb"
 	"github.com/lavaorg/lrtx/management"
 	"github.com/lavaorg/lrtx/mlog"
@@ -35,6 +39,8 @@ type Client interface {
 	GetInvocationsByAccountId(accountId string, limit int) ([]*model.InvocationData, *management.Error)
 	GetInvocationResults(accountId string, snippetId string, limit int) ([]*model.InvocationData, *management.Error)
 	DeleteInvocation(accountId string, invocationId string) *management.Error
+	AppendLogs(accountId string, invocationId string, entries []*model.LogEntry) *management.Error
+	GetLogs(accountId string, invocationId string, query *model.LogQuery) ([]*model.LogEntry, *management.Error)
 }
 
 type InvocationClient struct {
@@ -143,3 +149,50 @@ func (client *InvocationClient) DeleteInvocation(accountId string,
 
 	return nil
 }
+
+func (client *InvocationClient) AppendLogs(accountId string,
+	invocationId string,
+	entries []*model.LogEntry) *management.Error {
+	path := fmt.Sprintf("%s/logs/%s/%s", BASE_URI, accountId, invocationId)
+	_, err := client.lbClient.PostJSON(path, entries)
+	if err != nil {
+		mlog.Error("Invocation dataservice client: Error appending logs: %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (client *InvocationClient) GetLogs(accountId string,
+	invocationId string,
+	query *model.LogQuery) ([]*model.LogEntry, *management.Error) {
+	params := url.Values{}
+	if query.Level != "" {
+		params.Set("level", query.Level)
+	}
+	if !query.Since.IsZero() {
+		params.Set("since", query.Since.Format(time.RFC3339))
+	}
+	if !query.Until.IsZero() {
+		params.Set("until", query.Until.Format(time.RFC3339))
+	}
+	if query.After != "" {
+		params.Set("after", query.After)
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	path := fmt.Sprintf("%s/logs/%s/%s?%s", BASE_URI, accountId, invocationId, params.Encode())
+	resp, mErr := client.lbClient.Get(path)
+	if mErr != nil {
+		mlog.Error("Invocation dataservice client: Error getting logs: %s", mErr.Error())
+		return nil, mErr
+	}
+
+	var entries []*model.LogEntry
+	if err := json.Unmarshal(resp, &entries); err != nil {
+		return nil, management.GetInternalError(err.Error())
+	}
+
+	return entries, nil
+}
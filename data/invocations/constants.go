@@ -19,4 +19,8 @@ package invocations
 const (
 	Keyspace         = "account"
 	InvocationsTable = "invocations"
+	LogsTable        = "invocation_logs"
+
+	// Number of log entries returned when the query sets no limit.
+	DefaultLogLimit = 1000
 )
@@ -66,6 +66,8 @@ func (s *InvocationService) AddRoutes() {
 	g.DELETE("/invocation/:accountId/:invocationId", deleteInvocation)
 	g.GET("/history/by-account/:accountId/:limit", getInvocationsByAccountId)
 	g.GET("/history/by-snippet/:accountId/:snippetId/:limit", getInvocationHistory)
+	g.POST("/logs/:accountId/:invocationId", appendLogs)
+	g.GET("/logs/:accountId/:invocationId", getLogs)
 }
 
 func addInvocation(c *gin.Context) {
@@ -0,0 +1,225 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package invocations
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/data/config"
+	"github.com/lavaorg/northstar/data/invocations/model"
+	"github.com/lavaorg/northstar/data/util"
+)
+
+func appendLogs(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+	if accountId == "" {
+		mlog.Error("Failed to append logs due to bad request. Account Id is missing.")
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(util.AccountIdMissing))
+		ErrAppendLogs.Incr()
+		return
+	}
+
+	invocationId := c.Params.ByName("invocationId")
+	if invocationId == "" {
+		mlog.Error("Failed to append logs due to bad request. Invocation Id is missing.")
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(util.InvocationIdMissing))
+		ErrAppendLogs.Incr()
+		return
+	}
+
+	var entries []*model.LogEntry
+	if err := c.Bind(&entries); err != nil {
+		mlog.Error("Failed to decode request body: %v", err)
+		ErrAppendLogs.Incr()
+		return
+	}
+
+	for _, entry := range entries {
+		if err := entry.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+			ErrAppendLogs.Incr()
+			return
+		}
+	}
+
+	if err := appendLogsQuery(accountId, invocationId, entries); err != nil {
+		mlog.Error("Error appending logs of invocation %s: %v", invocationId, err)
+		if err == gocql.ErrNoConnections {
+			c.JSON(http.StatusBadGateway, management.GetExternalError(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		}
+		ErrAppendLogs.Incr()
+		return
+	}
+
+	mlog.Debug("%d log entries appended to invocation %s", len(entries), invocationId)
+	AppendLogs.Incr()
+	c.String(http.StatusCreated, "")
+}
+
+// appendLogsQuery inserts the entries, identified by time uuids of their
+// time, which expire after the log retention.
+func appendLogsQuery(accountId string, invocationId string, entries []*model.LogEntry) error {
+	session, err := getSession()
+	if err != nil {
+		return err
+	}
+
+	batch := session.NewBatch(gocql.UnloggedBatch)
+	for _, entry := range entries {
+		batch.Query(`INSERT INTO `+LogsTable+`(accountid, invocationid, id, level, message, fields)`+
+			` VALUES(?, ?, ?, ?, ?, ?) USING TTL ?`,
+			accountId,
+			invocationId,
+			gocql.UUIDFromTime(entry.Time),
+			entry.Level,
+			entry.Message,
+			entry.Fields,
+			config.InvocationLogRetention)
+	}
+
+	return session.ExecuteBatch(batch)
+}
+
+func getLogs(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+	if accountId == "" {
+		mlog.Error("Failed to get logs due to bad request. Account Id is missing.")
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(util.AccountIdMissing))
+		ErrGetLogs.Incr()
+		return
+	}
+
+	invocationId := c.Params.ByName("invocationId")
+	query, err := parseLogQuery(c)
+	if err != nil {
+		mlog.Error("Failed to get logs due to bad request: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		ErrGetLogs.Incr()
+		return
+	}
+
+	entries, err := getLogsQuery(accountId, invocationId, query)
+	if err != nil {
+		em := fmt.Sprintf("Error getting logs of invocation %s: %v", invocationId, err)
+		mlog.Error(em)
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(em))
+		ErrGetLogs.Incr()
+		return
+	}
+
+	GetLogs.Incr()
+	c.JSON(http.StatusOK, entries)
+}
+
+// parseLogQuery reads the query from the level, since, until, after and limit
+// parameters. Times are in RFC 3339 format.
+func parseLogQuery(c *gin.Context) (*model.LogQuery, error) {
+	query := &model.LogQuery{Level: c.DefaultQuery("level", model.LOG_DEBUG),
+		After: c.Query("after"),
+		Limit: DefaultLogLimit}
+
+	if err := model.ValidateLogLevel(query.Level); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if since := c.Query("since"); since != "" {
+		if query.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return nil, err
+		}
+	}
+
+	if until := c.Query("until"); until != "" {
+		if query.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			return nil, err
+		}
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			return nil, err
+		}
+	}
+
+	return query, nil
+}
+
+// getLogsQuery returns the entries selected by the query in time order. The
+// level is filtered while iterating, so the limit applies to the entries
+// returned.
+func getLogsQuery(accountId string, invocationId string, query *model.LogQuery) ([]*model.LogEntry, error) {
+	session, err := getSession()
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"accountid=?", "invocationid=?"}
+	values := []interface{}{accountId, invocationId}
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "id>=minTimeuuid(?)")
+		values = append(values, query.Since)
+	}
+
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "id<=maxTimeuuid(?)")
+		values = append(values, query.Until)
+	}
+
+	if query.After != "" {
+		after, err := gocql.ParseUUID(query.After)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, "id>?")
+		values = append(values, after)
+	}
+
+	entries := make([]*model.LogEntry, 0)
+	var id gocql.UUID
+	var level, message string
+	var fields map[string]string
+
+	iter := session.Query(`SELECT id, level, message, fields FROM `+LogsTable+
+		` WHERE `+strings.Join(conditions, " AND "), values...).Iter()
+	for (query.Limit <= 0 || len(entries) < query.Limit) && iter.Scan(&id, &level, &message, &fields) {
+		entry := &model.LogEntry{Id: id.String(),
+			Time:    id.Time(),
+			Level:   level,
+			Message: message,
+			Fields:  fields}
+		if entry.AtLeast(query.Level) {
+			entries = append(entries, entry)
+		}
+		fields = nil
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package invocations
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lavaorg/northstar/data/invocations/model"
+	"github.com/stretchr/testify/require"
+)
+
+// parse runs parseLogQuery on a request with the raw query.
+func parse(rawQuery string) (*model.LogQuery, error) {
+	gin.SetMode(gin.TestMode)
+
+	var query *model.LogQuery
+	var err error
+	engine := gin.New()
+	engine.GET("/logs", func(c *gin.Context) {
+		query, err = parseLogQuery(c)
+		c.String(http.StatusOK, "")
+	})
+
+	req, _ := http.NewRequest("GET", "/logs?"+rawQuery, nil)
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+	return query, err
+}
+
+func TestParseLogQueryDefaults(t *testing.T) {
+	query, err := parse("")
+	require.Nil(t, err)
+	require.Equal(t, &model.LogQuery{Level: model.LOG_DEBUG, Limit: DefaultLogLimit}, query)
+}
+
+func TestParseLogQuery(t *testing.T) {
+	query, err := parse("level=warn&since=2017-03-01T10:00:00Z&until=2017-03-01T11:00:00%2B01:00" +
+		"&after=50554d6e-29bb-11e5-b345-feff819cdc9f&limit=20")
+	require.Nil(t, err)
+	require.Equal(t, model.LOG_WARN, query.Level)
+	require.True(t, query.Since.Equal(time.Date(2017, 3, 1, 10, 0, 0, 0, time.UTC)))
+	require.True(t, query.Until.Equal(time.Date(2017, 3, 1, 10, 0, 0, 0, time.UTC)))
+	require.Equal(t, "50554d6e-29bb-11e5-b345-feff819cdc9f", query.After)
+	require.Equal(t, 20, query.Limit)
+}
+
+func TestParseLogQueryErrors(t *testing.T) {
+	for _, rawQuery := range []string{
+		"level=fatal",
+		"since=yesterday",
+		"until=2017-03-01",
+		"limit=ten",
+	} {
+		_, err := parse(rawQuery)
+		require.NotNil(t, err, rawQuery)
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	LOG_DEBUG = "debug"
+	LOG_INFO  = "info"
+	LOG_WARN  = "warn"
+	LOG_ERROR = "error"
+)
+
+// logLevels orders the levels by severity.
+var logLevels = map[string]int{LOG_DEBUG: 0, LOG_INFO: 1, LOG_WARN: 2, LOG_ERROR: 3}
+
+// LogEntry is an entry of the log of an invocation, written by the snippet
+// with the nsLog module.
+type LogEntry struct {
+	Id      string            `json:"id,omitempty"`
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// LogQuery selects the entries of an invocation log at or above the level,
+// written in the time range or after the entry with the id, which is how a
+// reader tails the log. Zero values do not restrict the query.
+type LogQuery struct {
+	Level string
+	Since time.Time
+	Until time.Time
+	After string
+	Limit int
+}
+
+func ValidateLogLevel(level string) error {
+	if _, ok := logLevels[level]; !ok {
+		return fmt.Errorf("Unknown log level: %s", level)
+	}
+
+	return nil
+}
+
+// AtLeast reports whether the level of the entry is the level or above.
+func (entry *LogEntry) AtLeast(level string) bool {
+	return logLevels[entry.Level] >= logLevels[level]
+}
+
+func (entry *LogEntry) Validate() error {
+	if entry.Time.IsZero() {
+		return fmt.Errorf("Time is empty")
+	}
+
+	return ValidateLogLevel(entry.Level)
+}
+
+func (entry *LogEntry) Print() string {
+	keys := make([]string, 0, len(entry.Fields))
+	for key := range entry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]string, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, fmt.Sprintf("%s=%q", key, entry.Fields[key]))
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s %-5s %s %s",
+		entry.Time.Format(time.RFC3339Nano),
+		strings.ToUpper(entry.Level),
+		entry.Message,
+		strings.Join(fields, " ")))
+}
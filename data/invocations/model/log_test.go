@@ -0,0 +1,82 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+	"time"
+)
+
+func TestLogEntryAtLeast(t *testing.T) {
+
+	Convey("Test AtLeast()", t, func() {
+		entry := LogEntry{Time: time.Now(), Level: LOG_WARN}
+
+		So(entry.AtLeast(LOG_DEBUG), ShouldBeTrue)
+		So(entry.AtLeast(LOG_INFO), ShouldBeTrue)
+		So(entry.AtLeast(LOG_WARN), ShouldBeTrue)
+		So(entry.AtLeast(LOG_ERROR), ShouldBeFalse)
+
+		entry.Level = LOG_DEBUG
+		So(entry.AtLeast(LOG_DEBUG), ShouldBeTrue)
+		So(entry.AtLeast(LOG_INFO), ShouldBeFalse)
+	})
+}
+
+func TestLogEntryValidate(t *testing.T) {
+
+	Convey("Test Validate()", t, func() {
+		entry := LogEntry{Time: time.Now(), Level: LOG_INFO, Message: "message"}
+
+		err := entry.Validate()
+		So(err, ShouldBeNil)
+
+		// Missing time.
+		errEntry := entry
+		errEntry.Time = time.Time{}
+		err = errEntry.Validate()
+		So(err, ShouldNotBeNil)
+
+		// Unknown level.
+		errEntry = entry
+		errEntry.Level = "fatal"
+		err = errEntry.Validate()
+		So(err, ShouldNotBeNil)
+
+		// Missing level.
+		errEntry = entry
+		errEntry.Level = ""
+		err = errEntry.Validate()
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestLogEntryPrint(t *testing.T) {
+
+	Convey("Test Print()", t, func() {
+		entry := LogEntry{Time: time.Date(2017, 3, 1, 10, 0, 0, 0, time.UTC),
+			Level:   LOG_ERROR,
+			Message: "failed",
+			Fields:  map[string]string{"b": "2", "a": "1"}}
+
+		So(entry.Print(), ShouldEqual, `2017-03-01T10:00:00Z ERROR failed a="1" b="2"`)
+
+		entry.Fields = nil
+		So(entry.Print(), ShouldEqual, `2017-03-01T10:00:00Z ERROR failed`)
+	})
+}
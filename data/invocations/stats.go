@@ -26,6 +26,8 @@ var (
 	GetInvocationsByAccountId = s.NewCounter("GetInvocationsByAccountId")
 	GetInvocationHistroy      = s.NewCounter("GetInvocationHistory")
 	DelInvocation             = s.NewCounter("DelInvocation")
+	AppendLogs                = s.NewCounter("AppendLogs")
+	GetLogs                   = s.NewCounter("GetLogs")
 
 	ErrInsertInvocation          = s.NewCounter("ErrInsertInvocation")
 	ErrUpdateInvocation          = s.NewCounter("ErrUpdateInvocation")
@@ -33,4 +35,6 @@ var (
 	ErrGetInvocationsByAccountId = s.NewCounter("ErrGetInvocationsByAccountId")
 	ErrGetInvocationHistory      = s.NewCounter("ErrGetInvocationHistory")
 	ErrDelInvocation             = s.NewCounter("ErrDelInvocation")
+	ErrAppendLogs                = s.NewCounter("ErrAppendLogs")
+	ErrGetLogs                   = s.NewCounter("ErrGetLogs")
 )
@@ -0,0 +1,127 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	lb "github.com/lavaorg/lrtx/httpclientlb"
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/data/libraries/model"
+	"github.com/lavaorg/northstar/data/util"
+)
+
+const BASE_URI = util.DataBasePath + "/libraries"
+
+type Client interface {
+	AddLibrary(accountId string, name string, data *model.LibraryData) (int, *management.Error)
+	ListLibraries(accountId string) ([]*model.LibraryData, *management.Error)
+	GetLibrary(accountId string, name string, version int) (*model.LibraryData, *management.Error)
+	DeleteLibrary(accountId string, name string, version int) *management.Error
+}
+
+type LibrariesClient struct {
+	lbClient *lb.LbClient
+}
+
+func NewLibrariesClient() (*LibrariesClient, error) {
+	url, err := util.GetDataBaseUrl()
+	if err != nil {
+		mlog.Error("Failed to get data base url with error: %s", err.Error())
+		return nil, err
+	}
+
+	lbClient, err := lb.GetClient(url)
+	if err != nil {
+		mlog.Info("Failed to create libraries data client with error: %s", err.Error())
+		return nil, err
+	}
+
+	return &LibrariesClient{lbClient: lbClient}, nil
+}
+
+// AddLibrary stores the code as a new version of the library and returns
+// the version.
+func (client *LibrariesClient) AddLibrary(accountId string,
+	name string,
+	data *model.LibraryData) (int, *management.Error) {
+	path := fmt.Sprintf("%s/%s/%s", BASE_URI, accountId, name)
+	resp, mErr := client.lbClient.PostJSON(path, data)
+	if mErr != nil {
+		mlog.Error("Libraries dataservice client: Error adding library %s", mErr.Error())
+		return 0, mErr
+	}
+
+	version, err := strconv.Atoi(string(resp))
+	if err != nil {
+		return 0, management.GetInternalError(err.Error())
+	}
+
+	return version, nil
+}
+
+// ListLibraries returns the versions of the libraries of the account,
+// without their code.
+func (client *LibrariesClient) ListLibraries(accountId string) ([]*model.LibraryData, *management.Error) {
+	path := fmt.Sprintf("%s/%s", BASE_URI, accountId)
+	resp, mErr := client.lbClient.Get(path)
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	var libraries []*model.LibraryData
+	if err := json.Unmarshal(resp, &libraries); err != nil {
+		return nil, management.GetInternalError(err.Error())
+	}
+
+	return libraries, nil
+}
+
+// GetLibrary returns the version of the library, the latest one for
+// model.LATEST. A not found error is returned when there is no such version.
+func (client *LibrariesClient) GetLibrary(accountId string,
+	name string,
+	version int) (*model.LibraryData, *management.Error) {
+	resp, mErr := client.lbClient.Get(client.path(accountId, name, version))
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	var library *model.LibraryData
+	if err := json.Unmarshal(resp, &library); err != nil {
+		return nil, management.GetInternalError(err.Error())
+	}
+
+	return library, nil
+}
+
+// DeleteLibrary deletes the version of the library, all its versions for
+// model.LATEST.
+func (client *LibrariesClient) DeleteLibrary(accountId string, name string, version int) *management.Error {
+	return client.lbClient.Delete(client.path(accountId, name, version))
+}
+
+func (client *LibrariesClient) path(accountId string, name string, version int) string {
+	if version == model.LATEST {
+		return fmt.Sprintf("%s/%s/%s", BASE_URI, accountId, name)
+	}
+
+	return fmt.Sprintf("%s/%s/%s/%d", BASE_URI, accountId, name, version)
+}
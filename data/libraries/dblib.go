@@ -0,0 +1,281 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libraries
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/data/libraries/model"
+	"github.com/lavaorg/northstar/data/util"
+)
+
+var (
+	sess *gocql.Session
+	lock sync.Mutex
+)
+
+// Helper method used to get/create database session.
+func getSession() (*gocql.Session, error) {
+	var err error
+
+	if sess == nil || sess.Closed() {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if sess == nil || sess.Closed() {
+			sess, err = util.NewDB(Keyspace).GetSessionWithError()
+		}
+	}
+
+	return sess, err
+}
+
+// LibrariesService stores the versions of the Lua libraries the snippets of
+// an account require as lib.<name>@<version>.
+type LibrariesService struct{}
+
+func (s *LibrariesService) AddRoutes() {
+	grp := management.Engine().Group(util.DataBasePath)
+	g := grp.Group("libraries")
+	g.POST("/:accountId/:name", addLibrary)
+	g.GET("/:accountId", listLibraries)
+	g.GET("/:accountId/:name", getLibrary)
+	g.GET("/:accountId/:name/:version", getLibrary)
+	g.DELETE("/:accountId/:name", deleteLibrary)
+	g.DELETE("/:accountId/:name/:version", deleteLibrary)
+}
+
+func addLibrary(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+	name := c.Params.ByName("name")
+	if err := model.ValidateName(name); err != nil {
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		ErrAddLibrary.Incr()
+		return
+	}
+
+	var library = new(model.LibraryData)
+	if err := c.Bind(library); err != nil {
+		mlog.Error("Failed to decode request body: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		ErrAddLibrary.Incr()
+		return
+	}
+
+	if err := library.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		ErrAddLibrary.Incr()
+		return
+	}
+
+	library.Name = name
+	version, err := addLibraryQuery(accountId, library)
+	if err != nil {
+		mlog.Error("Error adding library %s: %v", name, err)
+		if err == gocql.ErrNoConnections {
+			c.JSON(http.StatusBadGateway, management.GetExternalError(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		}
+		ErrAddLibrary.Incr()
+		return
+	}
+
+	mlog.Info("Version %d of library %s added to account %s", version, name, accountId)
+	AddLibrary.Incr()
+	c.String(http.StatusCreated, strconv.Itoa(version))
+}
+
+// addLibraryQuery inserts the library as the version following the latest
+// one. The insert is conditional, so concurrent additions get distinct
+// versions.
+func addLibraryQuery(accountId string, library *model.LibraryData) (int, error) {
+	session, err := getSession()
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < AddRetries; i++ {
+		var latest int
+		if err := session.Query(`SELECT version FROM `+LibrariesTable+
+			` WHERE accountid=? AND name=? LIMIT 1`, accountId, library.Name).Scan(&latest); err != nil &&
+			err != gocql.ErrNotFound {
+			return 0, err
+		}
+
+		applied, err := session.Query(`INSERT INTO `+LibrariesTable+
+			`(accountid, name, version, description, code, createdon) VALUES(?, ?, ?, ?, ?, ?) IF NOT EXISTS`,
+			accountId,
+			library.Name,
+			latest+1,
+			library.Description,
+			library.Code,
+			time.Now().In(time.UTC)).MapScanCAS(make(map[string]interface{}))
+		if err != nil {
+			return 0, err
+		}
+
+		if applied {
+			return latest + 1, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Library %s was changed concurrently", library.Name)
+}
+
+func listLibraries(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+
+	session, err := getSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrListLibraries.Incr()
+		return
+	}
+
+	libraries := make([]*model.LibraryData, 0)
+	library := new(model.LibraryData)
+	iter := session.Query(`SELECT name, version, description, createdon FROM `+LibrariesTable+
+		` WHERE accountid=?`, accountId).Iter()
+	for iter.Scan(&library.Name, &library.Version, &library.Description, &library.CreatedOn) {
+		libraries = append(libraries, library)
+		library = new(model.LibraryData)
+	}
+
+	if err := iter.Close(); err != nil {
+		em := fmt.Sprintf("Error listing libraries %v", err)
+		mlog.Error(em)
+		c.JSON(http.StatusInternalServerError, management.GetExternalError(em))
+		ErrListLibraries.Incr()
+		return
+	}
+
+	ListLibraries.Incr()
+	c.JSON(http.StatusOK, libraries)
+}
+
+// getLibrary returns the version of the library, the latest one when the
+// version is not set.
+func getLibrary(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+	name := c.Params.ByName("name")
+	version, err := getVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		ErrGetLibrary.Incr()
+		return
+	}
+
+	session, err := getSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrGetLibrary.Incr()
+		return
+	}
+
+	query := `SELECT version, description, code, createdon FROM ` + LibrariesTable +
+		` WHERE accountid=? AND name=?`
+	values := []interface{}{accountId, name}
+	if version == model.LATEST {
+		query += ` LIMIT 1`
+	} else {
+		query += ` AND version=?`
+		values = append(values, version)
+	}
+
+	library := &model.LibraryData{Name: name}
+	if err := session.Query(query, values...).Scan(&library.Version,
+		&library.Description,
+		&library.Code,
+		&library.CreatedOn); err != nil {
+		if err == gocql.ErrNotFound {
+			c.JSON(http.StatusNotFound, management.GetNotFoundError("Library not found"))
+			return
+		}
+
+		em := fmt.Sprintf("Error retrieving library %v", err)
+		if err == gocql.ErrNoConnections {
+			c.JSON(http.StatusBadGateway, management.GetExternalError(em))
+		} else {
+			c.JSON(http.StatusInternalServerError, management.GetExternalError(em))
+		}
+		ErrGetLibrary.Incr()
+		return
+	}
+
+	GetLibrary.Incr()
+	c.JSON(http.StatusOK, library)
+}
+
+// deleteLibrary deletes the version of the library, all its versions when
+// the version is not set.
+func deleteLibrary(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+	name := c.Params.ByName("name")
+	version, err := getVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		ErrDelLibrary.Incr()
+		return
+	}
+
+	session, err := getSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrDelLibrary.Incr()
+		return
+	}
+
+	query := `DELETE FROM ` + LibrariesTable + ` WHERE accountid=? AND name=?`
+	values := []interface{}{accountId, name}
+	if version != model.LATEST {
+		query += ` AND version=?`
+		values = append(values, version)
+	}
+
+	if err := session.Query(query, values...).Exec(); err != nil {
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrDelLibrary.Incr()
+		return
+	}
+
+	mlog.Info("Library %s deleted from account %s", name, accountId)
+	DelLibrary.Incr()
+	c.String(http.StatusOK, "")
+}
+
+func getVersion(c *gin.Context) (int, error) {
+	param := c.Params.ByName("version")
+	if param == "" {
+		return model.LATEST, nil
+	}
+
+	version, err := strconv.Atoi(param)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("Invalid library version: %q", param)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LATEST is the version selecting the latest version of a library.
+const LATEST = 0
+
+var nameRegexp = regexp.MustCompile(`^[A-Za-z0-9_]+(\.[A-Za-z0-9_]+)*$`)
+
+// LibraryData is a version of a Lua library shared by the snippets of an
+// account. Versions are numbered from 1 in the order they are added and are
+// immutable.
+type LibraryData struct {
+	Name        string    `json:"name,omitempty"`
+	Version     int       `json:"version,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Code        string    `json:"code,omitempty"`
+	CreatedOn   time.Time `json:"createdOn,omitempty"`
+}
+
+// ValidateName checks the library name is made of dot separated
+// identifiers, the way modules are named in require.
+func ValidateName(name string) error {
+	if !nameRegexp.MatchString(name) {
+		return fmt.Errorf("Invalid library name: %q", name)
+	}
+
+	return nil
+}
+
+func (l *LibraryData) Validate() error {
+	if strings.TrimSpace(l.Code) == "" {
+		return fmt.Errorf("Code is empty")
+	}
+
+	return nil
+}
+
+func (l *LibraryData) Print() string {
+	return fmt.Sprintf("Name: %s, "+
+		"Version: %d, "+
+		"Description: %s, "+
+		"CreatedOn: %s", l.Name, l.Version, l.Description, l.CreatedOn)
+}
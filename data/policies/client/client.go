@@ -0,0 +1,92 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	lb "github.com/lavaorg/lrtx/httpclientlb"
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/data/policies/model"
+	"github.com/lavaorg/northstar/data/util"
+)
+
+const BASE_URI = util.DataBasePath + "/policies"
+
+type Client interface {
+	SetPolicy(accountId string, snippetId string, data *model.PolicyData) *management.Error
+	GetPolicy(accountId string, snippetId string) (*model.PolicyData, *management.Error)
+	DeletePolicy(accountId string, snippetId string) *management.Error
+}
+
+type PoliciesClient struct {
+	lbClient *lb.LbClient
+}
+
+func NewPoliciesClient() (*PoliciesClient, error) {
+	url, err := util.GetDataBaseUrl()
+	if err != nil {
+		mlog.Error("Failed to get data base url with error: %s", err.Error())
+		return nil, err
+	}
+
+	lbClient, err := lb.GetClient(url)
+	if err != nil {
+		mlog.Info("Failed to create policies data client with error: %s", err.Error())
+		return nil, err
+	}
+
+	return &PoliciesClient{lbClient: lbClient}, nil
+}
+
+// SetPolicy stores the policy of the snippet. The account policy is set
+// with model.ACCOUNT_POLICY as snippet id.
+func (client *PoliciesClient) SetPolicy(accountId string,
+	snippetId string,
+	data *model.PolicyData) *management.Error {
+	path := fmt.Sprintf("%s/by-accountid/%s/%s", BASE_URI, accountId, snippetId)
+	if _, err := client.lbClient.PutJSON(path, data); err != nil {
+		mlog.Error("Policies dataservice client: Error setting policy %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetPolicy returns the policy of the snippet. A not found error is
+// returned when no policy is set.
+func (client *PoliciesClient) GetPolicy(accountId string,
+	snippetId string) (*model.PolicyData, *management.Error) {
+	path := fmt.Sprintf("%s/by-accountid/%s/%s", BASE_URI, accountId, snippetId)
+	resp, mErr := client.lbClient.Get(path)
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	var policy *model.PolicyData
+	if err := json.Unmarshal(resp, &policy); err != nil {
+		return nil, management.GetInternalError(err.Error())
+	}
+
+	return policy, nil
+}
+
+func (client *PoliciesClient) DeletePolicy(accountId string, snippetId string) *management.Error {
+	path := fmt.Sprintf("%s/by-accountid/%s/%s", BASE_URI, accountId, snippetId)
+	return client.lbClient.Delete(path)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policies
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+	"github.com/lavaorg/lrtx/database"
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/data/policies/model"
+	"github.com/lavaorg/northstar/data/util"
+)
+
+var (
+	sess *gocql.Session
+	lock sync.Mutex
+)
+
+// Helper method used to get/create database session.
+func getSession() (*gocql.Session, error) {
+	var err error
+
+	if sess == nil || sess.Closed() {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if sess == nil || sess.Closed() {
+			sess, err = util.NewDB(Keyspace).GetSessionWithError()
+		}
+	}
+
+	return sess, err
+}
+
+// PoliciesService stores the module and host permissions of the snippets.
+// The policy of an account is stored with model.ACCOUNT_POLICY as snippet
+// id, snippet policies override it.
+type PoliciesService struct{}
+
+func (s *PoliciesService) AddRoutes() {
+	grp := management.Engine().Group(util.DataBasePath)
+	g := grp.Group("policies")
+	g.PUT("/by-accountid/:accountId/:snippetId", setPolicy)
+	g.GET("/by-accountid/:accountId/:snippetId", getPolicy)
+	g.DELETE("/by-accountid/:accountId/:snippetId", deletePolicy)
+}
+
+func setPolicy(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+	snippetId := c.Params.ByName("snippetId")
+
+	var policy = new(model.PolicyData)
+	if err := c.Bind(policy); err != nil {
+		mlog.Error("Failed to decode request body: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		ErrSetPolicy.Incr()
+		return
+	}
+
+	if err := policy.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		ErrSetPolicy.Incr()
+		return
+	}
+
+	session, err := getSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrSetPolicy.Incr()
+		return
+	}
+
+	if _, err := database.Insert(Keyspace, PoliciesTable).
+		Param("accountid", accountId).
+		Param("snippetid", snippetId).
+		Param("modules", policy.Modules).
+		Param("hosts", policy.Hosts).
+		Param("updatedon", time.Now().In(time.UTC)).
+		Exec(session); err != nil {
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrSetPolicy.Incr()
+		return
+	}
+
+	mlog.Info("Policy of snippet %s in account %s set", snippetId, accountId)
+	SetPolicy.Incr()
+	c.String(http.StatusOK, "")
+}
+
+func getPolicy(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+	snippetId := c.Params.ByName("snippetId")
+
+	session, err := getSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrGetPolicy.Incr()
+		return
+	}
+
+	policy := &model.PolicyData{SnippetId: snippetId}
+	if err := database.Select(Keyspace, PoliciesTable).
+		Value("modules", &policy.Modules).
+		Value("hosts", &policy.Hosts).
+		Value("updatedon", &policy.UpdatedOn).
+		Where("accountid", accountId).
+		Where("snippetid", snippetId).
+		Scan(session); err != nil {
+		if err == gocql.ErrNotFound {
+			c.JSON(http.StatusNotFound, management.GetNotFoundError("Policy not found"))
+			return
+		}
+
+		em := fmt.Sprintf("Error retrieving policy %v", err)
+		if err == gocql.ErrNoConnections {
+			c.JSON(http.StatusBadGateway, management.GetExternalError(em))
+		} else {
+			c.JSON(http.StatusInternalServerError, management.GetExternalError(em))
+		}
+		ErrGetPolicy.Incr()
+		return
+	}
+
+	GetPolicy.Incr()
+	c.JSON(http.StatusOK, policy)
+}
+
+func deletePolicy(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+	snippetId := c.Params.ByName("snippetId")
+
+	session, err := getSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrDelPolicy.Incr()
+		return
+	}
+
+	if _, err := database.Delete(Keyspace, PoliciesTable).
+		Where("accountid", accountId).
+		Where("snippetid", snippetId).
+		Exec(session); err != nil {
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrDelPolicy.Incr()
+		return
+	}
+
+	mlog.Info("Policy of snippet %s deleted from account %s", snippetId, accountId)
+	DelPolicy.Incr()
+	c.String(http.StatusOK, "")
+}
@@ -0,0 +1,59 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ACCOUNT_POLICY is the snippet id under which the policy applying to all
+// snippets of an account is stored.
+const ACCOUNT_POLICY = "*"
+
+// PolicyData lists the modules the snippets of an account, or a single
+// snippet, may require and the hosts the network modules may connect to.
+type PolicyData struct {
+	SnippetId string    `json:"snippetId,omitempty"`
+	Modules   []string  `json:"modules"`
+	Hosts     []string  `json:"hosts,omitempty"`
+	UpdatedOn time.Time `json:"updatedOn,omitempty"`
+}
+
+func (p *PolicyData) Validate() error {
+	for _, module := range p.Modules {
+		if strings.TrimSpace(module) == "" {
+			return fmt.Errorf("Module name is empty")
+		}
+	}
+
+	for _, host := range p.Hosts {
+		if strings.TrimSpace(host) == "" {
+			return fmt.Errorf("Host is empty")
+		}
+	}
+
+	return nil
+}
+
+func (p *PolicyData) Print() string {
+	return fmt.Sprintf("SnippetId: %s, "+
+		"Modules: %v, "+
+		"Hosts: %v, "+
+		"UpdatedOn: %s", p.SnippetId, p.Modules, p.Hosts, p.UpdatedOn)
+}
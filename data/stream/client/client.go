@@ -39,6 +39,9 @@ type Client interface {
 	GetJobs(accountId string) ([]*model.JobData, *management.Error)
 	UpdateJob(accountId string, jobId string, update *model.JobData) *management.Error
 	DeleteJob(accountId string, jobId string) *management.Error
+	UpdateWorker(accountId string, jobId string, status *model.WorkerStatus) *management.Error
+	AddScalingEvent(accountId string, jobId string, event *model.ScalingEvent) *management.Error
+	GetAllJobs() ([]*model.JobData, *management.Error)
 }
 
 func NewStreamClient() (*StreamClient, error) {
@@ -121,3 +124,44 @@ func (client *StreamClient) DeleteJob(accountId string, jobId string) *managemen
 
 	return nil
 }
+
+func (client *StreamClient) UpdateWorker(accountId string,
+	jobId string,
+	status *model.WorkerStatus) *management.Error {
+	path := fmt.Sprintf("%s/%s/%s/workers/%s", BASE_URI, accountId, jobId, status.WorkerId)
+	_, err := client.lbClient.PutJSON(path, status)
+	if err != nil {
+		mlog.Error("DPE stream data client: Error updating worker: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (client *StreamClient) AddScalingEvent(accountId string,
+	jobId string,
+	event *model.ScalingEvent) *management.Error {
+	path := fmt.Sprintf("%s/%s/%s/scaling", BASE_URI, accountId, jobId)
+	_, err := client.lbClient.PostJSON(path, event)
+	if err != nil {
+		mlog.Error("DPE stream data client: Error adding scaling event: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (client *StreamClient) GetAllJobs() ([]*model.JobData, *management.Error) {
+	resp, err := client.lbClient.Get(BASE_URI)
+	if err != nil {
+		mlog.Error("DPE stream data client: Error listing all jobs: %s", err.Error())
+		return nil, err
+	}
+
+	var out []*model.JobData
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return nil, management.NewError(http.StatusInternalServerError, "server_error", err.Error())
+	}
+
+	return out, nil
+}
@@ -19,4 +19,17 @@ package stream
 const (
 	Keyspace  = "stream"
 	JobsTable = "jobs"
+
+	WorkersTable    = "workers"
+	ThroughputTable = "throughput"
+	ScalingTable    = "scaling"
+
+	// Number of throughput samples returned with a job.
+	ThroughputHistoryLimit = 360
+
+	// Number of scaling events returned with a job.
+	ScalingHistoryLimit = 50
+
+	// Number of heartbeat intervals after which a silent worker is dropped.
+	WorkerExpiryHeartbeats = 10
 )
@@ -32,9 +32,12 @@ import (
 )
 
 var (
-	jobColumns = "id, accountid, invocationId, memory, source, functions, createdon, updatedon, status, errordescr, description"
-	sess       *gocql.Session
-	lock       sync.Mutex
+	jobColumns        = "id, accountid, invocationId, memory, source, functions, createdon, updatedon, status, errordescr, description, instances, autoscale, output, revision"
+	workerColumns     = "workerid, heartbeat, interval, processed, failed, throughput, lag"
+	throughputColumns = "time, workerid, processed, failed, rate"
+	scalingColumns    = "time, fromworkers, toworkers, reason"
+	sess              *gocql.Session
+	lock              sync.Mutex
 )
 
 // Helper method used to get/create database session.
@@ -58,11 +61,14 @@ type StreamService struct{}
 func (s *StreamService) AddRoutes() {
 	grp := management.Engine().Group(util.DataBasePath)
 	g := grp.Group("stream")
+	g.GET("", getAllJobs)
 	g.POST(":accountId", addJob)
 	g.GET(":accountId", getJobs)
 	g.GET(":accountId/:jobId", getJob)
 	g.PUT(":accountId/:jobId", updateJob)
 	g.DELETE(":accountId/:jobId", deleteJob)
+	g.PUT(":accountId/:jobId/workers/:workerId", updateWorker)
+	g.POST(":accountId/:jobId/scaling", addScalingEvent)
 }
 
 func addJob(c *gin.Context) {
@@ -111,6 +117,22 @@ func addJob(c *gin.Context) {
 		return
 	}
 
+	autoscaleBytes, err := json.Marshal(job.Autoscale)
+	if err != nil {
+		mlog.Error("Marshal err: %v", err)
+		ErrAddJob.Incr()
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		return
+	}
+
+	outputBytes, err := json.Marshal(job.Output)
+	if err != nil {
+		mlog.Error("Marshal err: %v", err)
+		ErrAddJob.Incr()
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		return
+	}
+
 	if _, err := database.Insert(Keyspace, JobsTable).
 		Param("accountId", accountId).
 		Param("id", job.Id).
@@ -121,6 +143,9 @@ func addJob(c *gin.Context) {
 		Param("createdon", time.Now().In(time.UTC)).
 		Param("status", job.Status).
 		Param("description", job.Description).
+		Param("instances", job.Instances).
+		Param("autoscale", autoscaleBytes).
+		Param("output", outputBytes).
 		Exec(session); err != nil {
 		ErrAddJob.Incr()
 		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
@@ -146,19 +171,42 @@ func getJobs(c *gin.Context) {
 func getJobsQuery(accountId string) ([]model.JobData, error) {
 	mlog.Info("Retrieving Jobs for account %s", accountId)
 
-	results := make([]model.JobData, 0, 10)
-	entry := new(model.JobData)
-
 	session, err := getSession()
 	if err != nil {
 		return nil, err
 	}
 
+	return scanJobs(session.Query(`SELECT `+jobColumns+` FROM `+JobsTable+` WHERE accountid=?`, accountId).Iter())
+}
+
+func getAllJobs(c *gin.Context) {
+	session, err := getSession()
+	if err != nil {
+		ErrGetJobs.Incr()
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		return
+	}
+
+	results, err := scanJobs(session.Query(`SELECT ` + jobColumns + ` FROM ` + JobsTable).Iter())
+	if err != nil {
+		ErrGetJobs.Incr()
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		return
+	}
+
+	GetJobs.Incr()
+	c.JSON(http.StatusOK, results)
+}
+
+func scanJobs(iter *gocql.Iter) ([]model.JobData, error) {
+	results := make([]model.JobData, 0, 10)
+	entry := new(model.JobData)
+
 	var source []byte
 	var functions []byte
+	var autoscale []byte
+	var output []byte
 
-	iter :=
-		session.Query(`SELECT `+jobColumns+` FROM `+JobsTable+` WHERE accountid=?`, accountId).Iter()
 	for iter.Scan(&entry.Id,
 		&entry.AccountId,
 		&entry.InvocationId,
@@ -169,9 +217,15 @@ func getJobsQuery(accountId string) ([]model.JobData, error) {
 		&entry.UpdatedOn,
 		&entry.Status,
 		&entry.ErrorDescr,
-		&entry.Description) {
+		&entry.Description,
+		&entry.Instances,
+		&autoscale,
+		&output,
+		&entry.Revision) {
 		entry.ByteArrToSource(source)
 		entry.ByteArrToFunctions(functions)
+		entry.ByteArrToAutoscale(autoscale)
+		entry.ByteArrToOutput(output)
 		results = append(results, *entry)
 		entry = new(model.JobData)
 	}
@@ -220,6 +274,8 @@ func getJobQuery(accountId string, jobId string) (*model.JobData, error) {
 
 	var source []byte
 	var functions []byte
+	var autoscale []byte
+	var output []byte
 
 	if err := database.Select(Keyspace, JobsTable).
 		Value("id", &job.Id).
@@ -230,7 +286,13 @@ func getJobQuery(accountId string, jobId string) (*model.JobData, error) {
 		Value("functions", &functions).
 		Value("createdon", &job.CreatedOn).
 		Value("updatedon", &job.UpdatedOn).
+		Value("status", &job.Status).
+		Value("errordescr", &job.ErrorDescr).
 		Value("description", &job.Description).
+		Value("instances", &job.Instances).
+		Value("autoscale", &autoscale).
+		Value("output", &output).
+		Value("revision", &job.Revision).
 		Where("accountid", accountId).
 		Where("id", jobId).
 		Scan(session); err != nil {
@@ -239,9 +301,94 @@ func getJobQuery(accountId string, jobId string) (*model.JobData, error) {
 
 	job.ByteArrToSource(source)
 	job.ByteArrToFunctions(functions)
+	job.ByteArrToAutoscale(autoscale)
+	job.ByteArrToOutput(output)
+
+	if job.Workers, err = getWorkersQuery(session, accountId, jobId); err != nil {
+		return nil, err
+	}
+
+	if job.Throughput, err = getThroughputQuery(session, accountId, jobId); err != nil {
+		return nil, err
+	}
+
+	if job.ScalingEvents, err = getScalingEventsQuery(session, accountId, jobId); err != nil {
+		return nil, err
+	}
+
 	return &job, nil
 }
 
+func getWorkersQuery(session *gocql.Session, accountId string, jobId string) ([]model.WorkerStatus, error) {
+	results := make([]model.WorkerStatus, 0)
+	entry := new(model.WorkerStatus)
+
+	iter := session.Query(`SELECT `+workerColumns+` FROM `+WorkersTable+
+		` WHERE accountid=? AND jobid=?`, accountId, jobId).Iter()
+	for iter.Scan(&entry.WorkerId,
+		&entry.Heartbeat,
+		&entry.Interval,
+		&entry.Processed,
+		&entry.Failed,
+		&entry.Throughput,
+		&entry.Lag) {
+		results = append(results, *entry)
+		entry = new(model.WorkerStatus)
+	}
+
+	if err := iter.Close(); err != nil {
+		mlog.Error("Error: ", err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func getScalingEventsQuery(session *gocql.Session, accountId string, jobId string) ([]model.ScalingEvent, error) {
+	results := make([]model.ScalingEvent, 0)
+	entry := new(model.ScalingEvent)
+
+	iter := session.Query(`SELECT `+scalingColumns+` FROM `+ScalingTable+
+		` WHERE accountid=? AND jobid=? LIMIT ?`, accountId, jobId, ScalingHistoryLimit).Iter()
+	for iter.Scan(&entry.Time,
+		&entry.FromWorkers,
+		&entry.ToWorkers,
+		&entry.Reason) {
+		results = append(results, *entry)
+		entry = new(model.ScalingEvent)
+	}
+
+	if err := iter.Close(); err != nil {
+		mlog.Error("Error: ", err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func getThroughputQuery(session *gocql.Session, accountId string, jobId string) ([]model.ThroughputSample, error) {
+	results := make([]model.ThroughputSample, 0)
+	entry := new(model.ThroughputSample)
+
+	iter := session.Query(`SELECT `+throughputColumns+` FROM `+ThroughputTable+
+		` WHERE accountid=? AND jobid=? LIMIT ?`, accountId, jobId, ThroughputHistoryLimit).Iter()
+	for iter.Scan(&entry.Time,
+		&entry.WorkerId,
+		&entry.Processed,
+		&entry.Failed,
+		&entry.Rate) {
+		results = append(results, *entry)
+		entry = new(model.ThroughputSample)
+	}
+
+	if err := iter.Close(); err != nil {
+		mlog.Error("Error: ", err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
 func updateJob(c *gin.Context) {
 	accountId := c.Params.ByName("accountId")
 	jobId := c.Params.ByName("jobId")
@@ -271,15 +418,39 @@ func updateJobQuery(accountId string, jobId string, update *model.JobData) error
 		Where("id", jobId)
 
 	if update.Memory > 0 {
-		queryBuilder = queryBuilder.Param("mem", update.Memory)
+		queryBuilder = queryBuilder.Param("memory", update.Memory)
 	}
 
 	if update.Source.Name != "" {
-		queryBuilder = queryBuilder.Param("source", update.Source)
+		sourceBytes, err := json.Marshal(update.Source)
+		if err != nil {
+			return err
+		}
+		queryBuilder = queryBuilder.Param("source", sourceBytes)
 	}
 
 	if len(update.Functions) > 0 {
-		queryBuilder = queryBuilder.Param("functions", update.Functions)
+		functionsBytes, err := json.Marshal(update.Functions)
+		if err != nil {
+			return err
+		}
+		queryBuilder = queryBuilder.Param("functions", functionsBytes)
+	}
+
+	if update.Output != nil {
+		outputBytes, err := json.Marshal(update.Output)
+		if err != nil {
+			return err
+		}
+		queryBuilder = queryBuilder.Param("output", outputBytes)
+	}
+
+	if update.Revision > 0 {
+		queryBuilder = queryBuilder.Param("revision", update.Revision)
+	}
+
+	if update.Instances > 0 {
+		queryBuilder = queryBuilder.Param("instances", update.Instances)
 	}
 
 	if update.Status != "" {
@@ -341,6 +512,125 @@ func deleteJob(c *gin.Context) {
 		return
 	}
 
+	// Worker heartbeats expire on their own, remove them now so a job
+	// recreated with the same id starts clean.
+	for _, table := range []string{WorkersTable, ThroughputTable, ScalingTable} {
+		if _, err := database.Delete(Keyspace, table).
+			Where("accountid", accountId).
+			Where("jobid", jobId).
+			Exec(session); err != nil {
+			mlog.Error("Failed to delete %s of stream job %s: %v", table, jobId, err)
+		}
+	}
+
 	DelJob.Incr()
 	c.String(http.StatusOK, "")
 }
+
+func updateWorker(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+	jobId := c.Params.ByName("jobId")
+	workerId := c.Params.ByName("workerId")
+
+	var status = new(model.WorkerStatus)
+	if err := c.Bind(status); err != nil {
+		mlog.Error("Failed to decode request body: %v", err)
+		ErrUpdateWorker.Incr()
+		return
+	}
+	status.WorkerId = workerId
+
+	if err := status.Validate(); err != nil {
+		ErrUpdateWorker.Incr()
+		c.JSON(http.StatusBadRequest, management.GetInternalError(err.Error()))
+		return
+	}
+
+	if err := updateWorkerQuery(accountId, jobId, status); err != nil {
+		ErrUpdateWorker.Incr()
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		return
+	}
+
+	UpdateWorker.Incr()
+	c.String(http.StatusOK, "")
+}
+
+func updateWorkerQuery(accountId string, jobId string, status *model.WorkerStatus) error {
+	session, err := getSession()
+	if err != nil {
+		return err
+	}
+
+	if status.Heartbeat.IsZero() {
+		status.Heartbeat = time.Now().In(time.UTC)
+	}
+
+	// Workers replaced after a restart report under a new id, so rows of
+	// silent workers expire instead of lingering as dead workers.
+	ttl := WorkerExpiryHeartbeats * status.Interval
+	if err := session.Query(`INSERT INTO `+WorkersTable+`(accountid, jobid, `+workerColumns+
+		`) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?`,
+		accountId,
+		jobId,
+		status.WorkerId,
+		status.Heartbeat,
+		status.Interval,
+		status.Processed,
+		status.Failed,
+		status.Throughput,
+		status.Lag,
+		ttl).Exec(); err != nil {
+		return err
+	}
+
+	_, err = database.Insert(Keyspace, ThroughputTable).
+		Param("accountid", accountId).
+		Param("jobid", jobId).
+		Param("time", status.Heartbeat).
+		Param("workerid", status.WorkerId).
+		Param("processed", status.Processed).
+		Param("failed", status.Failed).
+		Param("rate", status.Throughput).
+		Exec(session)
+	return err
+}
+
+func addScalingEvent(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+	jobId := c.Params.ByName("jobId")
+
+	var event = new(model.ScalingEvent)
+	if err := c.Bind(event); err != nil {
+		mlog.Error("Failed to decode request body: %v", err)
+		ErrAddScalingEvent.Incr()
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now().In(time.UTC)
+	}
+
+	session, err := getSession()
+	if err != nil {
+		ErrAddScalingEvent.Incr()
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		return
+	}
+
+	if _, err := database.Insert(Keyspace, ScalingTable).
+		Param("accountid", accountId).
+		Param("jobid", jobId).
+		Param("time", event.Time).
+		Param("fromworkers", event.FromWorkers).
+		Param("toworkers", event.ToWorkers).
+		Param("reason", event.Reason).
+		Exec(session); err != nil {
+		ErrAddScalingEvent.Incr()
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		return
+	}
+
+	AddScalingEvent.Incr()
+	c.String(http.StatusCreated, "")
+}
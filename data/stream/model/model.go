@@ -25,6 +25,16 @@ import (
 type Source struct {
 	Name       string      `json:"name,omitempty"`
 	Connection interface{} `json:"connection,omitempty"`
+	Format     *Format     `json:"format,omitempty"`
+}
+
+type Format struct {
+	Type      string   `json:"type,omitempty"`
+	Schema    string   `json:"schema,omitempty"`
+	SchemaId  int      `json:"schemaId,omitempty"`
+	Message   string   `json:"message,omitempty"`
+	Columns   []string `json:"columns,omitempty"`
+	Delimiter string   `json:"delimiter,omitempty"`
 }
 
 type Function struct {
@@ -33,6 +43,72 @@ type Function struct {
 	Evaluator  interface{} `json:"evaluator,omitempty"`
 }
 
+// Autoscale bounds the number of workers of a job and sets the per worker
+// consumer lag above which workers are added and below which they are removed.
+type Autoscale struct {
+	MinWorkers   int   `json:"minWorkers,omitempty"`
+	MaxWorkers   int   `json:"maxWorkers,omitempty"`
+	ScaleUpLag   int64 `json:"scaleUpLag,omitempty"`
+	ScaleDownLag int64 `json:"scaleDownLag,omitempty"`
+}
+
+type ScalingEvent struct {
+	Time        time.Time `json:"time,omitempty"`
+	FromWorkers int       `json:"fromWorkers"`
+	ToWorkers   int       `json:"toWorkers"`
+	Reason      string    `json:"reason,omitempty"`
+}
+
+// WorkerStatus is the heartbeat a stream worker reports every Interval seconds.
+type WorkerStatus struct {
+	WorkerId   string          `json:"workerId,omitempty"`
+	Heartbeat  time.Time       `json:"heartbeat,omitempty"`
+	Interval   int             `json:"interval,omitempty"`
+	Processed  uint64          `json:"processed"`
+	Failed     uint64          `json:"failed"`
+	Throughput float64         `json:"throughput"`
+	Lag        map[int32]int64 `json:"lag,omitempty"`
+}
+
+func (w *WorkerStatus) Validate() error {
+	if w.WorkerId == "" {
+		return fmt.Errorf("Worker id is empty")
+	}
+
+	if w.Interval < 1 {
+		return fmt.Errorf("Heartbeat interval less than one")
+	}
+
+	return nil
+}
+
+// MergeLag returns the lag of every partition of a job from the lag the
+// workers report. Every worker reports all the partitions, those it does not
+// consume from their oldest message, so the smallest lag of a partition is
+// the one of the worker consuming it.
+func MergeLag(workers []WorkerStatus) map[int32]int64 {
+	lag := map[int32]int64{}
+	for _, worker := range workers {
+		for partition, partitionLag := range worker.Lag {
+			if merged, ok := lag[partition]; !ok || partitionLag < merged {
+				lag[partition] = partitionLag
+			}
+		}
+	}
+
+	return lag
+}
+
+// ThroughputSample is a point of the job throughput history, recorded with
+// every worker heartbeat.
+type ThroughputSample struct {
+	Time      time.Time `json:"time,omitempty"`
+	WorkerId  string    `json:"workerId,omitempty"`
+	Processed uint64    `json:"processed"`
+	Failed    uint64    `json:"failed"`
+	Rate      float64   `json:"rate"`
+}
+
 type JobData struct {
 	Id           string     `json:"id,omitempty"`
 	AccountId    string     `json:"accountId,omitempty"`
@@ -45,6 +121,14 @@ type JobData struct {
 	Status       string     `json:"status,omitempty"`
 	ErrorDescr   string     `json:"errorDescr,omitempty"`
 	Description  string     `json:"description:omitempty"`
+	Instances    int        `json:"instances,omitempty"`
+	Autoscale    *Autoscale `json:"autoscale,omitempty"`
+	Output       *Format    `json:"output,omitempty"`
+	Revision     int        `json:"revision,omitempty"`
+
+	Workers       []WorkerStatus     `json:"workers,omitempty"`
+	Throughput    []ThroughputSample `json:"throughput,omitempty"`
+	ScalingEvents []ScalingEvent     `json:"scalingEvents,omitempty"`
 }
 
 func (j *JobData) ByteArrToSource(data []byte) error {
@@ -67,6 +151,34 @@ func (j *JobData) ByteArrToFunctions(data []byte) error {
 	return nil
 }
 
+func (j *JobData) ByteArrToAutoscale(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var autoscale *Autoscale
+	if err := json.Unmarshal(data, &autoscale); err != nil {
+		return err
+	}
+
+	j.Autoscale = autoscale
+	return nil
+}
+
+func (j *JobData) ByteArrToOutput(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var output *Format
+	if err := json.Unmarshal(data, &output); err != nil {
+		return err
+	}
+
+	j.Output = output
+	return nil
+}
+
 func (j *JobData) Validate() error {
 	if j.InvocationId == "" {
 		return fmt.Errorf("Invocation id is empty")
@@ -26,9 +26,15 @@ var (
 	UpdateJob = s.NewCounter("UpdateJob")
 	DelJob    = s.NewCounter("DelJob")
 
+	UpdateWorker    = s.NewCounter("UpdateWorker")
+	AddScalingEvent = s.NewCounter("AddScalingEvent")
+
 	ErrAddJob    = s.NewCounter("ErrAddJob")
 	ErrGetJob    = s.NewCounter("ErrGetJob")
 	ErrGetJobs   = s.NewCounter("ErrGetJobs")
 	ErrUpdateJob = s.NewCounter("ErrUpdateJob")
 	ErrDelJob    = s.NewCounter("ErrDelJob")
+
+	ErrUpdateWorker    = s.NewCounter("ErrUpdateWorker")
+	ErrAddScalingEvent = s.NewCounter("ErrAddScalingEvent")
 )
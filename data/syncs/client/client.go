@@ -0,0 +1,90 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	lb "github.com/lavaorg/lrtx/httpclientlb"
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/data/syncs/model"
+	"github.com/lavaorg/northstar/data/util"
+)
+
+const BASE_URI = util.DataBasePath + "/syncs"
+
+type Client interface {
+	GetSynced(accountId string, syncId string) ([]*model.SyncedFile, *management.Error)
+	AddSynced(accountId string, syncId string, files []*model.SyncedFile) *management.Error
+	ResetSynced(accountId string, syncId string) *management.Error
+}
+
+type SyncsClient struct {
+	lbClient *lb.LbClient
+}
+
+func NewSyncsClient() (*SyncsClient, error) {
+	url, err := util.GetDataBaseUrl()
+	if err != nil {
+		mlog.Error("Failed to get data base url with error: %s", err.Error())
+		return nil, err
+	}
+
+	lbClient, err := lb.GetClient(url)
+	if err != nil {
+		mlog.Info("Failed to create syncs data client with error: %s", err.Error())
+		return nil, err
+	}
+
+	return &SyncsClient{lbClient: lbClient}, nil
+}
+
+// GetSynced returns the files copied by the sync.
+func (client *SyncsClient) GetSynced(accountId string, syncId string) ([]*model.SyncedFile, *management.Error) {
+	resp, mErr := client.lbClient.Get(client.path(accountId, syncId))
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	var files []*model.SyncedFile
+	if err := json.Unmarshal(resp, &files); err != nil {
+		return nil, management.GetInternalError(err.Error())
+	}
+
+	return files, nil
+}
+
+// AddSynced records the files copied by the sync.
+func (client *SyncsClient) AddSynced(accountId string, syncId string, files []*model.SyncedFile) *management.Error {
+	if _, mErr := client.lbClient.PostJSON(client.path(accountId, syncId), files); mErr != nil {
+		mlog.Error("Syncs dataservice client: Error adding synced files %s", mErr.Error())
+		return mErr
+	}
+
+	return nil
+}
+
+// ResetSynced forgets the files copied by the sync.
+func (client *SyncsClient) ResetSynced(accountId string, syncId string) *management.Error {
+	return client.lbClient.Delete(client.path(accountId, syncId))
+}
+
+func (client *SyncsClient) path(accountId string, syncId string) string {
+	return fmt.Sprintf("%s/%s/%s", BASE_URI, accountId, syncId)
+}
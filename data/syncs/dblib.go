@@ -0,0 +1,185 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncs
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/data/syncs/model"
+	"github.com/lavaorg/northstar/data/util"
+)
+
+var (
+	sess *gocql.Session
+	lock sync.Mutex
+)
+
+// Helper method used to get/create database session.
+func getSession() (*gocql.Session, error) {
+	var err error
+
+	if sess == nil || sess.Closed() {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if sess == nil || sess.Closed() {
+			sess, err = util.NewDB(Keyspace).GetSessionWithError()
+		}
+	}
+
+	return sess, err
+}
+
+// SyncsService stores the remote files copied into the object store by the
+// syncs of an account, so a sync only copies the files new or changed since.
+type SyncsService struct{}
+
+func (s *SyncsService) AddRoutes() {
+	grp := management.Engine().Group(util.DataBasePath)
+	g := grp.Group("syncs")
+	g.GET("/:accountId/:syncId", getSynced)
+	g.POST("/:accountId/:syncId", addSynced)
+	g.DELETE("/:accountId/:syncId", resetSynced)
+}
+
+func getSynced(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+	syncId := c.Params.ByName("syncId")
+
+	session, err := getSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrGetSynced.Incr()
+		return
+	}
+
+	files := make([]*model.SyncedFile, 0)
+	file := new(model.SyncedFile)
+	iter := session.Query(`SELECT path, size, modtime, checksum, bucket, object, syncedon FROM `+SyncsTable+
+		` WHERE accountid=? AND syncid=?`, accountId, syncId).Iter()
+	for iter.Scan(&file.Path,
+		&file.Size,
+		&file.ModTime,
+		&file.Checksum,
+		&file.Bucket,
+		&file.Object,
+		&file.SyncedOn) {
+		files = append(files, file)
+		file = new(model.SyncedFile)
+	}
+
+	if err := iter.Close(); err != nil {
+		em := fmt.Sprintf("Error retrieving synced files %v", err)
+		mlog.Error(em)
+		if err == gocql.ErrNoConnections {
+			c.JSON(http.StatusBadGateway, management.GetExternalError(em))
+		} else {
+			c.JSON(http.StatusInternalServerError, management.GetExternalError(em))
+		}
+		ErrGetSynced.Incr()
+		return
+	}
+
+	GetSynced.Incr()
+	c.JSON(http.StatusOK, files)
+}
+
+func addSynced(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+	syncId := c.Params.ByName("syncId")
+
+	var files []*model.SyncedFile
+	if err := c.Bind(&files); err != nil {
+		mlog.Error("Failed to decode request body: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		ErrAddSynced.Incr()
+		return
+	}
+
+	for _, file := range files {
+		if err := file.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+			ErrAddSynced.Incr()
+			return
+		}
+	}
+
+	session, err := getSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrAddSynced.Incr()
+		return
+	}
+
+	batch := session.NewBatch(gocql.UnloggedBatch)
+	now := time.Now().In(time.UTC)
+	for _, file := range files {
+		batch.Query(`INSERT INTO `+SyncsTable+
+			`(accountid, syncid, path, size, modtime, checksum, bucket, object, syncedon)`+
+			` VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			accountId,
+			syncId,
+			file.Path,
+			file.Size,
+			file.ModTime,
+			file.Checksum,
+			file.Bucket,
+			file.Object,
+			now)
+	}
+
+	if err := session.ExecuteBatch(batch); err != nil {
+		mlog.Error("Error adding synced files of sync %s: %v", syncId, err)
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrAddSynced.Incr()
+		return
+	}
+
+	AddSynced.Incr()
+	c.String(http.StatusOK, "")
+}
+
+// resetSynced forgets the files synced, so the next sync copies all files.
+func resetSynced(c *gin.Context) {
+	accountId := c.Params.ByName("accountId")
+	syncId := c.Params.ByName("syncId")
+
+	session, err := getSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrResetSynced.Incr()
+		return
+	}
+
+	if err := session.Query(`DELETE FROM `+SyncsTable+` WHERE accountid=? AND syncid=?`,
+		accountId, syncId).Exec(); err != nil {
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
+		ErrResetSynced.Incr()
+		return
+	}
+
+	mlog.Info("Sync %s of account %s reset", syncId, accountId)
+	ResetSynced.Incr()
+	c.String(http.StatusOK, "")
+}
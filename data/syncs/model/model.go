@@ -0,0 +1,57 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// SyncedFile is a remote file copied into the object store by a sync,
+// identified by its path. The size, modification time and checksum tell
+// whether the file changed since.
+type SyncedFile struct {
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"modTime"`
+	Checksum string    `json:"checksum,omitempty"`
+	Bucket   string    `json:"bucket,omitempty"`
+	Object   string    `json:"object,omitempty"`
+	SyncedOn time.Time `json:"syncedOn,omitempty"`
+}
+
+func (f *SyncedFile) Validate() error {
+	if f.Path == "" {
+		return fmt.Errorf("Path is empty")
+	}
+
+	return nil
+}
+
+// Changed reports whether the file differs from the synced one. Files are
+// compared by checksum when both have one, modification times to the second.
+func (f *SyncedFile) Changed(synced *SyncedFile) bool {
+	if synced == nil {
+		return true
+	}
+
+	if f.Checksum != "" && synced.Checksum != "" {
+		return f.Checksum != synced.Checksum
+	}
+
+	return f.Size != synced.Size || f.ModTime.Unix() != synced.ModTime.Unix()
+}
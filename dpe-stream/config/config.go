@@ -20,6 +20,7 @@ import (
 	"github.com/lavaorg/lrtx/config"
 	"github.com/lavaorg/lrtx/mlog"
 	"io/ioutil"
+	"os"
 )
 
 var (
@@ -28,6 +29,30 @@ var (
 	NumThreads, _          = config.GetInt("DPE_STREAM_WORKER_NUM_THREADS", 200)
 	WorkerQueueCapacity, _ = config.GetInt("DPE_STREAM_WORKER_BUFFER_CAPACITY", 5000)
 	WorkerMarathonJson, _  = config.GetString("DPE_STREAM_WORKER_MARATHON_JSON", readLocalMarathonFile())
+	HeartbeatInterval, _   = config.GetInt("DPE_STREAM_WORKER_HEARTBEAT_INTERVAL", 30)
+	ControlInterval, _     = config.GetInt("DPE_STREAM_WORKER_CONTROL_INTERVAL", 10)
+	SchemaRegistryDir, _   = config.GetString("DPE_STREAM_SCHEMA_REGISTRY_DIR", "./schemas")
+
+	// Autoscaling settings, lags are per worker and apply to jobs that do not set their own.
+	AutoscaleInterval, _     = config.GetInt("DPE_STREAM_AUTOSCALE_INTERVAL", 60)
+	AutoscaleCooldown, _     = config.GetInt("DPE_STREAM_AUTOSCALE_COOLDOWN", 300)
+	AutoscaleScaleUpLag, _   = config.GetInt("DPE_STREAM_AUTOSCALE_UP_LAG", 10000)
+	AutoscaleScaleDownLag, _ = config.GetInt("DPE_STREAM_AUTOSCALE_DOWN_LAG", 100)
+
+	// Cluster backend used by the master to run stream workers. One of
+	// marathon, local, process or kubernetes.
+	Cluster, _ = config.GetString("DPE_STREAM_CLUSTER", "marathon")
+
+	// Process cluster settings.
+	WorkerCommand, _ = config.GetString("DPE_STREAM_WORKER_COMMAND", os.Args[0])
+
+	// Kubernetes cluster settings.
+	KubernetesApiUrl, _      = config.GetString("DPE_STREAM_K8S_API_URL", "https://kubernetes.default.svc")
+	KubernetesNamespace, _   = config.GetString("DPE_STREAM_K8S_NAMESPACE", "default")
+	KubernetesTokenFile, _   = config.GetString("DPE_STREAM_K8S_TOKEN_FILE", "/var/run/secrets/kubernetes.io/serviceaccount/token")
+	KubernetesCAFile, _      = config.GetString("DPE_STREAM_K8S_CA_FILE", "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt")
+	KubernetesWorkerImage, _ = config.GetString("DPE_STREAM_K8S_WORKER_IMAGE", "")
+	KubernetesWorkerEnv, _   = config.GetString("DPE_STREAM_K8S_WORKER_ENV", "")
 )
 
 func readLocalMarathonFile() string {
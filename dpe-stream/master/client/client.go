@@ -65,3 +65,33 @@ func (client *StreamClient) DeleteJob(accountId string, jobId string) *managemen
 	}
 	return nil
 }
+
+func (client *StreamClient) UpdateJob(accountId string,
+	jobId string,
+	update *model.JobUpdate) *management.Error {
+	path := fmt.Sprintf("%s/%s/%s", BASE_URI, accountId, jobId)
+	_, err := client.lbClient.PutJSON(path, update)
+	if err != nil {
+		mlog.Error("DPE stream client: Error updating: %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (client *StreamClient) PauseJob(accountId string, jobId string) *management.Error {
+	path := fmt.Sprintf("%s/%s/%s/pause", BASE_URI, accountId, jobId)
+	_, err := client.lbClient.PutJSON(path, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (client *StreamClient) ResumeJob(accountId string, jobId string) *management.Error {
+	path := fmt.Sprintf("%s/%s/%s/resume", BASE_URI, accountId, jobId)
+	_, err := client.lbClient.PutJSON(path, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
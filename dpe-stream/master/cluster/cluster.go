@@ -17,10 +17,22 @@ limitations under the License.
 package cluster
 
 import (
+	"errors"
 	"fmt"
+	dataModel "github.com/lavaorg/northstar/data/stream/model"
+	"github.com/lavaorg/northstar/dpe-stream/config"
 	"github.com/lavaorg/northstar/dpe-stream/master/model"
 )
 
+const (
+	MARATHON   = "marathon"
+	LOCAL      = "local"
+	PROCESS    = "process"
+	KUBERNETES = "kubernetes"
+)
+
+var ErrJobNotFound = errors.New("Job not found")
+
 type StartJob struct {
 	AccountId    string           `json:"accountId,omitempty"`
 	JobId        string           `json:"jobId,omitempty"`
@@ -29,6 +41,51 @@ type StartJob struct {
 	Instances    int              `json:"instances,omitempty"`
 	Source       model.Source     `json:"source,omitempty"`
 	Functions    []model.Function `json:"functions,omitempty"`
+	Output       *model.Format    `json:"output,omitempty"`
+	Revision     int              `json:"revision,omitempty"`
+}
+
+// NewStartJob rebuilds the job to start from its description stored in the data service.
+func NewStartJob(job *dataModel.JobData, instances int) (*StartJob, error) {
+	startJob := &StartJob{AccountId: job.AccountId,
+		JobId:        job.Id,
+		InvocationId: job.InvocationId,
+		Memory:       job.Memory,
+		Instances:    instances,
+		Source: model.Source{Name: job.Source.Name,
+			Connection: job.Source.Connection,
+			Format:     fromDataFormat(job.Source.Format)},
+		Output:   fromDataFormat(job.Output),
+		Revision: job.Revision}
+
+	for _, function := range job.Functions {
+		var parameters []interface{}
+		if function.Parameters != nil {
+			var ok bool
+			if parameters, ok = function.Parameters.([]interface{}); !ok {
+				return nil, fmt.Errorf("Invalid parameters of function %s", function.Name)
+			}
+		}
+
+		startJob.Functions = append(startJob.Functions, model.Function{Name: function.Name,
+			Parameters: parameters,
+			Evaluator:  function.Evaluator})
+	}
+
+	return startJob, nil
+}
+
+func fromDataFormat(format *dataModel.Format) *model.Format {
+	if format == nil {
+		return nil
+	}
+
+	return &model.Format{Type: format.Type,
+		Schema:    format.Schema,
+		SchemaId:  format.SchemaId,
+		Message:   format.Message,
+		Columns:   format.Columns,
+		Delimiter: format.Delimiter}
 }
 
 func (j *StartJob) Validate() error {
@@ -57,5 +114,41 @@ func (j *StartJob) Validate() error {
 
 type Cluster interface {
 	StartJob(job *StartJob) error
+	// StopJob stops all workers of the job. ErrJobNotFound is returned
+	// when the cluster does not know about the job.
 	StopJob(accountId, jobId string) error
+	// ScaleJob changes the number of workers of a running job from the given
+	// count to job.Instances. Workers that are kept are not restarted, the
+	// consumers rebalance the partitions among them. ErrJobNotFound is
+	// returned when the job has no workers left.
+	ScaleJob(job *StartJob, from int) error
+}
+
+// Worker is a stream worker running inside the master process.
+type Worker interface {
+	Stop()
+}
+
+// WorkerRunner starts an in-process worker for the job. It is provided by
+// the worker package, which cannot be imported from here.
+type WorkerRunner func(job *StartJob) (Worker, error)
+
+// NewCluster returns the cluster selected by DPE_STREAM_CLUSTER.
+func NewCluster(runner WorkerRunner) (Cluster, error) {
+	switch config.Cluster {
+	case MARATHON:
+		return NewMarathonCluster()
+	case LOCAL:
+		return NewLocalCluster(runner)
+	case PROCESS:
+		return NewProcessCluster(config.WorkerCommand)
+	case KUBERNETES:
+		return NewKubernetesCluster()
+	default:
+		return nil, fmt.Errorf("Unknown cluster selected: %v", config.Cluster)
+	}
+}
+
+func getJobKey(accountId, jobId string) string {
+	return accountId + "/" + jobId
 }
@@ -0,0 +1,289 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	b64 "encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/dpe-stream/config"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	K8S_ACCOUNT_LABEL = "northstar.dpe-stream/account"
+	K8S_JOB_LABEL     = "northstar.dpe-stream/job"
+)
+
+// KubernetesCluster runs every stream worker as a Kubernetes Job.
+type KubernetesCluster struct {
+	httpClient *http.Client
+	apiUrl     string
+	namespace  string
+	token      string
+	image      string
+	env        []string
+}
+
+func NewKubernetesCluster() (*KubernetesCluster, error) {
+	if config.KubernetesWorkerImage == "" {
+		return nil, fmt.Errorf("Please set DPE_STREAM_K8S_WORKER_IMAGE!")
+	}
+
+	token, err := ioutil.ReadFile(config.KubernetesTokenFile)
+	if err != nil {
+		mlog.Error("Failed to read kubernetes token: %v", err)
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{}
+	if ca, err := ioutil.ReadFile(config.KubernetesCAFile); err == nil {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(ca)
+		tlsConfig.RootCAs = pool
+	} else {
+		mlog.Info("Could not read kubernetes CA file, using system roots: %v", err)
+	}
+
+	var env []string
+	for _, name := range strings.Split(config.KubernetesWorkerEnv, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			env = append(env, name)
+		}
+	}
+
+	return &KubernetesCluster{
+		httpClient: &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}},
+		apiUrl:     strings.TrimRight(config.KubernetesApiUrl, "/"),
+		namespace:  config.KubernetesNamespace,
+		token:      strings.TrimSpace(string(token)),
+		image:      config.KubernetesWorkerImage,
+		env:        env,
+	}, nil
+}
+
+func (k *KubernetesCluster) StartJob(job *StartJob) error {
+	mlog.Info("Starting kubernetes job %s for account %s", job.JobId, job.AccountId)
+
+	out, err := json.Marshal(job)
+	if err != nil {
+		mlog.Error("Failed to marshal job: %v", err)
+		return err
+	}
+
+	for i := 0; i < job.Instances; i++ {
+		mlog.Debug("Starting worker %d", i)
+		k8sJob := k.newWorkerJob(job, i, b64.StdEncoding.EncodeToString(out))
+		if err := k.do("POST", k.jobsPath(), k8sJob, nil); err != nil {
+			mlog.Error("Failed to create kubernetes job: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (k *KubernetesCluster) StopJob(accountId string, jobId string) error {
+	names, err := k.workerJobs(accountId, jobId)
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		return ErrJobNotFound
+	}
+
+	// Background propagation removes the worker pods together with the jobs.
+	selector := k.jobSelector(accountId, jobId)
+	selector.Set("propagationPolicy", "Background")
+	return k.do("DELETE", k.jobsPath()+"?"+selector.Encode(), nil, nil)
+}
+
+func (k *KubernetesCluster) ScaleJob(job *StartJob, from int) error {
+	mlog.Info("Scaling kubernetes job %s for account %s to %d workers", job.JobId, job.AccountId, job.Instances)
+
+	names, err := k.workerJobs(job.AccountId, job.JobId)
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		return ErrJobNotFound
+	}
+
+	out, err := json.Marshal(job)
+	if err != nil {
+		mlog.Error("Failed to marshal job: %v", err)
+		return err
+	}
+
+	// Workers are named by index, create the missing ones first so the job
+	// keeps running while it is scaled.
+	for i := 0; i < job.Instances; i++ {
+		if names[getWorkerJobName(job.JobId, i)] {
+			continue
+		}
+
+		mlog.Debug("Starting worker %d", i)
+		k8sJob := k.newWorkerJob(job, i, b64.StdEncoding.EncodeToString(out))
+		if err := k.do("POST", k.jobsPath(), k8sJob, nil); err != nil {
+			mlog.Error("Failed to create kubernetes job: %v", err)
+			return err
+		}
+	}
+
+	for name := range names {
+		if index, ok := getWorkerIndex(job.JobId, name); !ok || index < job.Instances {
+			continue
+		}
+
+		mlog.Debug("Stopping worker %s", name)
+		if err := k.do("DELETE", k.jobsPath()+"/"+name+"?propagationPolicy=Background", nil, nil); err != nil {
+			mlog.Error("Failed to delete kubernetes job: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// workerJobs returns the names of the kubernetes jobs running the workers
+// of the job.
+func (k *KubernetesCluster) workerJobs(accountId string, jobId string) (map[string]bool, error) {
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	selector := k.jobSelector(accountId, jobId)
+	if err := k.do("GET", k.jobsPath()+"?"+selector.Encode(), nil, &list); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(list.Items))
+	for _, item := range list.Items {
+		names[item.Metadata.Name] = true
+	}
+
+	return names, nil
+}
+
+func (k *KubernetesCluster) jobSelector(accountId string, jobId string) url.Values {
+	selector := url.Values{}
+	selector.Set("labelSelector", fmt.Sprintf("%s=%s,%s=%s",
+		K8S_ACCOUNT_LABEL, accountId, K8S_JOB_LABEL, jobId))
+	return selector
+}
+
+func (k *KubernetesCluster) jobsPath() string {
+	return fmt.Sprintf("/apis/batch/v1/namespaces/%s/jobs", k.namespace)
+}
+
+func (k *KubernetesCluster) newWorkerJob(job *StartJob, index int, encodedJob string) map[string]interface{} {
+	labels := map[string]string{
+		K8S_ACCOUNT_LABEL: job.AccountId,
+		K8S_JOB_LABEL:     job.JobId,
+	}
+
+	env := []map[string]string{{"name": "DPE_STREAM_WORKER_JOB", "value": encodedJob}}
+	for _, name := range k.env {
+		env = append(env, map[string]string{"name": name, "value": os.Getenv(name)})
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata": map[string]interface{}{
+			"name":   getWorkerJobName(job.JobId, index),
+			"labels": labels,
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": labels},
+				"spec": map[string]interface{}{
+					"restartPolicy": "OnFailure",
+					"containers": []map[string]interface{}{{
+						"name":  "worker",
+						"image": k.image,
+						"args":  []string{"/usr/local/bin/dpe-stream", "worker"},
+						"env":   env,
+					}},
+				},
+			},
+		},
+	}
+}
+
+func getWorkerJobName(jobId string, index int) string {
+	return fmt.Sprintf("dpe-stream-%s-%d", jobId, index)
+}
+
+// getWorkerIndex returns the index of the worker from its kubernetes job name.
+func getWorkerIndex(jobId string, name string) (int, bool) {
+	index, err := strconv.Atoi(strings.TrimPrefix(name, fmt.Sprintf("dpe-stream-%s-", jobId)))
+	return index, err == nil
+}
+
+func (k *KubernetesCluster) do(method string, path string, in interface{}, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, k.apiUrl+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+k.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := k.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Kubernetes API %s %s failed with status %d: %s",
+			method, path, resp.StatusCode, string(respBody))
+	}
+
+	if out != nil {
+		return json.Unmarshal(respBody, out)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/dpe-stream/master/model"
+	"sync"
+)
+
+// LocalCluster runs stream workers as goroutines of the master process. It
+// is meant for development and integration tests.
+type LocalCluster struct {
+	runner  WorkerRunner
+	lock    sync.Mutex
+	workers map[string][]Worker
+}
+
+func NewLocalCluster(runner WorkerRunner) (*LocalCluster, error) {
+	if runner == nil {
+		return nil, fmt.Errorf("Worker runner is not set")
+	}
+
+	return &LocalCluster{runner: runner, workers: make(map[string][]Worker)}, nil
+}
+
+func (l *LocalCluster) StartJob(job *StartJob) error {
+	mlog.Info("Starting local job %s for account %s", job.JobId, job.AccountId)
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	key := getJobKey(job.AccountId, job.JobId)
+	if _, ok := l.workers[key]; ok {
+		return fmt.Errorf("Job %s already running", job.JobId)
+	}
+
+	workers := make([]Worker, 0, job.Instances)
+	for i := 0; i < job.Instances; i++ {
+		mlog.Debug("Starting local worker %d", i)
+
+		// Every worker decodes the functions in place, so each gets its own copy.
+		worker, err := l.runner(copyJob(job))
+		if err != nil {
+			mlog.Error("Failed to start local worker: %v", err)
+			stopWorkers(workers)
+			return err
+		}
+
+		workers = append(workers, worker)
+	}
+
+	l.workers[key] = workers
+	return nil
+}
+
+func (l *LocalCluster) StopJob(accountId string, jobId string) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	key := getJobKey(accountId, jobId)
+	workers, ok := l.workers[key]
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	stopWorkers(workers)
+	delete(l.workers, key)
+	return nil
+}
+
+func (l *LocalCluster) ScaleJob(job *StartJob, from int) error {
+	mlog.Info("Scaling local job %s for account %s to %d workers", job.JobId, job.AccountId, job.Instances)
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	key := getJobKey(job.AccountId, job.JobId)
+	workers, ok := l.workers[key]
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	for len(workers) < job.Instances {
+		worker, err := l.runner(copyJob(job))
+		if err != nil {
+			mlog.Error("Failed to start local worker: %v", err)
+			l.workers[key] = workers
+			return err
+		}
+
+		workers = append(workers, worker)
+	}
+
+	if len(workers) > job.Instances {
+		stopWorkers(workers[job.Instances:])
+		workers = workers[:job.Instances]
+	}
+
+	l.workers[key] = workers
+	return nil
+}
+
+func stopWorkers(workers []Worker) {
+	for _, worker := range workers {
+		worker.Stop()
+	}
+}
+
+func copyJob(job *StartJob) *StartJob {
+	out := *job
+	out.Functions = make([]model.Function, len(job.Functions))
+	for i, function := range job.Functions {
+		out.Functions[i] = function
+		out.Functions[i].Parameters = append([]interface{}(nil), function.Parameters...)
+	}
+
+	return &out
+}
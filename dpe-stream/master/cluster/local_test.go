@@ -0,0 +1,139 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/lavaorg/northstar/dpe-stream/master/model"
+)
+
+type fakeWorker struct {
+	stopped bool
+}
+
+func (w *fakeWorker) Stop() {
+	w.stopped = true
+}
+
+func TestLocalCluster(t *testing.T) {
+	var workers []*fakeWorker
+	runner := func(job *StartJob) (Worker, error) {
+		worker := &fakeWorker{}
+		workers = append(workers, worker)
+		return worker, nil
+	}
+
+	local, err := NewLocalCluster(runner)
+	if err != nil {
+		t.Fatalf("Failed to create local cluster: %v", err)
+	}
+
+	job := &StartJob{AccountId: "account",
+		JobId:        "job",
+		InvocationId: "invocation",
+		Instances:    3,
+		Source:       model.Source{Name: model.SOURCE_KAFKA},
+		Functions:    []model.Function{{Name: "map", Parameters: []interface{}{"a"}}}}
+
+	if err := local.StartJob(job); err != nil {
+		t.Fatalf("Failed to start job: %v", err)
+	}
+
+	if len(workers) != 3 {
+		t.Fatalf("Expected 3 workers, got %d", len(workers))
+	}
+
+	if err := local.StartJob(job); err == nil {
+		t.Errorf("Expected error when starting a running job")
+	}
+
+	if err := local.StopJob("account", "job"); err != nil {
+		t.Fatalf("Failed to stop job: %v", err)
+	}
+
+	for i, worker := range workers {
+		if !worker.stopped {
+			t.Errorf("Worker %d was not stopped", i)
+		}
+	}
+
+	if err := local.StopJob("account", "job"); err != ErrJobNotFound {
+		t.Errorf("Expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestLocalClusterScale(t *testing.T) {
+	var workers []*fakeWorker
+	runner := func(job *StartJob) (Worker, error) {
+		worker := &fakeWorker{}
+		workers = append(workers, worker)
+		return worker, nil
+	}
+
+	local, err := NewLocalCluster(runner)
+	if err != nil {
+		t.Fatalf("Failed to create local cluster: %v", err)
+	}
+
+	job := &StartJob{AccountId: "account",
+		JobId:     "job",
+		Instances: 2,
+		Source:    model.Source{Name: model.SOURCE_KAFKA}}
+
+	if err := local.ScaleJob(job, 1); err != ErrJobNotFound {
+		t.Errorf("Expected ErrJobNotFound, got %v", err)
+	}
+
+	if err := local.StartJob(job); err != nil {
+		t.Fatalf("Failed to start job: %v", err)
+	}
+
+	job.Instances = 4
+	if err := local.ScaleJob(job, 2); err != nil {
+		t.Fatalf("Failed to scale job up: %v", err)
+	}
+
+	if len(workers) != 4 {
+		t.Fatalf("Expected 4 workers, got %d", len(workers))
+	}
+
+	for i, worker := range workers {
+		if worker.stopped {
+			t.Errorf("Worker %d was stopped when scaling up", i)
+		}
+	}
+
+	job.Instances = 1
+	if err := local.ScaleJob(job, 4); err != nil {
+		t.Fatalf("Failed to scale job down: %v", err)
+	}
+
+	for i, worker := range workers {
+		if worker.stopped != (i > 0) {
+			t.Errorf("Worker %d: expected stopped %t, got %t", i, i > 0, worker.stopped)
+		}
+	}
+
+	if err := local.StopJob("account", "job"); err != nil {
+		t.Fatalf("Failed to stop job: %v", err)
+	}
+
+	if !workers[0].stopped {
+		t.Errorf("Remaining worker was not stopped")
+	}
+}
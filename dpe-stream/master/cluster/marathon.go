@@ -20,6 +20,7 @@ import (
 	b64 "encoding/base64"
 	"encoding/json"
 	"fmt"
+	gomarathon "github.com/gambol99/go-marathon"
 	"github.com/lavaorg/lrtx/marathon"
 	"github.com/lavaorg/lrtx/mlog"
 	"github.com/lavaorg/northstar/dpe-stream/config"
@@ -43,29 +44,32 @@ func (m *MarathonCluster) StartJob(job *StartJob) error {
 	mlog.Info("Starting job %s for account %s", job.JobId, job.AccountId)
 
 	for i := 0; i < job.Instances; i++ {
-		mlog.Debug("Starting worker %d", i)
-		app, err := marathon.GetApplicationFromJson(config.WorkerMarathonJson)
-		if err != nil {
-			mlog.Error("Failed to get application from json: %v", err)
+		if err := m.createWorker(job, i); err != nil {
 			return err
 		}
+	}
 
-		app.Name(getWorkerName(job.AccountId, job.JobId, i))
-		app.Args = &[]string{"/usr/local/bin/dpe-stream worker"}
-		env := *app.Env
+	return nil
+}
 
-		out, err := json.Marshal(job)
-		if err != nil {
-			mlog.Error("Failed to marshal job: %v", err)
+func (m *MarathonCluster) ScaleJob(job *StartJob, from int) error {
+	mlog.Info("Scaling job %s for account %s from %d to %d workers", job.JobId, job.AccountId, from, job.Instances)
+
+	for i := from; i < job.Instances; i++ {
+		if err := m.createWorker(job, i); err != nil {
 			return err
 		}
+	}
 
-		env["DPE_STREAM_WORKER_JOB"] = b64.StdEncoding.EncodeToString(out)
-		mlog.Debug("Stream job base64: %v", env["DPE_STREAM_WORKER_JOB"])
+	for i := job.Instances; i < from; i++ {
+		mlog.Debug("Stopping worker %d", i)
+		err := m.marathonClient.DeleteGroup(getWorkerGroup(job.AccountId, job.JobId, i))
+		if apiErr, ok := err.(*gomarathon.APIError); ok && apiErr.ErrCode == gomarathon.ErrCodeNotFound {
+			continue
+		}
 
-		err = m.marathonClient.CreateApplication(app)
 		if err != nil {
-			mlog.Error("Failed to create application: %v", err)
+			mlog.Error("Failed to delete worker group: %v", err)
 			return err
 		}
 	}
@@ -73,16 +77,57 @@ func (m *MarathonCluster) StartJob(job *StartJob) error {
 	return nil
 }
 
+func (m *MarathonCluster) createWorker(job *StartJob, index int) error {
+	mlog.Debug("Starting worker %d", index)
+	app, err := marathon.GetApplicationFromJson(config.WorkerMarathonJson)
+	if err != nil {
+		mlog.Error("Failed to get application from json: %v", err)
+		return err
+	}
+
+	app.Name(getWorkerName(job.AccountId, job.JobId, index))
+	app.Args = &[]string{"/usr/local/bin/dpe-stream worker"}
+	env := *app.Env
+
+	out, err := json.Marshal(job)
+	if err != nil {
+		mlog.Error("Failed to marshal job: %v", err)
+		return err
+	}
+
+	env["DPE_STREAM_WORKER_JOB"] = b64.StdEncoding.EncodeToString(out)
+	mlog.Debug("Stream job base64: %v", env["DPE_STREAM_WORKER_JOB"])
+
+	err = m.marathonClient.CreateApplication(app)
+	if err != nil {
+		mlog.Error("Failed to create application: %v", err)
+		return err
+	}
+
+	return nil
+}
+
 func (m *MarathonCluster) StopJob(accountId string, jobId string) error {
 	groupName := fmt.Sprintf("/%s/%s/dpe-stream-jobs/%s/%s",
 		os.Getenv("MON_GROUP"), os.Getenv("ENV"), accountId, jobId)
 	mlog.Debug("Group name: %v", groupName)
-	return m.marathonClient.DeleteGroup(groupName)
+	err := m.marathonClient.DeleteGroup(groupName)
+	if apiErr, ok := err.(*gomarathon.APIError); ok && apiErr.ErrCode == gomarathon.ErrCodeNotFound {
+		return ErrJobNotFound
+	}
+
+	return err
 }
 
-func getWorkerName(accountId, jobId string, index int) string {
-	appName := fmt.Sprintf("/%s/%s/dpe-stream-jobs/%s/%s/worker-%d",
+// getWorkerGroup returns the group of a worker application. Every worker has
+// its own group so it can be removed when the job is scaled down.
+func getWorkerGroup(accountId, jobId string, index int) string {
+	return fmt.Sprintf("/%s/%s/dpe-stream-jobs/%s/%s/worker-%d",
 		os.Getenv("MON_GROUP"), os.Getenv("ENV"), accountId, jobId, index)
+}
+
+func getWorkerName(accountId, jobId string, index int) string {
+	appName := getWorkerGroup(accountId, jobId, index) + "/worker"
 	mlog.Debug("Worker name: %v", appName)
 	return appName
 }
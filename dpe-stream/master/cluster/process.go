@@ -0,0 +1,179 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	b64 "encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/lavaorg/lrtx/mlog"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// ProcessCluster runs every stream worker as a child process of the master.
+type ProcessCluster struct {
+	command   string
+	lock      sync.Mutex
+	processes map[string][]*exec.Cmd
+}
+
+func NewProcessCluster(command string) (*ProcessCluster, error) {
+	if command == "" {
+		return nil, fmt.Errorf("Worker command is empty")
+	}
+
+	return &ProcessCluster{command: command, processes: make(map[string][]*exec.Cmd)}, nil
+}
+
+func (p *ProcessCluster) StartJob(job *StartJob) error {
+	mlog.Info("Starting job %s for account %s as child processes", job.JobId, job.AccountId)
+
+	out, err := json.Marshal(job)
+	if err != nil {
+		mlog.Error("Failed to marshal job: %v", err)
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := getJobKey(job.AccountId, job.JobId)
+	if _, ok := p.processes[key]; ok {
+		return fmt.Errorf("Job %s already running", job.JobId)
+	}
+
+	processes := make([]*exec.Cmd, 0, job.Instances)
+	for i := 0; i < job.Instances; i++ {
+		cmd, err := p.startProcess(job, key, out, i)
+		if err != nil {
+			killProcesses(processes)
+			return err
+		}
+
+		processes = append(processes, cmd)
+	}
+
+	p.processes[key] = processes
+	return nil
+}
+
+func (p *ProcessCluster) ScaleJob(job *StartJob, from int) error {
+	mlog.Info("Scaling job %s for account %s to %d worker processes", job.JobId, job.AccountId, job.Instances)
+
+	out, err := json.Marshal(job)
+	if err != nil {
+		mlog.Error("Failed to marshal job: %v", err)
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := getJobKey(job.AccountId, job.JobId)
+	processes, ok := p.processes[key]
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	for len(processes) < job.Instances {
+		cmd, err := p.startProcess(job, key, out, len(processes))
+		if err != nil {
+			p.processes[key] = processes
+			return err
+		}
+
+		processes = append(processes, cmd)
+	}
+
+	if len(processes) > job.Instances {
+		killProcesses(processes[job.Instances:])
+		processes = processes[:job.Instances]
+	}
+
+	p.processes[key] = processes
+	return nil
+}
+
+// startProcess starts worker process index of the job, out is the encoded job.
+func (p *ProcessCluster) startProcess(job *StartJob, key string, out []byte, index int) (*exec.Cmd, error) {
+	mlog.Debug("Starting worker process %d", index)
+	cmd := exec.Command(p.command, "worker")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Workers share the host, so let each one pick a free web port.
+	cmd.Env = append(os.Environ(),
+		"DPE_STREAM_WORKER_JOB="+b64.StdEncoding.EncodeToString(out),
+		"DPE_STREAM_PORT=0")
+
+	if err := cmd.Start(); err != nil {
+		mlog.Error("Failed to start worker process: %v", err)
+		return nil, err
+	}
+
+	go p.waitProcess(cmd, key, job.JobId, index)
+	return cmd, nil
+}
+
+func (p *ProcessCluster) StopJob(accountId string, jobId string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := getJobKey(accountId, jobId)
+	processes, ok := p.processes[key]
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	killProcesses(processes)
+	delete(p.processes, key)
+	return nil
+}
+
+func killProcesses(processes []*exec.Cmd) {
+	for _, cmd := range processes {
+		if err := cmd.Process.Kill(); err != nil {
+			mlog.Error("Failed to kill worker process %d: %v", cmd.Process.Pid, err)
+		}
+	}
+}
+
+// waitProcess waits for a worker process to exit and forgets it, so a job
+// whose workers all exited can be started again.
+func (p *ProcessCluster) waitProcess(cmd *exec.Cmd, key string, jobId string, index int) {
+	err := cmd.Wait()
+	mlog.Info("Worker process %d of job %s exited: %v", index, jobId, err)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	processes := p.processes[key]
+	for i, process := range processes {
+		if process == cmd {
+			processes = append(processes[:i], processes[i+1:]...)
+			break
+		}
+	}
+
+	if len(processes) == 0 {
+		delete(p.processes, key)
+	} else {
+		p.processes[key] = processes
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/lavaorg/northstar/dpe-stream/master/model"
+)
+
+func TestProcessClusterForgetsExitedWorkers(t *testing.T) {
+	command, err := exec.LookPath("true")
+	if err != nil {
+		t.Skip("true command not available")
+	}
+
+	process, err := NewProcessCluster(command)
+	if err != nil {
+		t.Fatalf("Failed to create process cluster: %v", err)
+	}
+
+	job := &StartJob{AccountId: "account",
+		JobId:     "job",
+		Instances: 2,
+		Source:    model.Source{Name: model.SOURCE_KAFKA}}
+
+	if err := process.StartJob(job); err != nil {
+		t.Fatalf("Failed to start job: %v", err)
+	}
+
+	// The workers exit right away, the job must be started again once
+	// they are all gone.
+	deadline := time.Now().Add(5 * time.Second)
+	for process.running("account", "job") {
+		if time.Now().After(deadline) {
+			t.Fatalf("Exited workers were not removed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := process.StopJob("account", "job"); err != ErrJobNotFound {
+		t.Errorf("Expected ErrJobNotFound, got %v", err)
+	}
+
+	if err := process.StartJob(job); err != nil {
+		t.Errorf("Failed to start job again: %v", err)
+	}
+}
+
+func (p *ProcessCluster) running(accountId string, jobId string) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	_, ok := p.processes[getJobKey(accountId, jobId)]
+	return ok
+}
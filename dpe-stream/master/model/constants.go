@@ -19,3 +19,25 @@ package model
 const (
 	SOURCE_KAFKA = "kafka"
 )
+
+// Status of a stream job, the workers follow it to pause and resume.
+const (
+	JOB_STARTED      = "STARTED"
+	JOB_PAUSED       = "PAUSED"
+	JOB_STOP_FAILED  = "FAILED_TO_STOP"
+	JOB_START_FAILED = "FAILED_TO_START"
+)
+
+// Number of heartbeat intervals a worker can miss before it is considered
+// dead and its lag is ignored.
+const MISSED_HEARTBEATS = 3
+
+// Message formats understood by the stream decoders and encoders.
+const (
+	FORMAT_RAW      = "raw"
+	FORMAT_JSON     = "json"
+	FORMAT_AVRO     = "avro"
+	FORMAT_PROTOBUF = "protobuf"
+	FORMAT_CSV      = "csv"
+	FORMAT_MSGPACK  = "msgpack"
+)
@@ -29,6 +29,41 @@ import (
 type Source struct {
 	Name       string      `json:"name,omitempty"`
 	Connection interface{} `json:"connection,omitempty"`
+	Format     *Format     `json:"format,omitempty"`
+}
+
+// Format describes how stream messages are decoded into Lua values and how
+// results are encoded. Schema holds the Avro schema, or the base64 encoded
+// protobuf FileDescriptorSet in which Message is looked up. Avro messages
+// without a schema carry a schema id resolved in the local schema registry.
+type Format struct {
+	Type      string   `json:"type,omitempty"`
+	Schema    string   `json:"schema,omitempty"`
+	SchemaId  int      `json:"schemaId,omitempty"`
+	Message   string   `json:"message,omitempty"`
+	Columns   []string `json:"columns,omitempty"`
+	Delimiter string   `json:"delimiter,omitempty"`
+}
+
+func (f *Format) Validate() error {
+	switch f.Type {
+	case FORMAT_RAW, FORMAT_JSON, FORMAT_MSGPACK:
+		return nil
+	case FORMAT_CSV:
+		if len([]rune(f.Delimiter)) > 1 {
+			return fmt.Errorf("CSV delimiter has to be a single character")
+		}
+		return nil
+	case FORMAT_AVRO:
+		return nil
+	case FORMAT_PROTOBUF:
+		if f.Schema == "" || f.Message == "" {
+			return fmt.Errorf("Protobuf format requires a schema and a message name")
+		}
+		return nil
+	default:
+		return fmt.Errorf("Unknown format: %v", f.Type)
+	}
 }
 
 type Function struct {
@@ -89,12 +124,43 @@ func (function *Function) Decode() error {
 	return nil
 }
 
+// Autoscale enables scaling the workers of a job between MinWorkers and
+// MaxWorkers based on the consumer lag per worker.
+type Autoscale struct {
+	MinWorkers   int   `json:"minWorkers,omitempty"`
+	MaxWorkers   int   `json:"maxWorkers,omitempty"`
+	ScaleUpLag   int64 `json:"scaleUpLag,omitempty"`
+	ScaleDownLag int64 `json:"scaleDownLag,omitempty"`
+}
+
+func (a *Autoscale) Validate() error {
+	if a.MinWorkers < 1 {
+		return fmt.Errorf("Minimum number of workers less than one")
+	}
+
+	if a.MaxWorkers < a.MinWorkers {
+		return fmt.Errorf("Maximum number of workers less than minimum")
+	}
+
+	if a.ScaleUpLag < 0 || a.ScaleDownLag < 0 {
+		return fmt.Errorf("Scaling lag is negative")
+	}
+
+	if a.ScaleUpLag > 0 && a.ScaleDownLag >= a.ScaleUpLag {
+		return fmt.Errorf("Scale down lag has to be lower than scale up lag")
+	}
+
+	return nil
+}
+
 type StreamJob struct {
 	InvocationId string     `json:"invocationId,omitempty"`
 	Memory       uint64     `json:"memory,omitempty"`
 	Source       Source     `json:"source,omitempty"`
 	Functions    []Function `json:"functions,omitempty"`
 	Description  string     `json:"description,omitempty"`
+	Autoscale    *Autoscale `json:"autoscale,omitempty"`
+	Output       *Format    `json:"output,omitempty"`
 }
 
 func (j *StreamJob) Validate() error {
@@ -110,6 +176,41 @@ func (j *StreamJob) Validate() error {
 		return fmt.Errorf("Number of functions less than one")
 	}
 
+	if j.Source.Format != nil {
+		if err := j.Source.Format.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if j.Output != nil {
+		if err := j.Output.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if j.Autoscale != nil {
+		return j.Autoscale.Validate()
+	}
+
+	return nil
+}
+
+// JobUpdate replaces the functions chain, and optionally the output format,
+// of a running job.
+type JobUpdate struct {
+	Functions []Function `json:"functions,omitempty"`
+	Output    *Format    `json:"output,omitempty"`
+}
+
+func (u *JobUpdate) Validate() error {
+	if len(u.Functions) < 1 {
+		return fmt.Errorf("Number of functions less than one")
+	}
+
+	if u.Output != nil {
+		return u.Output.Validate()
+	}
+
 	return nil
 }
 
@@ -0,0 +1,198 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"github.com/lavaorg/lrtx/mlog"
+	dataModel "github.com/lavaorg/northstar/data/stream/model"
+	"github.com/lavaorg/northstar/dpe-stream/config"
+	"github.com/lavaorg/northstar/dpe-stream/master/cluster"
+	"github.com/lavaorg/northstar/dpe-stream/master/model"
+	"github.com/lavaorg/northstar/dpe-stream/master/stats"
+	"time"
+)
+
+// StartAutoscaler periodically resizes the jobs that have autoscaling enabled.
+func (s *StreamService) StartAutoscaler() {
+	if config.AutoscaleInterval < 1 {
+		mlog.Info("Autoscaling disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(config.AutoscaleInterval) * time.Second)
+		for range ticker.C {
+			s.autoscale()
+		}
+	}()
+}
+
+func (s *StreamService) autoscale() {
+	jobs, mErr := s.dataClient.GetAllJobs()
+	if mErr != nil {
+		mlog.Error("Failed to list jobs: %v", mErr)
+		stats.ErrAutoscale.Incr()
+		return
+	}
+
+	for _, job := range jobs {
+		if job.Autoscale == nil || job.Status != model.JOB_STARTED {
+			continue
+		}
+
+		if err := s.autoscaleJob(job.AccountId, job.Id); err != nil {
+			mlog.Error("Failed to autoscale job %s: %v", job.Id, err)
+			stats.ErrAutoscale.Incr()
+		}
+	}
+}
+
+func (s *StreamService) autoscaleJob(accountId string, jobId string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Reload the job under the lock, it may have been stopped meanwhile.
+	job, mErr := s.dataClient.GetJob(accountId, jobId)
+	if mErr != nil {
+		return mErr
+	}
+
+	if job.Autoscale == nil || job.Status != model.JOB_STARTED {
+		return nil
+	}
+
+	if len(job.ScalingEvents) > 0 {
+		cooldown := time.Duration(config.AutoscaleCooldown) * time.Second
+		if time.Since(job.ScalingEvents[0].Time) < cooldown {
+			return nil
+		}
+	}
+
+	lag, alive := getJobLag(job.Workers)
+	if alive < job.Instances {
+		mlog.Debug("Job %s has %d of %d workers reporting, not scaling", jobId, alive, job.Instances)
+		return nil
+	}
+
+	instances, reason := desiredWorkers(job.Autoscale, job.Instances, lag)
+	if instances == job.Instances {
+		return nil
+	}
+
+	return s.scaleJob(job, instances, reason)
+}
+
+// scaleJob changes the number of workers of the job. The workers that are
+// kept continue to run, the consumers rebalance the partitions among the
+// old and new workers.
+func (s *StreamService) scaleJob(job *dataModel.JobData, instances int, reason string) error {
+	mlog.Info("Scaling job %s from %d to %d workers: %s", job.Id, job.Instances, instances, reason)
+
+	startJob, err := cluster.NewStartJob(job, instances)
+	if err != nil {
+		return err
+	}
+
+	err = s.jobCluster.ScaleJob(startJob, job.Instances)
+	if err == cluster.ErrJobNotFound {
+		// All workers are gone, start the job again with the new count.
+		mlog.Info("Job %s has no workers, starting it with %d workers", job.Id, instances)
+		if err = s.jobCluster.StartJob(startJob); err != nil {
+			stats.ErrScaleJob.Incr()
+			update := dataModel.JobData{Status: model.JOB_START_FAILED, ErrorDescr: err.Error()}
+			if mErr := s.dataClient.UpdateJob(job.AccountId, job.Id, &update); mErr != nil {
+				mlog.Error("Failed to update job: %v", mErr)
+			}
+			return err
+		}
+	}
+
+	if err != nil {
+		stats.ErrScaleJob.Incr()
+		return err
+	}
+
+	update := dataModel.JobData{Instances: instances}
+	if mErr := s.dataClient.UpdateJob(job.AccountId, job.Id, &update); mErr != nil {
+		stats.ErrDataUpdateJob.Incr()
+		return mErr
+	}
+
+	event := &dataModel.ScalingEvent{Time: time.Now().In(time.UTC),
+		FromWorkers: job.Instances,
+		ToWorkers:   instances,
+		Reason:      reason}
+	if mErr := s.dataClient.AddScalingEvent(job.AccountId, job.Id, event); mErr != nil {
+		stats.ErrDataUpdateJob.Incr()
+		return mErr
+	}
+
+	stats.ScaleJob.Incr()
+	return nil
+}
+
+// getJobLag returns the total lag reported by live workers and their number.
+func getJobLag(workers []dataModel.WorkerStatus) (int64, int) {
+	alive := []dataModel.WorkerStatus{}
+	for _, worker := range workers {
+		timeout := time.Duration(model.MISSED_HEARTBEATS*worker.Interval) * time.Second
+		if time.Since(worker.Heartbeat) < timeout {
+			alive = append(alive, worker)
+		}
+	}
+
+	var lag int64
+	for _, partitionLag := range dataModel.MergeLag(alive) {
+		lag += partitionLag
+	}
+
+	return lag, len(alive)
+}
+
+// desiredWorkers returns the number of workers the job should run given its
+// total lag, moving one worker at a time within the autoscale bounds.
+func desiredWorkers(autoscale *dataModel.Autoscale, instances int, lag int64) (int, string) {
+	scaleUpLag := autoscale.ScaleUpLag
+	if scaleUpLag == 0 {
+		scaleUpLag = int64(config.AutoscaleScaleUpLag)
+	}
+
+	scaleDownLag := autoscale.ScaleDownLag
+	if scaleDownLag == 0 {
+		scaleDownLag = int64(config.AutoscaleScaleDownLag)
+	}
+
+	if instances < autoscale.MinWorkers {
+		return autoscale.MinWorkers, "below minimum number of workers"
+	}
+
+	if instances > autoscale.MaxWorkers {
+		return autoscale.MaxWorkers, "above maximum number of workers"
+	}
+
+	lagPerWorker := lag / int64(instances)
+	if lagPerWorker > scaleUpLag && instances < autoscale.MaxWorkers {
+		return instances + 1, fmt.Sprintf("lag per worker %d above %d", lagPerWorker, scaleUpLag)
+	}
+
+	if lagPerWorker < scaleDownLag && instances > autoscale.MinWorkers {
+		return instances - 1, fmt.Sprintf("lag per worker %d below %d", lagPerWorker, scaleDownLag)
+	}
+
+	return instances, ""
+}
@@ -0,0 +1,72 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	dataModel "github.com/lavaorg/northstar/data/stream/model"
+)
+
+func TestDesiredWorkers(t *testing.T) {
+	autoscale := &dataModel.Autoscale{MinWorkers: 1, MaxWorkers: 4, ScaleUpLag: 1000, ScaleDownLag: 10}
+
+	tests := []struct {
+		instances int
+		lag       int64
+		expected  int
+	}{
+		{instances: 2, lag: 5000, expected: 3},
+		{instances: 4, lag: 50000, expected: 4},
+		{instances: 2, lag: 10, expected: 1},
+		{instances: 1, lag: 0, expected: 1},
+		{instances: 2, lag: 500, expected: 2},
+		{instances: 6, lag: 500, expected: 4},
+	}
+
+	for _, test := range tests {
+		instances, _ := desiredWorkers(autoscale, test.instances, test.lag)
+		if instances != test.expected {
+			t.Errorf("Instances %d with lag %d: expected %d workers, got %d",
+				test.instances, test.lag, test.expected, instances)
+		}
+	}
+}
+
+func TestGetJobLag(t *testing.T) {
+	workers := []dataModel.WorkerStatus{
+		{WorkerId: "alive", Heartbeat: time.Now(), Interval: 30, Lag: map[int32]int64{0: 10, 1: 5}},
+		{WorkerId: "dead", Heartbeat: time.Now().Add(-time.Hour), Interval: 30, Lag: map[int32]int64{2: 100}},
+	}
+
+	lag, alive := getJobLag(workers)
+	if lag != 15 || alive != 1 {
+		t.Errorf("Expected lag 15 from 1 worker, got %d from %d", lag, alive)
+	}
+
+	// Each partition counts once, with the lag of the worker consuming it.
+	workers = append(workers, dataModel.WorkerStatus{WorkerId: "other",
+		Heartbeat: time.Now(),
+		Interval:  30,
+		Lag:       map[int32]int64{0: 3, 1: 40}})
+
+	lag, alive = getJobLag(workers)
+	if lag != 8 || alive != 2 {
+		t.Errorf("Expected lag 8 from 2 workers, got %d from %d", lag, alive)
+	}
+}
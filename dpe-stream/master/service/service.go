@@ -17,7 +17,7 @@ limitations under the License.
 package service
 
 import (
-	"github.com/gambol99/go-marathon"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/lavaorg/lrtx/management"
 	"github.com/lavaorg/lrtx/mlog"
@@ -30,21 +30,19 @@ import (
 	"github.com/lavaorg/northstar/dpe-stream/master/util"
 	uuid "github.com/satori/go.uuid"
 	"net/http"
-)
-
-const (
-	JOB_STARTED      = "STARTED"
-	JOB_STOP_FAILED  = "FAILED_TO_STOP"
-	JOB_START_FAILED = "FAILED_TO_START"
+	"sync"
 )
 
 type StreamService struct {
-	marathonCluster cluster.Cluster
-	dataClient      client.Client
+	jobCluster cluster.Cluster
+	dataClient client.Client
+
+	// Serializes stopping, rescaling and updating of jobs.
+	lock sync.Mutex
 }
 
-func NewSteamService() (*StreamService, error) {
-	cluster, err := cluster.NewMarathonCluster()
+func NewSteamService(runner cluster.WorkerRunner) (*StreamService, error) {
+	jobCluster, err := cluster.NewCluster(runner)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +52,7 @@ func NewSteamService() (*StreamService, error) {
 		return nil, err
 	}
 
-	return &StreamService{marathonCluster: cluster, dataClient: dataClient}, nil
+	return &StreamService{jobCluster: jobCluster, dataClient: dataClient}, nil
 }
 
 func (s *StreamService) AddRoutes() {
@@ -62,6 +60,9 @@ func (s *StreamService) AddRoutes() {
 	g := grp.Group("jobs")
 	g.POST(":accountId", s.startJob)
 	g.DELETE(":accountId/:jobId", s.stopJob)
+	g.PUT(":accountId/:jobId", s.updateJob)
+	g.PUT(":accountId/:jobId/pause", s.pauseJob)
+	g.PUT(":accountId/:jobId/resume", s.resumeJob)
 }
 
 func (s *StreamService) startJob(c *gin.Context) {
@@ -94,14 +95,6 @@ func (s *StreamService) startJob(c *gin.Context) {
 		return
 	}
 	jobId := vuuid.String()
-	jobData := createJobData(accountId, jobId, job)
-	mErr := s.dataClient.AddJob(accountId, jobData)
-	if err != nil {
-		mlog.Error("Failed to add job: %v", mErr)
-		stats.ErrDataAddJob.Incr()
-		c.JSON(http.StatusInternalServerError, management.GetInternalError(mErr.Error()))
-		return
-	}
 
 	numberOfWorkers, err := connection.GetNumberOfWorkers(&job.Source)
 	if err != nil {
@@ -110,15 +103,39 @@ func (s *StreamService) startJob(c *gin.Context) {
 		return
 	}
 
+	// More workers than partitions would sit idle, so autoscaled jobs are
+	// capped at the partition count and start with the minimum.
+	if job.Autoscale != nil {
+		if job.Autoscale.MaxWorkers > numberOfWorkers {
+			job.Autoscale.MaxWorkers = numberOfWorkers
+		}
+
+		if job.Autoscale.MinWorkers > job.Autoscale.MaxWorkers {
+			job.Autoscale.MinWorkers = job.Autoscale.MaxWorkers
+		}
+
+		numberOfWorkers = job.Autoscale.MinWorkers
+	}
+
+	jobData := createJobData(accountId, jobId, numberOfWorkers, job)
+	mErr := s.dataClient.AddJob(accountId, jobData)
+	if mErr != nil {
+		mlog.Error("Failed to add job: %v", mErr)
+		stats.ErrDataAddJob.Incr()
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(mErr.Error()))
+		return
+	}
+
 	startJob := &cluster.StartJob{AccountId: accountId,
 		JobId:        jobId,
 		InvocationId: job.InvocationId,
 		Instances:    numberOfWorkers,
 		Source:       job.Source,
-		Functions:    job.Functions}
-	err = s.marathonCluster.StartJob(startJob)
+		Functions:    job.Functions,
+		Output:       job.Output}
+	err = s.jobCluster.StartJob(startJob)
 	if err != nil {
-		jobData := dataModel.JobData{Status: JOB_START_FAILED, ErrorDescr: err.Error()}
+		jobData := dataModel.JobData{Status: model.JOB_START_FAILED, ErrorDescr: err.Error()}
 		mErr := s.dataClient.UpdateJob(accountId, jobId, &jobData)
 		if mErr != nil {
 			mlog.Error("Failed to update job: %v", mErr)
@@ -127,7 +144,7 @@ func (s *StreamService) startJob(c *gin.Context) {
 			return
 		}
 
-		mlog.Error("Marathon error: %v", err)
+		mlog.Error("Cluster error: %v", err)
 		stats.ErrMarathonStartJob.Incr()
 		c.JSON(http.StatusInternalServerError, management.GetInternalError(err.Error()))
 		return
@@ -137,20 +154,33 @@ func (s *StreamService) startJob(c *gin.Context) {
 	c.String(http.StatusCreated, jobId)
 }
 
-func createJobData(accountId string, jobId string, job *model.StreamJob) *dataModel.JobData {
+func createJobData(accountId string, jobId string, instances int, job *model.StreamJob) *dataModel.JobData {
 	jobData := dataModel.JobData{Id: jobId,
 		AccountId:    accountId,
 		InvocationId: job.InvocationId,
 		Memory:       job.Memory,
-		Status:       JOB_STARTED,
-		Source:       dataModel.Source{Name: job.Source.Name, Connection: job.Source.Connection},
-		Description:  job.Description,
+		Status:       model.JOB_STARTED,
+		Source: dataModel.Source{Name: job.Source.Name,
+			Connection: job.Source.Connection,
+			Format:     toDataFormat(job.Source.Format)},
+		Description: job.Description,
+		Instances:   instances,
+		Output:      toDataFormat(job.Output),
 	}
 
+	if job.Autoscale != nil {
+		jobData.Autoscale = &dataModel.Autoscale{MinWorkers: job.Autoscale.MinWorkers,
+			MaxWorkers:   job.Autoscale.MaxWorkers,
+			ScaleUpLag:   job.Autoscale.ScaleUpLag,
+			ScaleDownLag: job.Autoscale.ScaleDownLag}
+	}
+
+	// Parameters are kept so workers can be started when the job is rescaled.
 	var functions = make([]dataModel.Function, 0)
 	for _, function := range job.Functions {
 		functions = append(functions, dataModel.Function{Name: function.Name,
-			Evaluator: function.Evaluator})
+			Parameters: function.Parameters,
+			Evaluator:  function.Evaluator})
 	}
 
 	jobData.Functions = functions
@@ -173,16 +203,18 @@ func (s *StreamService) stopJob(c *gin.Context) {
 	}
 
 	mlog.Debug("Stopping job %s from account %s", jobId, accountId)
-	err := s.marathonCluster.StopJob(accountId, jobId)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	err := s.jobCluster.StopJob(accountId, jobId)
 	if err != nil {
-		apiErr := err.(*marathon.APIError)
-		if apiErr.ErrCode == marathon.ErrCodeNotFound {
+		if err == cluster.ErrJobNotFound {
 			mlog.Debug("Job %s not found on account %v", jobId, accountId)
 			s.deleteJob(accountId, jobId, c)
 			return
 		}
 
-		jobData := dataModel.JobData{Status: JOB_STOP_FAILED, ErrorDescr: err.Error()}
+		jobData := dataModel.JobData{Status: model.JOB_STOP_FAILED, ErrorDescr: err.Error()}
 		mErr := s.dataClient.UpdateJob(accountId, jobId, &jobData)
 		if mErr != nil {
 			mlog.Error("Failed to update job: %v", mErr)
@@ -212,3 +244,128 @@ func (s *StreamService) deleteJob(accountId string, jobId string, c *gin.Context
 	stats.DataDeleteJob.Incr()
 	c.String(http.StatusOK, "")
 }
+
+func toDataFormat(format *model.Format) *dataModel.Format {
+	if format == nil {
+		return nil
+	}
+
+	return &dataModel.Format{Type: format.Type,
+		Schema:    format.Schema,
+		SchemaId:  format.SchemaId,
+		Message:   format.Message,
+		Columns:   format.Columns,
+		Delimiter: format.Delimiter}
+}
+
+// updateJob swaps the functions chain of a running job. Workers pick up the
+// new revision in place, without restarting their consumers.
+func (s *StreamService) updateJob(c *gin.Context) {
+	accountId, jobId, ok := getJobParams(c)
+	if !ok {
+		return
+	}
+
+	var update = new(model.JobUpdate)
+	if err := c.Bind(update); err != nil {
+		mlog.Error("Bind error: %v", err)
+		stats.ErrBindJob.Incr()
+		return
+	}
+
+	if err := update.Validate(); err != nil {
+		mlog.Error("Failed to validate: %v", err)
+		stats.ErrValidateJob.Incr()
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	job, mErr := s.dataClient.GetJob(accountId, jobId)
+	if mErr != nil {
+		stats.ErrUpdateJob.Incr()
+		c.JSON(mErr.HttpStatus, mErr)
+		return
+	}
+
+	jobData := dataModel.JobData{Revision: job.Revision + 1, Output: toDataFormat(update.Output)}
+	for _, function := range update.Functions {
+		jobData.Functions = append(jobData.Functions, dataModel.Function{Name: function.Name,
+			Parameters: function.Parameters,
+			Evaluator:  function.Evaluator})
+	}
+
+	if mErr := s.dataClient.UpdateJob(accountId, jobId, &jobData); mErr != nil {
+		mlog.Error("Failed to update job: %v", mErr)
+		stats.ErrDataUpdateJob.Incr()
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(mErr.Error()))
+		return
+	}
+
+	stats.UpdateJob.Incr()
+	c.String(http.StatusOK, "")
+}
+
+// pauseJob makes the workers stop consuming. They keep their state and do
+// not acknowledge further offsets until the job is resumed.
+func (s *StreamService) pauseJob(c *gin.Context) {
+	s.setJobStatus(c, model.JOB_STARTED, model.JOB_PAUSED)
+}
+
+func (s *StreamService) resumeJob(c *gin.Context) {
+	s.setJobStatus(c, model.JOB_PAUSED, model.JOB_STARTED)
+}
+
+func (s *StreamService) setJobStatus(c *gin.Context, from string, to string) {
+	accountId, jobId, ok := getJobParams(c)
+	if !ok {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	job, mErr := s.dataClient.GetJob(accountId, jobId)
+	if mErr != nil {
+		stats.ErrUpdateJob.Incr()
+		c.JSON(mErr.HttpStatus, mErr)
+		return
+	}
+
+	if job.Status != from {
+		stats.ErrUpdateJob.Incr()
+		c.JSON(http.StatusConflict, management.NewError(http.StatusConflict, "conflict",
+			fmt.Sprintf("Job is %s, expected %s", job.Status, from)))
+		return
+	}
+
+	if mErr := s.dataClient.UpdateJob(accountId, jobId, &dataModel.JobData{Status: to}); mErr != nil {
+		mlog.Error("Failed to update job: %v", mErr)
+		stats.ErrDataUpdateJob.Incr()
+		c.JSON(http.StatusInternalServerError, management.GetInternalError(mErr.Error()))
+		return
+	}
+
+	stats.UpdateJob.Incr()
+	c.String(http.StatusOK, "")
+}
+
+func getJobParams(c *gin.Context) (string, string, bool) {
+	accountId := c.Params.ByName("accountId")
+	if accountId == "" {
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError("accountId is empty"))
+		stats.ErrCheckAccountId.Incr()
+		return "", "", false
+	}
+
+	jobId := c.Params.ByName("jobId")
+	if jobId == "" {
+		stats.ErrCheckJobId.Incr()
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError("Job id is empty"))
+		return "", "", false
+	}
+
+	return accountId, jobId, true
+}
@@ -23,6 +23,8 @@ var (
 	StartJob      = s.NewCounter("StartJob")
 	StopJob       = s.NewCounter("StopJob")
 	DataDeleteJob = s.NewCounter("DataDeleteJob")
+	ScaleJob      = s.NewCounter("ScaleJob")
+	UpdateJob     = s.NewCounter("UpdateJob")
 
 	ErrDataDeleteJob      = s.NewCounter("ErrDataDeleteJob")
 	ErrDataAddJob         = s.NewCounter("ErrDataAddJob")
@@ -34,4 +36,7 @@ var (
 	ErrBindJob            = s.NewCounter("ErrBindJob")
 	ErrCheckAccountId     = s.NewCounter("ErrCheckAccountId")
 	ErrCheckJobId         = s.NewCounter("ErrCheckJobId")
+	ErrScaleJob           = s.NewCounter("ErrScaleJob")
+	ErrUpdateJob          = s.NewCounter("ErrUpdateJob")
+	ErrAutoscale          = s.NewCounter("ErrAutoscale")
 )
@@ -0,0 +1,148 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package codec
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/lavaorg/northstar/dpe-stream/config"
+	"github.com/lavaorg/northstar/dpe-stream/master/model"
+	"github.com/linkedin/goavro"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// Framed Avro messages start with a zero magic byte followed by the big
+// endian schema id, as written by Confluent serializers.
+const (
+	avroMagicByte  = 0
+	avroHeaderSize = 5
+)
+
+// SchemaRegistry resolves the Avro schema of framed messages.
+type SchemaRegistry interface {
+	GetSchema(id int) (string, error)
+}
+
+// LocalSchemaRegistry stands in for a schema registry service by reading
+// schemas from <id>.avsc files of a directory.
+type LocalSchemaRegistry struct {
+	Dir string
+}
+
+var LocalRegistry SchemaRegistry = &LocalSchemaRegistry{Dir: config.SchemaRegistryDir}
+
+func (r *LocalSchemaRegistry) GetSchema(id int) (string, error) {
+	schema, err := ioutil.ReadFile(filepath.Join(r.Dir, strconv.Itoa(id)+".avsc"))
+	if err != nil {
+		return "", fmt.Errorf("Schema %d not found in local registry: %v", id, err)
+	}
+
+	return string(schema), nil
+}
+
+// AvroCodec decodes plain Avro binary with the schema of the format, or
+// framed messages whose schema is looked up in the registry.
+type AvroCodec struct {
+	codec    *goavro.Codec
+	schemaId int
+	registry SchemaRegistry
+	lock     sync.Mutex
+	codecs   map[int]*goavro.Codec
+}
+
+func NewAvroCodec(format *model.Format, registry SchemaRegistry) (*AvroCodec, error) {
+	avro := &AvroCodec{schemaId: format.SchemaId, registry: registry, codecs: make(map[int]*goavro.Codec)}
+	if format.Schema == "" {
+		return avro, nil
+	}
+
+	codec, err := goavro.NewCodec(format.Schema)
+	if err != nil {
+		return nil, err
+	}
+	avro.codec = codec
+
+	return avro, nil
+}
+
+func (c *AvroCodec) Decode(message []byte) (interface{}, error) {
+	codec := c.codec
+	if codec == nil {
+		if len(message) < avroHeaderSize || message[0] != avroMagicByte {
+			return nil, fmt.Errorf("Avro message without schema id")
+		}
+
+		var err error
+		codec, err = c.getCodec(int(binary.BigEndian.Uint32(message[1:avroHeaderSize])))
+		if err != nil {
+			return nil, err
+		}
+		message = message[avroHeaderSize:]
+	}
+
+	value, _, err := codec.NativeFromBinary(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func (c *AvroCodec) Encode(value interface{}) ([]byte, error) {
+	if c.codec != nil {
+		return c.codec.BinaryFromNative(nil, normalize(value))
+	}
+
+	if c.schemaId == 0 {
+		return nil, fmt.Errorf("Avro output requires a schema or a schema id")
+	}
+
+	codec, err := c.getCodec(c.schemaId)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, avroHeaderSize)
+	header[0] = avroMagicByte
+	binary.BigEndian.PutUint32(header[1:], uint32(c.schemaId))
+	return codec.BinaryFromNative(header, normalize(value))
+}
+
+func (c *AvroCodec) getCodec(id int) (*goavro.Codec, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if codec, ok := c.codecs[id]; ok {
+		return codec, nil
+	}
+
+	schema, err := c.registry.GetSchema(id)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	c.codecs[id] = codec
+	return codec, nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/vmihailenco/msgpack"
+)
+
+// RawCodec passes messages through unchanged.
+type RawCodec struct{}
+
+func (c *RawCodec) Decode(message []byte) (interface{}, error) {
+	return message, nil
+}
+
+func (c *RawCodec) Encode(value interface{}) ([]byte, error) {
+	switch converted := value.(type) {
+	case string:
+		return []byte(converted), nil
+	case []interface{}:
+		out := make([]byte, len(converted))
+		for i, b := range converted {
+			n, ok := b.(float64)
+			if !ok || n < 0 || n > 255 {
+				return nil, fmt.Errorf("raw output has to be a string or a byte table")
+			}
+			out[i] = byte(n)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("raw output has to be a string or a byte table")
+	}
+}
+
+type JsonCodec struct{}
+
+func (c *JsonCodec) Decode(message []byte) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal(message, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func (c *JsonCodec) Encode(value interface{}) ([]byte, error) {
+	return json.Marshal(normalize(value))
+}
+
+type MsgpackCodec struct{}
+
+func (c *MsgpackCodec) Decode(message []byte) (interface{}, error) {
+	var value interface{}
+	if err := msgpack.Unmarshal(message, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func (c *MsgpackCodec) Encode(value interface{}) ([]byte, error) {
+	return msgpack.Marshal(normalize(value))
+}
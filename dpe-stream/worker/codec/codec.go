@@ -0,0 +1,94 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package codec
+
+import (
+	"fmt"
+	"github.com/lavaorg/northstar/dpe-stream/master/model"
+)
+
+// Decoder converts a raw stream message into Go values that can be
+// converted into Lua tables.
+type Decoder interface {
+	Decode(message []byte) (interface{}, error)
+}
+
+// Encoder converts a value produced by the stream functions into bytes.
+type Encoder interface {
+	Encode(value interface{}) ([]byte, error)
+}
+
+// NewDecoder returns the decoder for the format. Messages are passed through
+// as byte arrays when no format is set.
+func NewDecoder(format *model.Format) (Decoder, error) {
+	if format == nil {
+		return &RawCodec{}, nil
+	}
+
+	switch format.Type {
+	case model.FORMAT_RAW:
+		return &RawCodec{}, nil
+	case model.FORMAT_JSON:
+		return &JsonCodec{}, nil
+	case model.FORMAT_MSGPACK:
+		return &MsgpackCodec{}, nil
+	case model.FORMAT_CSV:
+		return NewCsvCodec(format)
+	case model.FORMAT_AVRO:
+		return NewAvroCodec(format, LocalRegistry)
+	case model.FORMAT_PROTOBUF:
+		return NewProtobufCodec(format)
+	default:
+		return nil, fmt.Errorf("Unknown format: %v", format.Type)
+	}
+}
+
+// NewEncoder returns the encoder for the format, or nil if no format is set.
+func NewEncoder(format *model.Format) (Encoder, error) {
+	if format == nil {
+		return nil, nil
+	}
+
+	decoder, err := NewDecoder(format)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every codec implements both directions.
+	return decoder.(Encoder), nil
+}
+
+// normalize turns the map[interface{}]interface{} tables produced by
+// util.FromLua into map[string]interface{} so they can be encoded.
+func normalize(value interface{}) interface{} {
+	switch converted := value.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(converted))
+		for k, v := range converted {
+			out[fmt.Sprint(k)] = normalize(v)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(converted))
+		for i, v := range converted {
+			out[i] = normalize(v)
+		}
+		return out
+	default:
+		return value
+	}
+}
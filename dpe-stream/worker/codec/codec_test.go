@@ -0,0 +1,129 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package codec
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lavaorg/northstar/dpe-stream/master/model"
+)
+
+func TestRawDecoderIsDefault(t *testing.T) {
+	decoder, err := NewDecoder(nil)
+	if err != nil {
+		t.Fatalf("Failed to create decoder: %v", err)
+	}
+
+	value, err := decoder.Decode([]byte("abc"))
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+
+	if !reflect.DeepEqual(value, []byte("abc")) {
+		t.Errorf("Expected message bytes, got %v", value)
+	}
+
+	if encoder, _ := NewEncoder(nil); encoder != nil {
+		t.Errorf("Expected no encoder without format")
+	}
+}
+
+func TestJsonCodec(t *testing.T) {
+	codec, err := NewDecoder(&model.Format{Type: model.FORMAT_JSON})
+	if err != nil {
+		t.Fatalf("Failed to create decoder: %v", err)
+	}
+
+	value, err := codec.Decode([]byte(`{"id": 1, "tags": ["a"]}`))
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+
+	expected := map[string]interface{}{"id": float64(1), "tags": []interface{}{"a"}}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("Expected %v, got %v", expected, value)
+	}
+
+	out, err := codec.(Encoder).Encode(map[interface{}]interface{}{"id": float64(2)})
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+
+	if string(out) != `{"id":2}` {
+		t.Errorf("Unexpected encoding: %s", out)
+	}
+}
+
+func TestCsvCodec(t *testing.T) {
+	format := &model.Format{Type: model.FORMAT_CSV, Columns: []string{"device", "temp"}, Delimiter: ";"}
+	codec, err := NewDecoder(format)
+	if err != nil {
+		t.Fatalf("Failed to create decoder: %v", err)
+	}
+
+	value, err := codec.Decode([]byte("sensor-1;21.5\n"))
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+
+	expected := map[string]interface{}{"device": "sensor-1", "temp": 21.5}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("Expected %v, got %v", expected, value)
+	}
+
+	if _, err := codec.Decode([]byte("a;1\nb;2\n")); err == nil {
+		t.Errorf("Expected error for multiple records")
+	}
+
+	out, err := codec.(Encoder).Encode(map[interface{}]interface{}{"device": "sensor-2", "temp": float64(3)})
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+
+	if string(out) != "sensor-2;3\n" {
+		t.Errorf("Unexpected encoding: %q", out)
+	}
+}
+
+func TestMsgpackCodec(t *testing.T) {
+	encoder, err := NewEncoder(&model.Format{Type: model.FORMAT_MSGPACK})
+	if err != nil {
+		t.Fatalf("Failed to create encoder: %v", err)
+	}
+
+	out, err := encoder.Encode(map[interface{}]interface{}{"name": "x"})
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+
+	value, err := encoder.(Decoder).Decode(out)
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+
+	converted, ok := normalize(value).(map[string]interface{})
+	if !ok || converted["name"] != "x" {
+		t.Errorf("Unexpected round trip value: %v", value)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	if _, err := NewDecoder(&model.Format{Type: "xml"}); err == nil {
+		t.Errorf("Expected error for unknown format")
+	}
+}
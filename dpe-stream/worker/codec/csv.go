@@ -0,0 +1,126 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package codec
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"github.com/lavaorg/northstar/dpe-stream/master/model"
+	"strconv"
+)
+
+// CsvCodec handles messages holding a single CSV record. Records become
+// tables keyed by column name when columns are set, arrays otherwise.
+// Fields that parse as numbers are returned as numbers.
+type CsvCodec struct {
+	columns   []string
+	delimiter rune
+}
+
+func NewCsvCodec(format *model.Format) (*CsvCodec, error) {
+	codec := &CsvCodec{columns: format.Columns, delimiter: ','}
+	if format.Delimiter != "" {
+		codec.delimiter = []rune(format.Delimiter)[0]
+	}
+
+	return codec, nil
+}
+
+func (c *CsvCodec) Decode(message []byte) (interface{}, error) {
+	reader := csv.NewReader(bytes.NewReader(message))
+	reader.Comma = c.delimiter
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) != 1 {
+		return nil, fmt.Errorf("CSV message has %d records, expected one", len(records))
+	}
+	record := records[0]
+
+	if len(c.columns) == 0 {
+		row := make([]interface{}, len(record))
+		for i, field := range record {
+			row[i] = parseField(field)
+		}
+		return row, nil
+	}
+
+	if len(record) != len(c.columns) {
+		return nil, fmt.Errorf("CSV record has %d fields, expected %d", len(record), len(c.columns))
+	}
+
+	row := make(map[string]interface{}, len(record))
+	for i, field := range record {
+		row[c.columns[i]] = parseField(field)
+	}
+
+	return row, nil
+}
+
+func (c *CsvCodec) Encode(value interface{}) ([]byte, error) {
+	var record []string
+	switch converted := normalize(value).(type) {
+	case []interface{}:
+		for _, field := range converted {
+			record = append(record, formatField(field))
+		}
+	case map[string]interface{}:
+		if len(c.columns) == 0 {
+			return nil, fmt.Errorf("CSV columns are required to encode tables")
+		}
+
+		for _, column := range c.columns {
+			record = append(record, formatField(converted[column]))
+		}
+	default:
+		record = []string{formatField(converted)}
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Comma = c.delimiter
+	if err := writer.Write(record); err != nil {
+		return nil, err
+	}
+	writer.Flush()
+
+	return buf.Bytes(), writer.Error()
+}
+
+func parseField(field string) interface{} {
+	if number, err := strconv.ParseFloat(field, 64); err == nil {
+		return number
+	}
+
+	return field
+}
+
+func formatField(field interface{}) string {
+	switch converted := field.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(converted, 'f', -1, 64)
+	default:
+		return fmt.Sprint(converted)
+	}
+}
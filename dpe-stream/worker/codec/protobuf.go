@@ -0,0 +1,99 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package codec
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/lavaorg/northstar/dpe-stream/master/model"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ProtobufCodec decodes messages of a type described by a FileDescriptorSet,
+// as produced by protoc --include_imports --descriptor_set_out. Messages go
+// through their JSON mapping to become tables.
+type ProtobufCodec struct {
+	descriptor protoreflect.MessageDescriptor
+}
+
+func NewProtobufCodec(format *model.Format) (*ProtobufCodec, error) {
+	encoded, err := base64.StdEncoding.DecodeString(format.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to decode protobuf descriptor set: %v", err)
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(encoded, &set); err != nil {
+		return nil, fmt.Errorf("Unable to parse protobuf descriptor set: %v", err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(format.Message))
+	if err != nil {
+		return nil, err
+	}
+
+	message, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a protobuf message", format.Message)
+	}
+
+	return &ProtobufCodec{descriptor: message}, nil
+}
+
+func (c *ProtobufCodec) Decode(message []byte) (interface{}, error) {
+	msg := dynamicpb.NewMessage(c.descriptor)
+	if err := proto.Unmarshal(message, msg); err != nil {
+		return nil, err
+	}
+
+	out, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(out, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func (c *ProtobufCodec) Encode(value interface{}) ([]byte, error) {
+	in, err := json.Marshal(normalize(value))
+	if err != nil {
+		return nil, err
+	}
+
+	msg := dynamicpb.NewMessage(c.descriptor)
+	if err := protojson.Unmarshal(in, msg); err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(msg)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/data/stream/client"
+	"github.com/lavaorg/northstar/dpe-stream/config"
+	"github.com/lavaorg/northstar/dpe-stream/master/cluster"
+	"github.com/lavaorg/northstar/dpe-stream/master/model"
+	"github.com/lavaorg/northstar/dpe-stream/worker/source"
+	"github.com/lavaorg/northstar/dpe-stream/worker/stats"
+	"time"
+)
+
+// Watcher polls the job stored in the data service and applies pause, resume
+// and function updates requested through the master to a running receiver.
+type Watcher struct {
+	job        *cluster.StartJob
+	receiver   source.Receiver
+	dataClient client.Client
+	paused     bool
+	stop       chan struct{}
+}
+
+func NewWatcher(job *cluster.StartJob, receiver source.Receiver) (*Watcher, error) {
+	dataClient, err := client.NewStreamClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watcher{job: job,
+		receiver:   receiver,
+		dataClient: dataClient,
+		stop:       make(chan struct{})}, nil
+}
+
+// Run checks the job every DPE_STREAM_WORKER_CONTROL_INTERVAL seconds until
+// Stop is called.
+func (w *Watcher) Run() {
+	ticker := time.NewTicker(time.Duration(config.ControlInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		w.check()
+
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops watching the job and the receiver it controls.
+func (w *Watcher) Stop() {
+	close(w.stop)
+	w.receiver.Stop()
+}
+
+func (w *Watcher) check() {
+	jobData, mErr := w.dataClient.GetJob(w.job.AccountId, w.job.JobId)
+	if mErr != nil {
+		mlog.Error("Failed to get job %s: %v", w.job.JobId, mErr)
+		stats.ErrWatchJob.Incr()
+		return
+	}
+
+	if jobData.Revision > w.job.Revision {
+		job, err := cluster.NewStartJob(jobData, w.job.Instances)
+		if err != nil {
+			mlog.Error("Failed to read revision %d of job %s: %v", jobData.Revision, w.job.JobId, err)
+			stats.ErrUpdateJob.Incr()
+			return
+		}
+
+		if err = w.receiver.Update(job); err != nil {
+			mlog.Error("Failed to update job %s to revision %d: %v", w.job.JobId, jobData.Revision, err)
+			stats.ErrUpdateJob.Incr()
+			return
+		}
+
+		w.job = job
+		stats.UpdateJob.Incr()
+	}
+
+	switch {
+	case jobData.Status == model.JOB_PAUSED && !w.paused:
+		w.receiver.Pause()
+		w.paused = true
+		stats.PauseJob.Incr()
+	case jobData.Status == model.JOB_STARTED && w.paused:
+		w.receiver.Resume()
+		w.paused = false
+		stats.ResumeJob.Incr()
+	}
+}
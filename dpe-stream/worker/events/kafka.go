@@ -48,19 +48,21 @@ func NewKafkaEventsProducer() (*KafkaEventsProducer, error) {
 		return nil, err
 	}
 
-	return &KafkaEventsProducer{kafkaProducer: kafkaProducer}, nil
+	return &KafkaEventsProducer{msgQ: msgQ, kafkaProducer: kafkaProducer}, nil
 }
 
 func (p *KafkaEventsProducer) StreamOutput(job *cluster.StartJob,
 	stdout string,
 	stderr string,
-	result string) error {
+	result string,
+	data []byte) error {
 	event := &StreamOutputEvent{AccountId: job.AccountId,
 		JobId:        job.JobId,
 		InvocationId: job.InvocationId,
 		StdOut:       stdout,
 		StdErr:       stderr,
-		Result:       result}
+		Result:       result,
+		Data:         data}
 
 	outputByte, err := json.Marshal(event)
 	if err != nil {
@@ -78,3 +80,16 @@ func (p *KafkaEventsProducer) StreamOutput(job *cluster.StartJob,
 	stats.StreamOutput.Incr()
 	return nil
 }
+
+// Close closes the producer and the connection of its message queue.
+func (p *KafkaEventsProducer) Close() error {
+	if err := p.kafkaProducer.Close(); err != nil {
+		return err
+	}
+
+	if queue, ok := p.msgQ.(*msgq.MsgQ); ok {
+		return queue.Client.Close()
+	}
+
+	return nil
+}
@@ -23,4 +23,5 @@ type StreamOutputEvent struct {
 	StdOut       string
 	StdErr       string
 	Result       string
+	Data         []byte `json:",omitempty"`
 }
@@ -19,5 +19,6 @@ package events
 import "github.com/lavaorg/northstar/dpe-stream/master/cluster"
 
 type EventsProducer interface {
-	StreamOutput(job *cluster.StartJob, stdout string, stderr string, result string) error
+	StreamOutput(job *cluster.StartJob, stdout string, stderr string, result string, data []byte) error
+	Close() error
 }
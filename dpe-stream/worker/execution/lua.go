@@ -21,6 +21,7 @@ import (
 	"github.com/lavaorg/lrtx/mlog"
 	"github.com/lavaorg/lua"
 	"github.com/lavaorg/northstar/dpe-stream/master/cluster"
+	"github.com/lavaorg/northstar/dpe-stream/worker/codec"
 	"github.com/lavaorg/northstar/dpe-stream/worker/events"
 	"github.com/lavaorg/northstar/rte-lua/util"
 	"github.com/lavaorg/northstar/rte/repl"
@@ -38,10 +39,17 @@ const (
 
 type LuaExecutor struct {
 	eventsProducer events.EventsProducer
+	decoder        codec.Decoder
+	encoder        codec.Encoder
 }
 
-func NewLuaExecution(eventsProducer events.EventsProducer) (*LuaExecutor, error) {
-	return &LuaExecutor{eventsProducer: eventsProducer}, nil
+// NewLuaExecution returns an executor decoding messages with the decoder. When
+// encoder is set, the value left at the end of the functions chain is encoded
+// and sent with the output event.
+func NewLuaExecution(eventsProducer events.EventsProducer,
+	decoder codec.Decoder,
+	encoder codec.Encoder) (*LuaExecutor, error) {
+	return &LuaExecutor{eventsProducer: eventsProducer, decoder: decoder, encoder: encoder}, nil
 }
 
 func (e *LuaExecutor) ExecuteJob(message []byte, job *cluster.StartJob) (bool, error) {
@@ -60,7 +68,12 @@ func (e *LuaExecutor) ExecuteJob(message []byte, job *cluster.StartJob) (bool, e
 	defer state.Clean()
 	defer LuaPool.Put(state)
 
-	data, err = util.ToLua(state.LuaState, message)
+	decoded, err := e.decoder.Decode(message)
+	if err != nil {
+		return false, err
+	}
+
+	data, err = util.ToLua(state.LuaState, decoded)
 	if err != nil {
 		return false, err
 	}
@@ -103,6 +116,11 @@ L:
 		}
 	}
 
+	var encoded []byte
+	if err == nil && data != nil && e.encoder != nil {
+		encoded, err = e.encode(data)
+	}
+
 	elapsed := time.Since(start)
 	stdout := strings.Join(state.Output.Stdout, "")
 
@@ -113,7 +131,7 @@ L:
 
 	mlog.Debug("Processing time: %v, stdout: %v, stderr: %v, result : %v",
 		elapsed, stdout, stderr, state.Output.Result)
-	err = e.eventsProducer.StreamOutput(job, stdout, stderr, state.Output.Result)
+	err = e.eventsProducer.StreamOutput(job, stdout, stderr, state.Output.Result, encoded)
 	if err != nil {
 		mlog.Error("Failed to send output event: %v", err)
 		return false, err
@@ -122,6 +140,24 @@ L:
 	return false, nil
 }
 
+func (e *LuaExecutor) encode(data interface{}) ([]byte, error) {
+	value, ok := data.(lua.LValue)
+	if !ok {
+		return nil, errors.New("invalid output type")
+	}
+
+	if value == lua.LNil {
+		return nil, nil
+	}
+
+	converted, err := util.FromLua(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.encoder.Encode(converted)
+}
+
 func (e *LuaExecutor) executeLimit(msg interface{}, params []interface{}) (interface{}, error) {
 	mlog.Debug("Executing limit")
 	if len(params) != 1 {
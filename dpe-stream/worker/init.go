@@ -26,19 +26,16 @@ import (
 	"github.com/lavaorg/northstar/dpe-stream/master/cluster"
 	"github.com/lavaorg/northstar/dpe-stream/master/connection"
 	"github.com/lavaorg/northstar/dpe-stream/master/model"
+	"github.com/lavaorg/northstar/dpe-stream/worker/control"
 	"github.com/lavaorg/northstar/dpe-stream/worker/events"
+	"github.com/lavaorg/northstar/dpe-stream/worker/metrics"
+	"github.com/lavaorg/northstar/dpe-stream/worker/source"
 	"github.com/lavaorg/northstar/dpe-stream/worker/source/kafka"
 	"github.com/lavaorg/northstar/dpe-stream/worker/stats"
 	"os"
 )
 
 func StartWorker() error {
-	svcMaster := service_master.New(config.NumThreads, config.WorkerQueueCapacity)
-	eventsProducer, err := events.NewKafkaEventsProducer()
-	if err != nil {
-		return err
-	}
-
 	job, err := getStreamingJob()
 	if err != nil {
 		stats.ErrGetJob.Incr()
@@ -46,35 +43,77 @@ func StartWorker() error {
 		return err
 	}
 
-	err = job.Validate()
+	_, err = RunJob(job)
+	return err
+}
+
+// RunJob starts receiving messages for the job in the current process. The
+// returned worker also follows pause, resume and updates of the job.
+func RunJob(job *cluster.StartJob) (cluster.Worker, error) {
+	err := job.Validate()
 	if err != nil {
 		stats.ErrValidateJob.Incr()
-		return err
+		return nil, err
+	}
+
+	reporter, err := metrics.NewReporter(job)
+	if err != nil {
+		return nil, err
 	}
 
+	eventsProducer, err := events.NewKafkaEventsProducer()
+	if err != nil {
+		return nil, err
+	}
+
+	svcMaster := service_master.New(config.NumThreads, config.WorkerQueueCapacity)
+	receiver, err := newReceiver(job, svcMaster, eventsProducer, reporter)
+	if err != nil {
+		svcMaster.Stop()
+		eventsProducer.Close()
+		return nil, err
+	}
+
+	// The receiver owns the dispatcher and the producer from here on, and
+	// releases them when it is stopped.
+	go receiver.ReceiveMessages()
+
+	watcher, err := control.NewWatcher(job, receiver)
+	if err != nil {
+		receiver.Stop()
+		return nil, err
+	}
+
+	go watcher.Run()
+
+	stats.StartWorker.Incr()
+	return watcher, nil
+}
+
+func newReceiver(job *cluster.StartJob,
+	svcMaster *service_master.ServiceMaster,
+	eventsProducer events.EventsProducer,
+	reporter *metrics.Reporter) (source.Receiver, error) {
 	switch job.Source.Name {
 	case model.SOURCE_KAFKA:
 		connection, err := connection.MakeKafkaConnection(job.Source.Connection)
 		if err != nil {
 			stats.ErrCreateKafkaReceiver.Incr()
-			return err
+			return nil, err
 		}
 
-		receiver, err := kafka.NewKafkaReceiver(job, *connection, svcMaster, eventsProducer)
+		receiver, err := kafka.NewKafkaReceiver(job, *connection, svcMaster, eventsProducer, reporter)
 		if err != nil {
 			stats.ErrCreateKafkaReceiver.Incr()
-			return err
+			return nil, err
 		}
 
-		go receiver.ReceiveMessages()
+		return receiver, nil
 	default:
 		stats.ErrCreateReceiver.Incr()
 		mlog.Error("Unknown source selected: %v", job.Source.Name)
-		os.Exit(-1)
+		return nil, fmt.Errorf("Unknown source selected: %v", job.Source.Name)
 	}
-
-	stats.StartWorker.Incr()
-	return nil
 }
 
 func getStreamingJob() (*cluster.StartJob, error) {
@@ -0,0 +1,144 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/data/stream/client"
+	"github.com/lavaorg/northstar/data/stream/model"
+	"github.com/lavaorg/northstar/dpe-stream/config"
+	"github.com/lavaorg/northstar/dpe-stream/master/cluster"
+	"github.com/lavaorg/northstar/dpe-stream/worker/stats"
+	uuid "github.com/satori/go.uuid"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LagFunc returns the consumer lag per partition given the last processed
+// offset of every partition.
+type LagFunc func(offsets map[int32]int64) (map[int32]int64, error)
+
+// Reporter keeps the counters of a worker and sends them to the data service
+// as periodic heartbeats.
+type Reporter struct {
+	job        *cluster.StartJob
+	workerId   string
+	dataClient client.Client
+	processed  uint64
+	failed     uint64
+	lock       sync.Mutex
+	offsets    map[int32]int64
+	stop       chan struct{}
+}
+
+func NewReporter(job *cluster.StartJob) (*Reporter, error) {
+	dataClient, err := client.NewStreamClient()
+	if err != nil {
+		return nil, err
+	}
+
+	vuuid, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reporter{job: job,
+		workerId:   vuuid.String(),
+		dataClient: dataClient,
+		offsets:    make(map[int32]int64),
+		stop:       make(chan struct{})}, nil
+}
+
+// Processed records a message successfully processed at the given position.
+func (r *Reporter) Processed(partition int32, offset int64) {
+	atomic.AddUint64(&r.processed, 1)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if current, ok := r.offsets[partition]; !ok || offset > current {
+		r.offsets[partition] = offset
+	}
+}
+
+// Failed records a message that could not be processed.
+func (r *Reporter) Failed() {
+	atomic.AddUint64(&r.failed, 1)
+}
+
+// Run sends a heartbeat every DPE_STREAM_WORKER_HEARTBEAT_INTERVAL seconds
+// until Stop is called.
+func (r *Reporter) Run(lag LagFunc) {
+	interval := time.Duration(config.HeartbeatInterval) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastProcessed uint64
+	for {
+		r.heartbeat(lag, &lastProcessed)
+
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Reporter) Stop() {
+	close(r.stop)
+}
+
+func (r *Reporter) heartbeat(lag LagFunc, lastProcessed *uint64) {
+	processed := atomic.LoadUint64(&r.processed)
+	status := &model.WorkerStatus{WorkerId: r.workerId,
+		Heartbeat:  time.Now().In(time.UTC),
+		Interval:   config.HeartbeatInterval,
+		Processed:  processed,
+		Failed:     atomic.LoadUint64(&r.failed),
+		Throughput: float64(processed-*lastProcessed) / float64(config.HeartbeatInterval)}
+	*lastProcessed = processed
+
+	if lag != nil {
+		partitionLag, err := lag(r.copyOffsets())
+		if err != nil {
+			mlog.Error("Failed to get consumer lag: %v", err)
+			stats.ErrGetLag.Incr()
+		}
+		status.Lag = partitionLag
+	}
+
+	if mErr := r.dataClient.UpdateWorker(r.job.AccountId, r.job.JobId, status); mErr != nil {
+		mlog.Error("Failed to send heartbeat: %v", mErr)
+		stats.ErrHeartbeat.Incr()
+		return
+	}
+
+	stats.Heartbeat.Incr()
+}
+
+func (r *Reporter) copyOffsets() map[int32]int64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	offsets := make(map[int32]int64, len(r.offsets))
+	for partition, offset := range r.offsets {
+		offsets[partition] = offset
+	}
+
+	return offsets
+}
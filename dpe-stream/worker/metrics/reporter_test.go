@@ -0,0 +1,162 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/northstar/data/stream/model"
+	"github.com/lavaorg/northstar/dpe-stream/config"
+	"github.com/lavaorg/northstar/dpe-stream/master/cluster"
+)
+
+type fakeClient struct {
+	statuses []*model.WorkerStatus
+}
+
+func (c *fakeClient) AddJob(accountId string, data *model.JobData) *management.Error {
+	return nil
+}
+
+func (c *fakeClient) GetJob(accountId string, jobId string) (*model.JobData, *management.Error) {
+	return nil, nil
+}
+
+func (c *fakeClient) GetJobs(accountId string) ([]*model.JobData, *management.Error) {
+	return nil, nil
+}
+
+func (c *fakeClient) UpdateJob(accountId string, jobId string, update *model.JobData) *management.Error {
+	return nil
+}
+
+func (c *fakeClient) DeleteJob(accountId string, jobId string) *management.Error {
+	return nil
+}
+
+func (c *fakeClient) UpdateWorker(accountId string, jobId string, status *model.WorkerStatus) *management.Error {
+	c.statuses = append(c.statuses, status)
+	return nil
+}
+
+func (c *fakeClient) AddScalingEvent(accountId string, jobId string, event *model.ScalingEvent) *management.Error {
+	return nil
+}
+
+func (c *fakeClient) GetAllJobs() ([]*model.JobData, *management.Error) {
+	return nil, nil
+}
+
+func newTestReporter(dataClient *fakeClient) *Reporter {
+	return &Reporter{job: &cluster.StartJob{AccountId: "account", JobId: "job"},
+		workerId:   "worker",
+		dataClient: dataClient,
+		offsets:    make(map[int32]int64),
+		stop:       make(chan struct{})}
+}
+
+func TestHeartbeat(t *testing.T) {
+	dataClient := &fakeClient{}
+	reporter := newTestReporter(dataClient)
+
+	reporter.Processed(0, 5)
+	reporter.Processed(0, 3)
+	reporter.Processed(1, 7)
+	reporter.Failed()
+
+	var received map[int32]int64
+	lag := func(offsets map[int32]int64) (map[int32]int64, error) {
+		received = offsets
+		return map[int32]int64{0: 2, 1: 0}, nil
+	}
+
+	var lastProcessed uint64
+	reporter.heartbeat(lag, &lastProcessed)
+
+	if len(received) != 2 || received[0] != 5 || received[1] != 7 {
+		t.Errorf("Expected the highest offset of every partition, got %v", received)
+	}
+
+	if len(dataClient.statuses) != 1 {
+		t.Fatalf("Expected 1 heartbeat, got %d", len(dataClient.statuses))
+	}
+
+	status := dataClient.statuses[0]
+	if status.WorkerId != "worker" || status.Processed != 3 || status.Failed != 1 {
+		t.Errorf("Unexpected counters in heartbeat: %+v", status)
+	}
+
+	if status.Interval != config.HeartbeatInterval {
+		t.Errorf("Expected interval %d, got %d", config.HeartbeatInterval, status.Interval)
+	}
+
+	if status.Throughput != 3/float64(config.HeartbeatInterval) {
+		t.Errorf("Unexpected throughput %f", status.Throughput)
+	}
+
+	if status.Lag[0] != 2 || status.Lag[1] != 0 {
+		t.Errorf("Unexpected lag %v", status.Lag)
+	}
+
+	// Nothing processed since, so the throughput drops to zero.
+	reporter.heartbeat(lag, &lastProcessed)
+	if throughput := dataClient.statuses[1].Throughput; throughput != 0 {
+		t.Errorf("Expected no throughput, got %f", throughput)
+	}
+}
+
+func TestHeartbeatLagError(t *testing.T) {
+	dataClient := &fakeClient{}
+	reporter := newTestReporter(dataClient)
+
+	reporter.Processed(0, 1)
+	lag := func(offsets map[int32]int64) (map[int32]int64, error) {
+		return nil, errors.New("offsets not available")
+	}
+
+	var lastProcessed uint64
+	reporter.heartbeat(lag, &lastProcessed)
+
+	// The heartbeat is still sent, only without lag.
+	if len(dataClient.statuses) != 1 {
+		t.Fatalf("Expected 1 heartbeat, got %d", len(dataClient.statuses))
+	}
+
+	if status := dataClient.statuses[0]; status.Processed != 1 || status.Lag != nil {
+		t.Errorf("Unexpected heartbeat: %+v", status)
+	}
+}
+
+func TestRunStop(t *testing.T) {
+	dataClient := &fakeClient{}
+	reporter := newTestReporter(dataClient)
+
+	done := make(chan struct{})
+	reporter.Stop()
+	go func() {
+		reporter.Run(nil)
+		close(done)
+	}()
+	<-done
+
+	// Run reports once right away before it checks for stop.
+	if len(dataClient.statuses) != 1 {
+		t.Errorf("Expected 1 heartbeat, got %d", len(dataClient.statuses))
+	}
+}
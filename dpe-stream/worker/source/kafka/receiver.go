@@ -17,29 +17,55 @@ limitations under the License.
 package kafka
 
 import (
+	"errors"
 	"github.com/lavaorg/lrtx/mlog"
 	"github.com/lavaorg/lrtx/msgq"
 	"github.com/lavaorg/lrtx/service_master"
 	"github.com/lavaorg/northstar/dpe-stream/config"
 	"github.com/lavaorg/northstar/dpe-stream/master/cluster"
 	"github.com/lavaorg/northstar/dpe-stream/master/connection"
+	"github.com/lavaorg/northstar/dpe-stream/worker/codec"
 	"github.com/lavaorg/northstar/dpe-stream/worker/events"
+	"github.com/lavaorg/northstar/dpe-stream/worker/metrics"
 	"sync/atomic"
 	"time"
 )
 
+// Offsets Kafka reports for the next message produced to a partition and
+// for the oldest message retained by it.
+const (
+	OFFSET_NEWEST = -1
+	OFFSET_OLDEST = -2
+)
+
 type KafkaReceiver struct {
 	topicName      string
 	job            *cluster.StartJob
 	svcMaster      *service_master.ServiceMaster
+	msgQ           msgq.MessageQueue
 	consumer       msgq.MsgQConsumer
+	messages       <-chan *msgq.ConsumerEvent
+	dispatch       func(worker *KafkaWorker) error
 	eventsProducer events.EventsProducer
+	reporter       *metrics.Reporter
+	decoder        codec.Decoder
+	encoder        codec.Encoder
+	pause          chan bool
+	update         chan *pipeline
+	stop           chan struct{}
+}
+
+// pipeline is the functions chain and output encoder swapped in by Update.
+type pipeline struct {
+	job     *cluster.StartJob
+	encoder codec.Encoder
 }
 
 func NewKafkaReceiver(job *cluster.StartJob,
 	connection connection.KafkaConnection,
 	svcMaster *service_master.ServiceMaster,
-	eventsProducer events.EventsProducer) (*KafkaReceiver, error) {
+	eventsProducer events.EventsProducer,
+	reporter *metrics.Reporter) (*KafkaReceiver, error) {
 	msgQ, err := msgq.NewMsgQ(connection.Topic+"_"+job.AccountId, connection.Brokers, connection.ZK)
 	if err != nil {
 		mlog.Error("Error to create msgq: %v", err.Error())
@@ -55,37 +81,92 @@ func NewKafkaReceiver(job *cluster.StartJob,
 		return nil, err
 	}
 
-	for i := 0; i < len(job.Functions); i++ {
-		if err = (&(job.Functions[i])).Decode(); err != nil {
-			return nil, err
-		}
+	decoder, err := codec.NewDecoder(job.Source.Format)
+	if err != nil {
+		mlog.Error("Failed to create decoder: %v", err)
+		return nil, err
+	}
+
+	p, err := newPipeline(job)
+	if err != nil {
+		return nil, err
 	}
 
-	return &KafkaReceiver{job: job,
+	return &KafkaReceiver{job: p.job,
 		topicName:      connection.Topic,
 		svcMaster:      svcMaster,
+		msgQ:           msgQ,
 		consumer:       consumer,
-		eventsProducer: eventsProducer}, nil
+		messages:       consumer.Receive(),
+		eventsProducer: eventsProducer,
+		reporter:       reporter,
+		decoder:        decoder,
+		encoder:        p.encoder,
+		pause:          make(chan bool),
+		update:         make(chan *pipeline),
+		stop:           make(chan struct{}),
+		dispatch: func(worker *KafkaWorker) error {
+			return svcMaster.Dispatch(connection.Topic, worker)
+		}}, nil
+}
+
+func newPipeline(job *cluster.StartJob) (*pipeline, error) {
+	for i := 0; i < len(job.Functions); i++ {
+		if err := (&(job.Functions[i])).Decode(); err != nil {
+			return nil, err
+		}
+	}
+
+	encoder, err := codec.NewEncoder(job.Output)
+	if err != nil {
+		mlog.Error("Failed to create encoder: %v", err)
+		return nil, err
+	}
+
+	return &pipeline{job: job, encoder: encoder}, nil
 }
 
 func (r *KafkaReceiver) ReceiveMessages() {
+	defer r.close()
+	go r.reporter.Run(r.Lag)
+	r.receive()
+}
+
+// receive dispatches the received messages and applies pause, resume and
+// updates between them until the receiver is stopped.
+func (r *KafkaReceiver) receive() {
 	tickChan := time.NewTicker(time.Duration(config.MsgInterval) * time.Second).C
 	var cps uint64 = 0
 	for {
 		select {
-		case event := <-r.consumer.Receive():
+		case <-r.stop:
+			mlog.Info("Stopped receiving messages on topic %s", r.topicName)
+			return
+		case p := <-r.update:
+			r.swapPipeline(p)
+		case paused := <-r.pause:
+			if paused && !r.waitResume() {
+				return
+			}
+		case event := <-r.messages:
 			if event.Err != nil {
 				mlog.Error(event.Err.Error())
 				continue
 			}
 
-			worker, err := NewKafkaWorker(r.job, event, r.consumer, r.eventsProducer)
+			worker, err := NewKafkaWorker(r.job,
+				event,
+				r.consumer,
+				r.eventsProducer,
+				r.reporter,
+				r.decoder,
+				r.encoder)
 			if err != nil {
 				mlog.Error("Failed to create kafka worker: %v", err)
 				continue
 			}
 
-			if err := r.svcMaster.Dispatch(r.topicName, worker); err != nil {
+			if err := r.dispatch(worker); err != nil {
 				mlog.Error(err.Error())
 			}
 			atomic.AddUint64(&cps, 1)
@@ -100,3 +181,134 @@ func (r *KafkaReceiver) ReceiveMessages() {
 		}
 	}
 }
+
+// Stop stops receiving messages. The consumer, the dispatcher and the
+// events producer of the receiver are closed once the receiving loop returns.
+func (r *KafkaReceiver) Stop() {
+	r.reporter.Stop()
+	close(r.stop)
+}
+
+// close releases the resources of the receiver.
+func (r *KafkaReceiver) close() {
+	r.svcMaster.Stop()
+
+	if err := r.consumer.Close(); err != nil {
+		mlog.Error("Failed to close consumer of topic %s: %v", r.topicName, err)
+	}
+
+	if queue, ok := r.msgQ.(*msgq.MsgQ); ok {
+		if err := queue.Client.Close(); err != nil {
+			mlog.Error("Failed to close message queue of topic %s: %v", r.topicName, err)
+		}
+	}
+
+	if err := r.eventsProducer.Close(); err != nil {
+		mlog.Error("Failed to close events producer: %v", err)
+	}
+}
+
+// Pause stops taking messages from the consumer. Unread messages stay in
+// Kafka and the state of the functions is kept until Resume.
+func (r *KafkaReceiver) Pause() {
+	select {
+	case r.pause <- true:
+	case <-r.stop:
+	}
+}
+
+func (r *KafkaReceiver) Resume() {
+	select {
+	case r.pause <- false:
+	case <-r.stop:
+	}
+}
+
+// Update replaces the functions chain of the job. The consumer is kept, so
+// processing continues from the current position with the new chain.
+func (r *KafkaReceiver) Update(job *cluster.StartJob) error {
+	p, err := newPipeline(job)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case r.update <- p:
+	case <-r.stop:
+	}
+
+	return nil
+}
+
+// waitResume blocks while paused, it returns false if stopped meanwhile.
+func (r *KafkaReceiver) waitResume() bool {
+	mlog.Info("Paused receiving messages on topic %s", r.topicName)
+	for {
+		select {
+		case <-r.stop:
+			return false
+		case p := <-r.update:
+			r.swapPipeline(p)
+		case paused := <-r.pause:
+			if !paused {
+				mlog.Info("Resumed receiving messages on topic %s", r.topicName)
+				return true
+			}
+		}
+	}
+}
+
+func (r *KafkaReceiver) swapPipeline(p *pipeline) {
+	mlog.Info("Updating job %s to revision %d", p.job.JobId, p.job.Revision)
+	r.job = p.job
+	r.encoder = p.encoder
+}
+
+// Lag returns how many messages of each partition of the topic are still to
+// be processed. The partitions consumed by other workers of the job are
+// reported from their oldest message, the master keeps the smallest lag
+// reported for a partition.
+func (r *KafkaReceiver) Lag(offsets map[int32]int64) (map[int32]int64, error) {
+	queue, ok := r.msgQ.(*msgq.MsgQ)
+	if !ok {
+		return nil, errors.New("Message queue does not expose partition offsets")
+	}
+
+	partitions, err := queue.Client.Partitions(r.topicName)
+	if err != nil {
+		return nil, err
+	}
+
+	return partitionLag(partitions, offsets, func(partition int32, position int64) (int64, error) {
+		return queue.Client.GetOffset(r.topicName, partition, position)
+	})
+}
+
+// partitionLag returns the lag of every partition from the last processed
+// offset, or the oldest offset when none was processed, and the offset of
+// the next message produced to the partition. Stalled partitions and
+// partitions not processed yet thus report a lag too.
+func partitionLag(partitions []int32,
+	offsets map[int32]int64,
+	offset func(partition int32, position int64) (int64, error)) (map[int32]int64, error) {
+	lag := make(map[int32]int64, len(partitions))
+	for _, partition := range partitions {
+		next, err := offset(partition, OFFSET_NEWEST)
+		if err != nil {
+			return lag, err
+		}
+
+		processed, ok := offsets[partition]
+		if !ok {
+			oldest, err := offset(partition, OFFSET_OLDEST)
+			if err != nil {
+				return lag, err
+			}
+			processed = oldest - 1
+		}
+
+		lag[partition] = next - processed - 1
+	}
+
+	return lag, nil
+}
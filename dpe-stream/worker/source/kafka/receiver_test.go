@@ -0,0 +1,161 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lavaorg/lrtx/msgq"
+	"github.com/lavaorg/northstar/dpe-stream/master/cluster"
+)
+
+func TestPartitionLag(t *testing.T) {
+	newest := map[int32]int64{0: 10, 1: 25, 2: 0, 3: 40}
+	oldest := map[int32]int64{0: 0, 1: 0, 2: 0, 3: 15}
+	offsets := map[int32]int64{0: 9, 1: 19, 2: -1}
+
+	lag, err := partitionLag([]int32{0, 1, 2, 3}, offsets, func(partition int32, position int64) (int64, error) {
+		if position == OFFSET_OLDEST {
+			return oldest[partition], nil
+		}
+		return newest[partition], nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to get lag: %v", err)
+	}
+
+	expected := map[int32]int64{0: 0, 1: 5, 2: 0, 3: 25}
+	if len(lag) != len(expected) {
+		t.Fatalf("Expected lag %v, got %v", expected, lag)
+	}
+
+	for partition, value := range expected {
+		if lag[partition] != value {
+			t.Errorf("Partition %d: expected lag %d, got %d", partition, value, lag[partition])
+		}
+	}
+}
+
+func TestPartitionLagError(t *testing.T) {
+	_, err := partitionLag([]int32{0}, map[int32]int64{0: 1}, func(partition int32, position int64) (int64, error) {
+		return 0, errors.New("offset not available")
+	})
+	if err == nil {
+		t.Errorf("Expected error when the newest offset is not available")
+	}
+}
+
+// newTestReceiver returns a receiver reading from messages and handing the
+// workers it creates to workers.
+func newTestReceiver(job *cluster.StartJob,
+	messages chan *msgq.ConsumerEvent,
+	workers chan *KafkaWorker) *KafkaReceiver {
+	return &KafkaReceiver{job: job,
+		topicName: "topic",
+		messages:  messages,
+		pause:     make(chan bool),
+		update:    make(chan *pipeline),
+		stop:      make(chan struct{}),
+		dispatch: func(worker *KafkaWorker) error {
+			workers <- worker
+			return nil
+		}}
+}
+
+func receiveWorker(t *testing.T, messages chan *msgq.ConsumerEvent, workers chan *KafkaWorker) *KafkaWorker {
+	messages <- &msgq.ConsumerEvent{Value: []byte("message")}
+	select {
+	case worker := <-workers:
+		return worker
+	case <-time.After(time.Second):
+		t.Fatalf("Message was not dispatched")
+		return nil
+	}
+}
+
+func TestReceiverUpdate(t *testing.T) {
+	messages := make(chan *msgq.ConsumerEvent)
+	workers := make(chan *KafkaWorker)
+	job := &cluster.StartJob{JobId: "job", Revision: 1}
+	receiver := newTestReceiver(job, messages, workers)
+
+	done := make(chan struct{})
+	go func() {
+		receiver.receive()
+		close(done)
+	}()
+
+	if worker := receiveWorker(t, messages, workers); worker.job != job {
+		t.Errorf("Expected revision 1, got %d", worker.job.Revision)
+	}
+
+	updated := &cluster.StartJob{JobId: "job", Revision: 2}
+	if err := receiver.Update(updated); err != nil {
+		t.Fatalf("Failed to update receiver: %v", err)
+	}
+
+	if worker := receiveWorker(t, messages, workers); worker.job != updated {
+		t.Errorf("Expected revision 2, got %d", worker.job.Revision)
+	}
+
+	close(receiver.stop)
+	<-done
+}
+
+func TestReceiverPauseResume(t *testing.T) {
+	messages := make(chan *msgq.ConsumerEvent)
+	workers := make(chan *KafkaWorker)
+	job := &cluster.StartJob{JobId: "job", Revision: 1}
+	receiver := newTestReceiver(job, messages, workers)
+
+	done := make(chan struct{})
+	go func() {
+		receiver.receive()
+		close(done)
+	}()
+
+	receiver.Pause()
+
+	// Paused receivers leave the messages in the queue.
+	select {
+	case messages <- &msgq.ConsumerEvent{}:
+		t.Fatalf("Message was received while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Updates are still applied while paused.
+	updated := &cluster.StartJob{JobId: "job", Revision: 2}
+	if err := receiver.Update(updated); err != nil {
+		t.Fatalf("Failed to update receiver: %v", err)
+	}
+
+	receiver.Resume()
+	if worker := receiveWorker(t, messages, workers); worker.job != updated {
+		t.Errorf("Expected revision 2, got %d", worker.job.Revision)
+	}
+
+	// Stopping a paused receiver ends it as well.
+	receiver.Pause()
+	close(receiver.stop)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Paused receiver did not stop")
+	}
+}
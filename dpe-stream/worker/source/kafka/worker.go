@@ -21,8 +21,10 @@ import (
 	"github.com/lavaorg/lrtx/mlog"
 	"github.com/lavaorg/lrtx/msgq"
 	"github.com/lavaorg/northstar/dpe-stream/master/cluster"
+	"github.com/lavaorg/northstar/dpe-stream/worker/codec"
 	"github.com/lavaorg/northstar/dpe-stream/worker/events"
 	"github.com/lavaorg/northstar/dpe-stream/worker/execution"
+	"github.com/lavaorg/northstar/dpe-stream/worker/metrics"
 )
 
 type KafkaWorker struct {
@@ -30,13 +32,17 @@ type KafkaWorker struct {
 	job       *cluster.StartJob
 	event     *msgq.ConsumerEvent
 	consumer  msgq.MsgQConsumer
+	reporter  *metrics.Reporter
 }
 
 func NewKafkaWorker(job *cluster.StartJob,
 	event *msgq.ConsumerEvent,
 	consumer msgq.MsgQConsumer,
-	eventsProducer events.EventsProducer) (*KafkaWorker, error) {
-	luaExecution, err := execution.NewLuaExecution(eventsProducer)
+	eventsProducer events.EventsProducer,
+	reporter *metrics.Reporter,
+	decoder codec.Decoder,
+	encoder codec.Encoder) (*KafkaWorker, error) {
+	luaExecution, err := execution.NewLuaExecution(eventsProducer, decoder, encoder)
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +51,7 @@ func NewKafkaWorker(job *cluster.StartJob,
 		job:       job,
 		event:     event,
 		consumer:  consumer,
+		reporter:  reporter,
 	}, nil
 }
 
@@ -54,8 +61,10 @@ func (s *KafkaWorker) Run(n int) error {
 	terminate, err := s.execution.ExecuteJob(s.event.Value, s.job)
 	if err != nil {
 		mlog.Error("Failed to execute functions: %v", err)
+		s.reporter.Failed()
 		return err
 	}
+	s.reporter.Processed(s.event.Partition, s.event.Offset)
 
 	if terminate {
 		return fmt.Errorf("Streaming processing ended, should be shutting down worker")
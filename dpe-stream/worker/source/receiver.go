@@ -16,6 +16,12 @@ limitations under the License.
 
 package source
 
+import "github.com/lavaorg/northstar/dpe-stream/master/cluster"
+
 type Receiver interface {
 	ReceiveMessages()
+	Stop()
+	Pause()
+	Resume()
+	Update(job *cluster.StartJob) error
 }
@@ -22,10 +22,18 @@ var (
 	s            = stats.New("worker")
 	StartWorker  = s.NewCounter("StartWorker")
 	StreamOutput = s.NewCounter("SreamOutput")
+	Heartbeat    = s.NewCounter("Heartbeat")
+	PauseJob     = s.NewCounter("PauseJob")
+	ResumeJob    = s.NewCounter("ResumeJob")
+	UpdateJob    = s.NewCounter("UpdateJob")
 
 	ErrGetJob              = s.NewCounter("ErrGetJob")
 	ErrValidateJob         = s.NewCounter("ErrValidateJob")
 	ErrCreateKafkaReceiver = s.NewCounter("ErrCreateKafkaReceiver")
 	ErrCreateReceiver      = s.NewCounter("ErrCreateReceiver")
 	ErrStreamOutput        = s.NewCounter("ErrStreamOutput")
+	ErrHeartbeat           = s.NewCounter("ErrHeartbeat")
+	ErrGetLag              = s.NewCounter("ErrGetLag")
+	ErrWatchJob            = s.NewCounter("ErrWatchJob")
+	ErrUpdateJob           = s.NewCounter("ErrUpdateJob")
 )
@@ -27,6 +27,7 @@ import (
 const (
 	ExecutionsPath    = "executions"
 	CallbackUrlHeader = "x-vz-callback-url"
+	DebugUrlHeader    = "x-vz-debug-url"
 )
 
 // ExecuteCell executes the specified cell returning the output through the callback URL.
@@ -46,6 +47,25 @@ func (client *Client) ExecuteCell(accessToken, callbackUrl string, cell *model.C
 	return nil
 }
 
+// DebugCell executes the specified cell in debug mode. The debugger reports to, and reads commands from, the
+// debug URL while the output is returned through the callback URL.
+func (client *Client) DebugCell(accessToken, callbackUrl, debugUrl string, cell *model.Cell) *management.Error {
+	mlog.Debug("DebugCell: callbackUrl:%s, debugUrl:%s", callbackUrl, debugUrl)
+
+	// Execute cell.
+	path := client.getResourcePath(ExecutionsPath) + "/cell"
+	headers := client.getRequestHeaders(accessToken)
+	headers[CallbackUrlHeader] = callbackUrl
+	headers[DebugUrlHeader] = debugUrl
+
+	// If error, return.
+	if _, mErr := client.lbClient.PostJSONWithHeaders(path, cell, headers); mErr != nil {
+		return mErr
+	}
+
+	return nil
+}
+
 // ExecuteTransformation executes the specified transformation returning the output through the callback URL.
 func (client *Client) ExecuteTransformation(accessToken, callbackUrl string, transformation *model.Transformation) *management.Error {
 	mlog.Debug("ExecuteTransformation: callbackUrl:%s", callbackUrl)
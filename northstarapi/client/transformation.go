@@ -27,6 +27,7 @@ import (
 
 const (
 	TransformationsPath = "transformations"
+	PolicyPath          = "policy"
 )
 
 // CreateTransformation creates a new transformation object.
@@ -92,6 +93,27 @@ func (client *Client) GetTransformationResults(accessToken string, transformatio
 
 }
 
+// ExportTransformationResults exports the latest table results of a transformation to a file of a bucket.
+func (client *Client) ExportTransformationResults(accessToken string, transformationID string, resultsExport *model.ResultsExport) (*model.ResultsExport, *management.Error) {
+	mlog.Debug("ExportTransformationResults")
+
+	path := client.getResourcePath(TransformationsPath) + "/" + transformationID + "/results/export"
+	headers := client.getRequestHeaders(accessToken)
+
+	//If error, return
+	response, mErr := client.lbClient.PostJSONWithHeaders(path, resultsExport, headers)
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	exported := &model.ResultsExport{}
+	if err := json.Unmarshal(response, exported); err != nil {
+		return nil, management.GetInternalError(fmt.Sprintf("Failed to umarshal body with error: %v", err))
+	}
+
+	return exported, nil
+}
+
 // ListTransformations returns all transformation associated with the specidied access token.
 func (client *Client) ListTransformations(accessToken string) ([]model.Transformation, *management.Error) {
 	mlog.Debug("ListTransformations")
@@ -209,3 +231,38 @@ func (client *Client) DeleteSchedule(accessToken, transformationId string) *mana
 
 	return nil
 }
+
+// GetPolicy returns the policy of the specified transformation id, or of
+// the account when the transformation id is empty.
+func (client *Client) GetPolicy(accessToken, transformationId string) (*model.Policy, *management.Error) {
+	mlog.Debug("GetPolicy")
+
+	// Get policy.
+	path := client.getPolicyPath(transformationId)
+	headers := client.getRequestHeaders(accessToken)
+
+	response, mErr := client.lbClient.GetWithHeaders(path, headers)
+
+	// If error, return.
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	// Otherwise, return the policy.
+	policy := &model.Policy{}
+
+	if err := json.Unmarshal(response, policy); err != nil {
+		return nil, management.GetInternalError(fmt.Sprintf("Failed to umarshal body with error: %v", err))
+	}
+
+	return policy, nil
+}
+
+// Helper method used to get the path of the transformation, or account, policy.
+func (client *Client) getPolicyPath(transformationId string) string {
+	if transformationId == "" {
+		return client.getResourcePath(PolicyPath)
+	}
+
+	return client.getResourcePath(TransformationsPath) + "/" + transformationId + "/" + PolicyPath
+}
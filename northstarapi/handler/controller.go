@@ -34,6 +34,7 @@ import (
 const (
 	// Defines the supported custom headers.
 	CallBackHeader = "x-vz-callback-url"
+	DebugHeader    = "x-vz-debug-url"
 )
 
 // Defines the service controller. This type implements the
@@ -46,6 +47,7 @@ type Controller struct {
 	templateProvider       provider.TemplateProvider
 	objectProvider         provider.ObjectProvider
 	streamProvider         provider.StreamProvider
+	policyProvider         provider.PolicyProvider
 }
 
 // Returns a new Controller.
@@ -96,6 +98,11 @@ func NewController() (*Controller, error) {
 		return nil, fmt.Errorf("Failed to create jobs provider with error: %+v", err)
 	}
 
+	policyProvider, err := northstar.NewNorthStarPolicyProvider()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create policy provider with error: %+v", err)
+	}
+
 	// Create the controller
 	controller := &Controller{
 		accountProvider:        nil, //RAU:TODO: need to decide what to do here
@@ -105,6 +112,7 @@ func NewController() (*Controller, error) {
 		templateProvider:       templateProvider,
 		objectProvider:         objectProvider,
 		streamProvider:         streamProvider,
+		policyProvider:         policyProvider,
 	}
 
 	return controller, nil
@@ -213,3 +221,28 @@ func (controller *Controller) getCallbackUrl(context *gin.Context) (string, erro
 
 	return value, nil
 }
+
+// Helper method used to set the debugger endpoint of a cell run in debug
+// mode from the debug url header.
+func (controller *Controller) setDebugUrl(context *gin.Context, cell *model.Cell) error {
+	mlog.Debug("setDebugUrl")
+
+	if cell.Input.Debug == nil {
+		return nil
+	}
+
+	// Get header
+	value := context.Request.Header.Get(DebugHeader)
+
+	if value == "" {
+		return fmt.Errorf("Debug url is empty")
+	}
+
+	// Validate the value.
+	if _, err := url.Parse(value); err != nil {
+		return fmt.Errorf("Parse url returned error: %+v", err)
+	}
+
+	cell.Input.Debug.Endpoint = value
+	return nil
+}
@@ -389,6 +389,14 @@ func (controller *Controller) ExecuteNotebookCell(context *gin.Context) {
 		return
 	}
 
+	// Get debug url.
+	if err := controller.setDebugUrl(context, cell); err != nil {
+		mlog.Error("Failed to execute notebook cell with error: %v.", err)
+		utils.ErrExecuteNotebookCell.Incr()
+		controller.RenderServiceError(context, model.ErrorInvalidDebugUrl)
+		return
+	}
+
 	// Get the notebook owner.
 	executionUser, mErr := controller.notebookProvider.GetExecutionInformation(user, notebookId)
 
@@ -463,6 +471,14 @@ func (controller *Controller) ExecuteCell(context *gin.Context) {
 		return
 	}
 
+	// Get debug url.
+	if err := controller.setDebugUrl(context, cell); err != nil {
+		mlog.Error("Failed to execute cell with error: %v.", err)
+		utils.ErrExecuteCell.Incr()
+		controller.RenderServiceError(context, model.ErrorInvalidDebugUrl)
+		return
+	}
+
 	executionRequest, mErr := controller.executionProvider.ExecuteCell(user, cell, callbackUrl)
 	if mErr != nil {
 		mlog.Error("Execute cell returned error: %v", mErr)
@@ -0,0 +1,126 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/northstarapi/model"
+	"github.com/lavaorg/northstar/northstarapi/utils"
+)
+
+// Sets the policy of the specified account and transformation id. When no
+// transformation id is specified, the policy of the account is set. Only
+// registered on the internal endpoints, the accounts can not change their
+// own policies.
+func (controller *Controller) SetPolicy(context *gin.Context) {
+	mlog.Info("SetPolicy")
+
+	// Get the resource.
+	var policy model.Policy
+
+	// Validate request message
+	if err := controller.Bind(context, &policy); err != nil {
+		mlog.Error("Failed to set policy with error: %v.", err)
+		utils.ErrSetPolicy.Incr()
+		controller.RenderServiceError(context, model.ErrorParseRequestBody)
+		return
+	}
+
+	// Get resource ids, the transformation id is empty for the account
+	// policy.
+	accountId := strings.TrimSpace(context.Params.ByName("accountId"))
+	transformationId := strings.TrimSpace(context.Params.ByName("transformationId"))
+
+	if accountId == "" {
+		mlog.Error("Failed to set policy, account id is empty.")
+		utils.ErrSetPolicy.Incr()
+		controller.RenderServiceError(context, model.ErrorInvalidResourceId)
+		return
+	}
+
+	if mErr := controller.policyProvider.SetPolicy(accountId, transformationId, &policy); mErr != nil {
+		mlog.Error("Failed to set policy with error: %v", mErr)
+		utils.ErrSetPolicy.Incr()
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	utils.SetPolicy.Incr()
+	context.String(http.StatusNoContent, http.StatusText(http.StatusNoContent))
+}
+
+// Gets the policy of the specified transformation id, or of the account.
+func (controller *Controller) GetPolicy(context *gin.Context) {
+	mlog.Info("GetPolicy")
+
+	// Get resource id, empty for the account policy.
+	transformationId := strings.TrimSpace(context.Params.ByName("transformationId"))
+
+	// Get account id.
+	accountId, mErr := controller.getAccountId(context)
+
+	if mErr != nil {
+		mlog.Error("Failed to get account id with error: %v", mErr)
+		utils.ErrGetPolicy.Incr()
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	policy, mErr := controller.policyProvider.GetPolicy(accountId, transformationId)
+
+	if mErr != nil {
+		mlog.Error("Failed to get policy with error: %v", mErr)
+		utils.ErrGetPolicy.Incr()
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	utils.GetPolicy.Incr()
+	context.JSON(http.StatusOK, policy)
+}
+
+// Deletes the policy of the specified account and transformation id, or of
+// the account. Only registered on the internal endpoints.
+func (controller *Controller) DeletePolicy(context *gin.Context) {
+	mlog.Info("DeletePolicy")
+
+	// Get resource ids, the transformation id is empty for the account
+	// policy.
+	accountId := strings.TrimSpace(context.Params.ByName("accountId"))
+	transformationId := strings.TrimSpace(context.Params.ByName("transformationId"))
+
+	if accountId == "" {
+		mlog.Error("Failed to delete policy, account id is empty.")
+		utils.ErrDeletePolicy.Incr()
+		controller.RenderServiceError(context, model.ErrorInvalidResourceId)
+		return
+	}
+
+	if mErr := controller.policyProvider.DeletePolicy(accountId, transformationId); mErr != nil {
+		mlog.Error("Failed to delete policy with error: %v", mErr)
+		utils.ErrDeletePolicy.Incr()
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	utils.DeletePolicy.Incr()
+	context.String(http.StatusNoContent, http.StatusText(http.StatusNoContent))
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/northstarapi/model"
 	"github.com/lavaorg/northstar/northstarapi/utils"
 )
 
@@ -95,3 +96,86 @@ func (controller *Controller) RemoveStream(context *gin.Context) {
 	utils.RemoveStream.Incr()
 	context.String(http.StatusNoContent, http.StatusText(http.StatusNoContent))
 }
+
+func (controller *Controller) UpdateStream(context *gin.Context) {
+	mlog.Info("UpdateStream")
+	jobId := context.Params.ByName("id")
+
+	// Get account id.
+	accountId, mErr := controller.getAccountId(context)
+	if mErr != nil {
+		mlog.Error("Failed to get account id with error: %v", mErr)
+		utils.ErrUpdateStream.Incr()
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	update := &model.StreamUpdate{}
+	if err := controller.Bind(context, update); err != nil {
+		mlog.Error("Failed to update stream with error: %v.", err)
+		utils.ErrUpdateStream.Incr()
+		controller.RenderServiceError(context, model.ErrorParseRequestBody)
+		return
+	}
+
+	mErr = controller.streamProvider.UpdateStream(accountId, jobId, update)
+	if mErr != nil {
+		mlog.Error("Failed to update job with error: %v", mErr)
+		utils.ErrUpdateStream.Incr()
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	utils.UpdateStream.Incr()
+	context.String(http.StatusNoContent, http.StatusText(http.StatusNoContent))
+}
+
+func (controller *Controller) PauseStream(context *gin.Context) {
+	mlog.Info("PauseStream")
+	jobId := context.Params.ByName("id")
+
+	// Get account id.
+	accountId, mErr := controller.getAccountId(context)
+	if mErr != nil {
+		mlog.Error("Failed to get account id with error: %v", mErr)
+		utils.ErrPauseStream.Incr()
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	mErr = controller.streamProvider.PauseStream(accountId, jobId)
+	if mErr != nil {
+		mlog.Error("Failed to pause job with error: %v", mErr)
+		utils.ErrPauseStream.Incr()
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	utils.PauseStream.Incr()
+	context.String(http.StatusNoContent, http.StatusText(http.StatusNoContent))
+}
+
+func (controller *Controller) ResumeStream(context *gin.Context) {
+	mlog.Info("ResumeStream")
+	jobId := context.Params.ByName("id")
+
+	// Get account id.
+	accountId, mErr := controller.getAccountId(context)
+	if mErr != nil {
+		mlog.Error("Failed to get account id with error: %v", mErr)
+		utils.ErrResumeStream.Incr()
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	mErr = controller.streamProvider.ResumeStream(accountId, jobId)
+	if mErr != nil {
+		mlog.Error("Failed to resume job with error: %v", mErr)
+		utils.ErrResumeStream.Incr()
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	utils.ResumeStream.Incr()
+	context.String(http.StatusNoContent, http.StatusText(http.StatusNoContent))
+}
@@ -148,6 +148,56 @@ func (controller *Controller) GetTransformationResults(context *gin.Context) {
 	context.JSON(http.StatusOK, results)
 }
 
+// Exports the latest table results of the transformation to a file of a bucket.
+func (controller *Controller) ExportTransformationResults(context *gin.Context) {
+	mlog.Info("ExportTransformationResults")
+
+	//Get transformationID
+	transformationId := strings.TrimSpace(context.Params.ByName("transformationId"))
+	if transformationId == "" {
+		mlog.Error("Failed to export transformation results due to bad request -- Invalid resource id.")
+		utils.ErrExportTransformationResults.Incr()
+		controller.RenderServiceError(context, model.ErrorInvalidResourceId)
+		return
+	}
+
+	// Get the resource.
+	var resultsExport model.ResultsExport
+
+	// Validate request message
+	if err := controller.Bind(context, &resultsExport); err != nil {
+		mlog.Error("Failed to export transformation results with error: %v.", err)
+		utils.ErrExportTransformationResults.Incr()
+		controller.RenderServiceError(context, model.ErrorParseRequestBody)
+		return
+	}
+
+	if err := resultsExport.Validate(); err != nil {
+		mlog.Error("Failed to export transformation results with error: %v.", err)
+		utils.ErrExportTransformationResults.Incr()
+		controller.RenderServiceError(context, model.ErrorParseRequestBody)
+		return
+	}
+
+	accountId, mErr := controller.getAccountId(context)
+	if mErr != nil {
+		mlog.Error("Failed to get account id with error: %v", mErr)
+		utils.ErrExportTransformationResults.Incr()
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	if mErr := controller.transformationProvider.ExportResults(accountId, transformationId, &resultsExport); mErr != nil {
+		mlog.Error("Failed to export transformation results with error: %v", mErr)
+		utils.ErrExportTransformationResults.Incr()
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	utils.ExportTransformationResults.Incr()
+	context.JSON(http.StatusOK, resultsExport)
+}
+
 // Returns the transformations associated with the authenticated user.
 func (controller *Controller) ListTransformations(context *gin.Context) {
 	mlog.Info("ListTransformations")
@@ -26,7 +26,10 @@ import (
 )
 
 const (
-	ExecutionsPath = "callbacks"
+	ExecutionsPath      = "callbacks"
+	AccountsPath        = "accounts"
+	TransformationsPath = "transformations"
+	PolicyPath          = "policy"
 )
 
 // InternalClient defines the type used to represent a service client.
@@ -48,6 +51,34 @@ func (client *InternalClient) ExecutionCallback(response *model.ExecutionRespons
 	return nil
 }
 
+// SetPolicy sets the policy of the specified account and transformation id.
+// When the transformation id is empty, the policy of the account is set.
+func (client *InternalClient) SetPolicy(accountId, transformationId string, policy *model.Policy) *management.Error {
+	mlog.Debug("SetPolicy: accountId:%s, transformationId:%s", accountId, transformationId)
+	path := client.getPolicyPath(accountId, transformationId)
+
+	// If error, return.
+	if _, mErr := client.lbClient.PutJSON(path, policy); mErr != nil {
+		return mErr
+	}
+
+	return nil
+}
+
+// DeletePolicy deletes the policy of the specified account and transformation
+// id, or of the account when the transformation id is empty.
+func (client *InternalClient) DeletePolicy(accountId, transformationId string) *management.Error {
+	mlog.Debug("DeletePolicy: accountId:%s, transformationId:%s", accountId, transformationId)
+	path := client.getPolicyPath(accountId, transformationId)
+
+	// If error, return.
+	if mErr := client.lbClient.Delete(path); mErr != nil {
+		return mErr
+	}
+
+	return nil
+}
+
 // NewInternalClient returns a new instance of the internal client.
 func NewInternalClient(protocol, hostAndPort string) (*InternalClient, error) {
 	baseUrl := fmt.Sprintf("%s://%s", protocol, hostAndPort)
@@ -68,6 +99,17 @@ func (client InternalClient) getResourcePath(resource string) string {
 	return fmt.Sprintf("/%s/%s/%s", model.ContextInternal, model.Version, resource)
 }
 
+// getPolicyPath is a helper method used to get the path of the transformation,
+// or account, policy.
+func (client InternalClient) getPolicyPath(accountId, transformationId string) string {
+	path := client.getResourcePath(AccountsPath) + "/" + accountId
+	if transformationId != "" {
+		path += "/" + TransformationsPath + "/" + transformationId
+	}
+
+	return path + "/" + PolicyPath
+}
+
 // getRequestHeaders is a helper method used to get request headers.
 func (client InternalClient) getRequestHeaders(accessToken string) map[string]string {
 	header := map[string]string{}
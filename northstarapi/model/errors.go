@@ -31,8 +31,10 @@ var (
 	ErrorParseRequestBody     = &management.Error{HttpStatus: http.StatusBadRequest, Id: management.ERR_BAD_REQUEST, Description: "The request body is invalid. Failed to parse content."}
 	ErrorInvalidResourceId    = &management.Error{HttpStatus: http.StatusBadRequest, Id: management.ERR_BAD_REQUEST, Description: "The resource id is missing or invalid."}
 	ErrorInvalidCallbackUrl   = &management.Error{HttpStatus: http.StatusBadRequest, Id: management.ERR_BAD_REQUEST, Description: "The callback url is missing or invalid."}
+	ErrorInvalidDebugUrl      = &management.Error{HttpStatus: http.StatusBadRequest, Id: management.ERR_BAD_REQUEST, Description: "The debug url is missing or invalid."}
 	ErrorInvalidNotebookModel = &management.Error{HttpStatus: http.StatusBadRequest, Id: management.ERR_BAD_REQUEST, Description: "The notebook model is invalid or not supported."}
 	ErrorInvalidEventCategory = &management.Error{HttpStatus: http.StatusBadRequest, Id: management.ERR_BAD_REQUEST, Description: "The event category is invalid or not supported."}
+	ErrorInvalidPolicy        = &management.Error{HttpStatus: http.StatusBadRequest, Id: management.ERR_BAD_REQUEST, Description: "The policy is invalid. Module names and hosts can not be empty."}
 
 	ErrorLoginNameNotFound     = &management.Error{HttpStatus: http.StatusInternalServerError, Id: management.ERR_SERVICE_ERROR, Description: "The service was unable to find information about the authenticated user."}
 	ErrorToExternalNotebook    = &management.Error{HttpStatus: http.StatusInternalServerError, Id: management.ERR_SERVICE_ERROR, Description: "The service found an error while marshaling notebook."}
@@ -46,6 +48,8 @@ var (
 	ErrorNoNotebookUsrPermission  = &management.Error{HttpStatus: http.StatusForbidden, Id: management.ERR_FORBIDDEN, Description: "The user is not authorized to access or update notebook users."}
 	ErrorNoNotebookExecPermission = &management.Error{HttpStatus: http.StatusForbidden, Id: management.ERR_FORBIDDEN, Description: "The user is not authorized to execute notebook."}
 
+	ErrorNoTableResults = &management.Error{HttpStatus: http.StatusNotFound, Id: management.ERR_NOT_FOUND, Description: "The transformation has no table results to export."}
+
 	ErrorTransformationScheduled = &management.Error{HttpStatus: http.StatusConflict, Id: ERR_CONFLICT, Description: "The request could not be completed due to conflict with current transformation scheduled state. E.g., scheduled transformation can not be updated, deleted, etc."}
 	ErrorOperationDisabled       = &management.Error{HttpStatus: http.StatusForbidden, Id: management.ERR_FORBIDDEN, Description: "This operation is forbidden in the current environment."}
 )
@@ -43,4 +43,5 @@ type ExecutionRequest struct {
 	Timeout     int                    `json:"timeout"`
 	Callback    string                 `json:"callback"`
 	Memory      uint64                 `json:"memory,omitempty"`
+	Debug       *Debug                 `json:"debug,omitempty"`
 }
@@ -201,6 +201,18 @@ type Input struct {
 	EntryPoint string    `json:"entryPoint"`
 	Body       string    `json:"body"`
 	Timeout    int       `json:"timeout"`
+	Debug      *Debug    `json:"debug,omitempty"`
+}
+
+// Defines the type used to run a cell in debug mode. The debugger pauses on
+// the breakpoints, given as lines of the cell body, reports to the endpoint
+// and reads the commands of the client from it. The endpoint is not part of
+// the request body; it is taken from the debug url header, and the runtime
+// only connects to the debugger of the portal.
+type Debug struct {
+	Endpoint    string `json:"-"`
+	Breakpoints []int  `json:"breakpoints,omitempty"`
+	StopOnEntry bool   `json:"stopOnEntry,omitempty"`
 }
 
 // Defines the Output status code.
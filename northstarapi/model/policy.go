@@ -0,0 +1,29 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import "time"
+
+// Policy lists the modules the transformations of an account, or a single
+// transformation, may require and the hosts the network modules may
+// connect to, any module or host when the list is empty. The policy of a
+// transformation overrides the policy of its account.
+type Policy struct {
+	Modules   []string  `json:"modules"`
+	Hosts     []string  `json:"hosts,omitempty"`
+	UpdatedOn time.Time `json:"updatedOn,omitempty"`
+}
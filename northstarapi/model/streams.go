@@ -24,19 +24,74 @@ type Stream struct {
 	Memory      uint64     `json:"memory,omitempty"`
 	Source      Source     `json:"source,omitempty"`
 	Functions   []Function `json:"functions,omitempty"`
+	Output      *Format    `json:"output,omitempty"`
+	Revision    int        `json:"revision,omitempty"`
 	CreatedOn   time.Time  `json:"createdOn,omitempty"`
 	UpdatedOn   time.Time  `json:"updatedOn,omitempty"`
 	Status      string     `json:"status,omitempty"`
 	ErrorDescr  string     `json:"errorDescr,omitempty"`
 	Description string     `json:"description,omitempty"`
+
+	// Healthy is true when the job is started and all workers sent a
+	// heartbeat recently.
+	Healthy    bool               `json:"healthy"`
+	TotalLag   int64              `json:"totalLag"`
+	Workers    []StreamWorker     `json:"workers,omitempty"`
+	Throughput []StreamThroughput `json:"throughput,omitempty"`
+
+	// Worker count changes of an autoscaled stream, latest first.
+	ScalingEvents []StreamScalingEvent `json:"scalingEvents,omitempty"`
+}
+
+type StreamWorker struct {
+	Id         string          `json:"id,omitempty"`
+	Heartbeat  time.Time       `json:"heartbeat,omitempty"`
+	Alive      bool            `json:"alive"`
+	Processed  uint64          `json:"processed"`
+	Failed     uint64          `json:"failed"`
+	Throughput float64         `json:"throughput"`
+	Lag        map[int32]int64 `json:"lag,omitempty"`
+}
+
+type StreamThroughput struct {
+	Time      time.Time `json:"time,omitempty"`
+	WorkerId  string    `json:"workerId,omitempty"`
+	Processed uint64    `json:"processed"`
+	Failed    uint64    `json:"failed"`
+	Rate      float64   `json:"rate"`
+}
+
+type StreamScalingEvent struct {
+	Time        time.Time `json:"time,omitempty"`
+	FromWorkers int       `json:"fromWorkers"`
+	ToWorkers   int       `json:"toWorkers"`
+	Reason      string    `json:"reason,omitempty"`
+}
+
+// StreamUpdate replaces the functions chain, and optionally the output
+// format, of a running stream.
+type StreamUpdate struct {
+	Functions []Function `json:"functions,omitempty"`
+	Output    *Format    `json:"output,omitempty"`
 }
 
 type Source struct {
 	Name       string      `json:"name,omitempty"`
 	Connection interface{} `json:"connection,omitempty"`
+	Format     *Format     `json:"format,omitempty"`
+}
+
+type Format struct {
+	Type      string   `json:"type,omitempty"`
+	Schema    string   `json:"schema,omitempty"`
+	SchemaId  int      `json:"schemaId,omitempty"`
+	Message   string   `json:"message,omitempty"`
+	Columns   []string `json:"columns,omitempty"`
+	Delimiter string   `json:"delimiter,omitempty"`
 }
 
 type Function struct {
 	Name       string      `json:"name,omitempty"`
 	Parameters interface{} `json:"parameters,omitempty"`
+	Evaluator  interface{} `json:"evaluator,omitempty"`
 }
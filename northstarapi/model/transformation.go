@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	"github.com/lavaorg/northstar/northstarapi/nsapiglobal"
+	"github.com/lavaorg/northstar/rte-lua/modules/nsOutput/export"
 )
 
 const (
@@ -145,3 +146,35 @@ func (transformation *Transformation) Validate() error {
 	}
 	return nil
 }
+
+// Defines the type used to request the export of the transformation table
+// results to a file of a bucket.
+type ResultsExport struct {
+	Bucket   string `json:"bucket"`
+	FileName string `json:"fileName"`
+	Format   string `json:"format,omitempty"`
+	Rows     int    `json:"rows"`
+}
+
+// Validates the results export. When the format is not set, it is taken
+// from the file name extension.
+func (resultsExport *ResultsExport) Validate() error {
+	if resultsExport.Bucket == "" {
+		return errors.New("The bucket is missing.")
+	}
+
+	if resultsExport.FileName == "" {
+		return errors.New("The file name is missing.")
+	}
+
+	if resultsExport.Format == "" {
+		resultsExport.Format = export.FormatOf(resultsExport.FileName)
+	}
+
+	if export.ContentType(resultsExport.Format) == "" {
+		return fmt.Errorf("The format is missing or invalid. Supported formats are %s, %s and %s.",
+			export.CSV, export.JSONL, export.PARQUET)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidateResultsExport(t *testing.T) {
+	Convey("Test -- Format is taken from the file name.", t, func() {
+		resultsExport := ResultsExport{Bucket: "bucket", FileName: "results/counts.parquet"}
+		err := resultsExport.Validate()
+
+		So(err, ShouldBeNil)
+		So(resultsExport.Format, ShouldEqual, "parquet")
+	})
+
+	Convey("Test -- Explicit format overrides the file name.", t, func() {
+		resultsExport := ResultsExport{Bucket: "bucket", FileName: "counts", Format: "jsonl"}
+		err := resultsExport.Validate()
+
+		So(err, ShouldBeNil)
+		So(resultsExport.Format, ShouldEqual, "jsonl")
+	})
+
+	Convey("Test -- Missing bucket or file name is invalid.", t, func() {
+		resultsExport := ResultsExport{FileName: "counts.csv"}
+		So(resultsExport.Validate(), ShouldNotBeNil)
+
+		resultsExport = ResultsExport{Bucket: "bucket"}
+		So(resultsExport.Validate(), ShouldNotBeNil)
+	})
+
+	Convey("Test -- Unknown format is invalid.", t, func() {
+		resultsExport := ResultsExport{Bucket: "bucket", FileName: "counts.xlsx"}
+		So(resultsExport.Validate(), ShouldNotBeNil)
+
+		resultsExport = ResultsExport{Bucket: "bucket", FileName: "counts.csv", Format: "xml"}
+		So(resultsExport.Validate(), ShouldNotBeNil)
+	})
+}
@@ -142,6 +142,7 @@ func (provider *NorthStarExecutionProvider) ExecuteCell(user *model.User, cell *
 		Timeout:    cell.Input.Timeout,
 		Callback:   callback,
 		Memory:     cell.Settings.Memory,
+		Debug:      cell.Input.Debug,
 	}
 
 	// Execute the cell.
@@ -189,6 +190,12 @@ func (provider *NorthStarExecutionProvider) Execute(accountId string, execution
 		Callback: execution.Callback,
 		Memory:   execution.Memory * lfmt.MEGABYTE,
 	}
+
+	if execution.Debug != nil {
+		options.Debug = &rtepub.Debug{Endpoint: execution.Debug.Endpoint,
+			Breakpoints: execution.Debug.Breakpoints,
+			StopOnEntry: execution.Debug.StopOnEntry}
+	}
 	// Create the snippet.
 	snippet := &snippetsModel.Snippet{
 		Runtime: execution.Language,
@@ -0,0 +1,104 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package northstar
+
+import (
+	"time"
+
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lrtx/mlog"
+	policiesClient "github.com/lavaorg/northstar/data/policies/client"
+	policiesModel "github.com/lavaorg/northstar/data/policies/model"
+	"github.com/lavaorg/northstar/northstarapi/model"
+)
+
+// Defines the type used to support operations on NorthStar policies.
+type NorthStarPolicyProvider struct {
+	policiesClient *policiesClient.PoliciesClient
+}
+
+// Returns a new NorthStar policy provider.
+func NewNorthStarPolicyProvider() (*NorthStarPolicyProvider, error) {
+	mlog.Info("NewNorthStarPolicyProvider")
+
+	client, err := policiesClient.NewPoliciesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &NorthStarPolicyProvider{policiesClient: client}, nil
+}
+
+// Sets the policy of the transformation, or of the account when the
+// transformation id is empty.
+func (provider *NorthStarPolicyProvider) SetPolicy(accountId string,
+	transformationId string,
+	policy *model.Policy) *management.Error {
+	mlog.Debug("SetPolicy: transformationId:%s", transformationId)
+
+	data := &policiesModel.PolicyData{
+		SnippetId: getPolicySnippetId(transformationId),
+		Modules:   policy.Modules,
+		Hosts:     policy.Hosts,
+		UpdatedOn: time.Now(),
+	}
+
+	if err := data.Validate(); err != nil {
+		mlog.Error("Invalid policy: %v", err)
+		return model.ErrorInvalidPolicy
+	}
+
+	return provider.policiesClient.SetPolicy(accountId, data.SnippetId, data)
+}
+
+// Returns the policy of the transformation, or of the account when the
+// transformation id is empty.
+func (provider *NorthStarPolicyProvider) GetPolicy(accountId string,
+	transformationId string) (*model.Policy, *management.Error) {
+	mlog.Debug("GetPolicy: transformationId:%s", transformationId)
+
+	data, mErr := provider.policiesClient.GetPolicy(accountId, getPolicySnippetId(transformationId))
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	policy := &model.Policy{
+		Modules:   data.Modules,
+		Hosts:     data.Hosts,
+		UpdatedOn: data.UpdatedOn,
+	}
+
+	return policy, nil
+}
+
+// Deletes the policy of the transformation, or of the account when the
+// transformation id is empty.
+func (provider *NorthStarPolicyProvider) DeletePolicy(accountId string,
+	transformationId string) *management.Error {
+	mlog.Debug("DeletePolicy: transformationId:%s", transformationId)
+
+	return provider.policiesClient.DeletePolicy(accountId, getPolicySnippetId(transformationId))
+}
+
+// Helper method used to get the snippet id a policy is stored under.
+func getPolicySnippetId(transformationId string) string {
+	if transformationId == "" {
+		return policiesModel.ACCOUNT_POLICY
+	}
+
+	return transformationId
+}
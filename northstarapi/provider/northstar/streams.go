@@ -22,7 +22,9 @@ import (
 	dataStreamClient "github.com/lavaorg/northstar/data/stream/client"
 	dataStreamModel "github.com/lavaorg/northstar/data/stream/model"
 	dpeStreamClient "github.com/lavaorg/northstar/dpe-stream/master/client"
+	dpeStreamModel "github.com/lavaorg/northstar/dpe-stream/master/model"
 	"github.com/lavaorg/northstar/northstarapi/model"
+	"time"
 )
 
 // Defines the type used to support operations on NorthStar streams.
@@ -91,6 +93,52 @@ func (provider *NorthstarStreamProvider) RemoveStream(accountId string, jobId st
 	return nil
 }
 
+func (provider *NorthstarStreamProvider) PauseStream(accountId string, jobId string) *management.Error {
+	mlog.Info("PauseStream")
+	return provider.streamDpeClient.PauseJob(accountId, jobId)
+}
+
+func (provider *NorthstarStreamProvider) ResumeStream(accountId string, jobId string) *management.Error {
+	mlog.Info("ResumeStream")
+	return provider.streamDpeClient.ResumeJob(accountId, jobId)
+}
+
+func (provider *NorthstarStreamProvider) UpdateStream(accountId string,
+	jobId string,
+	update *model.StreamUpdate) *management.Error {
+	mlog.Info("UpdateStream")
+
+	jobUpdate := &dpeStreamModel.JobUpdate{}
+	if update.Output != nil {
+		jobUpdate.Output = &dpeStreamModel.Format{Type: update.Output.Type,
+			Schema:    update.Output.Schema,
+			SchemaId:  update.Output.SchemaId,
+			Message:   update.Output.Message,
+			Columns:   update.Output.Columns,
+			Delimiter: update.Output.Delimiter}
+	}
+
+	for _, function := range update.Functions {
+		var parameters []interface{}
+		if function.Parameters != nil {
+			var ok bool
+			if parameters, ok = function.Parameters.([]interface{}); !ok {
+				return management.GetBadRequestError("Invalid parameters of function " + function.Name)
+			}
+		}
+
+		jobUpdate.Functions = append(jobUpdate.Functions, dpeStreamModel.Function{Name: function.Name,
+			Parameters: parameters,
+			Evaluator:  function.Evaluator})
+	}
+
+	if err := jobUpdate.Validate(); err != nil {
+		return management.GetBadRequestError(err.Error())
+	}
+
+	return provider.streamDpeClient.UpdateJob(accountId, jobId, jobUpdate)
+}
+
 func (provider *NorthstarStreamProvider) fromExternalStream(externalJob *dataStreamModel.JobData) *model.Stream {
 	mlog.Info("fromExternalStream")
 
@@ -101,7 +149,10 @@ func (provider *NorthstarStreamProvider) fromExternalStream(externalJob *dataStr
 		Source: model.Source{
 			Name:       externalJob.Source.Name,
 			Connection: externalJob.Source.Connection,
+			Format:     fromExternalFormat(externalJob.Source.Format),
 		},
+		Output:      fromExternalFormat(externalJob.Output),
+		Revision:    externalJob.Revision,
 		CreatedOn:   externalJob.CreatedOn,
 		UpdatedOn:   externalJob.UpdatedOn,
 		Status:      externalJob.Status,
@@ -114,9 +165,81 @@ func (provider *NorthstarStreamProvider) fromExternalStream(externalJob *dataStr
 		functions = append(functions, model.Function{
 			Name:       externalFunction.Name,
 			Parameters: externalFunction.Parameters,
+			Evaluator:  externalFunction.Evaluator,
 		})
 	}
 	stream.Functions = functions
 
+	alive := []dataStreamModel.WorkerStatus{}
+	for _, externalWorker := range externalJob.Workers {
+		timeout := time.Duration(dpeStreamModel.MISSED_HEARTBEATS*externalWorker.Interval) * time.Second
+		worker := model.StreamWorker{
+			Id:         externalWorker.WorkerId,
+			Heartbeat:  externalWorker.Heartbeat,
+			Alive:      time.Since(externalWorker.Heartbeat) < timeout,
+			Processed:  externalWorker.Processed,
+			Failed:     externalWorker.Failed,
+			Throughput: externalWorker.Throughput,
+			Lag:        externalWorker.Lag,
+		}
+
+		if worker.Alive {
+			alive = append(alive, externalWorker)
+		}
+
+		stream.Workers = append(stream.Workers, worker)
+	}
+
+	for _, lag := range dataStreamModel.MergeLag(alive) {
+		stream.TotalLag += lag
+	}
+
+	for _, sample := range externalJob.Throughput {
+		stream.Throughput = append(stream.Throughput, model.StreamThroughput{
+			Time:      sample.Time,
+			WorkerId:  sample.WorkerId,
+			Processed: sample.Processed,
+			Failed:    sample.Failed,
+			Rate:      sample.Rate,
+		})
+	}
+
+	for _, event := range externalJob.ScalingEvents {
+		stream.ScalingEvents = append(stream.ScalingEvents, model.StreamScalingEvent{
+			Time:        event.Time,
+			FromWorkers: event.FromWorkers,
+			ToWorkers:   event.ToWorkers,
+			Reason:      event.Reason,
+		})
+	}
+
+	stream.Healthy = isStreamHealthy(stream)
 	return stream
 }
+
+func fromExternalFormat(format *dataStreamModel.Format) *model.Format {
+	if format == nil {
+		return nil
+	}
+
+	return &model.Format{Type: format.Type,
+		Schema:    format.Schema,
+		SchemaId:  format.SchemaId,
+		Message:   format.Message,
+		Columns:   format.Columns,
+		Delimiter: format.Delimiter}
+}
+
+func isStreamHealthy(stream *model.Stream) bool {
+	if stream.Status != dpeStreamModel.JOB_STARTED || len(stream.Workers) == 0 {
+		return false
+	}
+
+	for _, worker := range stream.Workers {
+		if !worker.Alive {
+			return false
+		}
+	}
+
+	return true
+}
@@ -32,6 +32,9 @@ import (
 	"github.com/lavaorg/northstar/northstarapi/model"
 	"github.com/lavaorg/northstar/northstarapi/provider/northstar/scheduler"
 	"github.com/lavaorg/northstar/northstarapi/provider/northstar/utils"
+	objectClient "github.com/lavaorg/northstar/object/client"
+	objectModel "github.com/lavaorg/northstar/object/model"
+	"github.com/lavaorg/northstar/rte-lua/modules/nsOutput/export"
 )
 
 // Defines the type used to support operations on NorthStar resources
@@ -39,6 +42,7 @@ import (
 type NorthStarTransformationProvider struct {
 	snippetClient    *snippets.SnippetsClient
 	invocationClient *invocation.InvocationClient
+	objectClient     *objectClient.ObjectClient
 }
 
 // Returns a new NorthStar transformation provider.
@@ -69,10 +73,16 @@ func NewNorthStarTransformationProvider() (*NorthStarTransformationProvider, err
 		return nil, err
 	}
 
+	objectsClient, err := objectClient.NewObjectClient()
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the provider.
 	provider := &NorthStarTransformationProvider{
 		snippetClient:    snippetsClient,
 		invocationClient: invocationsClient,
+		objectClient:     objectsClient,
 	}
 
 	return provider, nil
@@ -171,6 +181,63 @@ func (provider *NorthStarTransformationProvider) Results(accountID string,
 
 }
 
+// Export the latest table results of the transformation to a file of a bucket.
+func (provider *NorthStarTransformationProvider) ExportResults(accountID string,
+	transformationID string,
+	resultsExport *model.ResultsExport) *management.Error {
+	mlog.Debug("ExportResults -- transformationID: %s", transformationID)
+
+	results, mErr := provider.Results(accountID, transformationID)
+	if mErr != nil {
+		return mErr
+	}
+
+	// Find the latest execution with table results.
+	var latest *model.Output
+	for i := range results {
+		result := &results[i]
+		if result.ExecutionResults == nil || result.ExecutionResults.Type != model.TableResultType {
+			continue
+		}
+
+		if latest == nil || result.LastExecution.After(latest.LastExecution) {
+			latest = result
+		}
+	}
+
+	if latest == nil {
+		return model.ErrorNoTableResults
+	}
+
+	content, err := json.Marshal(latest.ExecutionResults.Content)
+	if err != nil {
+		return management.GetInternalError(fmt.Sprintf("Failed to marshal table results with error: %v", err))
+	}
+
+	table := &export.Table{}
+	if err := json.Unmarshal(content, table); err != nil {
+		return management.GetInternalError(fmt.Sprintf("Failed to unmarshal table results with error: %v", err))
+	}
+
+	data, err := export.Export(table, resultsExport.Format)
+	if err != nil {
+		return management.GetBadRequestError(fmt.Sprintf("Export table results returned error: %v", err))
+	}
+
+	upload := &objectModel.UploadData{
+		FileName:    resultsExport.FileName,
+		Payload:     data,
+		ContentType: export.ContentType(resultsExport.Format),
+	}
+
+	if _, mErr := provider.objectClient.UploadFile(accountID, resultsExport.Bucket, upload); mErr != nil {
+		return management.GetExternalError(fmt.Sprintf("Upload file returned error: %v", mErr))
+	}
+
+	resultsExport.Rows = len(table.Rows)
+	return nil
+}
+
 // Get snippet for the specified account and transformation id.
 func (provider *NorthStarTransformationProvider) Get(accountId string,
 	transformationId string) (*model.Transformation, *management.Error) {
@@ -47,6 +47,7 @@ type TransformationProvider interface {
 
 	// Defines the transformation execution results.
 	Results(accountID string, transformationID string) ([]model.Output, *management.Error)
+	ExportResults(accountID string, transformationID string, resultsExport *model.ResultsExport) *management.Error
 }
 
 // Defines the interface used to support notebook resource operations.
@@ -134,6 +135,20 @@ type ObjectProvider interface {
 	GetObject(user *model.User, bucket string, path string) (*model.Data, *management.Error)
 }
 
+//PolicyProvider defines the interface for managing the module and host
+//policies of an account and its transformations
+type PolicyProvider interface {
+	//SetPolicy sets the policy of the transformation, or of the account
+	//when the transformation id is empty
+	SetPolicy(accountId string, transformationId string, policy *model.Policy) *management.Error
+
+	//GetPolicy retrieves the policy of the transformation or account
+	GetPolicy(accountId string, transformationId string) (*model.Policy, *management.Error)
+
+	//DeletePolicy removes the policy of the transformation or account
+	DeletePolicy(accountId string, transformationId string) *management.Error
+}
+
 //StreamProvider defines the interface for supporting long running jobs
 type StreamProvider interface {
 	//ListStreams lists the jobs belonging to the user
@@ -144,4 +159,13 @@ type StreamProvider interface {
 
 	//RemoveStream removes the specified job
 	RemoveStream(accountId string, jobId string) *management.Error
+
+	//PauseStream stops the workers of the job from consuming until resumed
+	PauseStream(accountId string, jobId string) *management.Error
+
+	//ResumeStream resumes consuming of a paused job
+	ResumeStream(accountId string, jobId string) *management.Error
+
+	//UpdateStream replaces the functions of the job without restarting it
+	UpdateStream(accountId string, jobId string, update *model.StreamUpdate) *management.Error
 }
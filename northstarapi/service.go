@@ -71,6 +71,12 @@ func NewService() (*Service, error) {
 	internal := engine.Group(path.Join(model.ContextInternal, model.Version))
 	{
 		internal.POST("/callbacks/execution", controller.ExecutionCallback)
+
+		// Register Policy endpoints, policies are set by the operators.
+		internal.PUT("/accounts/:accountId/policy", controller.SetPolicy)
+		internal.DELETE("/accounts/:accountId/policy", controller.DeletePolicy)
+		internal.PUT("/accounts/:accountId/transformations/:transformationId/policy", controller.SetPolicy)
+		internal.DELETE("/accounts/:accountId/transformations/:transformationId/policy", controller.DeletePolicy)
 	}
 
 	// Register service APIs
@@ -86,6 +92,7 @@ func NewService() (*Service, error) {
 		v1.PUT("/transformations", controller.UpdateTransformation)
 		v1.GET("/transformations/:transformationId", controller.GetTransformation)
 		v1.GET("/transformations/:transformationId/results", controller.GetTransformationResults)
+		v1.POST("/transformations/:transformationId/results/export", controller.ExportTransformationResults)
 		v1.DELETE("/transformations/:transformationId", controller.DeleteTransformation)
 
 		// Register Transformation Schedule endpoints.
@@ -93,6 +100,10 @@ func NewService() (*Service, error) {
 		v1.POST("/transformations/:transformationId/schedule", controller.CreateSchedule)
 		v1.DELETE("/transformations/:transformationId/schedule", controller.DeleteSchedule)
 
+		// Register Policy endpoints, for the account and per transformation.
+		v1.GET("/policy", controller.GetPolicy)
+		v1.GET("/transformations/:transformationId/policy", controller.GetPolicy)
+
 		// Register Notebook endpoints.
 		v1.POST("/notebooks", controller.CreateNotebook)
 		v1.GET("/notebooks", controller.ListNotebooks)
@@ -109,6 +120,9 @@ func NewService() (*Service, error) {
 		v1.GET("/streams", controller.ListStreams)
 		v1.GET("/streams/:id", controller.GetStream)
 		v1.DELETE("/streams/:id", controller.RemoveStream)
+		v1.PUT("/streams/:id", controller.UpdateStream)
+		v1.POST("/streams/:id/actions/pause", controller.PauseStream)
+		v1.POST("/streams/:id/actions/resume", controller.ResumeStream)
 
 		// Register Executions endpoints. Note that these are for one off executions.
 
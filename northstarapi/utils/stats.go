@@ -59,20 +59,22 @@ var (
 	StopExecution       = Stats.NewCounter("StopExecution")
 	ErrStopExecution    = Stats.NewCounter("ErrStopExecution")
 
-	CreateTransformation     = Stats.NewCounter("CreateTransformation")
-	ErrCreateTransformation  = Stats.NewCounter("ErrCreateTransformation")
-	ListTransformations      = Stats.NewCounter("ListTransformations")
-	ErrListTransformations   = Stats.NewCounter("ErrListTransformations")
-	UpdateTransformation     = Stats.NewCounter("UpdateTransformation")
-	ErrUpdateTransformation  = Stats.NewCounter("ErrUpdateTransformation")
-	GetTransformation        = Stats.NewCounter("GetTransformation")
-	ErrGetTransformation     = Stats.NewCounter("ErrGetTransformation")
-	TransformationResults    = Stats.NewCounter("TransformationResults")
-	ErrTransformationResults = Stats.NewCounter("ErrTransformationResults")
-	DeleteTransformation     = Stats.NewCounter("DeleteTransformation")
-	ErrDeleteTransformation  = Stats.NewCounter("ErrDeleteTransformation")
-	ExecuteTransformation    = Stats.NewCounter("ExecuteTransformation")
-	ErrExecuteTransformation = Stats.NewCounter("ErrExecuteTransformation")
+	CreateTransformation           = Stats.NewCounter("CreateTransformation")
+	ErrCreateTransformation        = Stats.NewCounter("ErrCreateTransformation")
+	ListTransformations            = Stats.NewCounter("ListTransformations")
+	ErrListTransformations         = Stats.NewCounter("ErrListTransformations")
+	UpdateTransformation           = Stats.NewCounter("UpdateTransformation")
+	ErrUpdateTransformation        = Stats.NewCounter("ErrUpdateTransformation")
+	GetTransformation              = Stats.NewCounter("GetTransformation")
+	ErrGetTransformation           = Stats.NewCounter("ErrGetTransformation")
+	TransformationResults          = Stats.NewCounter("TransformationResults")
+	ErrTransformationResults       = Stats.NewCounter("ErrTransformationResults")
+	ExportTransformationResults    = Stats.NewCounter("ExportTransformationResults")
+	ErrExportTransformationResults = Stats.NewCounter("ErrExportTransformationResults")
+	DeleteTransformation           = Stats.NewCounter("DeleteTransformation")
+	ErrDeleteTransformation        = Stats.NewCounter("ErrDeleteTransformation")
+	ExecuteTransformation          = Stats.NewCounter("ExecuteTransformation")
+	ErrExecuteTransformation       = Stats.NewCounter("ErrExecuteTransformation")
 
 	CreateSchedule    = Stats.NewCounter("CreateSchedule")
 	ErrCreateSchedule = Stats.NewCounter("ErrCreateSchedule")
@@ -97,4 +99,17 @@ var (
 	ErrGetStream    = Stats.NewCounter("ErrGetStream")
 	RemoveStream    = Stats.NewCounter("RemoveStream")
 	ErrRemoveStream = Stats.NewCounter("ErrRemoveStream")
+	PauseStream     = Stats.NewCounter("PauseStream")
+	ErrPauseStream  = Stats.NewCounter("ErrPauseStream")
+	ResumeStream    = Stats.NewCounter("ResumeStream")
+	ErrResumeStream = Stats.NewCounter("ErrResumeStream")
+	UpdateStream    = Stats.NewCounter("UpdateStream")
+	ErrUpdateStream = Stats.NewCounter("ErrUpdateStream")
+
+	SetPolicy       = Stats.NewCounter("SetPolicy")
+	ErrSetPolicy    = Stats.NewCounter("ErrSetPolicy")
+	GetPolicy       = Stats.NewCounter("GetPolicy")
+	ErrGetPolicy    = Stats.NewCounter("ErrGetPolicy")
+	DeletePolicy    = Stats.NewCounter("DeletePolicy")
+	ErrDeletePolicy = Stats.NewCounter("ErrDeletePolicy")
 )
@@ -24,13 +24,48 @@ import (
 	"github.com/lavaorg/lrtx/mlog"
 	"github.com/lavaorg/northstar/object/model"
 	"github.com/lavaorg/northstar/object/util"
+	"net/url"
 )
 
 const (
-	BUCKETS_URI = util.ObjectBasePath + "/buckets"
-	FILES_URI   = util.ObjectBasePath + "/files"
+	BUCKETS_URI  = util.ObjectBasePath + "/buckets"
+	FILES_URI    = util.ObjectBasePath + "/files"
+	STAT_URI     = util.ObjectBasePath + "/stat"
+	COPY_URI     = util.ObjectBasePath + "/copy"
+	UPLOADS_URI  = util.ObjectBasePath + "/uploads"
+	VERSIONS_URI = util.ObjectBasePath + "/versions"
+	RESTORE_URI  = util.ObjectBasePath + "/restore"
+	SIGN_URI     = util.ObjectBasePath + "/sign"
 )
 
+// Client is the interface of the object service client.
+type Client interface {
+	CreateBucket(accountId string, bucket *model.Bucket) (string, *management.Error)
+	DeleteBucket(accountId string, bucketName string) *management.Error
+	ListBuckets(accountId string) ([]model.Bucket, *management.Error)
+	UploadFile(accountId string, bucketName string, data *model.UploadData) (string, *management.Error)
+	DownloadFile(accountId, bucketName, fileName string) (*model.DownloadData, *management.Error)
+	DeleteFile(accountId, bucketName, fileName string) *management.Error
+	ListFiles(accountId, bucketName string) ([]model.Object, *management.Error)
+	DownloadRange(accountId, bucketName, fileName string, offset, length int64) (*model.DownloadData, *management.Error)
+	StatFile(accountId, bucketName, fileName string) (*model.Object, *management.Error)
+	CopyFile(accountId, bucketName string, data *model.CopyData) *management.Error
+	CreateUpload(accountId, bucketName string, upload *model.Upload) (string, *management.Error)
+	UploadPart(accountId, bucketName, uploadId string, part *model.PartData) (*model.Part, *management.Error)
+	CompleteUpload(accountId, bucketName string, upload *model.Upload) *management.Error
+	AbortUpload(accountId, bucketName, uploadId, fileName string) *management.Error
+	FindFiles(accountId, bucketName string, filter *model.Filter) ([]model.Object, *management.Error)
+	SetVersioning(accountId, bucketName string, enabled bool) *management.Error
+	GetVersioning(accountId, bucketName string) (bool, *management.Error)
+	ListVersions(accountId, bucketName, fileName string) ([]model.Object, *management.Error)
+	RestoreVersion(accountId, bucketName string, data *model.RestoreData) *management.Error
+	SetLifecycle(accountId, bucketName string, lifecycle *model.Lifecycle) *management.Error
+	GetLifecycle(accountId, bucketName string) (*model.Lifecycle, *management.Error)
+	SetNotifications(accountId, bucketName string, notifications *model.Notifications) *management.Error
+	GetNotifications(accountId, bucketName string) (*model.Notifications, *management.Error)
+	SignUrl(accountId, bucketName string, request *model.SignRequest) (*model.SignedUrl, *management.Error)
+}
+
 type ObjectClient struct {
 	lbClient *lb.LbClient
 }
@@ -147,3 +182,253 @@ func (client *ObjectClient) ListFiles(accountId,
 
 	return out, nil
 }
+
+// DownloadRange downloads length bytes of the file from the offset, the
+// rest of the file when length is 0.
+func (client *ObjectClient) DownloadRange(accountId,
+	bucketName,
+	fileName string,
+	offset, length int64) (*model.DownloadData, *management.Error) {
+
+	path := fmt.Sprintf("%s/%s/%s/%s?offset=%d&length=%d", FILES_URI, accountId, bucketName, fileName, offset, length)
+	resp, mErr := client.lbClient.Get(path)
+	if mErr != nil {
+		mlog.Error("Object client: Error downloading range: %v", mErr.Error())
+		return nil, mErr
+	}
+
+	var data *model.DownloadData
+	if err := json.Unmarshal(resp, &data); err != nil {
+		return nil, management.GetInternalError(err.Error())
+	}
+
+	return data, nil
+}
+
+func (client *ObjectClient) StatFile(accountId, bucketName, fileName string) (*model.Object, *management.Error) {
+	path := fmt.Sprintf("%s/%s/%s/%s", STAT_URI, accountId, bucketName, fileName)
+	resp, mErr := client.lbClient.Get(path)
+	if mErr != nil {
+		mlog.Error("Object client: Error getting file metadata: %v", mErr.Error())
+		return nil, mErr
+	}
+
+	var out *model.Object
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return nil, management.GetInternalError(err.Error())
+	}
+
+	return out, nil
+}
+
+func (client *ObjectClient) CopyFile(accountId, bucketName string, data *model.CopyData) *management.Error {
+	path := fmt.Sprintf("%s/%s/%s", COPY_URI, accountId, bucketName)
+	if _, mErr := client.lbClient.PostJSON(path, data); mErr != nil {
+		mlog.Error("Object client: Error copying file: %v", mErr.Error())
+		return mErr
+	}
+	return nil
+}
+
+// CreateUpload starts a multipart upload of the file and returns its id.
+func (client *ObjectClient) CreateUpload(accountId, bucketName string, upload *model.Upload) (string, *management.Error) {
+	path := fmt.Sprintf("%s/%s/%s", UPLOADS_URI, accountId, bucketName)
+	resp, mErr := client.lbClient.PostJSON(path, upload)
+	if mErr != nil {
+		mlog.Error("Object client: Error creating upload: %v", mErr.Error())
+		return "", mErr
+	}
+
+	var out model.Upload
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return "", management.GetInternalError(err.Error())
+	}
+
+	return out.UploadId, nil
+}
+
+func (client *ObjectClient) UploadPart(accountId,
+	bucketName,
+	uploadId string,
+	part *model.PartData) (*model.Part, *management.Error) {
+
+	path := fmt.Sprintf("%s/%s/%s/%s/parts", UPLOADS_URI, accountId, bucketName, uploadId)
+	resp, mErr := client.lbClient.PostJSON(path, part)
+	if mErr != nil {
+		mlog.Error("Object client: Error uploading part: %v", mErr.Error())
+		return nil, mErr
+	}
+
+	var out *model.Part
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return nil, management.GetInternalError(err.Error())
+	}
+
+	return out, nil
+}
+
+func (client *ObjectClient) CompleteUpload(accountId, bucketName string, upload *model.Upload) *management.Error {
+	path := fmt.Sprintf("%s/%s/%s/%s", UPLOADS_URI, accountId, bucketName, upload.UploadId)
+	if _, mErr := client.lbClient.PostJSON(path, upload); mErr != nil {
+		mlog.Error("Object client: Error completing upload: %v", mErr.Error())
+		return mErr
+	}
+	return nil
+}
+
+func (client *ObjectClient) AbortUpload(accountId, bucketName, uploadId, fileName string) *management.Error {
+	path := fmt.Sprintf("%s/%s/%s/%s/%s", UPLOADS_URI, accountId, bucketName, uploadId, fileName)
+	return client.lbClient.Delete(path)
+}
+
+// FindFiles lists the files of the bucket matching the filter.
+func (client *ObjectClient) FindFiles(accountId,
+	bucketName string,
+	filter *model.Filter) ([]model.Object, *management.Error) {
+
+	query := url.Values{}
+	if filter.Prefix != "" {
+		query.Set("prefix", filter.Prefix)
+	}
+	for key, value := range filter.Tags {
+		query.Add("tag", key+"="+value)
+	}
+	for key, value := range filter.Metadata {
+		query.Add("metadata", key+"="+value)
+	}
+
+	path := fmt.Sprintf("%s/%s/%s?%s", FILES_URI, accountId, bucketName, query.Encode())
+	resp, mErr := client.lbClient.Get(path)
+	if mErr != nil {
+		mlog.Error("Object client: Error finding files: %v", mErr.Error())
+		return nil, mErr
+	}
+
+	var out []model.Object
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return nil, management.GetInternalError(err.Error())
+	}
+
+	return out, nil
+}
+
+func (client *ObjectClient) SetVersioning(accountId, bucketName string, enabled bool) *management.Error {
+	path := fmt.Sprintf("%s/%s/%s/versioning", BUCKETS_URI, accountId, bucketName)
+	if _, mErr := client.lbClient.PutJSON(path, &model.Versioning{Enabled: enabled}); mErr != nil {
+		mlog.Error("Object client: Error setting versioning: %v", mErr.Error())
+		return mErr
+	}
+	return nil
+}
+
+func (client *ObjectClient) GetVersioning(accountId, bucketName string) (bool, *management.Error) {
+	path := fmt.Sprintf("%s/%s/%s/versioning", BUCKETS_URI, accountId, bucketName)
+	resp, mErr := client.lbClient.Get(path)
+	if mErr != nil {
+		mlog.Error("Object client: Error getting versioning: %v", mErr.Error())
+		return false, mErr
+	}
+
+	var out model.Versioning
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return false, management.GetInternalError(err.Error())
+	}
+
+	return out.Enabled, nil
+}
+
+// ListVersions returns the versions of the file, newest first.
+func (client *ObjectClient) ListVersions(accountId, bucketName, fileName string) ([]model.Object, *management.Error) {
+	path := fmt.Sprintf("%s/%s/%s/%s", VERSIONS_URI, accountId, bucketName, fileName)
+	resp, mErr := client.lbClient.Get(path)
+	if mErr != nil {
+		mlog.Error("Object client: Error listing versions: %v", mErr.Error())
+		return nil, mErr
+	}
+
+	var out []model.Object
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return nil, management.GetInternalError(err.Error())
+	}
+
+	return out, nil
+}
+
+func (client *ObjectClient) RestoreVersion(accountId, bucketName string, data *model.RestoreData) *management.Error {
+	path := fmt.Sprintf("%s/%s/%s", RESTORE_URI, accountId, bucketName)
+	if _, mErr := client.lbClient.PostJSON(path, data); mErr != nil {
+		mlog.Error("Object client: Error restoring version: %v", mErr.Error())
+		return mErr
+	}
+	return nil
+}
+
+func (client *ObjectClient) SetLifecycle(accountId, bucketName string, lifecycle *model.Lifecycle) *management.Error {
+	path := fmt.Sprintf("%s/%s/%s/lifecycle", BUCKETS_URI, accountId, bucketName)
+	if _, mErr := client.lbClient.PutJSON(path, lifecycle); mErr != nil {
+		mlog.Error("Object client: Error setting lifecycle: %v", mErr.Error())
+		return mErr
+	}
+	return nil
+}
+
+func (client *ObjectClient) GetLifecycle(accountId, bucketName string) (*model.Lifecycle, *management.Error) {
+	path := fmt.Sprintf("%s/%s/%s/lifecycle", BUCKETS_URI, accountId, bucketName)
+	resp, mErr := client.lbClient.Get(path)
+	if mErr != nil {
+		mlog.Error("Object client: Error getting lifecycle: %v", mErr.Error())
+		return nil, mErr
+	}
+
+	var out *model.Lifecycle
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return nil, management.GetInternalError(err.Error())
+	}
+
+	return out, nil
+}
+
+func (client *ObjectClient) SetNotifications(accountId, bucketName string,
+	notifications *model.Notifications) *management.Error {
+	path := fmt.Sprintf("%s/%s/%s/notifications", BUCKETS_URI, accountId, bucketName)
+	if _, mErr := client.lbClient.PutJSON(path, notifications); mErr != nil {
+		mlog.Error("Object client: Error setting notifications: %v", mErr.Error())
+		return mErr
+	}
+	return nil
+}
+
+func (client *ObjectClient) GetNotifications(accountId, bucketName string) (*model.Notifications, *management.Error) {
+	path := fmt.Sprintf("%s/%s/%s/notifications", BUCKETS_URI, accountId, bucketName)
+	resp, mErr := client.lbClient.Get(path)
+	if mErr != nil {
+		mlog.Error("Object client: Error getting notifications: %v", mErr.Error())
+		return nil, mErr
+	}
+
+	var out *model.Notifications
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return nil, management.GetInternalError(err.Error())
+	}
+
+	return out, nil
+}
+
+// SignUrl returns a url to download or upload a file of the bucket until it
+// expires, without the credentials of the account.
+func (client *ObjectClient) SignUrl(accountId, bucketName string,
+	request *model.SignRequest) (*model.SignedUrl, *management.Error) {
+	path := fmt.Sprintf("%s/%s/%s", SIGN_URI, accountId, bucketName)
+	resp, mErr := client.lbClient.PostJSON(path, request)
+	if mErr != nil {
+		mlog.Error("Object client: Error signing url: %v", mErr.Error())
+		return nil, mErr
+	}
+
+	var out *model.SignedUrl
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return nil, management.GetInternalError(err.Error())
+	}
+
+	return out, nil
+}
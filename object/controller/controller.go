@@ -19,8 +19,13 @@ package controller
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
@@ -28,12 +33,28 @@ import (
 	"github.com/lavaorg/lrtx/mlog"
 	"github.com/lavaorg/northstar/object/model"
 	"github.com/lavaorg/northstar/object/s3"
+	"github.com/lavaorg/northstar/object/signing"
 	"github.com/lavaorg/northstar/object/stats"
 	"github.com/lavaorg/northstar/object/util"
 )
 
 type Controller struct {
 	StorageProvider s3.StorageProvider
+
+	// Notifier notifies the events of the files, when it is set.
+	Notifier Notifier
+
+	// Signer signs the urls of the files, when it is set. The signed urls
+	// are prefixed with PublicUrl, and upload at most SignedUploadMaxSize
+	// bytes.
+	Signer              *signing.Signer
+	PublicUrl           string
+	SignedUploadMaxSize int64
+}
+
+// Notifier notifies the creation and deletion of the files of a bucket.
+type Notifier interface {
+	Notify(bucketName string, event *model.ObjectEvent)
 }
 
 func NewController(sProvider s3.StorageProvider) (controller *Controller) {
@@ -75,6 +96,10 @@ func (controller *Controller) UploadFile(c *gin.Context) {
 		return
 	}
 
+	if reservedFile(c, data.FileName) {
+		return
+	}
+
 	mlog.Debug("UploadFile(): fileName: %v", data.FileName)
 	bucketName = getBucketName(accountId, bucketName)
 	mErr := controller.StorageProvider.Upload(bucketName, data)
@@ -85,6 +110,11 @@ func (controller *Controller) UploadFile(c *gin.Context) {
 		return
 	}
 
+	controller.notify(accountId, c.Params.ByName("bucketName"), &model.ObjectEvent{Type: model.ObjectCreated,
+		FileName:    data.FileName,
+		ContentType: data.ContentType,
+		Size:        int64(len(data.Payload))})
+
 	stats.ErrUploadWriteDataCount.Incr()
 	c.JSON(http.StatusOK, "Data received")
 	mlog.Debug("Uploadfile ends")
@@ -124,7 +154,23 @@ func (controller *Controller) DownloadFile(c *gin.Context) {
 	}
 
 	bucketName = getBucketName(accountId, bucketName)
-	data, mErr := controller.StorageProvider.Download(bucketName, fileName)
+	var data *model.DownloadData
+	var mErr *management.Error
+	if c.Query("offset") != "" || c.Query("length") != "" {
+		offset, length, err := getRange(c)
+		if err != nil {
+			stats.ErrDownloadRangeInvalidCount.Incr()
+			mlog.Error("Downloadfile(): invalid range: %v", err)
+			c.JSON(http.StatusBadRequest, management.GetBadRequestError(util.RangeInvalid))
+			return
+		}
+
+		stats.DownloadRangeReqCount.Incr()
+		data, mErr = controller.StorageProvider.DownloadRange(bucketName, fileName, offset, length)
+	} else {
+		data, mErr = controller.StorageProvider.Download(bucketName, fileName)
+	}
+
 	if mErr != nil {
 		stats.ErrDownloadReadFailCount.Incr()
 		mlog.Error("Downloadfile():  failed due to %v", mErr)
@@ -175,6 +221,10 @@ func (controller *Controller) DeleteFile(c *gin.Context) {
 	}
 
 	fileName = strings.TrimLeft(fileName, "/")
+	if reservedFile(c, fileName) {
+		return
+	}
+
 	bucketName = getBucketName(accountId, bucketName)
 	mlog.Debug("DeleteFile(): : %s/%s", bucketName, fileName)
 	mErr := controller.StorageProvider.Delete(bucketName, fileName)
@@ -185,6 +235,7 @@ func (controller *Controller) DeleteFile(c *gin.Context) {
 		return
 	}
 
+	controller.notify(accountId, c.Params.ByName("bucketName"), &model.ObjectEvent{Type: model.ObjectDeleted, FileName: fileName})
 	c.JSON(http.StatusOK, "Data deleted")
 	mlog.Debug("DeleteFile ends")
 }
@@ -211,9 +262,21 @@ func (controller *Controller) ListFiles(c *gin.Context) {
 		return
 	}
 
+	filter, err := getFilter(c)
+	if err != nil {
+		stats.ErrListFilesFilterInvalidCount.Incr()
+		mlog.Error("ListFiles(): invalid filter: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(util.FilterInvalid))
+		return
+	}
+
 	bucketName = getBucketName(accountId, bucketName)
-	files, _, mErr := controller.StorageProvider.List(bucketName, "")
+	files, _, mErr := controller.StorageProvider.List(bucketName, filter.Prefix)
 	mlog.Debug("Data from status file is data=%v", files)
+	if mErr == nil && filter.HasAttributes() {
+		files, mErr = controller.filterFiles(bucketName, files, filter)
+	}
+
 	if mErr != nil {
 		stats.ErrListFilesCount.Incr()
 		mlog.Error("Downloadfile(): failed due to %v", mErr.Error())
@@ -331,6 +394,718 @@ func (controller *Controller) DeleteBucket(c *gin.Context) {
 	c.JSON(http.StatusOK, "Data received")
 }
 
+func (controller *Controller) StatFile(c *gin.Context) {
+	mlog.Debug("StatFile starts")
+	stats.StatFileReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName", "fileName") {
+		stats.ErrStatFileBadRequestCount.Incr()
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	fileName := strings.TrimLeft(c.Params.ByName("fileName"), "/")
+	object, mErr := controller.StorageProvider.Stat(bucketName, fileName)
+	if mErr != nil {
+		stats.ErrStatFileCount.Incr()
+		mlog.Error("StatFile(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, object)
+	mlog.Debug("StatFile ends")
+}
+
+func (controller *Controller) CreateUpload(c *gin.Context) {
+	mlog.Debug("CreateUpload starts")
+	stats.CreateUploadReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName") {
+		stats.ErrCreateUploadBadRequestCount.Incr()
+		return
+	}
+
+	var upload = new(model.Upload)
+	c.Bind(upload)
+	upload.UploadId, upload.Parts = "", nil
+	if err := upload.Validate(); err != nil {
+		stats.ErrCreateUploadBadRequestCount.Incr()
+		mlog.Error("Failed to validate upload: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		return
+	}
+
+	if reservedFile(c, upload.FileName) {
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	uploadId, mErr := controller.StorageProvider.CreateUpload(bucketName, upload)
+	if mErr != nil {
+		stats.ErrCreateUploadCount.Incr()
+		mlog.Error("CreateUpload(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Upload{UploadId: uploadId, FileName: upload.FileName})
+	mlog.Debug("CreateUpload ends")
+}
+
+func (controller *Controller) UploadPart(c *gin.Context) {
+	mlog.Debug("UploadPart starts")
+	stats.UploadPartReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName", "uploadId") {
+		stats.ErrUploadPartBadRequestCount.Incr()
+		return
+	}
+
+	var part = new(model.PartData)
+	c.Bind(part)
+	if err := part.Validate(); err != nil {
+		stats.ErrUploadPartBadRequestCount.Incr()
+		mlog.Error("Failed to validate part: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	uploaded, mErr := controller.StorageProvider.UploadPart(bucketName, c.Params.ByName("uploadId"), part)
+	if mErr != nil {
+		stats.ErrUploadPartCount.Incr()
+		mlog.Error("UploadPart(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, uploaded)
+	mlog.Debug("UploadPart ends")
+}
+
+func (controller *Controller) CompleteUpload(c *gin.Context) {
+	mlog.Debug("CompleteUpload starts")
+	stats.CompleteUploadReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName", "uploadId") {
+		stats.ErrCompleteUploadBadRequestCount.Incr()
+		return
+	}
+
+	var upload = new(model.Upload)
+	c.Bind(upload)
+	upload.UploadId = c.Params.ByName("uploadId")
+	if err := upload.Validate(); err != nil {
+		stats.ErrCompleteUploadBadRequestCount.Incr()
+		mlog.Error("Failed to validate upload: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	if mErr := controller.StorageProvider.CompleteUpload(bucketName, upload); mErr != nil {
+		stats.ErrCompleteUploadCount.Incr()
+		mlog.Error("CompleteUpload(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	controller.notifyCreated(c.Params.ByName("accountId"), c.Params.ByName("bucketName"), upload.FileName)
+	c.JSON(http.StatusOK, "Data received")
+	mlog.Debug("CompleteUpload ends")
+}
+
+func (controller *Controller) AbortUpload(c *gin.Context) {
+	mlog.Debug("AbortUpload starts")
+	stats.AbortUploadReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName", "uploadId", "fileName") {
+		stats.ErrAbortUploadBadRequestCount.Incr()
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	fileName := strings.TrimLeft(c.Params.ByName("fileName"), "/")
+	mErr := controller.StorageProvider.AbortUpload(bucketName, fileName, c.Params.ByName("uploadId"))
+	if mErr != nil {
+		stats.ErrAbortUploadCount.Incr()
+		mlog.Error("AbortUpload(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, "Upload aborted")
+	mlog.Debug("AbortUpload ends")
+}
+
+// CopyFile copies the file to another name or bucket of the account, and
+// deletes the original when the file is moved.
+func (controller *Controller) CopyFile(c *gin.Context) {
+	mlog.Debug("CopyFile starts")
+	stats.CopyFileReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName") {
+		stats.ErrCopyFileBadRequestCount.Incr()
+		return
+	}
+
+	var data = new(model.CopyData)
+	c.Bind(data)
+	if err := data.Validate(); err != nil {
+		stats.ErrCopyFileBadRequestCount.Incr()
+		mlog.Error("Failed to validate copy: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		return
+	}
+
+	accountId := c.Params.ByName("accountId")
+	bucketName := getBucketName(accountId, c.Params.ByName("bucketName"))
+	toBucket, toFileName := c.Params.ByName("bucketName"), data.FileName
+	if data.ToBucket != "" {
+		toBucket = data.ToBucket
+	}
+	toBucketName := getBucketName(accountId, toBucket)
+	if data.ToFileName != "" {
+		toFileName = data.ToFileName
+	}
+
+	if reservedFile(c, toFileName) || (data.Move && reservedFile(c, data.FileName)) {
+		return
+	}
+
+	mErr := controller.StorageProvider.Copy(bucketName, data.FileName, toBucketName, toFileName)
+	if mErr == nil && data.Move {
+		mErr = controller.StorageProvider.Delete(bucketName, data.FileName)
+	}
+
+	if mErr != nil {
+		stats.ErrCopyFileCount.Incr()
+		mlog.Error("CopyFile(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	controller.notifyCreated(accountId, toBucket, toFileName)
+	if data.Move {
+		controller.notify(accountId, c.Params.ByName("bucketName"), &model.ObjectEvent{Type: model.ObjectDeleted, FileName: data.FileName})
+	}
+
+	c.JSON(http.StatusOK, "Data copied")
+	mlog.Debug("CopyFile ends")
+}
+
+func (controller *Controller) SetVersioning(c *gin.Context) {
+	mlog.Debug("SetVersioning starts")
+	stats.SetVersioningReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName") {
+		stats.ErrSetVersioningBadRequestCount.Incr()
+		return
+	}
+
+	var versioning = new(model.Versioning)
+	if err := c.Bind(versioning); err != nil {
+		stats.ErrSetVersioningBadRequestCount.Incr()
+		mlog.Error("Failed to read versioning: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	if mErr := controller.StorageProvider.SetVersioning(bucketName, versioning.Enabled); mErr != nil {
+		stats.ErrSetVersioningCount.Incr()
+		mlog.Error("SetVersioning(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, versioning)
+	mlog.Debug("SetVersioning ends")
+}
+
+func (controller *Controller) GetVersioning(c *gin.Context) {
+	mlog.Debug("GetVersioning starts")
+	stats.GetVersioningReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName") {
+		stats.ErrGetVersioningBadRequestCount.Incr()
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	enabled, mErr := controller.StorageProvider.GetVersioning(bucketName)
+	if mErr != nil {
+		stats.ErrGetVersioningCount.Incr()
+		mlog.Error("GetVersioning(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.Versioning{Enabled: enabled})
+	mlog.Debug("GetVersioning ends")
+}
+
+func (controller *Controller) ListVersions(c *gin.Context) {
+	mlog.Debug("ListVersions starts")
+	stats.ListVersionsReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName", "fileName") {
+		stats.ErrListVersionsBadRequestCount.Incr()
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	fileName := strings.TrimLeft(c.Params.ByName("fileName"), "/")
+	versions, mErr := controller.StorageProvider.ListVersions(bucketName, fileName)
+	if mErr != nil {
+		stats.ErrListVersionsCount.Incr()
+		mlog.Error("ListVersions(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+	mlog.Debug("ListVersions ends")
+}
+
+func (controller *Controller) RestoreVersion(c *gin.Context) {
+	mlog.Debug("RestoreVersion starts")
+	stats.RestoreVersionReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName") {
+		stats.ErrRestoreVersionBadRequestCount.Incr()
+		return
+	}
+
+	var data = new(model.RestoreData)
+	c.Bind(data)
+	if err := data.Validate(); err != nil {
+		stats.ErrRestoreVersionBadRequestCount.Incr()
+		mlog.Error("Failed to validate restore: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		return
+	}
+
+	if reservedFile(c, data.FileName) {
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	mErr := controller.StorageProvider.RestoreVersion(bucketName, data.FileName, data.VersionId)
+	if mErr != nil {
+		stats.ErrRestoreVersionCount.Incr()
+		mlog.Error("RestoreVersion(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, "Version restored")
+	mlog.Debug("RestoreVersion ends")
+}
+
+func (controller *Controller) SetLifecycle(c *gin.Context) {
+	mlog.Debug("SetLifecycle starts")
+	stats.SetLifecycleReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName") {
+		stats.ErrSetLifecycleBadRequestCount.Incr()
+		return
+	}
+
+	var lifecycle = new(model.Lifecycle)
+	c.Bind(lifecycle)
+	if err := lifecycle.Validate(); err != nil {
+		stats.ErrSetLifecycleBadRequestCount.Incr()
+		mlog.Error("Failed to validate lifecycle: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	if mErr := controller.StorageProvider.SetLifecycle(bucketName, lifecycle); mErr != nil {
+		stats.ErrSetLifecycleCount.Incr()
+		mlog.Error("SetLifecycle(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, "Lifecycle received")
+	mlog.Debug("SetLifecycle ends")
+}
+
+func (controller *Controller) GetLifecycle(c *gin.Context) {
+	mlog.Debug("GetLifecycle starts")
+	stats.GetLifecycleReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName") {
+		stats.ErrGetLifecycleBadRequestCount.Incr()
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	lifecycle, mErr := controller.StorageProvider.GetLifecycle(bucketName)
+	if mErr != nil {
+		stats.ErrGetLifecycleCount.Incr()
+		mlog.Error("GetLifecycle(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, lifecycle)
+	mlog.Debug("GetLifecycle ends")
+}
+
+func (controller *Controller) SetNotifications(c *gin.Context) {
+	mlog.Debug("SetNotifications starts")
+	stats.SetNotificationsReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName") {
+		stats.ErrSetNotificationsBadRequestCount.Incr()
+		return
+	}
+
+	var notifications = new(model.Notifications)
+	c.Bind(notifications)
+	if err := notifications.Validate(); err != nil {
+		stats.ErrSetNotificationsBadRequestCount.Incr()
+		mlog.Error("Failed to validate notifications: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	if mErr := controller.StorageProvider.SetNotifications(bucketName, notifications); mErr != nil {
+		stats.ErrSetNotificationsCount.Incr()
+		mlog.Error("SetNotifications(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, "Notifications received")
+	mlog.Debug("SetNotifications ends")
+}
+
+func (controller *Controller) GetNotifications(c *gin.Context) {
+	mlog.Debug("GetNotifications starts")
+	stats.GetNotificationsReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName") {
+		stats.ErrGetNotificationsBadRequestCount.Incr()
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	notifications, mErr := controller.StorageProvider.GetNotifications(bucketName)
+	if mErr != nil {
+		stats.ErrGetNotificationsCount.Incr()
+		mlog.Error("GetNotifications(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+	mlog.Debug("GetNotifications ends")
+}
+
+func (controller *Controller) SignUrl(c *gin.Context) {
+	mlog.Debug("SignUrl starts")
+	stats.SignUrlReqCount.Incr()
+
+	if missingParam(c, "accountId", "bucketName") {
+		stats.ErrSignUrlBadRequestCount.Incr()
+		return
+	}
+
+	if controller.Signer == nil {
+		stats.ErrSignUrlDisabledCount.Incr()
+		mlog.Error(util.SigningDisabled)
+		c.JSON(http.StatusForbidden, management.GetForbiddenError(util.SigningDisabled))
+		return
+	}
+
+	var request = new(model.SignRequest)
+	c.Bind(request)
+	if err := request.Validate(); err != nil {
+		stats.ErrSignUrlBadRequestCount.Incr()
+		mlog.Error("Failed to validate sign request: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		return
+	}
+
+	accountId, bucket := c.Params.ByName("accountId"), c.Params.ByName("bucketName")
+	fileName := strings.TrimLeft(request.FileName, "/")
+	expires := time.Now().Add(time.Duration(request.ExpiresIn) * time.Second).Truncate(time.Second)
+	signature := controller.Signer.Sign(request.Method, accountId, bucket, fileName, expires.Unix())
+
+	path := (&url.URL{Path: fmt.Sprintf("%s/signed/%s/%s/%s", util.ObjectBasePath, accountId, bucket, fileName)}).EscapedPath()
+	query := url.Values{"expires": {strconv.FormatInt(expires.Unix(), 10)}, "signature": {signature}}
+	c.JSON(http.StatusOK, &model.SignedUrl{
+		Url:     strings.TrimRight(controller.PublicUrl, "/") + path + "?" + query.Encode(),
+		Method:  request.Method,
+		Expires: expires,
+	})
+	mlog.Debug("SignUrl ends")
+}
+
+// SignedDownload replies with the content of a file, to the holder of a
+// signed download url.
+func (controller *Controller) SignedDownload(c *gin.Context) {
+	mlog.Debug("SignedDownload starts")
+	stats.SignedDownloadReqCount.Incr()
+
+	if !controller.verifySignature(c) {
+		stats.ErrSignedDownloadForbiddenCount.Incr()
+		return
+	}
+
+	bucketName := getBucketName(c.Params.ByName("accountId"), c.Params.ByName("bucketName"))
+	data, mErr := controller.StorageProvider.Download(bucketName, strings.TrimLeft(c.Params.ByName("fileName"), "/"))
+	if mErr != nil {
+		stats.ErrSignedDownloadCount.Incr()
+		mlog.Error("SignedDownload(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	c.Data(http.StatusOK, data.ContentType, data.Payload)
+	mlog.Debug("SignedDownload ends")
+}
+
+// SignedUpload writes the body of the request to a file, for the holder of
+// a signed upload url. Its content type is the one of the request.
+func (controller *Controller) SignedUpload(c *gin.Context) {
+	mlog.Debug("SignedUpload starts")
+	stats.SignedUploadReqCount.Incr()
+
+	if !controller.verifySignature(c) {
+		stats.ErrSignedUploadForbiddenCount.Incr()
+		return
+	}
+
+	payload, err := ioutil.ReadAll(io.LimitReader(c.Request.Body, controller.SignedUploadMaxSize+1))
+	if err != nil {
+		stats.ErrSignedUploadCount.Incr()
+		mlog.Error("SignedUpload(): failed to read body: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		return
+	}
+
+	if int64(len(payload)) > controller.SignedUploadMaxSize {
+		stats.ErrSignedUploadTooLargeCount.Incr()
+		mlog.Error(util.SignedUploadTooLarge)
+		c.JSON(http.StatusRequestEntityTooLarge, management.NewError(http.StatusRequestEntityTooLarge,
+			"request_entity_too_large", util.SignedUploadTooLarge))
+		return
+	}
+
+	data := &model.UploadData{
+		FileName:    strings.TrimLeft(c.Params.ByName("fileName"), "/"),
+		Payload:     payload,
+		ContentType: c.Request.Header.Get("Content-Type"),
+	}
+	if data.ContentType == "" {
+		data.ContentType = "application/octet-stream"
+	}
+
+	if err := data.Validate(); err != nil {
+		stats.ErrSignedUploadCount.Incr()
+		mlog.Error("Failed to validate upload data: %v", err)
+		c.JSON(http.StatusBadRequest, management.GetBadRequestError(err.Error()))
+		return
+	}
+
+	if reservedFile(c, data.FileName) {
+		return
+	}
+
+	accountId, bucket := c.Params.ByName("accountId"), c.Params.ByName("bucketName")
+	if mErr := controller.StorageProvider.Upload(getBucketName(accountId, bucket), data); mErr != nil {
+		stats.ErrSignedUploadCount.Incr()
+		mlog.Error("SignedUpload(): failed due to %v", mErr)
+		c.JSON(http.StatusInternalServerError, mErr)
+		return
+	}
+
+	controller.notify(accountId, bucket, &model.ObjectEvent{Type: model.ObjectCreated,
+		FileName:    data.FileName,
+		ContentType: data.ContentType,
+		Size:        int64(len(data.Payload))})
+
+	c.JSON(http.StatusOK, "Data received")
+	mlog.Debug("SignedUpload ends")
+}
+
+// verifySignature replies with forbidden when the url of the request is
+// not signed for its method, or has expired, and reports whether it is.
+func (controller *Controller) verifySignature(c *gin.Context) bool {
+	if controller.Signer == nil {
+		mlog.Error(util.SigningDisabled)
+		c.JSON(http.StatusForbidden, management.GetForbiddenError(util.SigningDisabled))
+		return false
+	}
+
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err == nil {
+		err = controller.Signer.Verify(c.Request.Method,
+			c.Params.ByName("accountId"),
+			c.Params.ByName("bucketName"),
+			strings.TrimLeft(c.Params.ByName("fileName"), "/"),
+			expires, c.Query("signature"), time.Now())
+	}
+
+	if err != nil {
+		mlog.Error("Failed to verify signed url: %v", err)
+		c.JSON(http.StatusForbidden, management.GetForbiddenError(util.SignatureInvalid))
+		return false
+	}
+
+	return true
+}
+
+// notify notifies the event of a file of the bucket of the account.
+func (controller *Controller) notify(accountId, bucket string, event *model.ObjectEvent) {
+	if controller.Notifier == nil {
+		return
+	}
+
+	event.AccountId, event.Bucket, event.Time = accountId, bucket, time.Now()
+	event.FileName = strings.TrimLeft(event.FileName, "/")
+	controller.Notifier.Notify(getBucketName(accountId, bucket), event)
+}
+
+// notifyCreated notifies the creation of a file assembled or copied by the
+// storage, reading its size.
+func (controller *Controller) notifyCreated(accountId, bucket, fileName string) {
+	if controller.Notifier == nil {
+		return
+	}
+
+	event := &model.ObjectEvent{Type: model.ObjectCreated, FileName: fileName}
+	object, mErr := controller.StorageProvider.Stat(getBucketName(accountId, bucket), strings.TrimLeft(fileName, "/"))
+	if mErr == nil {
+		event.Size = object.Size
+	}
+	controller.notify(accountId, bucket, event)
+}
+
+// filterFiles returns the files matching the tags and metadata of the
+// filter. A listing has neither, so they are read file by file.
+func (controller *Controller) filterFiles(bucketName string,
+	files []model.Object, filter *model.Filter) ([]model.Object, *management.Error) {
+	matching := []model.Object{}
+	for _, file := range files {
+		object, mErr := controller.StorageProvider.Stat(bucketName, file.Key)
+		if mErr != nil {
+			return nil, mErr
+		}
+
+		file.Metadata, file.Tags = object.Metadata, object.Tags
+		if filter.Matches(&file) {
+			matching = append(matching, file)
+		}
+	}
+
+	return matching, nil
+}
+
+var paramErrors = map[string]string{
+	"accountId":  util.AccountIdMissing,
+	"bucketName": util.BucketNameMissing,
+	"fileName":   util.FileNameMissing,
+	"uploadId":   util.UploadIdMissing,
+}
+
+// missingParam replies with a bad request when one of the parameters is
+// missing from the request, and reports whether it did.
+func missingParam(c *gin.Context, names ...string) bool {
+	for _, name := range names {
+		if strings.TrimLeft(c.Params.ByName(name), "/") == "" {
+			mlog.Error(paramErrors[name])
+			c.JSON(http.StatusBadRequest, management.GetBadRequestError(paramErrors[name]))
+			return true
+		}
+	}
+
+	return false
+}
+
+// reservedFile replies with a bad request when the file is under the prefix
+// of the bucket configuration, which is only written through the lifecycle
+// and notification rules, and reports whether it did.
+func reservedFile(c *gin.Context, fileNames ...string) bool {
+	for _, fileName := range fileNames {
+		if util.IsConfigFile(fileName) {
+			stats.ErrReservedFileNameCount.Incr()
+			mlog.Error("%s: %s", util.FileNameReserved, fileName)
+			c.JSON(http.StatusBadRequest, management.GetBadRequestError(util.FileNameReserved))
+			return true
+		}
+	}
+
+	return false
+}
+
+// getRange returns the offset and length of a ranged download, the length
+// being 0 for the rest of the file.
+func getRange(c *gin.Context) (int64, int64, error) {
+	var offset, length int64
+	var err error
+	if value := c.Query("offset"); value != "" {
+		if offset, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if value := c.Query("length"); value != "" {
+		if length, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if offset < 0 || length < 0 {
+		return 0, 0, errors.New("negative offset or length")
+	}
+
+	return offset, length, nil
+}
+
+// getFilter returns the filter of a listing: the prefix of the file names,
+// and "key=value" pairs for the tag and metadata parameters, which can be
+// repeated.
+func getFilter(c *gin.Context) (*model.Filter, error) {
+	query := c.Request.URL.Query()
+	filter := &model.Filter{Prefix: query.Get("prefix")}
+
+	var err error
+	if filter.Tags, err = getPairs(query["tag"]); err != nil {
+		return nil, err
+	}
+
+	if filter.Metadata, err = getPairs(query["metadata"]); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func getPairs(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	pairs := make(map[string]string, len(values))
+	for _, value := range values {
+		pair := strings.SplitN(value, "=", 2)
+		if len(pair) != 2 || pair[0] == "" {
+			return nil, fmt.Errorf("invalid pair %s", value)
+		}
+		pairs[pair[0]] = pair[1]
+	}
+
+	return pairs, nil
+}
+
 func getBucketName(accountId, bucketName string) string {
 	return fmt.Sprintf("%s_%s", accountId, bucketName)
 }
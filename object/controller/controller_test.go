@@ -31,6 +31,8 @@ import (
 	"github.com/lavaorg/lrtx/management"
 	"github.com/lavaorg/northstar/object/mocks/storage"
 	"github.com/lavaorg/northstar/object/model"
+	"github.com/lavaorg/northstar/object/s3"
+	"github.com/lavaorg/northstar/object/signing"
 	"github.com/lavaorg/northstar/object/util"
 )
 
@@ -742,3 +744,235 @@ func testHTTPResponse(t *testing.T,
 		t.Fail()
 	}
 }
+
+func TestDownloadFileInvalidRange(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	engine := gin.Default()
+	engine.GET("/:accountId/:bucketName/*fileName", controller.DownloadFile)
+
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/%s/%s/%s?offset=-1", AccountId, BucketName, FileName), nil)
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.RangeInvalid))
+	})
+}
+
+func TestCreateUploadMissingContentType(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	engine := gin.Default()
+	engine.POST("/:accountId/:bucketName", controller.CreateUpload)
+
+	upload, _ := json.Marshal(&model.Upload{FileName: FileName})
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/%s/%s", AccountId, BucketName), bytes.NewReader(upload))
+	req.Header.Add("Content-Type", "application/json")
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, ErrMissingContentType)
+	})
+}
+
+func TestUploadPartInvalidPartNumber(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	engine := gin.Default()
+	engine.POST("/:accountId/:bucketName/:uploadId/parts", controller.UploadPart)
+
+	part, _ := json.Marshal(&model.PartData{FileName: FileName, Payload: []byte("Random data")})
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/%s/%s/upload/parts", AccountId, BucketName), bytes.NewReader(part))
+	req.Header.Add("Content-Type", "application/json")
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.PartNumberInvalid))
+	})
+}
+
+func TestCompleteUploadMissingParts(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	engine := gin.Default()
+	engine.POST("/:accountId/:bucketName/:uploadId", controller.CompleteUpload)
+
+	upload, _ := json.Marshal(&model.Upload{FileName: FileName})
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/%s/%s/upload", AccountId, BucketName), bytes.NewReader(upload))
+	req.Header.Add("Content-Type", "application/json")
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.PartsMissing))
+	})
+}
+
+func TestCopyFileMissingDestination(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	engine := gin.Default()
+	engine.POST("/:accountId/:bucketName", controller.CopyFile)
+
+	data, _ := json.Marshal(&model.CopyData{FileName: FileName})
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/%s/%s", AccountId, BucketName), bytes.NewReader(data))
+	req.Header.Add("Content-Type", "application/json")
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.DestinationMissing))
+	})
+}
+
+// Helper function to check the response is the bad request error
+func isBadRequest(t *testing.T, w *httptest.ResponseRecorder, expected *management.Error) bool {
+	var errMessage management.Error
+	p, err := ioutil.ReadAll(w.Body)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if merr := json.Unmarshal(p, &errMessage); merr != nil {
+		t.Error(merr)
+	}
+
+	return w.Code == http.StatusBadRequest &&
+		errMessage.Id == expected.Id &&
+		errMessage.Description == expected.Description
+}
+
+func TestListFilesInvalidFilter(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	engine := gin.Default()
+	engine.GET("/:accountId/:bucketName", controller.ListFiles)
+
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/%s/%s?tag=stage", AccountId, "bucket"), nil)
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.FilterInvalid))
+	})
+}
+
+func TestRestoreVersionMissingVersionId(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	engine := gin.Default()
+	engine.POST("/:accountId/:bucketName", controller.RestoreVersion)
+
+	data, _ := json.Marshal(&model.RestoreData{FileName: FileName})
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/%s/%s", AccountId, "bucket"), bytes.NewReader(data))
+	req.Header.Add("Content-Type", "application/json")
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.VersionIdMissing))
+	})
+}
+
+func TestSetLifecycleInvalidTransition(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	engine := gin.Default()
+	engine.PUT("/:accountId/:bucketName/lifecycle", controller.SetLifecycle)
+
+	lifecycle, _ := json.Marshal(&model.Lifecycle{Rules: []model.LifecycleRule{
+		{Id: "raw", Prefix: "raw/", TransitionDays: 1, TransitionPrefix: "raw/archive/"},
+	}})
+	req, _ := http.NewRequest("PUT", fmt.Sprintf("/%s/%s/lifecycle", AccountId, "bucket"), bytes.NewReader(lifecycle))
+	req.Header.Add("Content-Type", "application/json")
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.RuleTransitionInvalid))
+	})
+}
+
+func TestUploadFileTooManyTags(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	engine := gin.Default()
+	engine.POST("/:accountId/:bucketName", controller.UploadFile)
+
+	tags := make(map[string]string)
+	for i := 0; i <= util.MaxTags; i++ {
+		tags[fmt.Sprint("tag", i)] = "value"
+	}
+
+	data, _ := json.Marshal(&model.UploadData{FileName: FileName, Payload: []byte("a"), ContentType: "text/plain", Tags: tags})
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/%s/%s", AccountId, "bucket"), bytes.NewReader(data))
+	req.Header.Add("Content-Type", "application/json")
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.TagsInvalid))
+	})
+}
+
+func TestSetNotificationsMissingEventId(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	engine := gin.Default()
+	engine.PUT("/:accountId/:bucketName/notifications", controller.SetNotifications)
+
+	notifications, _ := json.Marshal(&model.Notifications{Rules: []model.NotificationRule{{Id: "drop", Prefix: "drop/"}}})
+	req, _ := http.NewRequest("PUT", fmt.Sprintf("/%s/%s/notifications", AccountId, "bucket"), bytes.NewReader(notifications))
+	req.Header.Add("Content-Type", "application/json")
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.EventIdMissing))
+	})
+}
+
+func TestSignUrlInvalidMethod(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	controller.Signer = signing.NewSigner("secret")
+	engine := gin.Default()
+	engine.POST("/:accountId/:bucketName", controller.SignUrl)
+
+	request, _ := json.Marshal(&model.SignRequest{FileName: FileName, Method: "DELETE"})
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/%s/%s", AccountId, "bucket"), bytes.NewReader(request))
+	req.Header.Add("Content-Type", "application/json")
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.MethodInvalid))
+	})
+}
+
+func TestSignedDownloadInvalidSignature(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	controller.Signer = signing.NewSigner("secret")
+	engine := gin.Default()
+	engine.GET("/:accountId/:bucketName/*fileName", controller.SignedDownload)
+
+	expires := time.Now().Add(time.Hour).Unix()
+	signature := controller.Signer.Sign("PUT", AccountId, "bucket", FileName, expires)
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/%s/%s/%s?expires=%d&signature=%s",
+		AccountId, "bucket", FileName, expires, signature), nil)
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return w.Code == http.StatusForbidden
+	})
+}
+
+func TestUploadFileReservedName(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	engine := gin.Default()
+	engine.POST("/:accountId/:bucketName", controller.UploadFile)
+
+	data, _ := json.Marshal(&model.UploadData{FileName: s3.LifecycleKey, Payload: []byte("{}"), ContentType: "application/json"})
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/%s/%s", AccountId, "bucket"), bytes.NewReader(data))
+	req.Header.Add("Content-Type", "application/json")
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.FileNameReserved))
+	})
+}
+
+func TestDeleteFileReservedName(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	engine := gin.Default()
+	engine.DELETE("/:accountId/:bucketName/*fileName", controller.DeleteFile)
+
+	req, _ := http.NewRequest("DELETE", fmt.Sprintf("/%s/%s/%s", AccountId, "bucket", s3.NotificationsKey), nil)
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.FileNameReserved))
+	})
+}
+
+func TestCopyFileReservedDestination(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	engine := gin.Default()
+	engine.POST("/:accountId/:bucketName", controller.CopyFile)
+
+	data, _ := json.Marshal(&model.CopyData{FileName: FileName, ToFileName: "raw/../" + s3.LifecycleKey})
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/%s/%s", AccountId, "bucket"), bytes.NewReader(data))
+	req.Header.Add("Content-Type", "application/json")
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.FileNameReserved))
+	})
+}
+
+func TestSignedUploadReservedName(t *testing.T) {
+	controller := NewController(storage.StorageMock{})
+	controller.Signer = signing.NewSigner("secret")
+	controller.SignedUploadMaxSize = 1024
+	engine := gin.Default()
+	engine.PUT("/:accountId/:bucketName/*fileName", controller.SignedUpload)
+
+	expires := time.Now().Add(time.Hour).Unix()
+	signature := controller.Signer.Sign("PUT", AccountId, "bucket", s3.LifecycleKey, expires)
+	req, _ := http.NewRequest("PUT", fmt.Sprintf("/%s/%s/%s?expires=%d&signature=%s",
+		AccountId, "bucket", s3.LifecycleKey, expires, signature), bytes.NewReader([]byte("{}")))
+	testHTTPResponse(t, engine, req, func(w *httptest.ResponseRecorder) bool {
+		return isBadRequest(t, w, management.GetBadRequestError(util.FileNameReserved))
+	})
+}
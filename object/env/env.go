@@ -29,6 +29,30 @@ var (
 	BlobStorageHostAndPort = getStorageHostPort()
 	BlobStorageUserId      = os.Getenv("OBJECT_BLOB_STORAGE_USER_ID")
 	BlobStorageUserSecret  = os.Getenv("OBJECT_BLOB_STORAGE_USER_SECRET")
+
+	// StorageProvider selects where the objects are stored: "s3", or "fs"
+	// for directories under StorageRoot.
+	StorageProvider, _ = config.GetString("OBJECT_STORAGE_PROVIDER", "s3")
+	StorageRoot, _     = config.GetString("OBJECT_STORAGE_ROOT", "/var/lib/northstar/object")
+
+	// LifecycleInterval is the number of seconds between two runs of the
+	// lifecycle rules of the buckets, 0 to disable them.
+	LifecycleInterval, _ = config.GetInt("OBJECT_LIFECYCLE_INTERVAL", 3600)
+
+	// NotificationQueueSize is the number of events of the files waiting to
+	// be notified, beyond which they are dropped.
+	NotificationQueueSize, _ = config.GetInt("OBJECT_NOTIFICATION_QUEUE_SIZE", 1000)
+
+	// SigningKey signs the urls giving access to the files until they
+	// expire. Without it, no url is signed.
+	SigningKey = os.Getenv("OBJECT_SIGNING_KEY")
+
+	// PublicUrl is the base url of the object service for the signed
+	// urls, which are paths without it.
+	PublicUrl, _ = config.GetString("OBJECT_PUBLIC_URL", "")
+
+	// SignedUploadMaxSize is the number of bytes a signed url can upload.
+	SignedUploadMaxSize, _ = config.GetInt("OBJECT_SIGNED_UPLOAD_MAX_SIZE", 100*1024*1024)
 )
 
 func getStorageHostPort() string {
@@ -0,0 +1,878 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fs
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/object/model"
+	"github.com/lavaorg/northstar/object/s3"
+)
+
+const (
+	OBJECTS_DIR  = "objects"
+	META_DIR     = "meta"
+	UPLOADS_DIR  = "uploads"
+	VERSIONS_DIR = "versions"
+	TMP_DIR      = ".tmp"
+	UPLOAD_FILE  = "upload.json"
+	CONFIG_FILE  = "config.json"
+
+	DefaultContentType = "application/octet-stream"
+
+	// The version of the files written while versioning was never enabled.
+	NullVersionId = "null"
+
+	// Object keys are escaped into a single file name.
+	maxNameLength = 255
+)
+
+// FSStorageProvider stores the buckets as directories of the root, for
+// development and deployments without S3. The content of an object is kept
+// in the objects directory of its bucket and its metadata in a sidecar of
+// the meta directory, both under the escaped key. Files are written to a
+// temporary file and renamed into place, so readers never see a partial
+// object.
+//
+// When versioning is enabled, a replaced or deleted object is moved with
+// its sidecar to the directory of its key under the versions directory.
+// Objects written while versioning is suspended have no version and are
+// replaced, as S3 does for its null version.
+type FSStorageProvider struct {
+	root string
+}
+
+// metadata is the sidecar of an object.
+type metadata struct {
+	Key         string            `json:"key"`
+	ContentType string            `json:"contentType"`
+	Etag        string            `json:"etag"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	VersionId   string            `json:"versionId,omitempty"`
+}
+
+// bucketConfig is the configuration of a bucket.
+type bucketConfig struct {
+	Versioning    bool                     `json:"versioning"`
+	Lifecycle     []model.LifecycleRule    `json:"lifecycle,omitempty"`
+	Notifications []model.NotificationRule `json:"notifications,omitempty"`
+}
+
+// Creates a new filesystem storage provider storing the buckets under root.
+func NewFSStorageProvider(root string) (s3.StorageProvider, error) {
+	mlog.Info("NewFSStorageProvider: %s", root)
+
+	if root == "" {
+		return nil, management.GetInternalError("Error, failed to create storage provider due to empty root")
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, TMP_DIR), 0700); err != nil {
+		return nil, management.GetInternalError(
+			fmt.Sprintf("Error, failed to create storage root %s with error: %s", root, err.Error()))
+	}
+
+	return &FSStorageProvider{root: root}, nil
+}
+
+func (provider *FSStorageProvider) CreateBucket(bucketName string) *management.Error {
+	dir, mErr := provider.bucketDir(bucketName)
+	if mErr != nil {
+		return mErr
+	}
+
+	if _, err := os.Stat(dir); err == nil {
+		return management.GetConflictError(fmt.Sprintf("Error, bucket %s already exists", bucketName))
+	}
+
+	for _, sub := range []string{OBJECTS_DIR, META_DIR, UPLOADS_DIR, VERSIONS_DIR} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return internalError("create bucket", bucketName, err)
+		}
+	}
+
+	mlog.Debug("Created bucket %s successfully", bucketName)
+	return nil
+}
+
+func (provider *FSStorageProvider) DeleteBucket(bucketName string) *management.Error {
+	dir, mErr := provider.existingBucket(bucketName)
+	if mErr != nil {
+		return mErr
+	}
+
+	entries, err := ioutil.ReadDir(filepath.Join(dir, OBJECTS_DIR))
+	if err != nil {
+		return internalError("delete bucket", bucketName, err)
+	}
+
+	if len(entries) > 0 {
+		return management.GetConflictError(fmt.Sprintf("Error, bucket %s is not empty", bucketName))
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return internalError("delete bucket", bucketName, err)
+	}
+
+	mlog.Debug("Deleted bucket %s successfully", bucketName)
+	return nil
+}
+
+func (provider *FSStorageProvider) ListBuckets(nameFilter string) ([]model.Bucket, *management.Error) {
+	entries, err := ioutil.ReadDir(provider.root)
+	if err != nil {
+		return nil, internalError("list buckets", provider.root, err)
+	}
+
+	var buckets = []model.Bucket{}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		if nameFilter == "" || strings.Contains(entry.Name(), nameFilter) {
+			buckets = append(buckets, model.Bucket{Name: entry.Name(), CreationDate: entry.ModTime()})
+		}
+	}
+
+	return buckets, nil
+}
+
+// Returns metadata for the objects of the bucket whose key starts with the
+// path, sorted by key.
+func (provider *FSStorageProvider) List(bucketName, path string) ([]model.Object, string, *management.Error) {
+	dir, mErr := provider.existingBucket(bucketName)
+	if mErr != nil {
+		return nil, "", mErr
+	}
+
+	entries, err := ioutil.ReadDir(filepath.Join(dir, OBJECTS_DIR))
+	if err != nil {
+		return nil, "", internalError("list files of", bucketName, err)
+	}
+
+	objects := []model.Object{}
+	for _, entry := range entries {
+		key, err := url.PathUnescape(entry.Name())
+		if err != nil || !strings.HasPrefix(key, path) {
+			continue
+		}
+
+		meta := provider.metadata(dir, key)
+		objects = append(objects, model.Object{Key: key,
+			LastModified: entry.ModTime(),
+			Size:         entry.Size(),
+			Etag:         meta.Etag,
+			StorageClass: "STANDARD"})
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	return objects, "", nil
+}
+
+func (provider *FSStorageProvider) Stat(bucketName, fileName string) (*model.Object, *management.Error) {
+	dir, name, mErr := provider.objectPath(bucketName, fileName)
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	info, err := os.Stat(filepath.Join(dir, OBJECTS_DIR, name))
+	if err != nil {
+		return nil, fileError("stat", fileName, err)
+	}
+
+	meta := provider.metadata(dir, fileName)
+	return &model.Object{Key: objectKey(fileName),
+		LastModified: info.ModTime(),
+		Size:         info.Size(),
+		Etag:         meta.Etag,
+		StorageClass: "STANDARD",
+		Metadata:     meta.Metadata,
+		Tags:         meta.Tags,
+		VersionId:    meta.VersionId}, nil
+}
+
+// Deletes the data for the specified file name. Like S3, deleting a file
+// which does not exist succeeds.
+func (provider *FSStorageProvider) Delete(bucketName, fileName string) *management.Error {
+	dir, name, mErr := provider.objectPath(bucketName, fileName)
+	if mErr != nil {
+		return mErr
+	}
+
+	if _, err := provider.archive(dir, name, fileName); err != nil {
+		return internalError("delete file", fileName, err)
+	}
+
+	for _, path := range []string{filepath.Join(dir, OBJECTS_DIR, name), filepath.Join(dir, META_DIR, name)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return internalError("delete file", fileName, err)
+		}
+	}
+
+	return nil
+}
+
+func (provider *FSStorageProvider) Upload(bucketName string, data *model.UploadData) *management.Error {
+	dir, name, mErr := provider.objectPath(bucketName, data.FileName)
+	if mErr != nil {
+		return mErr
+	}
+
+	versionId, err := provider.archive(dir, name, data.FileName)
+	if err != nil {
+		return internalError("upload file", data.FileName, err)
+	}
+
+	hash := md5.Sum(data.Payload)
+	if err := provider.writeFile(filepath.Join(dir, OBJECTS_DIR, name), data.Payload); err != nil {
+		return internalError("upload file", data.FileName, err)
+	}
+
+	meta := &metadata{Key: objectKey(data.FileName),
+		ContentType: data.ContentType,
+		Etag:        etag(hash[:]),
+		Metadata:    lowerKeys(data.Metadata),
+		Tags:        data.Tags,
+		VersionId:   versionId}
+	if err := provider.writeMetadata(dir, name, meta); err != nil {
+		return internalError("upload file", data.FileName, err)
+	}
+
+	mlog.Info("Successfully uploaded object %s", data.FileName)
+	return nil
+}
+
+func (provider *FSStorageProvider) Download(bucketName, fileName string) (*model.DownloadData, *management.Error) {
+	return provider.DownloadRange(bucketName, fileName, 0, 0)
+}
+
+func (provider *FSStorageProvider) DownloadRange(bucketName,
+	fileName string, offset, length int64) (*model.DownloadData, *management.Error) {
+	dir, name, mErr := provider.objectPath(bucketName, fileName)
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	file, err := os.Open(filepath.Join(dir, OBJECTS_DIR, name))
+	if err != nil {
+		return nil, fileError("get file", fileName, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, internalError("get file", fileName, err)
+	}
+
+	size := info.Size()
+	if offset > size || (offset == size && size > 0) {
+		return nil, management.GetBadRequestError(
+			fmt.Sprintf("Error, range of file %s is not satisfiable", fileName))
+	}
+
+	if length == 0 || offset+length > size {
+		length = size - offset
+	}
+
+	payload := make([]byte, length)
+	if _, err := file.ReadAt(payload, offset); err != nil && err != io.EOF {
+		return nil, internalError("read file", fileName, err)
+	}
+
+	meta := provider.metadata(dir, fileName)
+	return &model.DownloadData{Payload: payload,
+		ContentType: meta.ContentType,
+		Offset:      offset,
+		Size:        size,
+		Metadata:    meta.Metadata}, nil
+}
+
+func (provider *FSStorageProvider) CreateUpload(bucketName string, upload *model.Upload) (string, *management.Error) {
+	dir, _, mErr := provider.objectPath(bucketName, upload.FileName)
+	if mErr != nil {
+		return "", mErr
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", internalError("start upload of", upload.FileName, err)
+	}
+
+	uploadId := hex.EncodeToString(id)
+	uploadDir := filepath.Join(dir, UPLOADS_DIR, uploadId)
+	if err := os.Mkdir(uploadDir, 0700); err != nil {
+		return "", internalError("start upload of", upload.FileName, err)
+	}
+
+	data, _ := json.Marshal(&model.Upload{FileName: upload.FileName,
+		ContentType: upload.ContentType,
+		Metadata:    upload.Metadata,
+		Tags:        upload.Tags})
+	if err := provider.writeFile(filepath.Join(uploadDir, UPLOAD_FILE), data); err != nil {
+		return "", internalError("start upload of", upload.FileName, err)
+	}
+
+	return uploadId, nil
+}
+
+func (provider *FSStorageProvider) UploadPart(bucketName,
+	uploadId string, part *model.PartData) (*model.Part, *management.Error) {
+	uploadDir, _, mErr := provider.upload(bucketName, part.FileName, uploadId)
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	hash := md5.Sum(part.Payload)
+	if err := provider.writeFile(partPath(uploadDir, part.PartNumber), part.Payload); err != nil {
+		return nil, internalError("upload part of", part.FileName, err)
+	}
+
+	return &model.Part{PartNumber: part.PartNumber, Etag: etag(hash[:])}, nil
+}
+
+// Assembles the parts into the file, checking each part is the one
+// uploaded with its etag.
+func (provider *FSStorageProvider) CompleteUpload(bucketName string, upload *model.Upload) *management.Error {
+	uploadDir, started, mErr := provider.upload(bucketName, upload.FileName, upload.UploadId)
+	if mErr != nil {
+		return mErr
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Join(provider.root, TMP_DIR), "upload")
+	if err != nil {
+		return internalError("complete upload of", upload.FileName, err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	whole := md5.New()
+	for _, part := range upload.Parts {
+		if mErr := appendPart(io.MultiWriter(tmp, whole), uploadDir, part); mErr != nil {
+			return mErr
+		}
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return internalError("complete upload of", upload.FileName, err)
+	}
+
+	dir, name, _ := provider.objectPath(bucketName, upload.FileName)
+	versionId, err := provider.archive(dir, name, upload.FileName)
+	if err != nil {
+		return internalError("complete upload of", upload.FileName, err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, OBJECTS_DIR, name)); err != nil {
+		return internalError("complete upload of", upload.FileName, err)
+	}
+
+	meta := &metadata{Key: objectKey(upload.FileName),
+		ContentType: started.ContentType,
+		Etag:        etag(whole.Sum(nil)),
+		Metadata:    lowerKeys(started.Metadata),
+		Tags:        started.Tags,
+		VersionId:   versionId}
+	if err := provider.writeMetadata(dir, name, meta); err != nil {
+		return internalError("complete upload of", upload.FileName, err)
+	}
+
+	if err := os.RemoveAll(uploadDir); err != nil {
+		mlog.Error("Failed to remove upload %s: %v", upload.UploadId, err)
+	}
+
+	mlog.Info("Successfully uploaded object %s in %d parts", upload.FileName, len(upload.Parts))
+	return nil
+}
+
+func (provider *FSStorageProvider) AbortUpload(bucketName, fileName, uploadId string) *management.Error {
+	uploadDir, _, mErr := provider.upload(bucketName, fileName, uploadId)
+	if mErr != nil {
+		return mErr
+	}
+
+	if err := os.RemoveAll(uploadDir); err != nil {
+		return internalError("abort upload of", fileName, err)
+	}
+	return nil
+}
+
+func (provider *FSStorageProvider) Copy(bucketName, fileName, toBucketName, toFileName string) *management.Error {
+	dir, name, mErr := provider.objectPath(bucketName, fileName)
+	if mErr != nil {
+		return mErr
+	}
+
+	toDir, toName, mErr := provider.objectPath(toBucketName, toFileName)
+	if mErr != nil {
+		return mErr
+	}
+
+	meta := provider.metadata(dir, fileName)
+	if mErr := provider.replace(toDir, toName, toFileName, filepath.Join(dir, OBJECTS_DIR, name), meta); mErr != nil {
+		return mErr
+	}
+
+	return nil
+}
+
+func (provider *FSStorageProvider) SetVersioning(bucketName string, enabled bool) *management.Error {
+	dir, mErr := provider.existingBucket(bucketName)
+	if mErr != nil {
+		return mErr
+	}
+
+	config := provider.config(dir)
+	config.Versioning = enabled
+	if err := provider.writeConfig(dir, config); err != nil {
+		return internalError("set versioning of", bucketName, err)
+	}
+	return nil
+}
+
+func (provider *FSStorageProvider) GetVersioning(bucketName string) (bool, *management.Error) {
+	dir, mErr := provider.existingBucket(bucketName)
+	if mErr != nil {
+		return false, mErr
+	}
+
+	return provider.config(dir).Versioning, nil
+}
+
+// Returns the current version of the file followed by its previous
+// versions, newest first.
+func (provider *FSStorageProvider) ListVersions(bucketName, fileName string) ([]model.Object, *management.Error) {
+	dir, name, mErr := provider.objectPath(bucketName, fileName)
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	versions := []model.Object{}
+	if current, mErr := provider.Stat(bucketName, fileName); mErr == nil {
+		current.Metadata, current.Tags, current.IsLatest = nil, nil, true
+		if current.VersionId == "" {
+			current.VersionId = NullVersionId
+		}
+		versions = append(versions, *current)
+	}
+
+	versionsDir := filepath.Join(dir, VERSIONS_DIR, name)
+	entries, err := ioutil.ReadDir(versionsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, internalError("list versions of", fileName, err)
+	}
+
+	previous := []model.Object{}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		meta := readMetadata(filepath.Join(versionsDir, entry.Name()+".json"), fileName)
+		previous = append(previous, model.Object{Key: objectKey(fileName),
+			LastModified: entry.ModTime(),
+			Size:         entry.Size(),
+			Etag:         meta.Etag,
+			StorageClass: "STANDARD",
+			VersionId:    entry.Name()})
+	}
+
+	sort.Slice(previous, func(i, j int) bool {
+		if previous[i].LastModified.Equal(previous[j].LastModified) {
+			return previous[i].VersionId > previous[j].VersionId
+		}
+		return previous[i].LastModified.After(previous[j].LastModified)
+	})
+	return append(versions, previous...), nil
+}
+
+// Restores a previous version of the file as a new current version.
+func (provider *FSStorageProvider) RestoreVersion(bucketName, fileName, versionId string) *management.Error {
+	dir, name, mErr := provider.objectPath(bucketName, fileName)
+	if mErr != nil {
+		return mErr
+	}
+
+	currentId := provider.metadata(dir, fileName).VersionId
+	if currentId == "" {
+		currentId = NullVersionId
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, OBJECTS_DIR, name)); err == nil && versionId == currentId {
+		return nil
+	}
+
+	if versionId == "" || strings.ContainsAny(versionId, `/\.`) {
+		return management.GetNotFoundError(fmt.Sprintf("Error, version %s of file %s not found", versionId, fileName))
+	}
+
+	versionPath := filepath.Join(dir, VERSIONS_DIR, name, versionId)
+	if _, err := os.Stat(versionPath); err != nil {
+		return fileError("restore version of", fileName, err)
+	}
+
+	return provider.replace(dir, name, fileName, versionPath, readMetadata(versionPath+".json", fileName))
+}
+
+func (provider *FSStorageProvider) SetLifecycle(bucketName string, lifecycle *model.Lifecycle) *management.Error {
+	dir, mErr := provider.existingBucket(bucketName)
+	if mErr != nil {
+		return mErr
+	}
+
+	config := provider.config(dir)
+	config.Lifecycle = lifecycle.Rules
+	if err := provider.writeConfig(dir, config); err != nil {
+		return internalError("set lifecycle of", bucketName, err)
+	}
+	return nil
+}
+
+func (provider *FSStorageProvider) GetLifecycle(bucketName string) (*model.Lifecycle, *management.Error) {
+	dir, mErr := provider.existingBucket(bucketName)
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	rules := provider.config(dir).Lifecycle
+	if rules == nil {
+		rules = []model.LifecycleRule{}
+	}
+	return &model.Lifecycle{Rules: rules}, nil
+}
+
+func (provider *FSStorageProvider) SetNotifications(bucketName string,
+	notifications *model.Notifications) *management.Error {
+	dir, mErr := provider.existingBucket(bucketName)
+	if mErr != nil {
+		return mErr
+	}
+
+	config := provider.config(dir)
+	config.Notifications = notifications.Rules
+	if err := provider.writeConfig(dir, config); err != nil {
+		return internalError("set notifications of", bucketName, err)
+	}
+	return nil
+}
+
+func (provider *FSStorageProvider) GetNotifications(bucketName string) (*model.Notifications, *management.Error) {
+	dir, mErr := provider.existingBucket(bucketName)
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	rules := provider.config(dir).Notifications
+	if rules == nil {
+		rules = []model.NotificationRule{}
+	}
+	return &model.Notifications{Rules: rules}, nil
+}
+
+// replace writes the content of the source file with its metadata as the
+// object, archiving the object it replaces.
+func (provider *FSStorageProvider) replace(dir, name, fileName, sourcePath string, meta *metadata) *management.Error {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return fileError("copy file", fileName, err)
+	}
+	defer source.Close()
+
+	versionId, err := provider.archive(dir, name, fileName)
+	if err != nil {
+		return internalError("copy file", fileName, err)
+	}
+
+	if err := provider.writeFrom(filepath.Join(dir, OBJECTS_DIR, name), source); err != nil {
+		return internalError("copy file", fileName, err)
+	}
+
+	meta.Key = objectKey(fileName)
+	meta.VersionId = versionId
+	if err := provider.writeMetadata(dir, name, meta); err != nil {
+		return internalError("copy file", fileName, err)
+	}
+
+	return nil
+}
+
+// archive moves the object about to be replaced or deleted to its versions
+// when it has a version, or when versioning is enabled. It returns the
+// version of the object replacing it, empty when versioning is suspended.
+func (provider *FSStorageProvider) archive(dir, name, fileName string) (string, error) {
+	versioning := provider.config(dir).Versioning
+	versionId := ""
+	if versioning {
+		id := make([]byte, 4)
+		if _, err := rand.Read(id); err != nil {
+			return "", err
+		}
+		versionId = fmt.Sprintf("%016x%s", time.Now().UnixNano(), hex.EncodeToString(id))
+	}
+
+	path := filepath.Join(dir, OBJECTS_DIR, name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return versionId, nil
+	}
+
+	meta := provider.metadata(dir, fileName)
+	if !versioning && meta.VersionId == "" {
+		return versionId, nil
+	}
+
+	if meta.VersionId == "" {
+		meta.VersionId = NullVersionId
+	}
+
+	versionsDir := filepath.Join(dir, VERSIONS_DIR, name)
+	if err := os.MkdirAll(versionsDir, 0700); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return "", err
+	}
+
+	if err := provider.writeFile(filepath.Join(versionsDir, meta.VersionId+".json"), data); err != nil {
+		return "", err
+	}
+
+	return versionId, os.Rename(path, filepath.Join(versionsDir, meta.VersionId))
+}
+
+// bucketDir returns the directory of the bucket. Bucket names are a single
+// path element which is not hidden.
+func (provider *FSStorageProvider) bucketDir(bucketName string) (string, *management.Error) {
+	if bucketName == "" || strings.HasPrefix(bucketName, ".") || strings.ContainsAny(bucketName, `/\`) {
+		return "", management.GetBadRequestError(fmt.Sprintf("Error, invalid bucket name %s", bucketName))
+	}
+
+	return filepath.Join(provider.root, bucketName), nil
+}
+
+func (provider *FSStorageProvider) existingBucket(bucketName string) (string, *management.Error) {
+	dir, mErr := provider.bucketDir(bucketName)
+	if mErr != nil {
+		return "", mErr
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		return "", fileError("find bucket", bucketName, err)
+	}
+
+	return dir, nil
+}
+
+// objectPath returns the directory of the bucket and the file name of the
+// object under the objects and meta directories.
+func (provider *FSStorageProvider) objectPath(bucketName, fileName string) (string, string, *management.Error) {
+	dir, mErr := provider.existingBucket(bucketName)
+	if mErr != nil {
+		return "", "", mErr
+	}
+
+	name := escapeKey(fileName)
+	if objectKey(fileName) == "" || len(name) > maxNameLength {
+		return "", "", management.GetBadRequestError(fmt.Sprintf("Error, invalid file name %s", fileName))
+	}
+
+	return dir, name, nil
+}
+
+// upload returns the directory of the multipart upload of the file and
+// the upload as it was started.
+func (provider *FSStorageProvider) upload(bucketName, fileName, uploadId string) (string, *model.Upload, *management.Error) {
+	dir, _, mErr := provider.objectPath(bucketName, fileName)
+	if mErr != nil {
+		return "", nil, mErr
+	}
+
+	if _, err := hex.DecodeString(uploadId); err != nil || uploadId == "" {
+		return "", nil, management.GetNotFoundError(fmt.Sprintf("Error, upload %s not found", uploadId))
+	}
+
+	uploadDir := filepath.Join(dir, UPLOADS_DIR, uploadId)
+	data, err := ioutil.ReadFile(filepath.Join(uploadDir, UPLOAD_FILE))
+	if err != nil {
+		return "", nil, fileError("find upload", uploadId, err)
+	}
+
+	var upload model.Upload
+	if err := json.Unmarshal(data, &upload); err != nil {
+		return "", nil, internalError("read upload", uploadId, err)
+	}
+
+	if objectKey(upload.FileName) != objectKey(fileName) {
+		return "", nil, management.GetNotFoundError(
+			fmt.Sprintf("Error, upload %s of file %s not found", uploadId, fileName))
+	}
+
+	return uploadDir, &upload, nil
+}
+
+// metadata returns the sidecar of the object, the default one when it has
+// none.
+func (provider *FSStorageProvider) metadata(dir, key string) *metadata {
+	return readMetadata(filepath.Join(dir, META_DIR, escapeKey(key)), key)
+}
+
+func (provider *FSStorageProvider) config(dir string) *bucketConfig {
+	config := &bucketConfig{}
+	if data, err := ioutil.ReadFile(filepath.Join(dir, CONFIG_FILE)); err == nil {
+		json.Unmarshal(data, config)
+	}
+	return config
+}
+
+func (provider *FSStorageProvider) writeConfig(dir string, config *bucketConfig) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return provider.writeFile(filepath.Join(dir, CONFIG_FILE), data)
+}
+
+func (provider *FSStorageProvider) writeMetadata(dir, name string, meta *metadata) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return provider.writeFile(filepath.Join(dir, META_DIR, name), data)
+}
+
+func (provider *FSStorageProvider) writeFile(path string, data []byte) error {
+	return provider.writeFrom(path, strings.NewReader(string(data)))
+}
+
+// writeFrom writes the file atomically: the data is written and synced to
+// a temporary file renamed into place.
+func (provider *FSStorageProvider) writeFrom(path string, data io.Reader) error {
+	tmp, err := ioutil.TempFile(filepath.Join(provider.root, TMP_DIR), filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func appendPart(w io.Writer, uploadDir string, part model.Part) *management.Error {
+	file, err := os.Open(partPath(uploadDir, part.PartNumber))
+	if err != nil {
+		return management.GetBadRequestError(fmt.Sprintf("Error, part %d was not uploaded", part.PartNumber))
+	}
+	defer file.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(io.MultiWriter(w, hash), file); err != nil {
+		return internalError("assemble part", fmt.Sprint(part.PartNumber), err)
+	}
+
+	if etag(hash.Sum(nil)) != part.Etag {
+		return management.GetBadRequestError(fmt.Sprintf("Error, etag of part %d does not match", part.PartNumber))
+	}
+
+	return nil
+}
+
+func readMetadata(path, key string) *metadata {
+	meta := &metadata{Key: objectKey(key), ContentType: DefaultContentType}
+	if data, err := ioutil.ReadFile(path); err == nil {
+		json.Unmarshal(data, meta)
+	}
+	return meta
+}
+
+// lowerKeys lowers the keys of the user metadata, as S3 does.
+func lowerKeys(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	out := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		out[strings.ToLower(key)] = value
+	}
+	return out
+}
+
+func partPath(uploadDir string, partNumber int64) string {
+	return filepath.Join(uploadDir, fmt.Sprintf("part-%05d", partNumber))
+}
+
+// objectKey returns the key of the file name. Leading slashes are dropped,
+// as S3 does when the key is part of the request path.
+func objectKey(fileName string) string {
+	return strings.TrimLeft(fileName, "/")
+}
+
+// escapeKey escapes the key of an object into a file name, including its
+// slashes and a leading dot so no key names a directory.
+func escapeKey(key string) string {
+	name := url.PathEscape(objectKey(key))
+	if strings.HasPrefix(name, ".") {
+		name = "%2E" + name[1:]
+	}
+	return name
+}
+
+func etag(sum []byte) string {
+	return `"` + hex.EncodeToString(sum) + `"`
+}
+
+func fileError(action, name string, err error) *management.Error {
+	if os.IsNotExist(err) {
+		return management.GetNotFoundError(fmt.Sprintf("Error, failed to %s %s: not found", action, name))
+	}
+	return internalError(action, name, err)
+}
+
+func internalError(action, name string, err error) *management.Error {
+	mlog.Error("Failed to %s %s: %v", action, name, err)
+	return management.GetInternalError(fmt.Sprintf("Error, failed to %s %s with error: %s", action, name, err.Error()))
+}
@@ -0,0 +1,224 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fs
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/lavaorg/northstar/object/model"
+	"github.com/lavaorg/northstar/object/s3"
+	"github.com/stretchr/testify/require"
+)
+
+const Bucket = "a3a424b8-9a30-11e6-822b-acbc32d30e43_bucket"
+
+func newProvider(t *testing.T) (s3.StorageProvider, func()) {
+	root, err := ioutil.TempDir("", "object")
+	require.Nil(t, err)
+
+	provider, err := NewFSStorageProvider(root)
+	require.Nil(t, err)
+	require.Nil(t, provider.CreateBucket(Bucket))
+	return provider, func() { os.RemoveAll(root) }
+}
+
+func TestUploadDownload(t *testing.T) {
+	provider, cleanup := newProvider(t)
+	defer cleanup()
+
+	upload := &model.UploadData{FileName: "drop/../a.csv", Payload: []byte("a,b\n1,2\n"), ContentType: "text/csv"}
+	require.Nil(t, provider.Upload(Bucket, upload))
+
+	data, mErr := provider.Download(Bucket, "/drop/../a.csv")
+	require.Nil(t, mErr)
+	require.Equal(t, "a,b\n1,2\n", string(data.Payload))
+	require.Equal(t, "text/csv", data.ContentType)
+
+	data, mErr = provider.DownloadRange(Bucket, "drop/../a.csv", 4, 2)
+	require.Nil(t, mErr)
+	require.Equal(t, "1,", string(data.Payload))
+	require.Equal(t, int64(8), data.Size)
+
+	objects, _, mErr := provider.List(Bucket, "drop/")
+	require.Nil(t, mErr)
+	require.Len(t, objects, 1)
+	require.Equal(t, "drop/../a.csv", objects[0].Key)
+	require.Equal(t, int64(8), objects[0].Size)
+
+	_, mErr = provider.Download(Bucket, "a.csv")
+	require.Equal(t, http.StatusNotFound, mErr.HttpStatus)
+
+	require.Nil(t, provider.Delete(Bucket, "drop/../a.csv"))
+	require.Nil(t, provider.Delete(Bucket, "drop/../a.csv"))
+	require.Nil(t, provider.DeleteBucket(Bucket))
+}
+
+func TestMultipartUpload(t *testing.T) {
+	provider, cleanup := newProvider(t)
+	defer cleanup()
+
+	uploadId, mErr := provider.CreateUpload(Bucket, &model.Upload{FileName: "big.bin", ContentType: "application/x-test"})
+	require.Nil(t, mErr)
+
+	second, mErr := provider.UploadPart(Bucket, uploadId, &model.PartData{FileName: "big.bin", PartNumber: 2, Payload: []byte("world")})
+	require.Nil(t, mErr)
+	first, mErr := provider.UploadPart(Bucket, uploadId, &model.PartData{FileName: "big.bin", PartNumber: 1, Payload: []byte("hello ")})
+	require.Nil(t, mErr)
+
+	invalid := &model.Upload{UploadId: uploadId, FileName: "big.bin", Parts: []model.Part{{PartNumber: 1, Etag: second.Etag}}}
+	require.Equal(t, http.StatusBadRequest, provider.CompleteUpload(Bucket, invalid).HttpStatus)
+
+	upload := &model.Upload{UploadId: uploadId, FileName: "big.bin", Parts: []model.Part{*first, *second}}
+	require.Nil(t, provider.CompleteUpload(Bucket, upload))
+
+	data, mErr := provider.Download(Bucket, "big.bin")
+	require.Nil(t, mErr)
+	require.Equal(t, "hello world", string(data.Payload))
+	require.Equal(t, "application/x-test", data.ContentType)
+
+	require.Equal(t, http.StatusNotFound, provider.AbortUpload(Bucket, "big.bin", uploadId).HttpStatus)
+}
+
+func TestCopy(t *testing.T) {
+	provider, cleanup := newProvider(t)
+	defer cleanup()
+
+	require.Nil(t, provider.CreateBucket(Bucket+"-archive"))
+	require.Nil(t, provider.Upload(Bucket, &model.UploadData{FileName: "a.txt", Payload: []byte("a"), ContentType: "text/plain"}))
+	require.Nil(t, provider.Copy(Bucket, "a.txt", Bucket+"-archive", "2017/a.txt"))
+
+	object, mErr := provider.Stat(Bucket+"-archive", "2017/a.txt")
+	require.Nil(t, mErr)
+	require.Equal(t, int64(1), object.Size)
+	require.Equal(t, `"0cc175b9c0f1b6a831c399e269772661"`, object.Etag)
+
+	require.Equal(t, http.StatusConflict, provider.DeleteBucket(Bucket).HttpStatus)
+}
+
+func TestInvalidBucketName(t *testing.T) {
+	provider, cleanup := newProvider(t)
+	defer cleanup()
+
+	require.Equal(t, http.StatusBadRequest, provider.CreateBucket("../escape").HttpStatus)
+	require.Equal(t, http.StatusBadRequest, provider.CreateBucket(".tmp").HttpStatus)
+
+	buckets, mErr := provider.ListBuckets("")
+	require.Nil(t, mErr)
+	require.Equal(t, []string{Bucket}, []string{buckets[0].Name})
+	require.Len(t, buckets, 1)
+}
+
+func TestMetadataAndTags(t *testing.T) {
+	provider, cleanup := newProvider(t)
+	defer cleanup()
+
+	upload := &model.UploadData{FileName: "a.csv",
+		Payload:     []byte("a"),
+		ContentType: "text/csv",
+		Metadata:    map[string]string{"Source": "sensor-1"},
+		Tags:        map[string]string{"stage": "raw"}}
+	require.Nil(t, provider.Upload(Bucket, upload))
+
+	object, mErr := provider.Stat(Bucket, "a.csv")
+	require.Nil(t, mErr)
+	require.Equal(t, map[string]string{"source": "sensor-1"}, object.Metadata)
+	require.Equal(t, map[string]string{"stage": "raw"}, object.Tags)
+
+	data, mErr := provider.Download(Bucket, "a.csv")
+	require.Nil(t, mErr)
+	require.Equal(t, "sensor-1", data.Metadata["source"])
+
+	require.Nil(t, provider.Copy(Bucket, "a.csv", Bucket, "b.csv"))
+	object, mErr = provider.Stat(Bucket, "b.csv")
+	require.Nil(t, mErr)
+	require.Equal(t, map[string]string{"stage": "raw"}, object.Tags)
+}
+
+func TestVersioning(t *testing.T) {
+	provider, cleanup := newProvider(t)
+	defer cleanup()
+
+	upload := func(content string) {
+		require.Nil(t, provider.Upload(Bucket, &model.UploadData{FileName: "a.txt", Payload: []byte(content), ContentType: "text/plain"}))
+	}
+
+	upload("unversioned")
+	require.Nil(t, provider.SetVersioning(Bucket, true))
+	enabled, mErr := provider.GetVersioning(Bucket)
+	require.Nil(t, mErr)
+	require.True(t, enabled)
+
+	upload("first")
+	upload("second")
+	require.Nil(t, provider.Delete(Bucket, "a.txt"))
+
+	versions, mErr := provider.ListVersions(Bucket, "a.txt")
+	require.Nil(t, mErr)
+	require.Len(t, versions, 3)
+	require.False(t, versions[0].IsLatest)
+	require.Equal(t, NullVersionId, versions[2].VersionId)
+
+	require.Nil(t, provider.RestoreVersion(Bucket, "a.txt", versions[1].VersionId))
+	data, mErr := provider.Download(Bucket, "a.txt")
+	require.Nil(t, mErr)
+	require.Equal(t, "first", string(data.Payload))
+
+	versions, mErr = provider.ListVersions(Bucket, "a.txt")
+	require.Nil(t, mErr)
+	require.Len(t, versions, 4)
+	require.True(t, versions[0].IsLatest)
+
+	require.Nil(t, provider.RestoreVersion(Bucket, "a.txt", NullVersionId))
+	data, mErr = provider.Download(Bucket, "a.txt")
+	require.Nil(t, mErr)
+	require.Equal(t, "unversioned", string(data.Payload))
+
+	require.Equal(t, http.StatusNotFound, provider.RestoreVersion(Bucket, "a.txt", "../a.txt").HttpStatus)
+
+	// Suspended versioning replaces the file, keeping its previous versions.
+	require.Nil(t, provider.SetVersioning(Bucket, false))
+	upload("suspended")
+	upload("replaced")
+	versions, mErr = provider.ListVersions(Bucket, "a.txt")
+	require.Nil(t, mErr)
+	require.Len(t, versions, 6)
+	require.Equal(t, NullVersionId, versions[0].VersionId)
+}
+
+func TestLifecycleRules(t *testing.T) {
+	provider, cleanup := newProvider(t)
+	defer cleanup()
+
+	lifecycle, mErr := provider.GetLifecycle(Bucket)
+	require.Nil(t, mErr)
+	require.Empty(t, lifecycle.Rules)
+
+	rules := &model.Lifecycle{Rules: []model.LifecycleRule{{Id: "logs", Prefix: "logs/", ExpireDays: 30}}}
+	require.Nil(t, provider.SetLifecycle(Bucket, rules))
+
+	lifecycle, mErr = provider.GetLifecycle(Bucket)
+	require.Nil(t, mErr)
+	require.Equal(t, rules, lifecycle)
+
+	objects, _, mErr := provider.List(Bucket, "")
+	require.Nil(t, mErr)
+	require.Empty(t, objects)
+	require.Nil(t, provider.DeleteBucket(Bucket))
+}
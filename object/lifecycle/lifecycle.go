@@ -0,0 +1,156 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"strings"
+	"time"
+
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/object/model"
+	"github.com/lavaorg/northstar/object/notification"
+	"github.com/lavaorg/northstar/object/s3"
+	"github.com/lavaorg/northstar/object/stats"
+	"github.com/lavaorg/northstar/object/util"
+)
+
+// Job applies the lifecycle rules of the buckets of the storage provider.
+// The files it deletes and moves are notified as the requests of the
+// accounts are, when a notifier is set.
+type Job struct {
+	Notifier *notification.Notifier
+
+	provider s3.StorageProvider
+	interval int
+}
+
+// NewJob returns a job applying the rules every interval seconds.
+func NewJob(provider s3.StorageProvider, interval int) *Job {
+	return &Job{provider: provider, interval: interval}
+}
+
+// Start periodically applies the rules, unless the interval is below one
+// second.
+func (job *Job) Start() {
+	if job.interval < 1 {
+		mlog.Info("Lifecycle job disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(job.interval) * time.Second)
+		for range ticker.C {
+			job.Run(time.Now())
+		}
+	}()
+}
+
+// Run applies the rules of all the buckets at the time.
+func (job *Job) Run(now time.Time) {
+	stats.LifecycleRunCount.Incr()
+
+	buckets, mErr := job.provider.ListBuckets("")
+	if mErr != nil {
+		mlog.Error("Lifecycle: failed to list buckets: %v", mErr)
+		stats.ErrLifecycleCount.Incr()
+		return
+	}
+
+	for _, bucket := range buckets {
+		lifecycle, mErr := job.provider.GetLifecycle(bucket.Name)
+		if mErr != nil {
+			mlog.Error("Lifecycle: failed to get rules of bucket %s: %v", bucket.Name, mErr)
+			stats.ErrLifecycleCount.Incr()
+			continue
+		}
+
+		for i := range lifecycle.Rules {
+			job.apply(bucket.Name, &lifecycle.Rules[i], now)
+		}
+	}
+}
+
+// apply deletes the expired files of the rule and moves the files due for
+// a transition. A file both expired and due for a transition is deleted.
+func (job *Job) apply(bucketName string, rule *model.LifecycleRule, now time.Time) {
+	objects, _, mErr := job.provider.List(bucketName, rule.Prefix)
+	if mErr != nil {
+		mlog.Error("Lifecycle: failed to list files of bucket %s: %v", bucketName, mErr)
+		stats.ErrLifecycleCount.Incr()
+		return
+	}
+
+	for i := range objects {
+		object := &objects[i]
+		if rule.Expired(object, now) {
+			if mErr := job.provider.Delete(bucketName, object.Key); mErr != nil {
+				mlog.Error("Lifecycle: failed to expire %s/%s: %v", bucketName, object.Key, mErr)
+				stats.ErrLifecycleCount.Incr()
+				continue
+			}
+
+			mlog.Debug("Lifecycle: rule %s expired %s/%s", rule.Id, bucketName, object.Key)
+			stats.LifecycleExpiredCount.Incr()
+			job.notify(bucketName, &model.ObjectEvent{Type: model.ObjectDeleted, FileName: object.Key})
+			continue
+		}
+
+		toFileName := rule.Transitioned(object, now)
+		if toFileName == "" {
+			continue
+		}
+
+		if util.IsConfigFile(toFileName) {
+			mlog.Error("Lifecycle: rule %s can not move %s/%s to %s: %s", rule.Id, bucketName, object.Key, toFileName, util.FileNameReserved)
+			stats.ErrLifecycleCount.Incr()
+			continue
+		}
+
+		mErr := job.provider.Copy(bucketName, object.Key, bucketName, toFileName)
+		if mErr == nil {
+			mErr = job.provider.Delete(bucketName, object.Key)
+		}
+
+		if mErr != nil {
+			mlog.Error("Lifecycle: failed to move %s/%s to %s: %v", bucketName, object.Key, toFileName, mErr)
+			stats.ErrLifecycleCount.Incr()
+			continue
+		}
+
+		mlog.Debug("Lifecycle: rule %s moved %s/%s to %s", rule.Id, bucketName, object.Key, toFileName)
+		stats.LifecycleTransitionedCount.Incr()
+		job.notify(bucketName, &model.ObjectEvent{Type: model.ObjectCreated, FileName: toFileName, Size: object.Size})
+		job.notify(bucketName, &model.ObjectEvent{Type: model.ObjectDeleted, FileName: object.Key})
+	}
+}
+
+// notify notifies the event of a file of the bucket, named after its
+// account by the storage provider.
+func (job *Job) notify(bucketName string, event *model.ObjectEvent) {
+	if job.Notifier == nil {
+		return
+	}
+
+	name := strings.SplitN(bucketName, "_", 2)
+	if len(name) < 2 {
+		mlog.Error("Lifecycle: failed to notify %s of bucket %s, wrong name", event.Type, bucketName)
+		return
+	}
+
+	event.AccountId, event.Bucket, event.Time = name[0], name[1], time.Now()
+	job.Notifier.Notify(bucketName, event)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/northstar/object/fs"
+	"github.com/lavaorg/northstar/object/model"
+	"github.com/lavaorg/northstar/object/notification"
+	"github.com/lavaorg/northstar/object/s3"
+	"github.com/lavaorg/northstar/object/util"
+	eventsModel "github.com/lavaorg/northstar/processing/events/model"
+	"github.com/stretchr/testify/require"
+)
+
+const Bucket = "a3a424b8-9a30-11e6-822b-acbc32d30e43_bucket"
+
+type invoker struct {
+	invocations chan map[string]interface{}
+}
+
+func (invoker *invoker) InvokeEvent(accountId string, eventId string, options *eventsModel.Options) (string, *management.Error) {
+	invoker.invocations <- options.Args
+	return "", nil
+}
+
+func TestRun(t *testing.T) {
+	root, err := ioutil.TempDir("", "lifecycle")
+	require.Nil(t, err)
+	defer os.RemoveAll(root)
+
+	provider, err := fs.NewFSStorageProvider(root)
+	require.Nil(t, err)
+	require.Nil(t, provider.CreateBucket(Bucket))
+
+	for _, name := range []string{"logs/a.log", "logs/b.log", "raw/a.csv", "keep.txt"} {
+		upload := &model.UploadData{FileName: name, Payload: []byte(name), ContentType: "text/plain"}
+		require.Nil(t, provider.Upload(Bucket, upload))
+	}
+
+	lifecycle := &model.Lifecycle{Rules: []model.LifecycleRule{
+		{Id: "logs", Prefix: "logs/", ExpireDays: 7},
+		{Id: "raw", Prefix: "raw/", TransitionDays: 1, TransitionPrefix: "archive/raw/", ExpireDays: 30},
+	}}
+	require.Nil(t, lifecycle.Validate())
+	require.Nil(t, provider.SetLifecycle(Bucket, lifecycle))
+
+	job := NewJob(provider, 0)
+	job.Run(time.Now())
+	require.Equal(t, []string{"keep.txt", "logs/a.log", "logs/b.log", "raw/a.csv"}, keys(t, provider))
+
+	job.Run(time.Now().AddDate(0, 0, 2))
+	require.Equal(t, []string{"archive/raw/a.csv", "keep.txt", "logs/a.log", "logs/b.log"}, keys(t, provider))
+
+	data, mErr := provider.Download(Bucket, "archive/raw/a.csv")
+	require.Nil(t, mErr)
+	require.Equal(t, "raw/a.csv", string(data.Payload))
+
+	job.Run(time.Now().AddDate(0, 0, 8))
+	require.Equal(t, []string{"archive/raw/a.csv", "keep.txt"}, keys(t, provider))
+}
+
+func TestRunNotify(t *testing.T) {
+	root, err := ioutil.TempDir("", "lifecycle")
+	require.Nil(t, err)
+	defer os.RemoveAll(root)
+
+	provider, err := fs.NewFSStorageProvider(root)
+	require.Nil(t, err)
+	require.Nil(t, provider.CreateBucket(Bucket))
+
+	for _, name := range []string{"logs/a.log", "raw/a.csv"} {
+		upload := &model.UploadData{FileName: name, Payload: []byte(name), ContentType: "text/plain"}
+		require.Nil(t, provider.Upload(Bucket, upload))
+	}
+
+	lifecycle := &model.Lifecycle{Rules: []model.LifecycleRule{
+		{Id: "logs", Prefix: "logs/", ExpireDays: 7},
+		{Id: "raw", Prefix: "raw/", TransitionDays: 1, TransitionPrefix: "archive/raw/"},
+	}}
+	require.Nil(t, provider.SetLifecycle(Bucket, lifecycle))
+	require.Nil(t, provider.SetNotifications(Bucket, &model.Notifications{Rules: []model.NotificationRule{
+		{Id: "audit", EventId: "audit"},
+	}}))
+
+	invoker := &invoker{invocations: make(chan map[string]interface{}, 10)}
+	notifier := notification.NewNotifier(provider, invoker, 10)
+	notifier.Start()
+
+	job := NewJob(provider, 0)
+	job.Notifier = notifier
+	job.Run(time.Now().AddDate(0, 0, 8))
+
+	events := map[string]string{}
+	for i := 0; i < 3; i++ {
+		select {
+		case args := <-invoker.invocations:
+			require.Equal(t, "bucket", args["bucket"])
+			events[args["fileName"].(string)] = args["type"].(string)
+		case <-time.After(time.Second):
+			t.Fatalf("expected 3 events, got %v", events)
+		}
+	}
+	require.Equal(t, map[string]string{
+		"logs/a.log":        model.ObjectDeleted,
+		"raw/a.csv":         model.ObjectDeleted,
+		"archive/raw/a.csv": model.ObjectCreated,
+	}, events)
+}
+
+func TestValidateReserved(t *testing.T) {
+	for _, rule := range []model.LifecycleRule{
+		{Id: "config", Prefix: util.ConfigPrefix, ExpireDays: 1},
+		{Id: "move", Prefix: "x/", TransitionDays: 1, TransitionPrefix: util.ConfigPrefix},
+		{Id: "slash", Prefix: "x/", TransitionDays: 1, TransitionPrefix: "/" + util.ConfigPrefix + "x/"},
+		{Id: "dots", Prefix: "x/", TransitionDays: 1, TransitionPrefix: "y/../" + util.ConfigPrefix},
+		{Id: "short", Prefix: "x/", TransitionDays: 1, TransitionPrefix: ".north"},
+	} {
+		require.EqualError(t, rule.Validate(), util.FileNameReserved, rule.Id)
+	}
+
+	rule := &model.LifecycleRule{Id: "archive", Prefix: "x/", TransitionDays: 1, TransitionPrefix: "archive/"}
+	require.Nil(t, rule.Validate())
+}
+
+func keys(t *testing.T, provider s3.StorageProvider) []string {
+	objects, _, mErr := provider.List(Bucket, "")
+	require.Nil(t, mErr)
+
+	names := []string{}
+	for _, object := range objects {
+		names = append(names, object.Key)
+	}
+	return names
+}
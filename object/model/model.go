@@ -18,6 +18,7 @@ package model
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/lavaorg/northstar/object/util"
@@ -28,18 +29,26 @@ type Bucket struct {
 	CreationDate time.Time `json:"creationDate,omitempty"`
 }
 
+// Object is the metadata of a file. The user metadata and tags are only
+// returned for a single file, and the version for the versions of a file.
 type Object struct {
-	Key          string    `json:"name,omitempty"`
-	LastModified time.Time `json:"lastModified,omitempty"`
-	Size         int64     `json:"size,omitempty"`
-	Etag         string    `json:"etag,omitempty"`
-	StorageClass string    `json:"storageClass,omitempty"`
+	Key          string            `json:"name,omitempty"`
+	LastModified time.Time         `json:"lastModified,omitempty"`
+	Size         int64             `json:"size,omitempty"`
+	Etag         string            `json:"etag,omitempty"`
+	StorageClass string            `json:"storageClass,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+	VersionId    string            `json:"versionId,omitempty"`
+	IsLatest     bool              `json:"isLatest,omitempty"`
 }
 
 type UploadData struct {
-	FileName    string `json:"fileName,omitempty"`
-	Payload     []byte `json:"payload,omitempty"`
-	ContentType string `json:"contentType,omitempty"`
+	FileName    string            `json:"fileName,omitempty"`
+	Payload     []byte            `json:"payload,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
 }
 
 func (upload *UploadData) Validate() error {
@@ -55,10 +64,406 @@ func (upload *UploadData) Validate() error {
 		return fmt.Errorf(util.ContentTypeMissing)
 	}
 
-	return nil
+	return validateAttributes(upload.Metadata, upload.Tags)
 }
 
+// DownloadData is the content of a file. For a ranged download Offset is
+// the position of the payload in the file and Size the size of the file.
 type DownloadData struct {
-	Payload     []byte `json:"payload,omitempty"`
-	ContentType string `json:"contentType,omitempty"`
+	Payload     []byte            `json:"payload,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	Offset      int64             `json:"offset,omitempty"`
+	Size        int64             `json:"size,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// Upload is a multipart upload of a file: its parts are uploaded one by one
+// and assembled into the file when the upload is completed.
+type Upload struct {
+	UploadId    string            `json:"uploadId,omitempty"`
+	FileName    string            `json:"fileName,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	Parts       []Part            `json:"parts,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+func (upload *Upload) Validate() error {
+	if upload.FileName == "" {
+		return fmt.Errorf(util.FileNameMissing)
+	}
+
+	if upload.UploadId == "" && upload.ContentType == "" {
+		return fmt.Errorf(util.ContentTypeMissing)
+	}
+
+	if upload.UploadId != "" && len(upload.Parts) == 0 {
+		return fmt.Errorf(util.PartsMissing)
+	}
+
+	return validateAttributes(upload.Metadata, upload.Tags)
+}
+
+type Part struct {
+	PartNumber int64  `json:"partNumber,omitempty"`
+	Etag       string `json:"etag,omitempty"`
+}
+
+type PartData struct {
+	FileName   string `json:"fileName,omitempty"`
+	PartNumber int64  `json:"partNumber,omitempty"`
+	Payload    []byte `json:"payload,omitempty"`
+}
+
+func (part *PartData) Validate() error {
+	if part.FileName == "" {
+		return fmt.Errorf(util.FileNameMissing)
+	}
+
+	if part.PartNumber < 1 || part.PartNumber > util.MaxParts {
+		return fmt.Errorf(util.PartNumberInvalid)
+	}
+
+	if len(part.Payload) == 0 {
+		return fmt.Errorf(util.PayloadMissing)
+	}
+
+	return nil
+}
+
+// CopyData copies the file to another name or bucket of the account, and
+// deletes the file when it is moved.
+type CopyData struct {
+	FileName   string `json:"fileName,omitempty"`
+	ToBucket   string `json:"toBucket,omitempty"`
+	ToFileName string `json:"toFileName,omitempty"`
+	Move       bool   `json:"move,omitempty"`
+}
+
+func (data *CopyData) Validate() error {
+	if data.FileName == "" {
+		return fmt.Errorf(util.FileNameMissing)
+	}
+
+	if data.ToBucket == "" && data.ToFileName == "" {
+		return fmt.Errorf(util.DestinationMissing)
+	}
+
+	return nil
+}
+
+// Filter selects the files of a listing by the prefix of their name and
+// by the values of their tags and user metadata.
+type Filter struct {
+	Prefix   string            `json:"prefix,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// HasAttributes reports whether the filter needs the tags or metadata of
+// the files, which are not part of a listing.
+func (filter *Filter) HasAttributes() bool {
+	return len(filter.Tags) > 0 || len(filter.Metadata) > 0
+}
+
+func (filter *Filter) Matches(object *Object) bool {
+	if !strings.HasPrefix(object.Key, filter.Prefix) {
+		return false
+	}
+
+	for key, value := range filter.Tags {
+		if tag, ok := object.Tags[key]; !ok || tag != value {
+			return false
+		}
+	}
+
+	for key, value := range filter.Metadata {
+		if meta, ok := object.Metadata[strings.ToLower(key)]; !ok || meta != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Versioning is the versioning state of a bucket. When it is enabled, the
+// previous versions of a file are kept when it is replaced or deleted.
+type Versioning struct {
+	Enabled bool `json:"enabled"`
+}
+
+// RestoreData makes a previous version of the file its current version.
+type RestoreData struct {
+	FileName  string `json:"fileName,omitempty"`
+	VersionId string `json:"versionId,omitempty"`
+}
+
+func (data *RestoreData) Validate() error {
+	if data.FileName == "" {
+		return fmt.Errorf(util.FileNameMissing)
+	}
+
+	if data.VersionId == "" {
+		return fmt.Errorf(util.VersionIdMissing)
+	}
+
+	return nil
+}
+
+// Lifecycle is the set of lifecycle rules of a bucket, applied by the
+// lifecycle job of the object service.
+type Lifecycle struct {
+	Rules []LifecycleRule `json:"rules"`
+}
+
+func (lifecycle *Lifecycle) Validate() error {
+	ids := make(map[string]bool)
+	for i := range lifecycle.Rules {
+		rule := &lifecycle.Rules[i]
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+
+		if ids[rule.Id] {
+			return fmt.Errorf(util.RuleIdDuplicate)
+		}
+		ids[rule.Id] = true
+	}
+
+	return nil
+}
+
+// LifecycleRule applies to the files whose name starts with the prefix.
+// The files are deleted ExpireDays after their last modification, and
+// moved under TransitionPrefix TransitionDays after it. A move modifies
+// the file, so the age of a moved file starts over.
+type LifecycleRule struct {
+	Id               string `json:"id,omitempty"`
+	Prefix           string `json:"prefix,omitempty"`
+	ExpireDays       int    `json:"expireDays,omitempty"`
+	TransitionDays   int    `json:"transitionDays,omitempty"`
+	TransitionPrefix string `json:"transitionPrefix,omitempty"`
+}
+
+func (rule *LifecycleRule) Validate() error {
+	if rule.Id == "" {
+		return fmt.Errorf(util.RuleIdMissing)
+	}
+
+	if rule.ExpireDays < 0 || rule.TransitionDays < 0 {
+		return fmt.Errorf(util.RuleDaysInvalid)
+	}
+
+	if rule.ExpireDays == 0 && rule.TransitionDays == 0 {
+		return fmt.Errorf(util.RuleActionMissing)
+	}
+
+	if (rule.TransitionDays == 0) != (rule.TransitionPrefix == "") {
+		return fmt.Errorf(util.RuleTransitionInvalid)
+	}
+
+	// Moved files must not match the rule again.
+	if rule.TransitionPrefix != "" && strings.HasPrefix(rule.TransitionPrefix, rule.Prefix) {
+		return fmt.Errorf(util.RuleTransitionInvalid)
+	}
+
+	// Nor be moved over the bucket configuration. The file names complete
+	// a prefix the configuration prefix starts with, e.g. ".north".
+	transition := strings.TrimLeft(rule.TransitionPrefix, "/")
+	if util.IsConfigFile(rule.Prefix) || util.IsConfigFile(transition) ||
+		(transition != "" && strings.HasPrefix(util.ConfigPrefix, transition)) {
+		return fmt.Errorf(util.FileNameReserved)
+	}
+
+	return nil
+}
+
+// Expired reports whether the file is to be deleted at the time.
+func (rule *LifecycleRule) Expired(object *Object, now time.Time) bool {
+	return rule.ExpireDays > 0 && !now.Before(object.LastModified.AddDate(0, 0, rule.ExpireDays))
+}
+
+// Transitioned returns the name the file is to be moved to at the time, or
+// an empty name.
+func (rule *LifecycleRule) Transitioned(object *Object, now time.Time) string {
+	if rule.TransitionDays == 0 || now.Before(object.LastModified.AddDate(0, 0, rule.TransitionDays)) {
+		return ""
+	}
+
+	return rule.TransitionPrefix + strings.TrimPrefix(object.Key, rule.Prefix)
+}
+
+// validateAttributes checks the user metadata and tags of a file against
+// the limits of S3.
+func validateAttributes(metadata, tags map[string]string) error {
+	size := 0
+	for key, value := range metadata {
+		if key == "" {
+			return fmt.Errorf(util.MetadataInvalid)
+		}
+		size += len(key) + len(value)
+	}
+
+	if size > util.MaxMetadataSize {
+		return fmt.Errorf(util.MetadataInvalid)
+	}
+
+	if len(tags) > util.MaxTags {
+		return fmt.Errorf(util.TagsInvalid)
+	}
+
+	for key, value := range tags {
+		if key == "" || len(key) > util.MaxTagKeyLength || len(value) > util.MaxTagValueLength {
+			return fmt.Errorf(util.TagsInvalid)
+		}
+	}
+
+	return nil
+}
+
+// The types of the events of the files of a bucket.
+const (
+	ObjectCreated = "object-created"
+	ObjectDeleted = "object-deleted"
+)
+
+// Notifications is the set of notification rules of a bucket, invoking
+// events when files are created or deleted.
+type Notifications struct {
+	Rules []NotificationRule `json:"rules"`
+}
+
+func (notifications *Notifications) Validate() error {
+	ids := make(map[string]bool)
+	for i := range notifications.Rules {
+		rule := &notifications.Rules[i]
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+
+		if ids[rule.Id] {
+			return fmt.Errorf(util.RuleIdDuplicate)
+		}
+		ids[rule.Id] = true
+	}
+
+	return nil
+}
+
+// NotificationRule invokes the event, and so the snippet mapped to it, for
+// the files whose name starts with the prefix. The rule applies to the
+// listed types of events, or to all of them when none is.
+type NotificationRule struct {
+	Id      string   `json:"id,omitempty"`
+	EventId string   `json:"eventId,omitempty"`
+	Prefix  string   `json:"prefix,omitempty"`
+	Types   []string `json:"types,omitempty"`
+}
+
+func (rule *NotificationRule) Validate() error {
+	if rule.Id == "" {
+		return fmt.Errorf(util.RuleIdMissing)
+	}
+
+	if rule.EventId == "" {
+		return fmt.Errorf(util.EventIdMissing)
+	}
+
+	for _, eventType := range rule.Types {
+		if eventType != ObjectCreated && eventType != ObjectDeleted {
+			return fmt.Errorf(util.EventTypeInvalid)
+		}
+	}
+
+	return nil
+}
+
+func (rule *NotificationRule) Matches(event *ObjectEvent) bool {
+	if !strings.HasPrefix(event.FileName, rule.Prefix) {
+		return false
+	}
+
+	if len(rule.Types) == 0 {
+		return true
+	}
+
+	for _, eventType := range rule.Types {
+		if eventType == event.Type {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ObjectEvent is the creation or deletion of a file of a bucket.
+type ObjectEvent struct {
+	Type        string    `json:"type"`
+	AccountId   string    `json:"accountId"`
+	Bucket      string    `json:"bucket"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType,omitempty"`
+	Size        int64     `json:"size,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// Args returns the event as the arguments of the snippet it invokes.
+func (event *ObjectEvent) Args() map[string]interface{} {
+	args := map[string]interface{}{
+		"type":     event.Type,
+		"bucket":   event.Bucket,
+		"fileName": event.FileName,
+		"time":     event.Time.UTC().Format(time.RFC3339),
+	}
+
+	if event.Type == ObjectCreated {
+		args["size"] = event.Size
+	}
+
+	if event.ContentType != "" {
+		args["contentType"] = event.ContentType
+	}
+
+	return args
+}
+
+// SignRequest requests a link to download (GET) or upload (PUT) a file of
+// a bucket without the credentials of the account, for a number of seconds.
+type SignRequest struct {
+	FileName  string `json:"fileName,omitempty"`
+	Method    string `json:"method,omitempty"`
+	ExpiresIn int64  `json:"expiresIn,omitempty"`
+}
+
+// Validate defaults the request to a download link expiring after
+// util.DefaultSignedUrlExpiry seconds.
+func (request *SignRequest) Validate() error {
+	if request.FileName == "" {
+		return fmt.Errorf(util.FileNameMissing)
+	}
+
+	if request.Method == "" {
+		request.Method = "GET"
+	}
+	request.Method = strings.ToUpper(request.Method)
+	if request.Method != "GET" && request.Method != "PUT" {
+		return fmt.Errorf(util.MethodInvalid)
+	}
+
+	if request.ExpiresIn == 0 {
+		request.ExpiresIn = util.DefaultSignedUrlExpiry
+	}
+	if request.ExpiresIn < 0 || request.ExpiresIn > util.MaxSignedUrlExpiry {
+		return fmt.Errorf(util.ExpiryInvalid)
+	}
+
+	return nil
+}
+
+// SignedUrl is a link to download or upload a file until it expires. The
+// url is a path of the object service when it has no public url.
+type SignedUrl struct {
+	Url     string    `json:"url"`
+	Method  string    `json:"method"`
+	Expires time.Time `json:"expires"`
 }
@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/object/model"
+	"github.com/lavaorg/northstar/object/s3"
+	"github.com/lavaorg/northstar/object/stats"
+	eventsModel "github.com/lavaorg/northstar/processing/events/model"
+)
+
+// Invoker invokes the events of an account, as the events client of the
+// processing service does.
+type Invoker interface {
+	InvokeEvent(accountId string, eventId string, options *eventsModel.Options) (string, *management.Error)
+}
+
+type notification struct {
+	bucketName string
+	event      *model.ObjectEvent
+}
+
+// Notifier invokes the events of the notification rules matching the
+// events of the files. The events are queued and invoked in the background
+// so the requests on the files do not wait for the snippets.
+type Notifier struct {
+	provider s3.StorageProvider
+	invoker  Invoker
+	queue    chan *notification
+}
+
+// NewNotifier returns a notifier queuing up to size events.
+func NewNotifier(provider s3.StorageProvider, invoker Invoker, size int) *Notifier {
+	return &Notifier{provider: provider, invoker: invoker, queue: make(chan *notification, size)}
+}
+
+// Start invokes the queued events.
+func (notifier *Notifier) Start() {
+	go func() {
+		for n := range notifier.queue {
+			notifier.notify(n.bucketName, n.event)
+		}
+	}()
+}
+
+// Notify queues the event of a file of the bucket. The event is dropped
+// when the queue is full.
+func (notifier *Notifier) Notify(bucketName string, event *model.ObjectEvent) {
+	select {
+	case notifier.queue <- &notification{bucketName: bucketName, event: event}:
+	default:
+		mlog.Error("Notifications: queue full, dropping %s of %s/%s", event.Type, event.Bucket, event.FileName)
+		stats.ErrNotificationDroppedCount.Incr()
+	}
+}
+
+func (notifier *Notifier) notify(bucketName string, event *model.ObjectEvent) {
+	notifications, mErr := notifier.provider.GetNotifications(bucketName)
+	if mErr != nil {
+		mlog.Error("Notifications: failed to get rules of bucket %s: %v", bucketName, mErr)
+		stats.ErrNotificationCount.Incr()
+		return
+	}
+
+	for i := range notifications.Rules {
+		rule := &notifications.Rules[i]
+		if !rule.Matches(event) {
+			continue
+		}
+
+		options := &eventsModel.Options{Args: event.Args()}
+		if _, mErr := notifier.invoker.InvokeEvent(event.AccountId, rule.EventId, options); mErr != nil {
+			mlog.Error("Notifications: rule %s failed to invoke event %s: %v", rule.Id, rule.EventId, mErr)
+			stats.ErrNotificationCount.Incr()
+			continue
+		}
+
+		mlog.Debug("Notifications: rule %s invoked event %s for %s/%s", rule.Id, rule.EventId, event.Bucket, event.FileName)
+		stats.NotificationCount.Incr()
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/northstar/object/fs"
+	"github.com/lavaorg/northstar/object/model"
+	eventsModel "github.com/lavaorg/northstar/processing/events/model"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	AccountId = "a3a424b8-9a30-11e6-822b-acbc32d30e43"
+	Bucket    = AccountId + "_bucket"
+)
+
+type invocation struct {
+	eventId string
+	args    map[string]interface{}
+}
+
+type invoker struct {
+	invocations chan invocation
+}
+
+func (invoker *invoker) InvokeEvent(accountId string, eventId string, options *eventsModel.Options) (string, *management.Error) {
+	invoker.invocations <- invocation{eventId: eventId, args: options.Args}
+	return "", nil
+}
+
+func TestNotify(t *testing.T) {
+	root, err := ioutil.TempDir("", "notification")
+	require.Nil(t, err)
+	defer os.RemoveAll(root)
+
+	provider, err := fs.NewFSStorageProvider(root)
+	require.Nil(t, err)
+	require.Nil(t, provider.CreateBucket(Bucket))
+
+	notifications := &model.Notifications{Rules: []model.NotificationRule{
+		{Id: "drop", EventId: "ingest", Prefix: "drop/", Types: []string{model.ObjectCreated}},
+		{Id: "audit", EventId: "audit"},
+	}}
+	require.Nil(t, notifications.Validate())
+	require.Nil(t, provider.SetNotifications(Bucket, notifications))
+
+	invoker := &invoker{invocations: make(chan invocation, 10)}
+	notifier := NewNotifier(provider, invoker, 10)
+	notifier.Start()
+
+	now := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	notifier.Notify(Bucket, &model.ObjectEvent{Type: model.ObjectCreated,
+		AccountId:   AccountId,
+		Bucket:      "bucket",
+		FileName:    "drop/a.csv",
+		ContentType: "text/csv",
+		Size:        8,
+		Time:        now})
+
+	invoked := map[string]map[string]interface{}{}
+	for i := 0; i < 2; i++ {
+		invocation := <-invoker.invocations
+		invoked[invocation.eventId] = invocation.args
+	}
+
+	require.Equal(t, map[string]interface{}{
+		"type":        model.ObjectCreated,
+		"bucket":      "bucket",
+		"fileName":    "drop/a.csv",
+		"contentType": "text/csv",
+		"size":        int64(8),
+		"time":        "2017-06-01T12:00:00Z",
+	}, invoked["ingest"])
+	require.Equal(t, invoked["ingest"], invoked["audit"])
+
+	notifier.Notify(Bucket, &model.ObjectEvent{Type: model.ObjectDeleted, AccountId: AccountId, Bucket: "bucket", FileName: "drop/a.csv", Time: now})
+	invocation := <-invoker.invocations
+	require.Equal(t, "audit", invocation.eventId)
+	require.Equal(t, model.ObjectDeleted, invocation.args["type"])
+
+	select {
+	case invocation := <-invoker.invocations:
+		t.Fatalf("unexpected invocation of event %s", invocation.eventId)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestInvalidRules(t *testing.T) {
+	invalid := &model.Notifications{Rules: []model.NotificationRule{{Id: "drop", EventId: "ingest", Types: []string{"object-updated"}}}}
+	require.NotNil(t, invalid.Validate())
+
+	duplicated := &model.Notifications{Rules: []model.NotificationRule{{Id: "drop", EventId: "a"}, {Id: "drop", EventId: "b"}}}
+	require.NotNil(t, duplicated.Validate())
+}
@@ -20,15 +20,18 @@ import (
 	"crypto/hmac"
 	"crypto/sha1"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"bytes"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/corehandlers"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/defaults"
@@ -39,6 +42,14 @@ import (
 	"github.com/lavaorg/lrtx/management"
 	"github.com/lavaorg/lrtx/mlog"
 	"github.com/lavaorg/northstar/object/model"
+	"github.com/lavaorg/northstar/object/util"
+)
+
+// The lifecycle and notification rules of a bucket are stored in the
+// bucket.
+const (
+	LifecycleKey     = util.ConfigPrefix + "lifecycle.json"
+	NotificationsKey = util.ConfigPrefix + "notifications.json"
 )
 
 // Define the parameters used to sign the request.
@@ -307,15 +318,16 @@ func (S3StorageProvider *S3StorageProvider) List(bucketName,
 	}
 
 	// Generate the string items
-	str := make([]model.Object, len(response.Contents), len(response.Contents))
-	for index, item := range response.Contents {
-		if item.Key != nil {
-			str[index] = model.Object{Key: *item.Key,
+	str := make([]model.Object, 0, len(response.Contents))
+	for _, item := range response.Contents {
+		if item.Key != nil && !strings.HasPrefix(*item.Key, util.ConfigPrefix) {
+			object := model.Object{Key: *item.Key,
 				Etag:         *item.ETag,
 				LastModified: *item.LastModified,
 				Size:         *item.Size,
 				StorageClass: *item.StorageClass}
-			mlog.Debug("Data is %v, str[index]=%v", item, str[index])
+			mlog.Debug("Data is %v, object=%v", item, object)
+			str = append(str, object)
 		}
 	}
 	// Set the next marker
@@ -344,6 +356,14 @@ func (S3StorageProvider *S3StorageProvider) Upload(bucketName string,
 		params.ContentType = aws.String(data.ContentType)
 	}
 
+	if len(data.Metadata) > 0 {
+		params.Metadata = aws.StringMap(data.Metadata)
+	}
+
+	if len(data.Tags) > 0 {
+		params.Tagging = aws.String(encodeTags(data.Tags))
+	}
+
 	response, err := S3StorageProvider.S3Storage.PutObject(params)
 	if err != nil {
 		// If external error, alert external service error.
@@ -382,7 +402,9 @@ func (S3StorageProvider *S3StorageProvider) Download(bucketName,
 	}
 
 	mlog.Debug("Downloaded file %s successfully", fileName)
-	return &model.DownloadData{ContentType: *response.ContentType, Payload: data}, nil
+	return &model.DownloadData{ContentType: *response.ContentType,
+		Payload:  data,
+		Metadata: metadataMap(response.Metadata)}, nil
 }
 
 func (S3StorageProvider *S3StorageProvider) CreateBucket(bucketName string) *management.Error {
@@ -406,12 +428,67 @@ func (S3StorageProvider *S3StorageProvider) CreateBucket(bucketName string) *man
 func (S3StorageProvider *S3StorageProvider) DeleteBucket(bucketName string) *management.Error {
 	mlog.Debug("DeleteBucket - bucketName: %s", bucketName)
 
+	// The rules are stored in the bucket and would keep it from being
+	// empty. They are only deleted when nothing else, including old
+	// versions, is left, and restored when the bucket can not be deleted.
+	configs, mErr := S3StorageProvider.configVersions(bucketName)
+	if mErr != nil {
+		return mErr
+	}
+
+	lifecycle, mErr := S3StorageProvider.GetLifecycle(bucketName)
+	if mErr != nil {
+		return mErr
+	}
+
+	notifications, mErr := S3StorageProvider.GetNotifications(bucketName)
+	if mErr != nil {
+		return mErr
+	}
+
+	restore := func() {
+		if mErr := S3StorageProvider.SetLifecycle(bucketName, lifecycle); mErr != nil {
+			mlog.Error("Failed to restore lifecycle rules of bucket %s: %v", bucketName, mErr)
+		}
+
+		if mErr := S3StorageProvider.SetNotifications(bucketName, notifications); mErr != nil {
+			mlog.Error("Failed to restore notification rules of bucket %s: %v", bucketName, mErr)
+		}
+	}
+
+	// At most 1000 objects are deleted per request.
+	for len(configs) > 0 {
+		count := len(configs)
+		if count > 1000 {
+			count = 1000
+		}
+
+		params := &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucketName),
+			Delete: &s3.Delete{Objects: configs[:count], Quiet: aws.Bool(true)},
+		}
+		configs = configs[count:]
+
+		response, err := S3StorageProvider.S3Storage.DeleteObjects(params)
+		if err == nil && len(response.Errors) > 0 {
+			err = fmt.Errorf("%s: %s", aws.StringValue(response.Errors[0].Key),
+				aws.StringValue(response.Errors[0].Message))
+		}
+
+		if err != nil {
+			restore()
+			return management.GetExternalError(
+				fmt.Sprintf("Error, failed to delete rules of bucket %s with error: %s", bucketName, err.Error()))
+		}
+	}
+
 	params := &s3.DeleteBucketInput{
 		Bucket: aws.String(bucketName),
 	}
 
 	response, err := S3StorageProvider.S3Storage.DeleteBucket(params)
 	if err != nil {
+		restore()
 		return management.GetExternalError(
 			fmt.Sprintf("Error, failed to delete bucket %s with error: %s", bucketName, err.Error()))
 	}
@@ -420,6 +497,47 @@ func (S3StorageProvider *S3StorageProvider) DeleteBucket(bucketName string) *man
 	return nil
 }
 
+// configVersions returns the versions of the rules stored in the bucket, or
+// a conflict error when the bucket holds any other file or version.
+func (S3StorageProvider *S3StorageProvider) configVersions(bucketName string) ([]*s3.ObjectIdentifier,
+	*management.Error) {
+	params := &s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucketName),
+	}
+
+	configs := []*s3.ObjectIdentifier{}
+	empty := true
+	add := func(key, versionId *string) {
+		if !strings.HasPrefix(aws.StringValue(key), util.ConfigPrefix) {
+			empty = false
+			return
+		}
+
+		configs = append(configs, &s3.ObjectIdentifier{Key: key, VersionId: versionId})
+	}
+
+	err := S3StorageProvider.S3Storage.ListObjectVersionsPages(params,
+		func(page *s3.ListObjectVersionsOutput, lastPage bool) bool {
+			for _, version := range page.Versions {
+				add(version.Key, version.VersionId)
+			}
+			for _, marker := range page.DeleteMarkers {
+				add(marker.Key, marker.VersionId)
+			}
+			return empty
+		})
+	if err != nil {
+		return nil, management.GetExternalError(
+			fmt.Sprintf("Error, failed to list files of bucket %s with error: %s", bucketName, err.Error()))
+	}
+
+	if !empty {
+		return nil, management.GetConflictError(fmt.Sprintf("Error, bucket %s is not empty", bucketName))
+	}
+
+	return configs, nil
+}
+
 func (S3StorageProvider *S3StorageProvider) ListBuckets(nameFilter string) ([]model.Bucket,
 	*management.Error) {
 	mlog.Debug("ListBuckets start")
@@ -452,3 +570,426 @@ func (S3StorageProvider *S3StorageProvider) ListBuckets(nameFilter string) ([]mo
 	mlog.Debug("List buckets successfull: %v", len(buckets))
 	return buckets, nil
 }
+
+// Download a range of given file from S3
+func (S3StorageProvider *S3StorageProvider) DownloadRange(bucketName,
+	fileName string, offset, length int64) (*model.DownloadData, *management.Error) {
+
+	mlog.Debug("GetRange - fileName: %s offset: %d length: %d", fileName, offset, length)
+	byteRange := fmt.Sprintf("bytes=%d-", offset)
+	if length > 0 {
+		byteRange = fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	}
+
+	params := &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(fileName),
+		Range:  aws.String(byteRange),
+	}
+
+	response, err := S3StorageProvider.S3Storage.GetObject(params)
+	if err != nil {
+		return nil, management.GetExternalError(
+			fmt.Sprintf("Error, failed to get range of file %s with error: %s", fileName, err.Error()))
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		mlog.Error("GetRange(): ioutil.ReadAll failed due to %v", err)
+		return nil, management.GetInternalError(
+			fmt.Sprintf("Error, failed to read file %s with error: %s", fileName, err.Error()))
+	}
+
+	// The content range is "bytes <first>-<last>/<size>".
+	size := offset + int64(len(data))
+	if response.ContentRange != nil {
+		contentRange := *response.ContentRange
+		if total, err := strconv.ParseInt(contentRange[strings.LastIndex(contentRange, "/")+1:], 10, 64); err == nil {
+			size = total
+		}
+	}
+
+	contentType := ""
+	if response.ContentType != nil {
+		contentType = *response.ContentType
+	}
+
+	return &model.DownloadData{ContentType: contentType,
+		Payload:  data,
+		Offset:   offset,
+		Size:     size,
+		Metadata: metadataMap(response.Metadata)}, nil
+}
+
+// Returns the metadata of given file
+func (S3StorageProvider *S3StorageProvider) Stat(bucketName,
+	fileName string) (*model.Object, *management.Error) {
+	mlog.Debug("Stat - fileName: %s", fileName)
+
+	params := &s3.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(fileName),
+	}
+
+	response, err := S3StorageProvider.S3Storage.HeadObject(params)
+	if err != nil {
+		return nil, management.GetExternalError(
+			fmt.Sprintf("Error, failed to stat file %s with error: %s", fileName, err.Error()))
+	}
+
+	tagging, err := S3StorageProvider.S3Storage.GetObjectTagging(&s3.GetObjectTaggingInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(fileName),
+	})
+	if err != nil {
+		return nil, management.GetExternalError(
+			fmt.Sprintf("Error, failed to get tags of file %s with error: %s", fileName, err.Error()))
+	}
+
+	var tags map[string]string
+	if len(tagging.TagSet) > 0 {
+		tags = make(map[string]string)
+		for _, tag := range tagging.TagSet {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+	}
+
+	return &model.Object{Key: fileName,
+		Etag:         aws.StringValue(response.ETag),
+		LastModified: aws.TimeValue(response.LastModified),
+		Size:         aws.Int64Value(response.ContentLength),
+		StorageClass: aws.StringValue(response.StorageClass),
+		Metadata:     metadataMap(response.Metadata),
+		Tags:         tags,
+		VersionId:    aws.StringValue(response.VersionId)}, nil
+}
+
+// Starts a multipart upload of given file to S3
+func (S3StorageProvider *S3StorageProvider) CreateUpload(bucketName string,
+	upload *model.Upload) (string, *management.Error) {
+	mlog.Debug("CreateUpload - fileName: %s", upload.FileName)
+
+	params := &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(upload.FileName),
+		ACL:         aws.String("private"),
+		ContentType: aws.String(upload.ContentType),
+	}
+
+	if len(upload.Metadata) > 0 {
+		params.Metadata = aws.StringMap(upload.Metadata)
+	}
+
+	if len(upload.Tags) > 0 {
+		params.Tagging = aws.String(encodeTags(upload.Tags))
+	}
+
+	response, err := S3StorageProvider.S3Storage.CreateMultipartUpload(params)
+	if err != nil {
+		mlog.Alarm("External Error, S3 Service Failed with error: %s", err.Error())
+		return "", management.GetExternalError(
+			fmt.Sprintf("Error, failed to start upload of %s with error: %s", upload.FileName, err.Error()))
+	}
+
+	return aws.StringValue(response.UploadId), nil
+}
+
+// Uploads a part of a multipart upload to S3
+func (S3StorageProvider *S3StorageProvider) UploadPart(bucketName,
+	uploadId string, part *model.PartData) (*model.Part, *management.Error) {
+	mlog.Debug("UploadPart - fileName: %s part: %d", part.FileName, part.PartNumber)
+
+	params := &s3.UploadPartInput{
+		Bucket:     aws.String(bucketName),
+		Key:        aws.String(part.FileName),
+		UploadId:   aws.String(uploadId),
+		PartNumber: aws.Int64(part.PartNumber),
+		Body:       bytes.NewReader(part.Payload),
+	}
+
+	response, err := S3StorageProvider.S3Storage.UploadPart(params)
+	if err != nil {
+		mlog.Alarm("External Error, S3 Service Failed with error: %s", err.Error())
+		return nil, management.GetExternalError(
+			fmt.Sprintf("Error, failed to upload part %d of %s with error: %s",
+				part.PartNumber, part.FileName, err.Error()))
+	}
+
+	return &model.Part{PartNumber: part.PartNumber, Etag: aws.StringValue(response.ETag)}, nil
+}
+
+// Completes a multipart upload to S3
+func (S3StorageProvider *S3StorageProvider) CompleteUpload(bucketName string,
+	upload *model.Upload) *management.Error {
+	mlog.Debug("CompleteUpload - fileName: %s parts: %d", upload.FileName, len(upload.Parts))
+
+	parts := make([]*s3.CompletedPart, len(upload.Parts))
+	for i, part := range upload.Parts {
+		parts[i] = &s3.CompletedPart{ETag: aws.String(part.Etag), PartNumber: aws.Int64(part.PartNumber)}
+	}
+
+	params := &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucketName),
+		Key:             aws.String(upload.FileName),
+		UploadId:        aws.String(upload.UploadId),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	}
+
+	if _, err := S3StorageProvider.S3Storage.CompleteMultipartUpload(params); err != nil {
+		mlog.Alarm("External Error, S3 Service Failed with error: %s", err.Error())
+		return management.GetExternalError(
+			fmt.Sprintf("Error, failed to complete upload of %s with error: %s", upload.FileName, err.Error()))
+	}
+
+	mlog.Info("Successfully uploaded object %s in %d parts", upload.FileName, len(parts))
+	return nil
+}
+
+// Aborts a multipart upload to S3
+func (S3StorageProvider *S3StorageProvider) AbortUpload(bucketName,
+	fileName, uploadId string) *management.Error {
+	mlog.Debug("AbortUpload - fileName: %s", fileName)
+
+	params := &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucketName),
+		Key:      aws.String(fileName),
+		UploadId: aws.String(uploadId),
+	}
+
+	if _, err := S3StorageProvider.S3Storage.AbortMultipartUpload(params); err != nil {
+		return management.GetExternalError(
+			fmt.Sprintf("Error, failed to abort upload of %s with error: %s", fileName, err.Error()))
+	}
+
+	return nil
+}
+
+// Copies given file within S3
+func (S3StorageProvider *S3StorageProvider) Copy(bucketName,
+	fileName, toBucketName, toFileName string) *management.Error {
+	mlog.Debug("Copy - %s/%s to %s/%s", bucketName, fileName, toBucketName, toFileName)
+
+	params := &s3.CopyObjectInput{
+		Bucket:     aws.String(toBucketName),
+		Key:        aws.String(toFileName),
+		ACL:        aws.String("private"),
+		CopySource: aws.String((&url.URL{Path: bucketName + "/" + fileName}).String()),
+	}
+
+	if _, err := S3StorageProvider.S3Storage.CopyObject(params); err != nil {
+		return management.GetExternalError(
+			fmt.Sprintf("Error, failed to copy file %s with error: %s", fileName, err.Error()))
+	}
+
+	return nil
+}
+
+// Enables or suspends the versioning of given bucket
+func (S3StorageProvider *S3StorageProvider) SetVersioning(bucketName string, enabled bool) *management.Error {
+	mlog.Debug("SetVersioning - bucketName: %s enabled: %v", bucketName, enabled)
+
+	status := s3.BucketVersioningStatusSuspended
+	if enabled {
+		status = s3.BucketVersioningStatusEnabled
+	}
+
+	params := &s3.PutBucketVersioningInput{
+		Bucket:                  aws.String(bucketName),
+		VersioningConfiguration: &s3.VersioningConfiguration{Status: aws.String(status)},
+	}
+
+	if _, err := S3StorageProvider.S3Storage.PutBucketVersioning(params); err != nil {
+		return management.GetExternalError(
+			fmt.Sprintf("Error, failed to set versioning of bucket %s with error: %s", bucketName, err.Error()))
+	}
+
+	return nil
+}
+
+// Returns whether the versioning of given bucket is enabled
+func (S3StorageProvider *S3StorageProvider) GetVersioning(bucketName string) (bool, *management.Error) {
+	mlog.Debug("GetVersioning - bucketName: %s", bucketName)
+
+	params := &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucketName),
+	}
+
+	response, err := S3StorageProvider.S3Storage.GetBucketVersioning(params)
+	if err != nil {
+		return false, management.GetExternalError(
+			fmt.Sprintf("Error, failed to get versioning of bucket %s with error: %s", bucketName, err.Error()))
+	}
+
+	return aws.StringValue(response.Status) == s3.BucketVersioningStatusEnabled, nil
+}
+
+// Returns the versions of given file, newest first
+func (S3StorageProvider *S3StorageProvider) ListVersions(bucketName,
+	fileName string) ([]model.Object, *management.Error) {
+	mlog.Debug("ListVersions - fileName: %s", fileName)
+
+	params := &s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(fileName),
+	}
+
+	versions := []model.Object{}
+	err := S3StorageProvider.S3Storage.ListObjectVersionsPages(params,
+		func(page *s3.ListObjectVersionsOutput, lastPage bool) bool {
+			for _, version := range page.Versions {
+				if aws.StringValue(version.Key) != fileName {
+					continue
+				}
+
+				versions = append(versions, model.Object{Key: fileName,
+					Etag:         aws.StringValue(version.ETag),
+					LastModified: aws.TimeValue(version.LastModified),
+					Size:         aws.Int64Value(version.Size),
+					StorageClass: aws.StringValue(version.StorageClass),
+					VersionId:    aws.StringValue(version.VersionId),
+					IsLatest:     aws.BoolValue(version.IsLatest)})
+			}
+			return true
+		})
+	if err != nil {
+		return nil, management.GetExternalError(
+			fmt.Sprintf("Error, failed to list versions of file %s with error: %s", fileName, err.Error()))
+	}
+
+	return versions, nil
+}
+
+// Restores a version of given file by copying it over the file
+func (S3StorageProvider *S3StorageProvider) RestoreVersion(bucketName,
+	fileName, versionId string) *management.Error {
+	mlog.Debug("RestoreVersion - fileName: %s versionId: %s", fileName, versionId)
+
+	source := (&url.URL{Path: bucketName + "/" + fileName}).String() + "?versionId=" + url.QueryEscape(versionId)
+	params := &s3.CopyObjectInput{
+		Bucket:     aws.String(bucketName),
+		Key:        aws.String(fileName),
+		ACL:        aws.String("private"),
+		CopySource: aws.String(source),
+	}
+
+	if _, err := S3StorageProvider.S3Storage.CopyObject(params); err != nil {
+		return management.GetExternalError(
+			fmt.Sprintf("Error, failed to restore version %s of file %s with error: %s",
+				versionId, fileName, err.Error()))
+	}
+
+	return nil
+}
+
+// Stores the lifecycle rules of given bucket in the bucket
+func (S3StorageProvider *S3StorageProvider) SetLifecycle(bucketName string,
+	lifecycle *model.Lifecycle) *management.Error {
+	mlog.Debug("SetLifecycle - bucketName: %s rules: %d", bucketName, len(lifecycle.Rules))
+	return S3StorageProvider.putConfig(bucketName, LifecycleKey, lifecycle, len(lifecycle.Rules) == 0)
+}
+
+// Returns the lifecycle rules of given bucket, none when they were not set
+func (S3StorageProvider *S3StorageProvider) GetLifecycle(bucketName string) (*model.Lifecycle, *management.Error) {
+	mlog.Debug("GetLifecycle - bucketName: %s", bucketName)
+
+	lifecycle := &model.Lifecycle{}
+	if mErr := S3StorageProvider.getConfig(bucketName, LifecycleKey, lifecycle); mErr != nil {
+		return nil, mErr
+	}
+
+	if lifecycle.Rules == nil {
+		lifecycle.Rules = []model.LifecycleRule{}
+	}
+	return lifecycle, nil
+}
+
+// Stores the notification rules of given bucket in the bucket
+func (S3StorageProvider *S3StorageProvider) SetNotifications(bucketName string,
+	notifications *model.Notifications) *management.Error {
+	mlog.Debug("SetNotifications - bucketName: %s rules: %d", bucketName, len(notifications.Rules))
+	return S3StorageProvider.putConfig(bucketName, NotificationsKey, notifications, len(notifications.Rules) == 0)
+}
+
+// Returns the notification rules of given bucket, none when they were not
+// set
+func (S3StorageProvider *S3StorageProvider) GetNotifications(bucketName string) (*model.Notifications, *management.Error) {
+	mlog.Debug("GetNotifications - bucketName: %s", bucketName)
+
+	notifications := &model.Notifications{}
+	if mErr := S3StorageProvider.getConfig(bucketName, NotificationsKey, notifications); mErr != nil {
+		return nil, mErr
+	}
+
+	if notifications.Rules == nil {
+		notifications.Rules = []model.NotificationRule{}
+	}
+	return notifications, nil
+}
+
+// putConfig stores the configuration under the key of the bucket, or
+// deletes it when it is empty.
+func (S3StorageProvider *S3StorageProvider) putConfig(bucketName, key string,
+	config interface{}, empty bool) *management.Error {
+	if empty {
+		return S3StorageProvider.Delete(bucketName, key)
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return management.GetInternalError(err.Error())
+	}
+
+	return S3StorageProvider.Upload(bucketName,
+		&model.UploadData{FileName: key, Payload: data, ContentType: "application/json"})
+}
+
+// getConfig reads the configuration stored under the key of the bucket,
+// leaving it unchanged when there is none.
+func (S3StorageProvider *S3StorageProvider) getConfig(bucketName, key string, config interface{}) *management.Error {
+	params := &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	}
+
+	response, err := S3StorageProvider.S3Storage.GetObject(params)
+	if aErr, ok := err.(awserr.Error); ok && aErr.Code() == s3.ErrCodeNoSuchKey {
+		return nil
+	}
+
+	if err != nil {
+		return management.GetExternalError(
+			fmt.Sprintf("Error, failed to get %s of bucket %s with error: %s", key, bucketName, err.Error()))
+	}
+	defer response.Body.Close()
+
+	if err := json.NewDecoder(response.Body).Decode(config); err != nil {
+		return management.GetInternalError(
+			fmt.Sprintf("Error, failed to read %s of bucket %s with error: %s", key, bucketName, err.Error()))
+	}
+
+	return nil
+}
+
+// encodeTags encodes the tags of a file as the query string S3 expects.
+func encodeTags(tags map[string]string) string {
+	values := url.Values{}
+	for key, value := range tags {
+		values.Set(key, value)
+	}
+	return values.Encode()
+}
+
+// metadataMap returns the user metadata of a file. S3 returns the keys as
+// header names, so they are lowered.
+func metadataMap(metadata map[string]*string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	out := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		out[strings.ToLower(key)] = aws.StringValue(value)
+	}
+	return out
+}
@@ -50,4 +50,51 @@ type StorageProvider interface {
 
 	// download specified file name.
 	Download(bucketName, fileName string) (*model.DownloadData, *management.Error)
+
+	// download length bytes of specified file name from the offset, the
+	// rest of the file when length is 0.
+	DownloadRange(bucketName, fileName string, offset, length int64) (*model.DownloadData, *management.Error)
+
+	// Returns metadata, including user metadata and tags, for specified
+	// file name.
+	Stat(bucketName, fileName string) (*model.Object, *management.Error)
+
+	// Starts a multipart upload of the file and returns its id.
+	CreateUpload(bucketName string, upload *model.Upload) (string, *management.Error)
+
+	// Uploads a part of a multipart upload.
+	UploadPart(bucketName, uploadId string, part *model.PartData) (*model.Part, *management.Error)
+
+	// Assembles the parts of a multipart upload into the file.
+	CompleteUpload(bucketName string, upload *model.Upload) *management.Error
+
+	// Aborts a multipart upload and drops its parts.
+	AbortUpload(bucketName, fileName, uploadId string) *management.Error
+
+	// Copies specified file name to another name or bucket.
+	Copy(bucketName, fileName, toBucketName, toFileName string) *management.Error
+
+	// Enables or suspends the versioning of a bucket.
+	SetVersioning(bucketName string, enabled bool) *management.Error
+
+	// Returns whether the versioning of a bucket is enabled.
+	GetVersioning(bucketName string) (bool, *management.Error)
+
+	// Returns the versions of specified file name, newest first.
+	ListVersions(bucketName, fileName string) ([]model.Object, *management.Error)
+
+	// Makes a version of specified file name its current version.
+	RestoreVersion(bucketName, fileName, versionId string) *management.Error
+
+	// Sets the lifecycle rules of a bucket.
+	SetLifecycle(bucketName string, lifecycle *model.Lifecycle) *management.Error
+
+	// Returns the lifecycle rules of a bucket.
+	GetLifecycle(bucketName string) (*model.Lifecycle, *management.Error)
+
+	// Sets the notification rules of a bucket.
+	SetNotifications(bucketName string, notifications *model.Notifications) *management.Error
+
+	// Returns the notification rules of a bucket.
+	GetNotifications(bucketName string) (*model.Notifications, *management.Error)
 }
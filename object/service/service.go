@@ -17,13 +17,20 @@ limitations under the License.
 package service
 
 import (
+	"fmt"
+
 	"github.com/gin-gonic/gin"
 	"github.com/lavaorg/lrtx/management"
 	"github.com/lavaorg/lrtx/mlog"
 	"github.com/lavaorg/northstar/object/controller"
 	"github.com/lavaorg/northstar/object/env"
+	"github.com/lavaorg/northstar/object/fs"
+	"github.com/lavaorg/northstar/object/lifecycle"
+	"github.com/lavaorg/northstar/object/notification"
 	"github.com/lavaorg/northstar/object/s3"
+	"github.com/lavaorg/northstar/object/signing"
 	"github.com/lavaorg/northstar/object/util"
+	eventsClient "github.com/lavaorg/northstar/processing/events/client"
 )
 
 type Service struct {
@@ -34,20 +41,34 @@ type Service struct {
 func NewService() (service *Service, err error) {
 	mlog.Debug("NewService")
 
-	input := &s3.ProviderInput{
-		Host:      env.BlobStorageHostAndPort,
-		UserId:    env.BlobStorageUserId,
-		Secret:    env.BlobStorageUserSecret,
-		DebugFlag: env.DebugFlag,
-	}
-
-	storageProvider, err := s3.NewS3StorageProvider(input)
+	storageProvider, err := newStorageProvider()
 	if err != nil {
 		mlog.Error("Error, failed to create storage provider with error %s.\n", err.Error())
 		return nil, err
 	}
 	controller := controller.NewController(storageProvider)
 
+	// Notifications invoke the events of the processing service. Without
+	// it, the files are not notified.
+	var notifier *notification.Notifier
+	if events, err := eventsClient.NewEventsClient(); err != nil {
+		mlog.Error("Notifications disabled, failed to create events client with error %s.", err.Error())
+	} else {
+		notifier = notification.NewNotifier(storageProvider, events, env.NotificationQueueSize)
+		notifier.Start()
+		controller.Notifier = notifier
+	}
+
+	// Signed urls give access to the files without the credentials of the
+	// account. Without a signing key, no url is signed.
+	if env.SigningKey == "" {
+		mlog.Info("Signed urls disabled, OBJECT_SIGNING_KEY is not set")
+	} else {
+		controller.Signer = signing.NewSigner(env.SigningKey)
+		controller.PublicUrl = env.PublicUrl
+		controller.SignedUploadMaxSize = int64(env.SignedUploadMaxSize)
+	}
+
 	engine := management.Engine()
 	g := engine.Group(util.ObjectBasePath)
 
@@ -55,12 +76,38 @@ func NewService() (service *Service, err error) {
 	g.POST("/buckets/:accountId", controller.CreateBucket)
 	g.GET("/buckets/:accountId", controller.ListBuckets)
 	g.DELETE("/buckets/:accountId/:bucketName", controller.DeleteBucket)
+	g.PUT("/buckets/:accountId/:bucketName/versioning", controller.SetVersioning)
+	g.GET("/buckets/:accountId/:bucketName/versioning", controller.GetVersioning)
+	g.PUT("/buckets/:accountId/:bucketName/lifecycle", controller.SetLifecycle)
+	g.GET("/buckets/:accountId/:bucketName/lifecycle", controller.GetLifecycle)
+	g.PUT("/buckets/:accountId/:bucketName/notifications", controller.SetNotifications)
+	g.GET("/buckets/:accountId/:bucketName/notifications", controller.GetNotifications)
 
 	// File
 	g.POST("/files/:accountId/:bucketName", controller.UploadFile)
 	g.GET("/files/:accountId/:bucketName", controller.ListFiles)
 	g.GET("/files/:accountId/:bucketName/*fileName", controller.DownloadFile)
 	g.DELETE("/files/:accountId/:bucketName/*fileName", controller.DeleteFile)
+	g.GET("/stat/:accountId/:bucketName/*fileName", controller.StatFile)
+	g.POST("/copy/:accountId/:bucketName", controller.CopyFile)
+	g.GET("/versions/:accountId/:bucketName/*fileName", controller.ListVersions)
+	g.POST("/restore/:accountId/:bucketName", controller.RestoreVersion)
+
+	// Signed urls
+	g.POST("/sign/:accountId/:bucketName", controller.SignUrl)
+	g.GET("/signed/:accountId/:bucketName/*fileName", controller.SignedDownload)
+	g.PUT("/signed/:accountId/:bucketName/*fileName", controller.SignedUpload)
+
+	// Multipart upload
+	g.POST("/uploads/:accountId/:bucketName", controller.CreateUpload)
+	g.POST("/uploads/:accountId/:bucketName/:uploadId", controller.CompleteUpload)
+	g.POST("/uploads/:accountId/:bucketName/:uploadId/parts", controller.UploadPart)
+	g.DELETE("/uploads/:accountId/:bucketName/:uploadId/*fileName", controller.AbortUpload)
+
+	// Lifecycle rules
+	job := lifecycle.NewJob(storageProvider, env.LifecycleInterval)
+	job.Notifier = notifier
+	job.Start()
 
 	service = &Service{
 		controller: controller,
@@ -69,6 +116,23 @@ func NewService() (service *Service, err error) {
 	return service, nil
 }
 
+func newStorageProvider() (s3.StorageProvider, error) {
+	switch env.StorageProvider {
+	case "s3":
+		input := &s3.ProviderInput{
+			Host:      env.BlobStorageHostAndPort,
+			UserId:    env.BlobStorageUserId,
+			Secret:    env.BlobStorageUserSecret,
+			DebugFlag: env.DebugFlag,
+		}
+		return s3.NewS3StorageProvider(input)
+	case "fs":
+		return fs.NewFSStorageProvider(env.StorageRoot)
+	}
+
+	return nil, fmt.Errorf("unknown storage provider %s", env.StorageProvider)
+}
+
 func (service *Service) Start() error {
 	port := ":" + env.WebPort
 	return management.Listen(port)
@@ -0,0 +1,72 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrExpired   = errors.New("signed url has expired")
+	ErrSignature = errors.New("signature of the url is invalid")
+)
+
+// Signer signs the links giving access to a file of a bucket, to download
+// or upload it until they expire, without the credentials of the account.
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(key string) *Signer {
+	return &Signer{key: []byte(key)}
+}
+
+// Sign returns the signature of the method on the file of the bucket of the
+// account, valid until the expires unix time.
+func (signer *Signer) Sign(method, accountId, bucketName, fileName string, expires int64) string {
+	mac := hmac.New(sha256.New, signer.key)
+
+	// The fields are prefixed with their length, so that no two links
+	// share the same signature.
+	for _, field := range []string{method, accountId, bucketName, fileName, strconv.FormatInt(expires, 10)} {
+		fmt.Fprintf(mac, "%d:%s", len(field), field)
+	}
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of the method on the file of the bucket of
+// the account, and that it has not expired at now.
+func (signer *Signer) Verify(method, accountId, bucketName, fileName string,
+	expires int64, signature string, now time.Time) error {
+	expected := signer.Sign(method, accountId, bucketName, fileName, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrSignature
+	}
+
+	if now.Unix() > expires {
+		return ErrExpired
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	signer := NewSigner("secret")
+	now := time.Unix(1500000000, 0)
+	expires := now.Add(time.Hour).Unix()
+	signature := signer.Sign("GET", "account", "bucket", "dir/file.csv", expires)
+
+	assert.NoError(t, signer.Verify("GET", "account", "bucket", "dir/file.csv", expires, signature, now))
+	assert.Equal(t, ErrExpired, signer.Verify("GET", "account", "bucket", "dir/file.csv", expires, signature,
+		now.Add(2*time.Hour)))
+
+	// Any change of the link invalidates it.
+	assert.Equal(t, ErrSignature, signer.Verify("PUT", "account", "bucket", "dir/file.csv", expires, signature, now))
+	assert.Equal(t, ErrSignature, signer.Verify("GET", "other", "bucket", "dir/file.csv", expires, signature, now))
+	assert.Equal(t, ErrSignature, signer.Verify("GET", "account", "bucket", "dir/other.csv", expires, signature, now))
+	assert.Equal(t, ErrSignature, signer.Verify("GET", "account", "bucket", "dir/file.csv", expires+1, signature, now))
+	assert.Equal(t, ErrSignature, signer.Verify("GET", "account", "bucket", "dir/file.csv", expires,
+		NewSigner("other").Sign("GET", "account", "bucket", "dir/file.csv", expires), now))
+
+	// Fields cannot be shifted from one to the other.
+	assert.NotEqual(t, signer.Sign("GET", "account", "bucket", "dir/file.csv", expires),
+		signer.Sign("GET", "account", "bucketdir", "/file.csv", expires))
+}
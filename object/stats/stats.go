@@ -66,4 +66,86 @@ var (
 	ErrDeleteFilesMissingBucketNameCount = s.NewCounter("ErrDeleteFilesMissingBucketNameCount")
 	ErrDeleteFileMissingFileNameCount    = s.NewCounter("ErrDeleteFileMissingFileNameCount")
 	ErrDeleteFileFailCount               = s.NewCounter("ErrDeleteFileFailCount")
+
+	// Ranged download
+	DownloadRangeReqCount        = s.NewCounter("DownloadRangeReqCount")
+	ErrDownloadRangeInvalidCount = s.NewCounter("ErrDownloadRangeInvalidCount")
+
+	// Stat file
+	StatFileReqCount           = s.NewCounter("StatFileReqCount")
+	ErrStatFileBadRequestCount = s.NewCounter("ErrStatFileBadRequestCount")
+	ErrStatFileCount           = s.NewCounter("ErrStatFileCount")
+
+	// Multipart upload
+	CreateUploadReqCount             = s.NewCounter("CreateUploadReqCount")
+	ErrCreateUploadBadRequestCount   = s.NewCounter("ErrCreateUploadBadRequestCount")
+	ErrCreateUploadCount             = s.NewCounter("ErrCreateUploadCount")
+	UploadPartReqCount               = s.NewCounter("UploadPartReqCount")
+	ErrUploadPartBadRequestCount     = s.NewCounter("ErrUploadPartBadRequestCount")
+	ErrUploadPartCount               = s.NewCounter("ErrUploadPartCount")
+	CompleteUploadReqCount           = s.NewCounter("CompleteUploadReqCount")
+	ErrCompleteUploadBadRequestCount = s.NewCounter("ErrCompleteUploadBadRequestCount")
+	ErrCompleteUploadCount           = s.NewCounter("ErrCompleteUploadCount")
+	AbortUploadReqCount              = s.NewCounter("AbortUploadReqCount")
+	ErrAbortUploadBadRequestCount    = s.NewCounter("ErrAbortUploadBadRequestCount")
+	ErrAbortUploadCount              = s.NewCounter("ErrAbortUploadCount")
+
+	// Copy and move files
+	CopyFileReqCount           = s.NewCounter("CopyFileReqCount")
+	ErrCopyFileBadRequestCount = s.NewCounter("ErrCopyFileBadRequestCount")
+	ErrCopyFileCount           = s.NewCounter("ErrCopyFileCount")
+
+	// Filtered listing
+	ErrListFilesFilterInvalidCount = s.NewCounter("ErrListFilesFilterInvalidCount")
+
+	// Versioning
+	SetVersioningReqCount            = s.NewCounter("SetVersioningReqCount")
+	ErrSetVersioningBadRequestCount  = s.NewCounter("ErrSetVersioningBadRequestCount")
+	ErrSetVersioningCount            = s.NewCounter("ErrSetVersioningCount")
+	GetVersioningReqCount            = s.NewCounter("GetVersioningReqCount")
+	ErrGetVersioningBadRequestCount  = s.NewCounter("ErrGetVersioningBadRequestCount")
+	ErrGetVersioningCount            = s.NewCounter("ErrGetVersioningCount")
+	ListVersionsReqCount             = s.NewCounter("ListVersionsReqCount")
+	ErrListVersionsBadRequestCount   = s.NewCounter("ErrListVersionsBadRequestCount")
+	ErrListVersionsCount             = s.NewCounter("ErrListVersionsCount")
+	RestoreVersionReqCount           = s.NewCounter("RestoreVersionReqCount")
+	ErrRestoreVersionBadRequestCount = s.NewCounter("ErrRestoreVersionBadRequestCount")
+	ErrRestoreVersionCount           = s.NewCounter("ErrRestoreVersionCount")
+
+	// Lifecycle
+	SetLifecycleReqCount           = s.NewCounter("SetLifecycleReqCount")
+	ErrSetLifecycleBadRequestCount = s.NewCounter("ErrSetLifecycleBadRequestCount")
+	ErrSetLifecycleCount           = s.NewCounter("ErrSetLifecycleCount")
+	GetLifecycleReqCount           = s.NewCounter("GetLifecycleReqCount")
+	ErrGetLifecycleBadRequestCount = s.NewCounter("ErrGetLifecycleBadRequestCount")
+	ErrGetLifecycleCount           = s.NewCounter("ErrGetLifecycleCount")
+	LifecycleRunCount              = s.NewCounter("LifecycleRunCount")
+	LifecycleExpiredCount          = s.NewCounter("LifecycleExpiredCount")
+	LifecycleTransitionedCount     = s.NewCounter("LifecycleTransitionedCount")
+	ErrLifecycleCount              = s.NewCounter("ErrLifecycleCount")
+
+	// Notifications
+	SetNotificationsReqCount           = s.NewCounter("SetNotificationsReqCount")
+	ErrSetNotificationsBadRequestCount = s.NewCounter("ErrSetNotificationsBadRequestCount")
+	ErrSetNotificationsCount           = s.NewCounter("ErrSetNotificationsCount")
+	GetNotificationsReqCount           = s.NewCounter("GetNotificationsReqCount")
+	ErrGetNotificationsBadRequestCount = s.NewCounter("ErrGetNotificationsBadRequestCount")
+	ErrGetNotificationsCount           = s.NewCounter("ErrGetNotificationsCount")
+	NotificationCount                  = s.NewCounter("NotificationCount")
+	ErrNotificationCount               = s.NewCounter("ErrNotificationCount")
+	ErrNotificationDroppedCount        = s.NewCounter("ErrNotificationDroppedCount")
+
+	// Signed urls
+	SignUrlReqCount                 = s.NewCounter("SignUrlReqCount")
+	ErrSignUrlBadRequestCount       = s.NewCounter("ErrSignUrlBadRequestCount")
+	ErrSignUrlDisabledCount         = s.NewCounter("ErrSignUrlDisabledCount")
+	SignedDownloadReqCount          = s.NewCounter("SignedDownloadReqCount")
+	ErrSignedDownloadForbiddenCount = s.NewCounter("ErrSignedDownloadForbiddenCount")
+	ErrSignedDownloadCount          = s.NewCounter("ErrSignedDownloadCount")
+	SignedUploadReqCount            = s.NewCounter("SignedUploadReqCount")
+	ErrSignedUploadForbiddenCount   = s.NewCounter("ErrSignedUploadForbiddenCount")
+	ErrSignedUploadTooLargeCount    = s.NewCounter("ErrSignedUploadTooLargeCount")
+	ErrSignedUploadCount            = s.NewCounter("ErrSignedUploadCount")
+
+	ErrReservedFileNameCount = s.NewCounter("ErrReservedFileNameCount")
 )
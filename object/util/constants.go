@@ -16,10 +16,68 @@ limitations under the License.
 
 package util
 
+import (
+	"path"
+	"strings"
+)
+
 const (
 	BucketNameMissing  = "Bucket name not received in request"
 	FileNameMissing    = "File name is empty"
 	AccountIdMissing   = "Account ID is missing"
 	PayloadMissing     = "Payload is empty"
 	ContentTypeMissing = "Content type is empty"
+	UploadIdMissing    = "Upload ID is missing"
+	PartsMissing       = "Parts of the upload are empty"
+	PartNumberInvalid  = "Part number is invalid"
+	RangeInvalid       = "Range is invalid"
+	DestinationMissing = "Destination of the copy is empty"
+	VersionIdMissing   = "Version ID is missing"
+	MetadataInvalid    = "Metadata is invalid"
+	TagsInvalid        = "Tags are invalid"
+	FilterInvalid      = "Filter is invalid"
+
+	RuleIdMissing         = "Rule ID is missing"
+	RuleIdDuplicate       = "Rule ID is duplicated"
+	RuleDaysInvalid       = "Lifecycle rule days are invalid"
+	RuleActionMissing     = "Lifecycle rule has no expiration or transition"
+	RuleTransitionInvalid = "Lifecycle rule transition is invalid"
+
+	EventIdMissing   = "Notification rule event ID is missing"
+	EventTypeInvalid = "Notification rule event type is invalid"
+
+	MethodInvalid        = "Method of the signed url is invalid"
+	ExpiryInvalid        = "Expiry of the signed url is invalid"
+	SigningDisabled      = "Signed urls are not enabled"
+	SignatureInvalid     = "Signature of the url is invalid or expired"
+	SignedUploadTooLarge = "Signed upload is too large"
+	FileNameReserved     = "File name is reserved for the bucket configuration"
+)
+
+// MaxParts is the number of parts a multipart upload can have.
+const MaxParts = 10000
+
+// Limits of the user metadata and tags of a file.
+const (
+	MaxMetadataSize   = 2048
+	MaxTags           = 10
+	MaxTagKeyLength   = 128
+	MaxTagValueLength = 256
+)
+
+// ConfigPrefix is the prefix of the files holding the configuration of a
+// bucket, which are not listed.
+const ConfigPrefix = ".northstar/"
+
+// IsConfigFile reports whether the file name is under the configuration
+// prefix, once leading slashes and dot segments are resolved.
+func IsConfigFile(fileName string) bool {
+	return strings.HasPrefix(strings.TrimLeft(fileName, "/"), ConfigPrefix) ||
+		strings.HasPrefix(path.Clean("/"+fileName)+"/", "/"+ConfigPrefix)
+}
+
+// Expiry in seconds of the signed urls.
+const (
+	DefaultSignedUrlExpiry = 3600
+	MaxSignedUrlExpiry     = 7 * 24 * 3600
 )
@@ -171,6 +171,44 @@ func (controller *Controller) processEvent(token string, connectionId string, ev
 				return mErr
 			}
 		}
+	case model.EventTypeDebugCell:
+		{
+			// Generate the callback and debugger urls.
+			callbackUrl := controller.getEventCallbackURL(connectionId, event.Id, model.EventTypeExecuteResult)
+			debugUrl := controller.getEventDebuggerURL(connectionId, event.Id)
+
+			// Parse expected payload.
+			var debug model.DebugCell
+
+			if err := json.Unmarshal([]byte(event.Payload), &debug); err != nil {
+				mlog.Error("Event umarshal returned error: %+v", err)
+				return management.GetBadRequestError("The event payload type is invalid. Expecting debug cell type.")
+			}
+
+			// Register the debugger before the cell can pause.
+			controller.debuggers.Set(debuggerKey(connectionId, event.Id), make(chan model.DebugCommand, 1))
+
+			// Debug cell.
+			if mErr := controller.portalProvider.DebugCell(token, callbackUrl, debugUrl, &debug); mErr != nil {
+				controller.debuggers.Delete(debuggerKey(connectionId, event.Id))
+				return mErr
+			}
+		}
+	case model.EventTypeDebugCommand:
+		{
+			// Parse expected payload.
+			var command model.DebugCommand
+
+			if err := json.Unmarshal([]byte(event.Payload), &command); err != nil {
+				mlog.Error("Event umarshal returned error: %+v", err)
+				return management.GetBadRequestError("The event payload type is invalid. Expecting debug command type.")
+			}
+
+			// Queue the command for the debugger of the cell debug event with the same id.
+			if mErr := controller.sendDebugCommand(connectionId, event.Id, command); mErr != nil {
+				return mErr
+			}
+		}
 	default:
 		return management.GetBadRequestError("The event type is not supported.")
 	}
@@ -178,6 +216,12 @@ func (controller *Controller) processEvent(token string, connectionId string, ev
 	return nil
 }
 
+// getEventDebuggerURL is a helper method used to generate the url the debugger of a cell reports to.
+func (controller *Controller) getEventDebuggerURL(connectionID, eventID string) string {
+	return fmt.Sprintf("http://%s/%s/%s/connections/%s/events/%s/debugger",
+		portalglobal.Config.ServiceHostPort, model.InternalContext, model.Version, connectionID, eventID)
+}
+
 // getEventcallbackURL is a helper method used to generate callback url.
 func (controller *Controller) getEventCallbackURL(connectionID, eventID string, eventType model.EventType) string {
 	return fmt.Sprintf("http://%s/%s/%s/connections/%s/events/%s/callbacks/%s",
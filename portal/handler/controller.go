@@ -38,6 +38,7 @@ type Controller struct {
 	userScopes     string
 	portalProvider provider.PortalProvider
 	writers        *utils.ThreadSafeMap
+	debuggers      *utils.ThreadSafeMap
 }
 
 // NewController returns a new controller
@@ -56,6 +57,7 @@ func NewController() (*Controller, error) {
 		userScopes:     portalglobal.Config.AcctUserScopes,
 		portalProvider: portalProvider,
 		writers:        utils.NewThreadSafeMap(),
+		debuggers:      utils.NewThreadSafeMap(),
 	}
 
 	return controller, nil
@@ -0,0 +1,125 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/portal/model"
+	"github.com/lavaorg/northstar/portal/portalglobal"
+)
+
+// DebugPaused sends the pause reported by the debugger of a cell to the user.
+func (controller *Controller) DebugPaused(context *gin.Context) {
+	mlog.Debug("DebugPaused")
+
+	// Get path parameter.
+	connectionID := context.Params.ByName("connectionId")
+	eventID := context.Params.ByName("eventId")
+
+	// Read message body.
+	request := context.Request
+
+	defer request.Body.Close()
+	payload, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		mlog.Error("Failed to read debug pause with error: %s", err)
+		controller.RenderServiceError(context, model.ErrorParseRequestBody)
+		return
+	}
+
+	// Create the event of the cell debug event.
+	event, mErr := controller.portalProvider.ProcessEvent(eventID, model.EventTypeDebugPaused.ToString(), payload)
+	if mErr != nil {
+		mlog.Error(mErr.String())
+		controller.RenderServiceError(context, model.ErrorParseRequestBody)
+		return
+	}
+
+	if mErr := controller.writeEvent(connectionID, event); mErr != nil {
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	context.String(http.StatusNoContent, http.StatusText(http.StatusNoContent))
+}
+
+// DebugCommands returns the next command of the user to the debugger of a cell. Note that the debugger
+// long polls: if the user sends no command in time, no content is returned and the debugger polls again.
+func (controller *Controller) DebugCommands(context *gin.Context) {
+	mlog.Debug("DebugCommands")
+
+	// Get path parameter.
+	connectionID := context.Params.ByName("connectionId")
+	eventID := context.Params.ByName("eventId")
+
+	commands, mErr := controller.getDebugger(connectionID, eventID)
+	if mErr != nil {
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	select {
+	case command := <-commands:
+		context.JSON(http.StatusOK, command)
+	case <-time.After(time.Duration(portalglobal.Config.DebugCommandTimeout) * time.Second):
+		context.String(http.StatusNoContent, http.StatusText(http.StatusNoContent))
+	}
+}
+
+// sendDebugCommand is a helper method used to queue a command of the user for the debugger of a cell.
+func (controller *Controller) sendDebugCommand(connectionID, eventID string, command model.DebugCommand) *management.Error {
+	commands, mErr := controller.getDebugger(connectionID, eventID)
+	if mErr != nil {
+		return mErr
+	}
+
+	// The debugger reads one command per pause, so pending commands are rejected.
+	select {
+	case commands <- command:
+		return nil
+	default:
+		return management.GetBadRequestError("The debugger has not processed the previous command.")
+	}
+}
+
+// getDebugger is a helper method used to get the commands queue of the debugger of a cell.
+func (controller *Controller) getDebugger(connectionID, eventID string) (chan model.DebugCommand, *management.Error) {
+	debugger, err := controller.debuggers.Get(debuggerKey(connectionID, eventID))
+	if err != nil {
+		mlog.Error("Get debugger returned error: %s", err.Error())
+		return nil, management.GetNotFoundError("The debug session does not exist.")
+	}
+
+	commands, ok := debugger.(chan model.DebugCommand)
+	if !ok {
+		mlog.Error("Debugger type is invalid.")
+		return nil, management.ErrorInternal
+	}
+
+	return commands, nil
+}
+
+// debuggerKey is a helper method used to identify the debugger of a cell debug event.
+func debuggerKey(connectionID, eventID string) string {
+	return connectionID + "/" + eventID
+}
@@ -57,24 +57,36 @@ func (controller *Controller) EventCallback(context *gin.Context) {
 		return
 	}
 
+	// The execution is over, so is its debugger if any.
+	if event.Type == model.EventTypeExecuteResult {
+		controller.debuggers.Delete(debuggerKey(connectionID, eventID))
+	}
+
+	if mErr := controller.writeEvent(connectionID, event); mErr != nil {
+		controller.RenderServiceError(context, mErr)
+		return
+	}
+
+	context.String(http.StatusNoContent, http.StatusText(http.StatusNoContent))
+}
+
+// writeEvent is a helper method used to send an event over the websocket of the connection.
+func (controller *Controller) writeEvent(connectionID string, event *model.Event) *management.Error {
 	// Get the channel corresponding to our websocket so that it can be sent to the user.
 	writer, err := controller.writers.Get(connectionID)
 	if err != nil {
 		mlog.Error("Get connection with id %s returned error: %s", connectionID, err.Error())
-		controller.RenderServiceError(context, management.ErrorInternal)
-		return
+		return management.ErrorInternal
 	}
 
 	// Convert to expected type.
 	channel, ok := writer.(chan model.Event)
 	if !ok {
 		mlog.Error("Connection writer type is invalid.")
-		controller.RenderServiceError(context, management.ErrorInternal)
-		return
+		return management.ErrorInternal
 	}
 
 	// Write our event to the channel so it can be sent over the websocket.
 	channel <- *event
-
-	context.String(http.StatusNoContent, http.StatusText(http.StatusNoContent))
+	return nil
 }
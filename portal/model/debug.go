@@ -0,0 +1,50 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+// DebugCell defines the payload of a cell debug event. The cell runs in debug
+// mode, pausing on the breakpoints, given as lines of the cell code.
+type DebugCell struct {
+	Cell        Cell  `json:"cell"`
+	Breakpoints []int `json:"breakpoints,omitempty"`
+	StopOnEntry bool  `json:"stopOnEntry,omitempty"`
+}
+
+// DebugCommand defines the payload of a debug command event, sent with the id
+// of the cell debug event. The command is one of continue, stepOver,
+// stepInto, stepOut, setBreakpoints and stop.
+type DebugCommand struct {
+	Command     string `json:"command"`
+	Breakpoints []int  `json:"breakpoints,omitempty"`
+}
+
+// DebugFrame defines an active function call of a paused cell.
+type DebugFrame struct {
+	Function string            `json:"function,omitempty"`
+	Source   string            `json:"source,omitempty"`
+	Line     int               `json:"line"`
+	Locals   map[string]string `json:"locals,omitempty"`
+	Upvalues map[string]string `json:"upvalues,omitempty"`
+}
+
+// DebugPaused defines the payload of a debug paused event, innermost frame
+// first.
+type DebugPaused struct {
+	Reason string       `json:"reason"`
+	Line   int          `json:"line"`
+	Stack  []DebugFrame `json:"stack"`
+}
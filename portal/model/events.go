@@ -31,6 +31,9 @@ const (
 	EventTypeExecuteResult         EventType = "ExecutionResult"
 	EventTypeError                 EventType = "Error"
 	EventTypePing                  EventType = "Ping"
+	EventTypeDebugCell             EventType = "CellDebug"
+	EventTypeDebugCommand          EventType = "DebugCommand"
+	EventTypeDebugPaused           EventType = "DebugPaused"
 )
 
 // Helper method used to translate event types to string.
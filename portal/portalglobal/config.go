@@ -45,6 +45,7 @@ type configuration struct {
 	AcctUserScopes       string `default:"ts.user ts.user.ro ts.transformation ts.transformation.ro ts.notebook ts.notebook.ro ts.model.ro ts.nsobject.ro"`
 	AcctClientScopes     string `default:"ts.configuration"`
 	ConnectionBufferSize int    `default:"1024"`
+	DebugCommandTimeout  int    `default:"20"`
 }
 
 // Load loads the configuration from the environment variables.
@@ -69,6 +69,19 @@ func (provider *NorthStarPortalProvider) ProcessEvent(id, payloadType string, pa
 			payload = &output
 			eventType = model.EventTypeExecuteResult
 		}
+	case model.EventTypeDebugPaused.ToString():
+		{
+			mlog.Debug("Processing debug pause.")
+			paused := model.DebugPaused{}
+
+			// Unmarshal the payload data reported by the debugger.
+			if err := json.Unmarshal(payloadData, &paused); err != nil {
+				return nil, management.GetBadRequestError(fmt.Sprintf("Failed to umarshal event payload with error: %v", err))
+			}
+
+			payload = &paused
+			eventType = model.EventTypeDebugPaused
+		}
 	default:
 		return nil, management.GetBadRequestError(fmt.Sprintf("The callback type %s is invalid.", payloadType))
 	}
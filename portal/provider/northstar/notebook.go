@@ -158,6 +158,30 @@ func (provider *NorthStarPortalProvider) ExecuteCell(token string, callbackUrl s
 	return nil
 }
 
+// DebugCell executes the input of the specified cell in debug mode. Note that the debugger reports to, and
+// reads commands from, the debug url while results will be returned, asynchronously, through the callback url.
+func (provider *NorthStarPortalProvider) DebugCell(token string, callbackUrl string, debugUrl string, debug *model.DebugCell) *management.Error {
+	mlog.Debug("DebugCell: callbackUrl:%s, debugUrl:%s, cell:%+v", callbackUrl, debugUrl, debug.Cell)
+
+	// Only the Lua runtime supports debug mode.
+	if debug.Cell.Language != "lua" {
+		return management.GetBadRequestError(fmt.Sprintf("Debugging %s cells is not supported.", debug.Cell.Language))
+	}
+
+	// Get the portal api (external) cell.
+	externalCell := toExternalCell(&debug.Cell)
+	externalCell.Input.Debug = &northstarApiModel.Debug{
+		Breakpoints: debug.Breakpoints,
+		StopOnEntry: debug.StopOnEntry,
+	}
+
+	if mErr := provider.northstarApiClient.DebugCell(token, callbackUrl, debugUrl, externalCell); mErr != nil {
+		return management.GetExternalError(fmt.Sprintf("Debug cell returned error: %v", mErr))
+	}
+
+	return nil
+}
+
 // Helper method used to translate portal model to portal api model.
 func (provider *NorthStarPortalProvider) toExternalNotebook(notebook *model.Notebook) *northstarApiModel.Notebook {
 	mlog.Debug("ToExternalNotebook")
@@ -42,6 +42,9 @@ type PortalProvider interface {
 	// ExecuteCell submits an execution request for a cell.
 	ExecuteCell(token string, callbackURL string, cell *model.Cell) *management.Error
 
+	// DebugCell submits an execution request for a cell in debug mode.
+	DebugCell(token string, callbackURL string, debugURL string, debug *model.DebugCell) *management.Error
+
 	// GetNotebookUsers returns a list of users that have access to the specified notebook
 	GetNotebookUsers(token string, notebookId string) ([]model.User, *management.Error)
 
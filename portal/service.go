@@ -82,6 +82,8 @@ func NewService() (*Service, error) {
 	internal := engine.Group(path.Join(model.InternalContext, model.Version))
 	{
 		internal.POST("/connections/:connectionId/events/:eventId/callbacks/:type", controller.EventCallback)
+		internal.POST("/connections/:connectionId/events/:eventId/debugger/paused", controller.DebugPaused)
+		internal.GET("/connections/:connectionId/events/:eventId/debugger/commands", controller.DebugCommands)
 	}
 
 	// Service RESTful API
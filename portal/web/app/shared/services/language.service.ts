@@ -48,6 +48,7 @@ export class LanguageService {
     constructor() {
         this.configs = new Map<string, LanguageConfig>();
         this.configs.set("lua", this.generateConfig("lua"));
+        this.configs.set("starlark", this.generateConfig("starlark"));
         this.configs.set("html", this.generateConfig("html"));
         this.configs.set("latex", this.generateConfig("latex"));
         this.configs.set("markdown", this.generateConfig("markdown"));
@@ -105,6 +106,14 @@ export class LanguageService {
                 };
                 config.icon = LanguageIconBaseUri + "lua.png";
                 break;
+            case "starlark":
+                // Starlark is a dialect of Python.
+                config.type = LanguageMode.Code;
+                config.editorConfig = this.getCodemirrorPreferences();
+                config.editorConfig.mode = {
+                    name: "python",
+                };
+                break;
             case "r":
                 // Note that "R" is not supported for now but keeping the configuration.
                 config.type = LanguageMode.Code;
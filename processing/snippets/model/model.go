@@ -18,6 +18,7 @@ package model
 
 import (
 	"fmt"
+	"github.com/lavaorg/northstar/rte/rtepub"
 )
 
 type Snippet struct {
@@ -34,6 +35,7 @@ type Options struct {
 	Callback string                 `json:"callback,omitempty"`
 	Memory   uint64                 `json:"memory,omitempty"`
 	Args     map[string]interface{} `json:"args,omitempty"`
+	Debug    *rtepub.Debug          `json:"debug,omitempty"`
 }
 
 func (snippet *Snippet) Validate() error {
@@ -159,6 +159,7 @@ func (s *SnippetsService) invokeSnippetDirect(accountId string, snippet *model.S
 		URL:       snippet.URL,
 		Code:      snippet.Code,
 		Callback:  snippet.Options.Callback,
+		Debug:     snippet.Options.Debug,
 	}
 
 	invocationId, err := eventsProducer.SnippetStart(accountId, start)
@@ -0,0 +1,155 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dop251/goja"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/rte/config"
+	"github.com/lavaorg/northstar/rte/rlimit"
+	"github.com/lavaorg/northstar/rte/rtepub"
+	"strings"
+	"time"
+)
+
+var errTerminated = errors.New("snippet terminated")
+
+type JsInterpreter struct {
+	State  *State
+	rLimit rlimit.ResourceLimit
+}
+
+func NewJsInterpreter(rLimit rlimit.ResourceLimit) rtepub.Interpreter {
+	return &JsInterpreter{rLimit: rLimit}
+}
+
+func (i *JsInterpreter) DoREPL(input *rtepub.Input) *rtepub.Output {
+	timer := JavaScript.NewTimer("DoREPLTimer")
+	startedOn := time.Now()
+	mlog.Debug("Running main: %s, code: %s, args: %s, timeout: %d, memory: %v, accountId: %s, "+
+		"invocationId: %s", input.MainFn, input.Code, input.Args, input.Timeout, input.Memory,
+		input.AccountId, input.InvocationId)
+
+	state, err := CreateState(input)
+	if err != nil {
+		mlog.Error("Failed to create state: %v", err.Error())
+		timer.Stop()
+		ErrDoREPL.Incr()
+		return &rtepub.Output{StartedOn: startedOn,
+			Status:     rtepub.STATE_CREATE_FAILED,
+			ErrorDescr: err.Error()}
+	}
+
+	i.State = state
+	defer state.Close()
+
+	if config.EnableRLimit {
+		limits, err := i.rLimit.Reserve(&rlimit.Resources{Memory: input.Memory})
+		if err != nil {
+			mlog.Error("Failed to reserve resources: %v", err.Error())
+			timer.Stop()
+			ErrDoREPL.Incr()
+			return &rtepub.Output{StartedOn: startedOn,
+				Status:     rtepub.START_MONITORING_FAILED,
+				ErrorDescr: err.Error()}
+		}
+		defer i.rLimit.Release(limits)
+
+		// goja has no instruction hook, only the memory limit and the
+		// timeout apply to JavaScript snippets.
+		if limits.Memory > 0 {
+			stop := rlimit.WatchHeap(limits.Memory, func() {
+				state.VM.Interrupt(rlimit.ErrOutOfMemory)
+			})
+			defer close(stop)
+		}
+	}
+
+	if input.Timeout > 0 {
+		deadline := time.AfterFunc(time.Duration(input.Timeout)*time.Millisecond, func() {
+			state.VM.Interrupt(context.DeadlineExceeded)
+		})
+		defer deadline.Stop()
+	}
+
+	value, err := i.run(state, input)
+	finishedOn := time.Now()
+
+	var stdout string
+	if state.Output != nil {
+		stdout = strings.Join(state.Output.Stdout, "")
+	}
+
+	if err != nil {
+		mlog.Error("Run error: %v", err)
+		timer.Stop()
+		ErrDoREPL.Incr()
+		execError := rtepub.GetExecutionError(err, nil)
+		return &rtepub.Output{StartedOn: startedOn,
+			FinishedOn: finishedOn,
+			Stdout:     stdout,
+			Status:     execError.Status,
+			ErrorDescr: execError.Description}
+	}
+
+	var result string
+	if value != nil && !goja.IsUndefined(value) && !goja.IsNull(value) {
+		result = value.String()
+	}
+	if state.Output != nil && state.Output.Result != "" {
+		result = state.Output.Result
+	}
+
+	timer.Stop()
+	DoREPL.Incr()
+
+	output := &rtepub.Output{
+		StartedOn:   startedOn,
+		FinishedOn:  finishedOn,
+		ElapsedTime: finishedOn.Sub(startedOn),
+		Stdout:      stdout,
+		Result:      result,
+		Status:      rtepub.SNIPPET_RUN_FINISHED,
+		ErrorDescr:  ""}
+	mlog.Debug("REPL output: %v", output)
+	return output
+}
+
+// run evaluates the snippet and calls its main function.
+func (i *JsInterpreter) run(state *State, input *rtepub.Input) (goja.Value, error) {
+	if _, err := state.VM.RunString(input.Code); err != nil {
+		return nil, err
+	}
+
+	main, ok := goja.AssertFunction(state.VM.Get(input.MainFn))
+	if !ok {
+		return nil, fmt.Errorf("function %s is not defined", input.MainFn)
+	}
+
+	return main(goja.Undefined())
+}
+
+func (i *JsInterpreter) Terminate() {
+	mlog.Debug("Terminating")
+
+	if i.State != nil {
+		i.State.Close()
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpreter
+
+import (
+	"github.com/lavaorg/northstar/rte/rlimit"
+	"github.com/lavaorg/northstar/rte/rtepub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func runSnippet(code string, timeout int) *rtepub.Output {
+	interpreter := NewJsInterpreter(rlimit.MockResourceLimit{})
+	params := make(map[string]interface{})
+	params["param1"] = "test"
+
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+		MainFn:  "main",
+		Code:    code,
+		Memory:  0,
+		Timeout: timeout,
+		Args:    params}
+	return interpreter.DoREPL(input)
+}
+
+func TestArgs(t *testing.T) {
+	code := `
+		var output = require("nsOutput");
+		function main() {
+			output.printf("My args are: %v", context.Args["param1"]);
+			return "10";
+		}
+	`
+	output := runSnippet(code, 1000)
+	require.Equal(t, rtepub.SNIPPET_RUN_FINISHED, output.Status, output.ErrorDescr)
+	assert.Equal(t, "10", output.Result)
+	assert.Equal(t, "My args are: test", output.Stdout)
+}
+
+func TestValueDirect(t *testing.T) {
+	code := `
+		var output = require("nsOutput");
+		function main() {
+			output.valueDirect({type: "int", value: "10"});
+		}
+	`
+	output := runSnippet(code, 1000)
+	require.Equal(t, rtepub.SNIPPET_RUN_FINISHED, output.Status, output.ErrorDescr)
+	assert.Contains(t, output.Result, "application/vnd.vz.value")
+}
+
+func TestChartDirect(t *testing.T) {
+	code := `
+		var output = require("nsOutput");
+		function main() {
+			output.chartDirect({type: "bar", series: [{x: ["a", "b"], y: [1, 2]}]});
+		}
+	`
+	output := runSnippet(code, 1000)
+	require.Equal(t, rtepub.SNIPPET_RUN_FINISHED, output.Status, output.ErrorDescr)
+	assert.Contains(t, output.Result, "application/vnd.vz.chart")
+}
+
+func TestSlowSnippet(t *testing.T) {
+	code := `
+		function main() {
+			while (true) {}
+		}
+	`
+	output := runSnippet(code, 1000)
+	require.Equal(t, rtepub.SNIPPET_RUN_TIMEDOUT, output.Status)
+}
+
+func TestSnippetRuntimeError(t *testing.T) {
+	code := `
+		function main() {
+			undefinedFunction();
+		}
+	`
+	output := runSnippet(code, 1000)
+	assert.Equal(t, rtepub.SNIPPET_REPL_FAILED, output.Status)
+}
+
+func TestUnknownModule(t *testing.T) {
+	code := `
+		var unknown = require("unknown");
+		function main() {}
+	`
+	output := runSnippet(code, 1000)
+	assert.Equal(t, rtepub.SNIPPET_REPL_FAILED, output.Status)
+	assert.Contains(t, output.ErrorDescr, "module unknown not found")
+}
@@ -0,0 +1,151 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpreter
+
+import (
+	"errors"
+	"github.com/dop251/goja"
+	"github.com/lavaorg/lrtx/config"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/rte-js/modules/nsKV"
+	"github.com/lavaorg/northstar/rte-js/modules/nsObject"
+	"github.com/lavaorg/northstar/rte-js/modules/nsOutput"
+	"github.com/lavaorg/northstar/rte-js/modules/nsQL"
+	pkgCfg "github.com/lavaorg/northstar/rte/config"
+	"github.com/lavaorg/northstar/rte/rtepub"
+)
+
+var (
+	EnableNSQL, _     = config.GetBool("ENABLE_NSQL", false)
+	EnableNSOutput, _ = config.GetBool("ENABLE_NSOUTPUT", true)
+	EnableNSObject, _ = config.GetBool("ENABLE_NSOBJECT", false)
+	EnableNSKV, _     = config.GetBool("ENABLE_NSKV", false)
+)
+
+// Loader creates the object returned by require for a module.
+type Loader func(vm *goja.Runtime) *goja.Object
+
+type ExecutionContext struct {
+	Args map[string]interface{}
+}
+
+type State struct {
+	VM      *goja.Runtime
+	Output  *nsOutput.NsOutputModule
+	NSQL    *nsQL.NsQLModule
+	policy  *rtepub.Policy
+	modules map[string]Loader
+	loaded  map[string]*goja.Object
+}
+
+func CreateState(input *rtepub.Input) (*State, error) {
+	vm := goja.New()
+	vm.Set("context", ExecutionContext{Args: input.Args})
+
+	output := &State{VM: vm,
+		policy:  input.Policy,
+		modules: make(map[string]Loader),
+		loaded:  make(map[string]*goja.Object)}
+	vm.Set("require", output.require)
+
+	if EnableNSQL && output.permitted("nsQL") {
+		mlog.Debug("Loading nsQL module")
+		output.NSQL = nsQL.NewNSQLModule()
+		output.PreloadModule("nsQL", output.NSQL.Loader)
+	}
+
+	if EnableNSOutput && output.permitted("nsOutput") {
+		mlog.Debug("Loading nsOutput module")
+		output.Output = nsOutput.NewNsOutputModule()
+		output.PreloadModule("nsOutput", output.Output.Loader)
+	}
+
+	if EnableNSObject && output.permitted("nsObject") {
+		mlog.Debug("Loading nsObject module")
+		nsObjectModule, err := nsObject.NewNsObjectModule(input.AccountId)
+		if err != nil {
+			return nil, err
+		}
+		output.PreloadModule("nsObject", nsObjectModule.Loader)
+	}
+
+	if EnableNSKV && output.permitted("nsKV") {
+		mlog.Debug("Loading nsKV module")
+		redisCluster, err := pkgCfg.CreateRedisCluster()
+		if err != nil {
+			return nil, err
+		}
+
+		output.PreloadModule("nsKV", nsKV.NewNsKVModule(redisCluster, input.AccountId).Loader)
+	}
+
+	return output, nil
+}
+
+// PreloadModule registers a module to be created the first time a snippet
+// requires it.
+func (s *State) PreloadModule(name string, loader Loader) {
+	s.modules[name] = loader
+}
+
+// permitted reports whether the policy of the snippet permits the module.
+// A module which is not permitted is registered with a loader throwing the
+// violation, so require reports it instead of a missing module.
+func (s *State) permitted(name string) bool {
+	err := s.policy.CheckModule(name)
+	if err == nil {
+		return true
+	}
+
+	mlog.Debug("Not loading %s module: %v", name, err)
+	s.PreloadModule(name, func(vm *goja.Runtime) *goja.Object {
+		panic(vm.NewGoError(err))
+	})
+	return false
+}
+
+func (s *State) require(name string) *goja.Object {
+	if module, ok := s.loaded[name]; ok {
+		return module
+	}
+
+	loader, ok := s.modules[name]
+	if !ok {
+		panic(s.VM.NewGoError(errors.New("module " + name + " not found")))
+	}
+
+	module := loader(s.VM)
+	s.loaded[name] = module
+	return module
+}
+
+func (s *State) Close() {
+	s.Clean()
+	if s.VM != nil {
+		s.VM.Interrupt(errTerminated)
+	}
+}
+
+func (s *State) Clean() {
+	if s.Output != nil {
+		s.Output.Reset()
+	}
+
+	if s.NSQL != nil {
+		s.NSQL.Reset()
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsKV
+
+import (
+	"github.com/dop251/goja"
+	"github.com/lavaorg/northstar/rte-js/util"
+	"github.com/lavaorg/northstar/rte/rtepub"
+	"time"
+)
+
+const (
+	NS_KV_ERROR = "nsKV error: "
+	GET         = "get"
+	SET         = "set"
+	DEL         = "del"
+)
+
+// NsKVModule is the JavaScript counterpart of the Lua nsKV module. Keys are
+// prefixed with the account id so accounts do not see each other's keys.
+type NsKVModule struct {
+	store     rtepub.KVStore
+	keyPrefix string
+}
+
+func NewNsKVModule(store rtepub.KVStore, keyPrefix string) *NsKVModule {
+	return &NsKVModule{store: store, keyPrefix: keyPrefix}
+}
+
+func (nsKV *NsKVModule) Loader(vm *goja.Runtime) *goja.Object {
+	module := vm.NewObject()
+	module.Set(GET, func(key string) string {
+		value, err := nsKV.store.Get(nsKV.key(key))
+		if err != nil {
+			nsKV.throw(vm, GET, err)
+		}
+
+		Get.Incr()
+		return value
+	})
+	// set(key, value[, ttl]) stores the value, optionally expiring after
+	// ttl seconds.
+	module.Set(SET, func(key string, value string, ttl int64) {
+		if err := nsKV.store.Set(nsKV.key(key), value, time.Duration(ttl)*time.Second); err != nil {
+			nsKV.throw(vm, SET, err)
+		}
+
+		Set.Incr()
+	})
+	module.Set(DEL, func(key string) {
+		if err := nsKV.store.Del(nsKV.key(key)); err != nil {
+			nsKV.throw(vm, DEL, err)
+		}
+
+		Del.Incr()
+	})
+
+	return module
+}
+
+func (nsKV *NsKVModule) key(key string) string {
+	return nsKV.keyPrefix + key
+}
+
+func (nsKV *NsKVModule) throw(vm *goja.Runtime, context string, err error) {
+	switch context {
+	case GET:
+		ErrGet.Incr()
+	case SET:
+		ErrSet.Incr()
+	case DEL:
+		ErrDel.Incr()
+	}
+
+	util.Throw(vm, NS_KV_ERROR, err)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsObject
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dop251/goja"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/object/client"
+	"github.com/lavaorg/northstar/object/model"
+	"github.com/lavaorg/northstar/rte-js/util"
+	"strconv"
+	"time"
+)
+
+const NS_OBJECT_ERROR = "nsObject error: "
+
+// NsObjectModule is the JavaScript counterpart of the Lua nsObject module.
+// File contents are exchanged as strings or arrays of bytes.
+type NsObjectModule struct {
+	Client    *client.ObjectClient
+	AccountId string
+}
+
+func NewNsObjectModule(accountId string) (*NsObjectModule, error) {
+	cli, err := client.NewObjectClient()
+	if err != nil {
+		return nil, err
+	}
+	return &NsObjectModule{Client: cli, AccountId: accountId}, nil
+}
+
+func (nsObject *NsObjectModule) Loader(vm *goja.Runtime) *goja.Object {
+	module := vm.NewObject()
+	module.Set("createBucket", func(bucketName string) {
+		bucket := &model.Bucket{Name: bucketName, CreationDate: time.Now()}
+		if _, mErr := nsObject.Client.CreateBucket(nsObject.AccountId, bucket); mErr != nil {
+			mlog.Error(mErr.Error())
+			nsObject.throw(vm, "createBucket", fmt.Errorf("Failed to create bucket: %s", bucketName))
+		}
+
+		CreateBucket.Incr()
+	})
+	module.Set("deleteBucket", func(bucketName string) {
+		if mErr := nsObject.Client.DeleteBucket(nsObject.AccountId, bucketName); mErr != nil {
+			mlog.Error(mErr.Error())
+			nsObject.throw(vm, "deleteBucket", fmt.Errorf("Failed to delete bucket: %s", bucketName))
+		}
+
+		DeleteBucket.Incr()
+	})
+	module.Set("listBuckets", func() []map[string]interface{} {
+		buckets, mErr := nsObject.Client.ListBuckets(nsObject.AccountId)
+		if mErr != nil {
+			mlog.Error(mErr.Error())
+			nsObject.throw(vm, "listBuckets", errors.New("Failed to list buckets"))
+		}
+
+		list := []map[string]interface{}{}
+		for _, bucket := range buckets {
+			list = append(list, map[string]interface{}{"name": bucket.Name,
+				"date": bucket.CreationDate.String()})
+		}
+
+		ListBuckets.Incr()
+		return list
+	})
+	module.Set("uploadFile", func(call goja.FunctionCall) goja.Value {
+		nsObject.uploadFile(vm, call)
+		return goja.Undefined()
+	})
+	module.Set("downloadFile", func(bucketName string, fileName string) map[string]interface{} {
+		data, mErr := nsObject.Client.DownloadFile(nsObject.AccountId, bucketName, fileName)
+		if mErr != nil {
+			mlog.Error(mErr.Error())
+			nsObject.throw(vm, "downloadFile", fmt.Errorf("Failed to download file %s", fileName))
+		}
+
+		payload := make([]interface{}, len(data.Payload))
+		for i, d := range data.Payload {
+			payload[i] = int64(d)
+		}
+
+		DownloadFile.Incr()
+		return map[string]interface{}{"Payload": payload, "ContentType": data.ContentType}
+	})
+	module.Set("deleteFile", func(bucketName string, fileName string) {
+		if mErr := nsObject.Client.DeleteFile(nsObject.AccountId, bucketName, fileName); mErr != nil {
+			mlog.Error(mErr.Error())
+			nsObject.throw(vm, "deleteFile", fmt.Errorf("Failed to delete file %s", fileName))
+		}
+
+		DeleteFile.Incr()
+	})
+	module.Set("listFiles", func(bucketName string) []map[string]interface{} {
+		objects, mErr := nsObject.Client.ListFiles(nsObject.AccountId, bucketName)
+		if mErr != nil {
+			mlog.Error(mErr.Error())
+			nsObject.throw(vm, "listFiles", errors.New("Failed to list files"))
+		}
+
+		list := []map[string]interface{}{}
+		for _, object := range objects {
+			list = append(list, map[string]interface{}{"key": object.Key,
+				"last_modified": object.LastModified.String(),
+				"size":          strconv.FormatInt(object.Size, 10),
+				"etag":          object.Etag,
+				"storage_class": object.StorageClass})
+		}
+
+		ListFiles.Incr()
+		return list
+	})
+
+	return module
+}
+
+func (nsObject *NsObjectModule) uploadFile(vm *goja.Runtime, call goja.FunctionCall) {
+	bucketName := call.Argument(0).String()
+	fileName := call.Argument(1).String()
+	contentType := call.Argument(3).String()
+
+	var data []byte
+	switch input := call.Argument(2).Export().(type) {
+	case string:
+		data = []byte(input)
+	case []interface{}:
+		if len(input) == 0 {
+			nsObject.throw(vm, "uploadFile", errors.New("unexpected array"))
+		}
+
+		for _, value := range input {
+			switch b := value.(type) {
+			case int64:
+				data = append(data, byte(b))
+			case float64:
+				data = append(data, byte(b))
+			default:
+				nsObject.throw(vm, "uploadFile", errors.New("unexpected value in array, byte expected"))
+			}
+		}
+	default:
+		nsObject.throw(vm, "uploadFile", errors.New("unexpected value, string or byte array expected"))
+	}
+
+	uploadData := &model.UploadData{FileName: fileName, Payload: data, ContentType: contentType}
+	if _, mErr := nsObject.Client.UploadFile(nsObject.AccountId, bucketName, uploadData); mErr != nil {
+		mlog.Error(mErr.Error())
+		nsObject.throw(vm, "uploadFile", fmt.Errorf("Failed to upload file %s", fileName))
+	}
+
+	UploadFile.Incr()
+}
+
+func (nsObject *NsObjectModule) throw(vm *goja.Runtime, context string, err error) {
+	switch context {
+	case "createBucket":
+		ErrCreateBucket.Incr()
+	case "deleteBucket":
+		ErrDeleteBucket.Incr()
+	case "listBuckets":
+		ErrListBuckets.Incr()
+	case "uploadFile":
+		ErrUploadFile.Incr()
+	case "downloadFile":
+		ErrDownloadFile.Incr()
+	case "deleteFile":
+		ErrDeleteFile.Incr()
+	case "listFiles":
+		ErrListFiles.Incr()
+	}
+
+	util.Throw(vm, NS_OBJECT_ERROR, err)
+}
@@ -0,0 +1,238 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsOutput
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dop251/goja"
+	"github.com/lavaorg/lrtx/config"
+	"github.com/lavaorg/northstar/rte-js/util"
+	luaOutput "github.com/lavaorg/northstar/rte-lua/modules/nsOutput"
+)
+
+const (
+	NS_OUTPUT_ERROR = "nsOutput error: "
+
+	_PRINT        = "print"
+	_PRINTF       = "printf"
+	_VALUE        = "value"
+	_VALUE_DIRECT = "valueDirect"
+	_TABLE        = "table"
+	_TABLE_DIRECT = "tableDirect"
+	_MAP          = "map"
+	_MAP_DIRECT   = "mapDirect"
+	_HTML         = "html"
+	_HTML_DIRECT  = "htmlDirect"
+	_CHART        = "chart"
+	_CHART_DIRECT = "chartDirect"
+	_TABLE_TO_CSV = "tableToCsv"
+)
+
+var (
+	NsOutputPrintLimit, _ = config.GetInt("NS_OUTPUT_PRINT_LIMIT", 10000)
+)
+
+// NsOutputModule is the JavaScript counterpart of the Lua nsOutput module,
+// producing the same output documents.
+type NsOutputModule struct {
+	Limit   int
+	Rolling int
+	Stdout  []string
+	Result  string
+}
+
+func NewNsOutputModule() *NsOutputModule {
+	return &NsOutputModule{Limit: NsOutputPrintLimit,
+		Rolling: NsOutputPrintLimit,
+		Stdout:  []string{}}
+}
+
+func (nsOutput *NsOutputModule) Loader(vm *goja.Runtime) *goja.Object {
+	module := vm.NewObject()
+	module.Set(_PRINT, func(call goja.FunctionCall) goja.Value {
+		nsOutput.write(vm, _PRINT, fmt.Sprint(exportArguments(call.Arguments)...))
+		Print.Incr()
+		return goja.Undefined()
+	})
+	module.Set(_PRINTF, func(call goja.FunctionCall) goja.Value {
+		format, ok := call.Argument(0).Export().(string)
+		if !ok {
+			nsOutput.throw(vm, _PRINTF, errors.New("first argument of printf must be a string"))
+		}
+
+		nsOutput.write(vm, _PRINTF, fmt.Sprintf(format, exportArguments(call.Arguments[1:])...))
+		Printf.Incr()
+		return goja.Undefined()
+	})
+	module.Set(_VALUE, nsOutput.generator(vm, _VALUE, false, func() interface{} { return &luaOutput.Value{} }))
+	module.Set(_VALUE_DIRECT, nsOutput.generator(vm, _VALUE_DIRECT, true, func() interface{} { return &luaOutput.Value{} }))
+	module.Set(_TABLE, nsOutput.generator(vm, _TABLE, false, func() interface{} { return &luaOutput.Table{} }))
+	module.Set(_TABLE_DIRECT, nsOutput.generator(vm, _TABLE_DIRECT, true, func() interface{} { return &luaOutput.Table{} }))
+	module.Set(_MAP, nsOutput.generator(vm, _MAP, false, func() interface{} { return &luaOutput.Map{} }))
+	module.Set(_MAP_DIRECT, nsOutput.generator(vm, _MAP_DIRECT, true, func() interface{} { return &luaOutput.Map{} }))
+	module.Set(_CHART, nsOutput.generator(vm, _CHART, false, func() interface{} { return &luaOutput.Chart{} }))
+	module.Set(_CHART_DIRECT, nsOutput.generator(vm, _CHART_DIRECT, true, func() interface{} { return &luaOutput.Chart{} }))
+	module.Set(_HTML, nsOutput.htmlGenerator(vm, _HTML, false))
+	module.Set(_HTML_DIRECT, nsOutput.htmlGenerator(vm, _HTML_DIRECT, true))
+	module.Set(_TABLE_TO_CSV, func(call goja.FunctionCall) goja.Value {
+		var table luaOutput.Table
+		if err := util.Map(call.Argument(0), &table); err != nil {
+			nsOutput.throw(vm, _TABLE_TO_CSV, err)
+		}
+
+		data, err := luaOutput.FormatTableCsv(&table)
+		if err != nil {
+			nsOutput.throw(vm, _TABLE_TO_CSV, err)
+		}
+
+		TableToCsv.Incr()
+		return vm.ToValue(data)
+	})
+
+	return module
+}
+
+func (nsOutput *NsOutputModule) Reset() {
+	nsOutput.Rolling = NsOutputPrintLimit
+	nsOutput.Stdout = nil
+	nsOutput.Result = ""
+}
+
+// generator returns a function building the output document of the given
+// type from its argument. Direct functions set the result of the snippet
+// instead of returning the document.
+func (nsOutput *NsOutputModule) generator(vm *goja.Runtime,
+	context string,
+	direct bool,
+	newValue func() interface{}) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		value := newValue()
+		if err := util.Map(call.Argument(0), value); err != nil {
+			nsOutput.throw(vm, context, err)
+		}
+
+		output, err := luaOutput.Generate(value)
+		if err != nil {
+			nsOutput.throw(vm, context, err)
+		}
+
+		return nsOutput.result(vm, context, direct, output)
+	}
+}
+
+func (nsOutput *NsOutputModule) htmlGenerator(vm *goja.Runtime,
+	context string,
+	direct bool) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		output, err := luaOutput.GenerateOutput("text/html", call.Argument(0).String())
+		if err != nil {
+			nsOutput.throw(vm, context, err)
+		}
+
+		return nsOutput.result(vm, context, direct, output)
+	}
+}
+
+func (nsOutput *NsOutputModule) result(vm *goja.Runtime, context string, direct bool, output string) goja.Value {
+	recordStats(context)
+	if direct {
+		nsOutput.Result = output
+		return goja.Undefined()
+	}
+
+	return vm.ToValue(output)
+}
+
+func (nsOutput *NsOutputModule) write(vm *goja.Runtime, context string, out string) {
+	if nsOutput.Rolling-len([]byte(out)) < 0 {
+		nsOutput.throw(vm, context, fmt.Errorf("%d-byte stdout limit is exceeded", nsOutput.Limit))
+	}
+
+	nsOutput.Rolling -= len([]byte(out))
+	nsOutput.Stdout = append(nsOutput.Stdout, out)
+}
+
+func (nsOutput *NsOutputModule) throw(vm *goja.Runtime, context string, err error) {
+	recordErrorStats(context)
+	util.Throw(vm, NS_OUTPUT_ERROR, err)
+}
+
+func exportArguments(arguments []goja.Value) []interface{} {
+	var exported []interface{}
+	for _, argument := range arguments {
+		exported = append(exported, argument.Export())
+	}
+
+	return exported
+}
+
+func recordStats(context string) {
+	switch context {
+	case _VALUE:
+		ValueCounter.Incr()
+	case _VALUE_DIRECT:
+		ValueDirectCounter.Incr()
+	case _TABLE:
+		TableCounter.Incr()
+	case _TABLE_DIRECT:
+		TableDirectCounter.Incr()
+	case _MAP:
+		MapCounter.Incr()
+	case _MAP_DIRECT:
+		MapDirectCounter.Incr()
+	case _HTML:
+		HTMLCounter.Incr()
+	case _HTML_DIRECT:
+		HTMLDirectCounter.Incr()
+	case _CHART:
+		ChartCounter.Incr()
+	case _CHART_DIRECT:
+		ChartDirectCounter.Incr()
+	}
+}
+
+func recordErrorStats(context string) {
+	switch context {
+	case _PRINT:
+		ErrPrint.Incr()
+	case _PRINTF:
+		ErrPrintf.Incr()
+	case _VALUE:
+		ErrValue.Incr()
+	case _VALUE_DIRECT:
+		ErrValueDirect.Incr()
+	case _TABLE:
+		ErrTable.Incr()
+	case _TABLE_DIRECT:
+		ErrTableDirect.Incr()
+	case _MAP:
+		ErrMap.Incr()
+	case _MAP_DIRECT:
+		ErrMapDirect.Incr()
+	case _HTML:
+		ErrHTML.Incr()
+	case _HTML_DIRECT:
+		ErrHTMLDirect.Incr()
+	case _CHART:
+		ErrChart.Incr()
+	case _CHART_DIRECT:
+		ErrChartDirect.Incr()
+	case _TABLE_TO_CSV:
+		ErrTableToCsv.Incr()
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsQL
+
+import (
+	"errors"
+	"github.com/dop251/goja"
+	"github.com/lavaorg/northstar/rte-js/util"
+	luaQL "github.com/lavaorg/northstar/rte-lua/modules/nsQL"
+	"github.com/lavaorg/northstar/rte-lua/modules/nsQL/compiler"
+	"github.com/lavaorg/northstar/rte-lua/modules/nsQL/compiler/cassandra"
+)
+
+const (
+	NSQL_ERROR = "nsQL error: "
+	CONNECT    = "connect"
+	DISCONNECT = "disconnect"
+	QUERY      = "query"
+)
+
+// NsQLModule is the JavaScript counterpart of the Lua nsQL module. It runs
+// queries with the same compilers.
+type NsQLModule struct {
+	compilers []*cassandra.CassandraCompiler
+}
+
+func NewNSQLModule() *NsQLModule {
+	return &NsQLModule{}
+}
+
+func (nsQL *NsQLModule) Loader(vm *goja.Runtime) *goja.Object {
+	module := vm.NewObject()
+	module.Set(CONNECT, func(call goja.FunctionCall) goja.Value {
+		return nsQL.connect(vm, call)
+	})
+	module.Set(QUERY, func(call goja.FunctionCall) goja.Value {
+		return nsQL.queryDirect(vm, call)
+	})
+
+	return module
+}
+
+// Reset disconnects the sessions opened by the snippet.
+func (nsQL *NsQLModule) Reset() {
+	for _, compiler := range nsQL.compilers {
+		compiler.Disconnect()
+	}
+
+	nsQL.compilers = nil
+}
+
+func (nsQL *NsQLModule) connect(vm *goja.Runtime, call goja.FunctionCall) goja.Value {
+	timer := NsQL.NewTimer("ConnectTimer")
+	defer timer.Stop()
+
+	var source compiler.Source
+	if err := util.Map(call.Argument(0), &source); err != nil {
+		nsQL.throw(vm, CONNECT, err)
+	}
+
+	comp, err := luaQL.NewCompiler(&source)
+	if err != nil {
+		nsQL.throw(vm, CONNECT, err)
+	}
+
+	cassandraCompiler, ok := comp.(*cassandra.CassandraCompiler)
+	if !ok {
+		nsQL.throw(vm, CONNECT, errors.New("invalid backend or protocol"))
+	}
+
+	cassandraCompiler.Connect()
+	nsQL.compilers = append(nsQL.compilers, cassandraCompiler)
+
+	ql := vm.NewObject()
+	ql.Set(QUERY, func(call goja.FunctionCall) goja.Value {
+		timer := NsQL.NewTimer("QueryTimer")
+		defer timer.Stop()
+
+		options, err := getOptions(call.Argument(1))
+		if err != nil {
+			nsQL.throw(vm, QUERY, err)
+		}
+
+		response, err := cassandraCompiler.Run(call.Argument(0).String(), options)
+		if err != nil {
+			nsQL.throw(vm, QUERY, err)
+		}
+
+		Query.Incr()
+		return vm.ToValue(response)
+	})
+	ql.Set(DISCONNECT, func(call goja.FunctionCall) goja.Value {
+		cassandraCompiler.Disconnect()
+		Disconnect.Incr()
+		return goja.Undefined()
+	})
+
+	Connect.Incr()
+	return ql
+}
+
+func (nsQL *NsQLModule) queryDirect(vm *goja.Runtime, call goja.FunctionCall) goja.Value {
+	timer := NsQL.NewTimer("QueryDirectTimer")
+	defer timer.Stop()
+
+	var source compiler.Source
+	if err := util.Map(call.Argument(1), &source); err != nil {
+		nsQL.throw(vm, QUERY, err)
+	}
+
+	comp, err := luaQL.NewCompiler(&source)
+	if err != nil {
+		nsQL.throw(vm, QUERY, err)
+	}
+
+	options, err := getOptions(call.Argument(2))
+	if err != nil {
+		nsQL.throw(vm, QUERY, err)
+	}
+
+	response, err := comp.Run(call.Argument(0).String(), options)
+	if err != nil {
+		nsQL.throw(vm, QUERY, err)
+	}
+
+	QueryDirect.Incr()
+	return vm.ToValue(response)
+}
+
+func getOptions(value goja.Value) (*compiler.Options, error) {
+	var options compiler.Options
+	if goja.IsUndefined(value) || goja.IsNull(value) {
+		return &options, nil
+	}
+
+	if err := util.Map(value, &options); err != nil {
+		return nil, err
+	}
+
+	return &options, nil
+}
+
+func (nsQL *NsQLModule) throw(vm *goja.Runtime, context string, err error) {
+	switch context {
+	case CONNECT:
+		ErrConnect.Incr()
+	case DISCONNECT:
+		ErrDisconnect.Incr()
+	case QUERY:
+		ErrQuery.Incr()
+	}
+
+	util.Throw(vm, NSQL_ERROR, err)
+}
@@ -0,0 +1,32 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsQL
+
+import (
+	"github.com/lavaorg/lrtx/stats"
+)
+
+var (
+	NsQL          = stats.New("jsNsQL")
+	Connect       = NsQL.NewCounter("Connect")
+	Disconnect    = NsQL.NewCounter("Disconnect")
+	Query         = NsQL.NewCounter("Query")
+	QueryDirect   = NsQL.NewCounter("QueryDirect")
+	ErrConnect    = NsQL.NewCounter("ErrConnect")
+	ErrDisconnect = NsQL.NewCounter("ErrDisconnect")
+	ErrQuery      = NsQL.NewCounter("ErrQuery")
+)
@@ -0,0 +1,43 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/dop251/goja"
+)
+
+// Map copies a JavaScript object into the given Go value using the json
+// names of its fields.
+func Map(value goja.Value, target interface{}) error {
+	if goja.IsUndefined(value) || goja.IsNull(value) {
+		return errors.New("object expected")
+	}
+
+	encoded, err := json.Marshal(value.Export())
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(encoded, target)
+}
+
+// Throw raises the error as a JavaScript exception in the runtime.
+func Throw(vm *goja.Runtime, prefix string, err error) {
+	panic(vm.NewGoError(errors.New(prefix + err.Error())))
+}
@@ -23,12 +23,14 @@ import (
 	"github.com/lavaorg/northstar/rte/rlimit"
 	"github.com/lavaorg/northstar/rte/rtepub"
 	"strings"
+	"sync"
 	"time"
 )
 
 type LuaInterpreter struct {
 	State  *State
 	rLimit rlimit.ResourceLimit
+	m      sync.Mutex
 }
 
 func NewLuaInterpreter(rLimit rlimit.ResourceLimit) rtepub.Interpreter {
@@ -42,7 +44,7 @@ func (i *LuaInterpreter) DoREPL(input *rtepub.Input) *rtepub.Output {
 		"invocationId: %s", input.MainFn, input.Code, input.Args, input.Timeout, input.Memory,
 		input.AccountId, input.InvocationId)
 
-	state, err := CreateState(input)
+	state, err := statePool.Get(input)
 	if err != nil {
 		mlog.Error("Failed to create state: %v", err.Error())
 		timer.Stop()
@@ -52,44 +54,58 @@ func (i *LuaInterpreter) DoREPL(input *rtepub.Input) *rtepub.Output {
 			ErrorDescr: err.Error()}
 	}
 
-	i.State = state
-	defer state.Close()
+	// Only states which ran the snippet to completion go back to the pool.
+	finished := false
+	i.setState(state)
+	defer func() {
+		i.setState(nil)
+		if finished {
+			statePool.Put(state)
+		} else {
+			state.Close()
+		}
+	}()
 
-	var rLimitErr error
 	if config.EnableRLimit {
-		msg, err := i.rLimit.StartMonitoring(&rlimit.Resources{Memory: input.Memory})
+		limits, err := i.rLimit.Reserve(&rlimit.Resources{Memory: input.Memory})
 		if err != nil {
-			mlog.Error("Failed to start resource monitoring: %v", err.Error())
+			mlog.Error("Failed to reserve resources: %v", err.Error())
 			timer.Stop()
 			ErrDoREPL.Incr()
 			return &rtepub.Output{StartedOn: startedOn,
 				Status:     rtepub.START_MONITORING_FAILED,
 				ErrorDescr: err.Error()}
 		}
+		defer i.rLimit.Release(limits)
 
-		go func() {
-		L:
-			for {
-				select {
-				case rLimitErr = <-msg:
-					mlog.Debug("Received resource limit msg: %v", rLimitErr)
-					i.State.Close()
-					break L
-				default:
-				}
-			}
-
-			mlog.Debug("Lua rlimit goroutine stopped")
-		}()
+		state.SetLimits(limits)
 	}
 
-	err = state.LuaState.DoString(input.Code)
+	if input.Debug != nil {
+		if err := rtepub.CheckDebugEndpoint(input.Debug.Endpoint, config.DebugBaseUrl); err != nil {
+			mlog.Error("Refused debug session: %v", err)
+			timer.Stop()
+			ErrDoREPL.Incr()
+			return &rtepub.Output{StartedOn: startedOn,
+				Status:     rtepub.SNIPPET_NOT_PERMITTED,
+				ErrorDescr: err.Error()}
+		}
+
+		session := rtepub.NewHttpDebugSession(input.Debug.Endpoint,
+			time.Duration(config.DebugPollTimeout)*time.Second)
+		state.Debug(input.InvocationId, input.Debug, session)
+	}
+
+	fn, err := codeCache.Load(state.LuaState, input.Code)
+	if err == nil {
+		state.LuaState.Push(fn)
+		err = state.LuaState.PCall(0, lua.MultRet, nil)
+	}
 	if err != nil {
-		mlog.Error("DoString error: %v", err)
-		i.rLimit.StopMonitoring()
+		mlog.Error("Load error: %v", err)
 		timer.Stop()
 		ErrDoREPL.Incr()
-		execError := rtepub.GetExecutionError(err, rLimitErr)
+		execError := rtepub.GetExecutionError(err, nil)
 		return &rtepub.Output{StartedOn: startedOn,
 			Status:     execError.Status,
 			ErrorDescr: execError.Description}
@@ -103,13 +119,15 @@ func (i *LuaInterpreter) DoREPL(input *rtepub.Input) *rtepub.Output {
 		Protect: true,
 	})
 
-	stdout := strings.Join(state.Output.Stdout, "")
+	var stdout string
+	if state.Output != nil {
+		stdout = strings.Join(state.Output.Stdout, "")
+	}
 	if err != nil {
 		mlog.Error("CallByParam error: %v", err)
-		i.rLimit.StopMonitoring()
 		timer.Stop()
 		ErrDoREPL.Incr()
-		execError := rtepub.GetExecutionError(err, rLimitErr)
+		execError := rtepub.GetExecutionError(err, nil)
 		return &rtepub.Output{StartedOn: startedOn,
 			FinishedOn: finishedOn,
 			Stdout:     stdout,
@@ -119,11 +137,11 @@ func (i *LuaInterpreter) DoREPL(input *rtepub.Input) *rtepub.Output {
 
 	result := lua.LVAsString(state.LuaState.Get(-1))
 	state.LuaState.Pop(1)
-	if state.Output.Result != "" {
+	if state.Output != nil && state.Output.Result != "" {
 		result = state.Output.Result
 	}
 
-	i.rLimit.StopMonitoring()
+	finished = true
 	timer.Stop()
 	DoREPL.Incr()
 
@@ -142,7 +160,17 @@ func (i *LuaInterpreter) DoREPL(input *rtepub.Input) *rtepub.Output {
 func (i *LuaInterpreter) Terminate() {
 	mlog.Debug("Terminating")
 
+	i.m.Lock()
+	defer i.m.Unlock()
+
 	if i.State != nil {
 		i.State.Close()
 	}
 }
+
+func (i *LuaInterpreter) setState(state *State) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	i.State = state
+}
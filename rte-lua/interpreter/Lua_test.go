@@ -17,12 +17,17 @@ limitations under the License.
 package interpreter
 
 import (
+	"fmt"
+	"github.com/lavaorg/lrtx/management"
 	"github.com/lavaorg/lrtx/mlog"
-	"github.com/lavaorg/northstar/rte/repl"
+	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/data/libraries/model"
 	"github.com/lavaorg/northstar/rte/rlimit"
+	"github.com/lavaorg/northstar/rte/rtepub"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestArgs(t *testing.T) {
@@ -37,7 +42,7 @@ func TestArgs(t *testing.T) {
 			return "10"
 		end
 	`
-	input := &repl.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
 		MainFn:  "main",
 		Code:    code,
 		Memory:  0,
@@ -59,7 +64,7 @@ func TestModule(t *testing.T) {
 			output.printf("hi\n")
 		end
 	`
-	input := &repl.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
 		MainFn:  "main",
 		Code:    code,
 		Memory:  0,
@@ -88,7 +93,7 @@ func TestFormatter(t *testing.T) {
 			return output.value(number)
 		end
 	`
-	input := &repl.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
 		MainFn:  "main",
 		Code:    code,
 		Memory:  0,
@@ -109,7 +114,7 @@ func TestFastSnippet(t *testing.T) {
 			end
 		end
 	`
-	input := &repl.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
 		MainFn:  "main",
 		Code:    code,
 		Memory:  0,
@@ -130,7 +135,7 @@ func TestSlowSnippet(t *testing.T) {
 			end
 		end
 	`
-	input := &repl.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
 		MainFn:  "main",
 		Code:    code,
 		Memory:  0,
@@ -155,7 +160,7 @@ func TestSnippetRuntimeError(t *testing.T) {
 			add(10)
 		end
 	`
-	input := &repl.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
 		MainFn:  "main",
 		Code:    code,
 		Memory:  0,
@@ -178,7 +183,7 @@ func TestJsonEncode(t *testing.T) {
 		    output.print(raw)
 		end
 	`
-	input := &repl.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
 		MainFn:  "main",
 		Code:    code,
 		Memory:  0,
@@ -187,3 +192,307 @@ func TestJsonEncode(t *testing.T) {
 	output := interpreter.DoREPL(input)
 	assert.Equal(t, "{\"some_field\":1}", output.Stdout)
 }
+
+func TestInstructionLimit(t *testing.T) {
+	interpreter := NewLuaInterpreter(rlimit.MockResourceLimit{Instructions: 100000})
+
+	code := `
+		function main()
+			local i = 0
+			while true do
+				i = i + 1
+			end
+		end
+	`
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+		MainFn:  "main",
+		Code:    code,
+		Memory:  0,
+		Timeout: 10000}
+	output := interpreter.DoREPL(input)
+	require.Equal(t, rtepub.SNIPPET_INSTRUCTION_LIMIT, output.Status, "should be equal")
+}
+
+func TestMemoryLimit(t *testing.T) {
+	interpreter := NewLuaInterpreter(rlimit.MockResourceLimit{})
+
+	code := `
+		function main()
+			local t = {}
+			for i = 1, 10000000 do
+				t[i] = "value " .. i
+			end
+		end
+	`
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+		MainFn:  "main",
+		Code:    code,
+		Memory:  1024 * 1024,
+		Timeout: 10000}
+	output := interpreter.DoREPL(input)
+	require.Equal(t, rtepub.SNIPPET_OUT_OF_MEMORY, output.Status, "should be equal")
+}
+
+func TestMemoryLimitPerState(t *testing.T) {
+	interpreter := NewLuaInterpreter(rlimit.MockResourceLimit{})
+
+	code := `
+		function main()
+			local t = {}
+			for i = 1, 1000 do
+				t[i] = i
+			end
+			return "done"
+		end
+	`
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+		MainFn:  "main",
+		Code:    code,
+		Memory:  1024 * 1024,
+		Timeout: 10000}
+
+	// Garbage left by other snippets in the process does not count against the limit.
+	garbage := make([][]byte, 0)
+	for i := 0; i < 64; i++ {
+		garbage = append(garbage, make([]byte, 1024*1024))
+	}
+
+	output := interpreter.DoREPL(input)
+	require.Equal(t, rtepub.SNIPPET_RUN_FINISHED, output.Status, "should be equal")
+	require.Equal(t, "done", output.Result, "should be equal")
+	require.Len(t, garbage, 64)
+}
+
+func TestModuleNotPermitted(t *testing.T) {
+	interpreter := NewLuaInterpreter(rlimit.MockResourceLimit{})
+
+	code := `
+		local output = require("nsOutput")
+		function main()
+			output.printf("hi\n")
+		end
+	`
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+		MainFn:  "main",
+		Code:    code,
+		Memory:  0,
+		Timeout: 1000,
+		Policy:  &rtepub.Policy{Modules: []string{"nsUtil"}}}
+	output := interpreter.DoREPL(input)
+	assert.Equal(t, rtepub.SNIPPET_NOT_PERMITTED, output.Status, "should be equal")
+	assert.Contains(t, output.ErrorDescr, "module nsOutput is not permitted by the policy")
+}
+
+func TestModulesNotRestricted(t *testing.T) {
+	interpreter := NewLuaInterpreter(rlimit.MockResourceLimit{})
+
+	code := `
+		local output = require("nsOutput")
+		function main()
+			output.printf("hi\n")
+		end
+	`
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+		MainFn:  "main",
+		Code:    code,
+		Memory:  0,
+		Timeout: 1000,
+		Policy:  &rtepub.Policy{Hosts: []string{"ftp.example.com"}}}
+	output := interpreter.DoREPL(input)
+	assert.Equal(t, rtepub.SNIPPET_RUN_FINISHED, output.Status, output.ErrorDescr)
+	assert.Equal(t, "hi\n", output.Stdout, "should be equal")
+}
+
+func TestWithoutOutputModule(t *testing.T) {
+	interpreter := NewLuaInterpreter(rlimit.MockResourceLimit{})
+
+	code := `
+		function main()
+			return "10"
+		end
+	`
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+		MainFn:  "main",
+		Code:    code,
+		Memory:  0,
+		Timeout: 1000,
+		Policy:  &rtepub.Policy{Modules: []string{"nsUtil"}}}
+	output := interpreter.DoREPL(input)
+	assert.Equal(t, rtepub.SNIPPET_RUN_FINISHED, output.Status, output.ErrorDescr)
+	assert.Equal(t, "10", output.Result, "should be equal")
+	assert.Equal(t, "", output.Stdout, "should be equal")
+
+	code = `
+		function main()
+			error("failed")
+		end
+	`
+	input.Code = code
+	output = interpreter.DoREPL(input)
+	assert.Equal(t, "REPL_FAILED", output.Status, "should be equal")
+}
+
+func TestStateReuse(t *testing.T) {
+	interpreter := NewLuaInterpreter(rlimit.MockResourceLimit{})
+
+	code := `
+		function main()
+			local leaked = leak
+			leak = "leaked"
+			string.upper = nil
+			return tostring(leaked)
+		end
+	`
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+		MainFn:  "main",
+		Code:    code,
+		Memory:  0,
+		Timeout: 1000}
+	output := interpreter.DoREPL(input)
+	assert.Equal(t, rtepub.SNIPPET_RUN_FINISHED, output.Status, output.ErrorDescr)
+
+	code = `
+		function main()
+			return tostring(leak) .. string.upper("-ok")
+		end
+	`
+	input.Code = code
+	output = interpreter.DoREPL(input)
+	assert.Equal(t, rtepub.SNIPPET_RUN_FINISHED, output.Status, output.ErrorDescr)
+	assert.Equal(t, "nil-OK", output.Result, "globals should be reset")
+}
+
+type fakeDebugSession struct {
+	paused   []*rtepub.DebugPaused
+	commands []string
+}
+
+func (s *fakeDebugSession) Paused(paused *rtepub.DebugPaused) error {
+	s.paused = append(s.paused, paused)
+	return nil
+}
+
+func (s *fakeDebugSession) Command() (*rtepub.DebugCommand, error) {
+	command := s.commands[0]
+	s.commands = s.commands[1:]
+	return &rtepub.DebugCommand{Command: command}, nil
+}
+
+func TestDebugger(t *testing.T) {
+	code := `
+function add(a, b)
+	local sum = a + b
+	return sum
+end
+function main()
+	local x = add(1, 2)
+	return x
+end`
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+		MainFn:  "main",
+		Code:    code,
+		Timeout: 1000}
+	state, err := CreateState(input)
+	require.NoError(t, err)
+	defer state.Close()
+
+	session := &fakeDebugSession{commands: []string{rtepub.DEBUG_STEP_OUT, rtepub.DEBUG_CONTINUE}}
+	state.Debug(input.InvocationId, &rtepub.Debug{Breakpoints: []int{4}}, session)
+
+	require.NoError(t, state.LuaState.DoString(code))
+	require.NoError(t, state.LuaState.CallByParam(lua.P{
+		Fn:      state.LuaState.GetGlobal("main"),
+		NRet:    1,
+		Protect: true,
+	}))
+	assert.Equal(t, "3", lua.LVAsString(state.LuaState.Get(-1)))
+
+	require.Len(t, session.paused, 2)
+	assert.Equal(t, rtepub.DEBUG_PAUSED_BREAKPOINT, session.paused[0].Reason)
+	assert.Equal(t, 4, session.paused[0].Line)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2", "sum": "3"}, session.paused[0].Stack[0].Locals)
+	assert.Equal(t, rtepub.DEBUG_PAUSED_STEP, session.paused[1].Reason)
+	assert.Equal(t, 8, session.paused[1].Line)
+	assert.Equal(t, "3", session.paused[1].Stack[0].Locals["x"])
+}
+
+type fakeLibraries struct {
+	code    map[string]string
+	fetched int
+}
+
+func (l *fakeLibraries) GetLibrary(accountId string, name string, version int) (*model.LibraryData, *management.Error) {
+	l.fetched++
+	if version == model.LATEST {
+		version = 2
+	}
+
+	code, ok := l.code[fmt.Sprintf("%s@%d", name, version)]
+	if !ok {
+		return nil, management.GetNotFoundError("Library not found")
+	}
+
+	return &model.LibraryData{Name: name, Version: version, Code: code}, nil
+}
+
+func TestLibraries(t *testing.T) {
+	libraries := &fakeLibraries{code: map[string]string{
+		"text.case@1": `return {upper = function(s) return string.upper(s) end}`,
+		"text.case@2": `return {upper = function(s) return "v2:" .. string.upper(s) end}`}}
+	cache := NewLibraryCache(libraries, 8, time.Minute)
+
+	code := `
+		local pinned = require("lib.text.case@1")
+		local latest = require("lib.text.case")
+		function main()
+			local ok, err = pcall(require, "lib.missing")
+			return pinned.upper("a") .. "," .. latest.upper("b") .. "," .. tostring(ok)
+		end
+	`
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+		MainFn:  "main",
+		Code:    code,
+		Timeout: 1000}
+	for i := 0; i < 2; i++ {
+		state, err := CreateState(input)
+		require.NoError(t, err)
+		state.Libraries = cache
+
+		require.NoError(t, state.LuaState.DoString(code))
+		require.NoError(t, state.LuaState.CallByParam(lua.P{
+			Fn:      state.LuaState.GetGlobal("main"),
+			NRet:    1,
+			Protect: true,
+		}))
+		assert.Equal(t, "A,v2:B,false", lua.LVAsString(state.LuaState.Get(-1)))
+		state.Close()
+	}
+
+	assert.Equal(t, 4, libraries.fetched, "found versions should be cached")
+}
+
+func TestChartDirect(t *testing.T) {
+	interpreter := NewLuaInterpreter(rlimit.MockResourceLimit{})
+
+	code := `
+		local output = require("nsOutput")
+		function main()
+			local chart, err = output.chart({type = "pie", series = {{y = {1}}}})
+			if chart ~= nil then
+				return "rendered an unknown chart type"
+			end
+			output.chartDirect({type = "line",
+				x = {label = "time", type = "time"},
+				series = {{name = "cpu", x = {0, 60}, y = {0.5, 0.7}}}})
+		end
+	`
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+		MainFn:  "main",
+		Code:    code,
+		Memory:  0,
+		Timeout: 1000}
+	output := interpreter.DoREPL(input)
+	require.Equal(t, rtepub.SNIPPET_RUN_FINISHED, output.Status, output.ErrorDescr)
+	assert.Contains(t, output.Result, "application/vnd.vz.chart")
+	assert.Contains(t, output.Result, "1970-01-01T00:01:00Z")
+}
@@ -0,0 +1,223 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpreter
+
+import (
+	"context"
+	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/rte/config"
+	"github.com/lavaorg/northstar/rte/rlimit"
+	"sync"
+	"sync/atomic"
+)
+
+// Estimated sizes in bytes of the values held by a Lua state.
+const (
+	valueSize    = 16
+	stringSize   = 32
+	tableSize    = 96
+	entrySize    = 40
+	functionSize = 96
+	userDataSize = 64
+)
+
+// budgetContext enforces the limits of a single invocation on its Lua state.
+// The VM polls Done before executing every instruction, which is used to
+// count instructions and to measure the memory held by the state from the
+// goroutine running it, without reading process wide memory statistics.
+// Counting is lock free, the lock is only taken to sample the memory.
+type budgetContext struct {
+	context.Context
+	state        *lua.LState
+	memory       uint64
+	instructions uint64
+	baseline     uint64
+	executed     uint64
+	nextSample   uint64
+	lock         sync.Mutex
+	once         sync.Once
+	err          error
+	done         chan struct{}
+}
+
+func newBudgetContext(parent context.Context, state *lua.LState, limits *rlimit.Resources) *budgetContext {
+	ctx := &budgetContext{Context: parent,
+		state:        state,
+		memory:       limits.Memory,
+		instructions: limits.Instructions,
+		nextSample:   uint64(config.MemorySampleInterval),
+		done:         make(chan struct{})}
+
+	if ctx.memory > 0 {
+		ctx.baseline, _ = measureState(state)
+	}
+
+	return ctx
+}
+
+func (c *budgetContext) Done() <-chan struct{} {
+	select {
+	case <-c.done:
+		return c.done
+	default:
+	}
+
+	executed := atomic.AddUint64(&c.executed, 1)
+	if c.instructions > 0 && executed > c.instructions {
+		rlimit.InstructionLimit.Incr()
+		return c.exceed(rlimit.ErrInstructionLimit)
+	}
+
+	if c.memory > 0 && executed >= atomic.LoadUint64(&c.nextSample) {
+		return c.sample(executed)
+	}
+
+	return c.Context.Done()
+}
+
+// sample measures the memory held by the state once the sample interval
+// has passed.
+func (c *budgetContext) sample(executed uint64) <-chan struct{} {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Another caller may have sampled meanwhile.
+	if executed < c.nextSample {
+		return c.Context.Done()
+	}
+
+	used, objects := measureState(c.state)
+	if used > c.baseline && used-c.baseline > c.memory {
+		rlimit.OutOfMemory.Incr()
+		return c.exceed(rlimit.ErrOutOfMemory)
+	}
+
+	// Measuring walks every object of the state, so large states are
+	// sampled less often to keep the overhead proportional.
+	atomic.StoreUint64(&c.nextSample, executed+maxUint64(uint64(config.MemorySampleInterval), objects))
+	return c.Context.Done()
+}
+
+func (c *budgetContext) Err() error {
+	select {
+	case <-c.done:
+		return c.err
+	default:
+		return c.Context.Err()
+	}
+}
+
+// exceed fails the invocation with the first limit exceeded.
+func (c *budgetContext) exceed(err error) <-chan struct{} {
+	c.once.Do(func() {
+		c.err = err
+		close(c.done)
+	})
+	return c.done
+}
+
+// measureState estimates the bytes held by the values reachable from the
+// globals, the registry and the locals of every active call of the state.
+// It returns the estimate and the number of objects visited.
+func measureState(state *lua.LState) (uint64, uint64) {
+	visited := make(map[interface{}]bool)
+	pending := []lua.LValue{state.G.Global, state.G.Registry}
+
+	for level := 0; ; level++ {
+		dbg, ok := state.GetStack(level)
+		if !ok {
+			break
+		}
+
+		for n := 1; ; n++ {
+			name, value := state.GetLocal(dbg, n)
+			if name == "" {
+				break
+			}
+			pending = append(pending, value)
+		}
+	}
+
+	var size uint64
+	for len(pending) > 0 {
+		value := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		switch v := value.(type) {
+		case lua.LString:
+			size += stringSize + uint64(len(v))
+		case *lua.LTable:
+			if v == nil || visited[v] {
+				continue
+			}
+			visited[v] = true
+
+			size += tableSize
+			if v.Metatable != nil {
+				pending = append(pending, v.Metatable)
+			}
+			v.ForEach(func(key lua.LValue, value lua.LValue) {
+				size += entrySize
+				pending = append(pending, key, value)
+			})
+		case *lua.LFunction:
+			if v == nil || visited[v] {
+				continue
+			}
+			visited[v] = true
+
+			size += functionSize
+			if v.Env != nil {
+				pending = append(pending, v.Env)
+			}
+			if v.Proto != nil && !visited[v.Proto] {
+				visited[v.Proto] = true
+				size += uint64(len(v.Proto.Code)) * 4
+				for _, constant := range v.Proto.Constants {
+					pending = append(pending, constant)
+				}
+			}
+			for _, upvalue := range v.Upvalues {
+				if upvalue != nil {
+					pending = append(pending, upvalue.Value())
+				}
+			}
+		case *lua.LUserData:
+			if v == nil || visited[v] {
+				continue
+			}
+			visited[v] = true
+
+			size += userDataSize
+			if v.Metatable != nil {
+				pending = append(pending, v.Metatable)
+			}
+		default:
+			size += valueSize
+		}
+	}
+
+	return size, uint64(len(visited))
+}
+
+func maxUint64(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
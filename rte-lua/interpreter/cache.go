@@ -0,0 +1,108 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpreter
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/rte/config"
+	"strings"
+	"sync"
+)
+
+// CodeCache keeps the compiled code of the most recently run snippets,
+// keyed by the hash of the code, so snippets triggered frequently are only
+// parsed once. Compiled code is immutable and shared between states.
+type CodeCache struct {
+	m       sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type cacheEntry struct {
+	key   string
+	proto *lua.FunctionProto
+}
+
+func NewCodeCache(size int) *CodeCache {
+	return &CodeCache{size: size, entries: make(map[string]*list.Element), lru: list.New()}
+}
+
+// Load returns the snippet code as a function of the state, compiling it
+// unless it is cached.
+func (cache *CodeCache) Load(L *lua.LState, code string) (*lua.LFunction, error) {
+	return cache.LoadChunk(L, code, SNIPPET_SOURCE)
+}
+
+// LoadChunk returns the code as a function of the state, compiled with the
+// chunk name reported in errors and debug information.
+func (cache *CodeCache) LoadChunk(L *lua.LState, code string, chunk string) (*lua.LFunction, error) {
+	sum := sha256.Sum256([]byte(chunk + "\x00" + code))
+	key := hex.EncodeToString(sum[:])
+
+	if proto := cache.get(key); proto != nil {
+		CacheHit.Incr()
+		return &lua.LFunction{Proto: proto, Env: L.Env}, nil
+	}
+
+	CacheMiss.Incr()
+	fn, err := L.Load(strings.NewReader(code), chunk)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.put(key, fn.Proto)
+	return fn, nil
+}
+
+func (cache *CodeCache) get(key string) *lua.FunctionProto {
+	cache.m.Lock()
+	defer cache.m.Unlock()
+
+	element, ok := cache.entries[key]
+	if !ok {
+		return nil
+	}
+
+	cache.lru.MoveToFront(element)
+	return element.Value.(*cacheEntry).proto
+}
+
+func (cache *CodeCache) put(key string, proto *lua.FunctionProto) {
+	if cache.size <= 0 {
+		return
+	}
+
+	cache.m.Lock()
+	defer cache.m.Unlock()
+
+	if _, ok := cache.entries[key]; ok {
+		return
+	}
+
+	cache.entries[key] = cache.lru.PushFront(&cacheEntry{key: key, proto: proto})
+	if cache.lru.Len() > cache.size {
+		oldest := cache.lru.Back()
+		cache.lru.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+var codeCache = NewCodeCache(config.CodeCacheSize)
@@ -0,0 +1,273 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/rte/rtepub"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// SNIPPET_SOURCE is the chunk name of the snippet code, the only source
+	// breakpoints apply to.
+	SNIPPET_SOURCE = "<string>"
+
+	// Depth and number of entries to which tables are rendered.
+	debugValueDepth   = 2
+	debugTableEntries = 20
+)
+
+// debugContext pauses the snippet on its breakpoints and steps. Like the
+// budgetContext, it relies on the VM polling Done before executing every
+// instruction: a change of the current line or call depth is a line event,
+// on which the debugger decides whether to pause. While paused, it blocks
+// the VM until the client sends a command resuming the snippet. The
+// invocation deadline keeps running while paused.
+type debugContext struct {
+	context.Context
+	state        *lua.LState
+	session      rtepub.DebugSession
+	invocationId string
+	breakpoints  map[int]bool
+	entry        bool
+	mode         string
+	stepDepth    int
+	line         int
+	depth        int
+	lock         sync.Mutex
+	err          error
+	done         chan struct{}
+}
+
+// Debug runs the snippet of the state in debug mode, connected to the client
+// by the session.
+func (s *State) Debug(invocationId string, debug *rtepub.Debug, session rtepub.DebugSession) {
+	ctx := &debugContext{Context: s.LuaState.Context(),
+		state:        s.LuaState,
+		session:      session,
+		invocationId: invocationId,
+		entry:        debug.StopOnEntry,
+		mode:         rtepub.DEBUG_CONTINUE,
+		done:         make(chan struct{})}
+	ctx.setBreakpoints(debug.Breakpoints)
+
+	s.LuaState.SetContext(ctx)
+}
+
+func (c *debugContext) Done() <-chan struct{} {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return c.done
+	}
+
+	source, line, depth := c.position()
+	if source == SNIPPET_SOURCE && (line != c.line || depth != c.depth) {
+		c.line, c.depth = line, depth
+		if reason := c.reason(line, depth); reason != "" {
+			if err := c.pause(reason, line, depth); err != nil {
+				c.err = err
+				close(c.done)
+				return c.done
+			}
+		}
+	}
+
+	return c.Context.Done()
+}
+
+func (c *debugContext) Err() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	return c.Context.Err()
+}
+
+// position returns the source and line executed and the call depth.
+func (c *debugContext) position() (string, int, int) {
+	dbg, ok := c.state.GetStack(0)
+	if !ok {
+		return "", 0, 0
+	}
+
+	if _, err := c.state.GetInfo("Sl", dbg, lua.LNil); err != nil {
+		return "", 0, 0
+	}
+
+	depth := 1
+	for ; ; depth++ {
+		if _, ok := c.state.GetStack(depth); !ok {
+			break
+		}
+	}
+
+	return dbg.Source, dbg.CurrentLine, depth
+}
+
+// reason returns why the snippet pauses on the line, if it does.
+func (c *debugContext) reason(line int, depth int) string {
+	switch {
+	case c.entry:
+		c.entry = false
+		return rtepub.DEBUG_PAUSED_ENTRY
+	case c.breakpoints[line]:
+		return rtepub.DEBUG_PAUSED_BREAKPOINT
+	case c.mode == rtepub.DEBUG_STEP_INTO,
+		c.mode == rtepub.DEBUG_STEP_OVER && depth <= c.stepDepth,
+		c.mode == rtepub.DEBUG_STEP_OUT && depth < c.stepDepth:
+		return rtepub.DEBUG_PAUSED_STEP
+	}
+
+	return ""
+}
+
+// pause reports the paused snippet and waits for the command resuming it.
+func (c *debugContext) pause(reason string, line int, depth int) error {
+	mlog.Debug("Debugger paused on line %d: %s", line, reason)
+	paused := &rtepub.DebugPaused{InvocationId: c.invocationId,
+		Reason: reason,
+		Line:   line,
+		Stack:  c.stack()}
+	if err := c.session.Paused(paused); err != nil {
+		mlog.Error("Failed to report pause: %v", err)
+		return err
+	}
+
+	for {
+		if err := c.Context.Err(); err != nil {
+			return err
+		}
+
+		command, err := c.session.Command()
+		if err != nil {
+			mlog.Error("Failed to get debug command: %v", err)
+			return err
+		}
+
+		if command == nil {
+			continue
+		}
+
+		mlog.Debug("Debug command: %s", command.Command)
+		switch command.Command {
+		case rtepub.DEBUG_SET_BREAKPOINTS:
+			c.setBreakpoints(command.Breakpoints)
+		case rtepub.DEBUG_STOP:
+			return errors.New(rtepub.ERR_DEBUG_STOPPED)
+		case rtepub.DEBUG_CONTINUE, rtepub.DEBUG_STEP_INTO, rtepub.DEBUG_STEP_OVER, rtepub.DEBUG_STEP_OUT:
+			c.mode = command.Command
+			c.stepDepth = depth
+			return nil
+		default:
+			mlog.Error("Unknown debug command: %s", command.Command)
+		}
+	}
+}
+
+func (c *debugContext) setBreakpoints(lines []int) {
+	c.breakpoints = make(map[int]bool, len(lines))
+	for _, line := range lines {
+		c.breakpoints[line] = true
+	}
+}
+
+// stack returns the active calls, innermost first, with their locals and
+// upvalues.
+func (c *debugContext) stack() []rtepub.DebugFrame {
+	var stack []rtepub.DebugFrame
+	for level := 0; ; level++ {
+		dbg, ok := c.state.GetStack(level)
+		if !ok {
+			break
+		}
+
+		fn, err := c.state.GetInfo("Slnf", dbg, lua.LNil)
+		if err != nil {
+			break
+		}
+
+		frame := rtepub.DebugFrame{Function: dbg.Name,
+			Source:   dbg.Source,
+			Line:     dbg.CurrentLine,
+			Locals:   make(map[string]string),
+			Upvalues: make(map[string]string)}
+
+		for n := 1; ; n++ {
+			name, value := c.state.GetLocal(dbg, n)
+			if name == "" {
+				break
+			}
+
+			// Skip the internal variables of the VM, e.g. "(for index)".
+			if !strings.HasPrefix(name, "(") {
+				frame.Locals[name] = describe(value, 0)
+			}
+		}
+
+		if lf, ok := fn.(*lua.LFunction); ok && lf.Proto != nil {
+			for i, upvalue := range lf.Upvalues {
+				if i < len(lf.Proto.DbgUpvalues) && upvalue != nil {
+					frame.Upvalues[lf.Proto.DbgUpvalues[i]] = describe(upvalue.Value(), 0)
+				}
+			}
+		}
+
+		stack = append(stack, frame)
+	}
+
+	return stack
+}
+
+// describe renders the value for the client, tables up to debugValueDepth
+// levels and debugTableEntries entries.
+func describe(value lua.LValue, depth int) string {
+	switch v := value.(type) {
+	case lua.LString:
+		return strconv.Quote(string(v))
+	case *lua.LTable:
+		if depth >= debugValueDepth {
+			return "{...}"
+		}
+
+		var entries []string
+		key, entry := v.Next(lua.LNil)
+		for key != lua.LNil {
+			if len(entries) == debugTableEntries {
+				entries = append(entries, "...")
+				break
+			}
+
+			entries = append(entries, fmt.Sprintf("[%s] = %s", describe(key, depth+1), describe(entry, depth+1)))
+			key, entry = v.Next(key)
+		}
+		return "{" + strings.Join(entries, ", ") + "}"
+	}
+
+	return value.String()
+}
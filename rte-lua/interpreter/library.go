@@ -0,0 +1,233 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpreter
+
+import (
+	"container/list"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/data/libraries/client"
+	"github.com/lavaorg/northstar/data/libraries/model"
+	"github.com/lavaorg/northstar/rte/config"
+)
+
+// LIBRARY_PREFIX is the prefix of the modules resolved from the libraries of
+// the account, required as lib.<name> for the latest version or
+// lib.<name>@<version> for a given one.
+const LIBRARY_PREFIX = "lib."
+
+// LibrarySource returns the versions of the libraries of the accounts.
+type LibrarySource interface {
+	GetLibrary(accountId string, name string, version int) (*model.LibraryData, *management.Error)
+}
+
+// LibraryCache keeps the library versions fetched from the source, so
+// snippets requiring a library do not reach the data service on every
+// invocation. Versions are immutable and kept until evicted, the latest
+// version of a library is fetched again once the ttl elapsed.
+type LibraryCache struct {
+	m       sync.Mutex
+	source  LibrarySource
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type libraryEntry struct {
+	key     string
+	library *model.LibraryData
+	expires time.Time
+}
+
+func NewLibraryCache(source LibrarySource, size int, ttl time.Duration) *LibraryCache {
+	return &LibraryCache{source: source,
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		lru:     list.New()}
+}
+
+// Get returns the version of the library of the account, fetching it unless
+// it is cached.
+func (cache *LibraryCache) Get(accountId string, name string, version int) (*model.LibraryData, *management.Error) {
+	key := fmt.Sprintf("%s/%s@%d", accountId, name, version)
+	if library := cache.get(key); library != nil {
+		LibraryCacheHit.Incr()
+		return library, nil
+	}
+
+	LibraryCacheMiss.Incr()
+	library, mErr := cache.source.GetLibrary(accountId, name, version)
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	var expires time.Time
+	if version == model.LATEST {
+		expires = time.Now().Add(cache.ttl)
+	}
+	cache.put(&libraryEntry{key: key, library: library, expires: expires})
+	return library, nil
+}
+
+func (cache *LibraryCache) get(key string) *model.LibraryData {
+	cache.m.Lock()
+	defer cache.m.Unlock()
+
+	element, ok := cache.entries[key]
+	if !ok {
+		return nil
+	}
+
+	entry := element.Value.(*libraryEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		cache.lru.Remove(element)
+		delete(cache.entries, key)
+		return nil
+	}
+
+	cache.lru.MoveToFront(element)
+	return entry.library
+}
+
+func (cache *LibraryCache) put(entry *libraryEntry) {
+	if cache.size <= 0 {
+		return
+	}
+
+	cache.m.Lock()
+	defer cache.m.Unlock()
+
+	if element, ok := cache.entries[entry.key]; ok {
+		cache.lru.Remove(element)
+	}
+
+	cache.entries[entry.key] = cache.lru.PushFront(entry)
+	if cache.lru.Len() > cache.size {
+		oldest := cache.lru.Back()
+		cache.lru.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*libraryEntry).key)
+	}
+}
+
+// ParseLibrary returns the name and version of the library required as the
+// module, model.LATEST when no version is given.
+func ParseLibrary(module string) (string, int, error) {
+	name := strings.TrimPrefix(module, LIBRARY_PREFIX)
+	version := model.LATEST
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		v, err := strconv.Atoi(name[i+1:])
+		if err != nil || v < 1 {
+			return "", 0, fmt.Errorf("invalid version of library %s", module)
+		}
+		name, version = name[:i], v
+	}
+
+	if err := model.ValidateName(name); err != nil {
+		return "", 0, err
+	}
+
+	return name, version, nil
+}
+
+// installLibraryLoader adds the library loader to package.loaders, after the
+// preload loader so the northstar modules cannot be shadowed.
+func (s *State) installLibraryLoader() {
+	loaders, ok := s.LuaState.GetField(s.LuaState.Get(lua.RegistryIndex), "_LOADERS").(*lua.LTable)
+	if !ok {
+		return
+	}
+
+	loader := s.LuaState.NewFunction(s.loadLibrary)
+	for i := loaders.Len(); i >= 2; i-- {
+		loaders.RawSetInt(i+1, loaders.RawGetInt(i))
+	}
+	loaders.RawSetInt(2, loader)
+}
+
+// loadLibrary is the package loader returning the chunk of the library
+// required by the snippet, or a message telling why it is not one.
+func (s *State) loadLibrary(L *lua.LState) int {
+	module := L.CheckString(1)
+	if !strings.HasPrefix(module, LIBRARY_PREFIX) {
+		L.Push(lua.LString(fmt.Sprintf("no library '%s'", module)))
+		return 1
+	}
+
+	if s.Libraries == nil {
+		L.Push(lua.LString(fmt.Sprintf("no library '%s': libraries are not enabled", module)))
+		return 1
+	}
+
+	name, version, err := ParseLibrary(module)
+	if err != nil {
+		L.RaiseError(err.Error())
+	}
+
+	library, mErr := s.Libraries.Get(s.accountId, name, version)
+	if mErr != nil {
+		if mErr.HttpStatus == http.StatusNotFound {
+			L.Push(lua.LString(fmt.Sprintf("no library '%s'", module)))
+			return 1
+		}
+
+		mlog.Error("Failed to get library %s: %v", module, mErr)
+		ErrLoadLibrary.Incr()
+		L.RaiseError("failed to get library %s: %v", module, mErr)
+	}
+
+	fn, err := codeCache.LoadChunk(L, library.Code, fmt.Sprintf("%s@%d", name, library.Version))
+	if err != nil {
+		ErrLoadLibrary.Incr()
+		L.RaiseError("failed to load library %s: %v", module, err)
+	}
+
+	L.Push(fn)
+	return 1
+}
+
+// dataLibraries fetches the libraries from the data service, creating the
+// client on first use.
+type dataLibraries struct {
+	once   sync.Once
+	client *client.LibrariesClient
+	err    error
+}
+
+func (d *dataLibraries) GetLibrary(accountId string, name string, version int) (*model.LibraryData, *management.Error) {
+	d.once.Do(func() {
+		d.client, d.err = client.NewLibrariesClient()
+	})
+	if d.err != nil {
+		return nil, management.GetInternalError(d.err.Error())
+	}
+
+	return d.client.GetLibrary(accountId, name, version)
+}
+
+var libraryCache = NewLibraryCache(&dataLibraries{},
+	config.LibraryCacheSize,
+	time.Duration(config.LibraryCacheTTL)*time.Second)
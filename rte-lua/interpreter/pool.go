@@ -0,0 +1,168 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpreter
+
+import (
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/rte/config"
+	"github.com/lavaorg/northstar/rte/rtepub"
+	"sync"
+)
+
+// StatePool keeps warm states between invocations to save creating the
+// state and opening the base modules. States are reset before they are
+// pooled and only reused for the account they last ran for.
+type StatePool struct {
+	m     sync.Mutex
+	size  int
+	saved []*State
+}
+
+func NewStatePool(size int) *StatePool {
+	return &StatePool{size: size, saved: make([]*State, 0, size)}
+}
+
+// Get returns a state bound to the invocation, warm if one is available.
+func (pool *StatePool) Get(input *rtepub.Input) (*State, error) {
+	state := pool.take(input.AccountId)
+	if state == nil {
+		mlog.Debug("Creating new state")
+		PoolMiss.Incr()
+		state = newState()
+	} else {
+		mlog.Debug("Returning existing state")
+		PoolHit.Incr()
+	}
+
+	if err := state.bind(input); err != nil {
+		state.Close()
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// Put resets the state and keeps it, closing the oldest pooled state when
+// the pool is full. Closed states, e.g. of terminated snippets, are dropped.
+func (pool *StatePool) Put(state *State) {
+	if pool.size <= 0 || state.closed {
+		state.Close()
+		return
+	}
+
+	state.Reset()
+
+	pool.m.Lock()
+	defer pool.m.Unlock()
+
+	if len(pool.saved) >= pool.size {
+		pool.saved[0].Close()
+		pool.saved = pool.saved[1:]
+	}
+	pool.saved = append(pool.saved, state)
+}
+
+func (pool *StatePool) Shutdown() {
+	pool.m.Lock()
+	defer pool.m.Unlock()
+
+	for _, state := range pool.saved {
+		state.Close()
+	}
+	pool.saved = nil
+}
+
+func (pool *StatePool) take(accountId string) *State {
+	pool.m.Lock()
+	defer pool.m.Unlock()
+
+	for i := len(pool.saved) - 1; i >= 0; i-- {
+		if pool.saved[i].accountId == accountId {
+			state := pool.saved[i]
+			pool.saved = append(pool.saved[:i], pool.saved[i+1:]...)
+			return state
+		}
+	}
+
+	return nil
+}
+
+// snapshot records the entries and metatable of a table. Restoring it
+// removes the entries added since and sets back the recorded ones.
+type snapshot struct {
+	table     *lua.LTable
+	metatable lua.LValue
+	entries   map[lua.LValue]lua.LValue
+}
+
+// takeSnapshots records the tables a snippet can change: the globals, the
+// libraries, the loaded and preloaded modules, the package loaders and the
+// string metatable.
+func takeSnapshots(L *lua.LState) []*snapshot {
+	values := []lua.LValue{L.G.Global,
+		L.GetField(L.Get(lua.RegistryIndex), "_LOADED"),
+		L.GetField(L.GetGlobal("package"), "preload"),
+		L.GetField(L.Get(lua.RegistryIndex), "_LOADERS"),
+		L.GetMetatable(lua.LString(""))}
+	L.G.Global.ForEach(func(key lua.LValue, value lua.LValue) {
+		values = append(values, value)
+	})
+
+	seen := make(map[*lua.LTable]bool)
+	var snapshots []*snapshot
+	for _, value := range values {
+		table, ok := value.(*lua.LTable)
+		if !ok || seen[table] {
+			continue
+		}
+		seen[table] = true
+
+		snapshot := &snapshot{table: table,
+			metatable: table.Metatable,
+			entries:   make(map[lua.LValue]lua.LValue)}
+		table.ForEach(func(key lua.LValue, value lua.LValue) {
+			snapshot.entries[key] = value
+		})
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots
+}
+
+func (s *snapshot) restore() {
+	var added []lua.LValue
+	s.table.ForEach(func(key lua.LValue, value lua.LValue) {
+		if _, ok := s.entries[key]; !ok {
+			added = append(added, key)
+		}
+	})
+
+	for _, key := range added {
+		s.table.RawSet(key, lua.LNil)
+	}
+
+	for key, value := range s.entries {
+		if s.table.RawGet(key) != value {
+			s.table.RawSet(key, value)
+		}
+	}
+
+	s.table.Metatable = s.metatable
+}
+
+var statePool = NewStatePool(config.StatePoolSize)
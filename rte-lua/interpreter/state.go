@@ -28,13 +28,16 @@ import (
 	"github.com/lavaorg/lrtx/mlog"
 	"github.com/lavaorg/lua"
 	"github.com/lavaorg/northstar/rte-lua/modules/nsFTP"
+	"github.com/lavaorg/northstar/rte-lua/modules/nsLog"
 	"github.com/lavaorg/northstar/rte-lua/modules/nsObject"
 	"github.com/lavaorg/northstar/rte-lua/modules/nsOutput"
 	"github.com/lavaorg/northstar/rte-lua/modules/nsQL"
 	"github.com/lavaorg/northstar/rte-lua/modules/nsSFTP"
 	"github.com/lavaorg/northstar/rte-lua/modules/nsStream"
 	"github.com/lavaorg/northstar/rte-lua/modules/nsUtil"
+	"github.com/lavaorg/northstar/rte-lua/modules/remotefs"
 	pkgCfg "github.com/lavaorg/northstar/rte/config"
+	"github.com/lavaorg/northstar/rte/rlimit"
 	"github.com/lavaorg/northstar/rte/rtepub"
 	"time"
 )
@@ -49,6 +52,8 @@ var (
 	EnableNSKV, _     = config.GetBool("ENABLE_NSKV", false)
 	EnableNSStream, _ = config.GetBool("ENABLE_NSSTREAM", false)
 	EnableNSUtil, _   = config.GetBool("ENABLE_NSUTIL", true)
+	EnableNSLog, _    = config.GetBool("ENABLE_NSLOG", false)
+	EnableLibs, _     = config.GetBool("ENABLE_LIBRARIES", false)
 )
 
 type ExecutionContext struct {
@@ -56,18 +61,35 @@ type ExecutionContext struct {
 }
 
 type State struct {
-	cancel   context.CancelFunc
-	LuaState *lua.LState
-	Output   *nsOutput.NsOutputModule
-	NSQL     *nsQL.NsQLModule
+	ctx       context.Context
+	cancel    context.CancelFunc
+	policy    *rtepub.Policy
+	accountId string
+	closed    bool
+	snapshots []*snapshot
+	LuaState  *lua.LState
+	Output    *nsOutput.NsOutputModule
+	NSQL      *nsQL.NsQLModule
+	Log       *nsLog.NsLogModule
+	Object    *nsObject.NsObjectModule
+	Libraries *LibraryCache
 }
 
+// CreateState creates a state bound to the invocation.
 func CreateState(input *rtepub.Input) (*State, error) {
-	luaState := lua.NewState(lua.Options{SkipOpenLibs: true, IncludeGoStackTrace: false})
+	state := newState()
+	if err := state.bind(input); err != nil {
+		state.Close()
+		return nil, err
+	}
 
-	ctx, cancel := createContext(input.Timeout)
-	luaState.SetContext(ctx)
-	luaState.SetGlobal("context", luar.New(luaState, ExecutionContext{Args: input.Args}))
+	return state, nil
+}
+
+// newState creates a state with the base modules opened. The state is
+// recorded so it can be reset between invocations.
+func newState() *State {
+	luaState := lua.NewState(lua.Options{SkipOpenLibs: true, IncludeGoStackTrace: false})
 
 	// Base modules
 	luaextended.OpenLibs(luaState)
@@ -79,69 +101,138 @@ func CreateState(input *rtepub.Input) (*State, error) {
 	lua.OpenPackage(luaState)
 	luaState.PreloadModule("re", gluare.Loader)
 
-	output := &State{LuaState: luaState,
-		cancel: cancel}
+	state := &State{LuaState: luaState}
+	state.installLibraryLoader()
+	state.snapshots = takeSnapshots(luaState)
+	return state
+}
 
-	if EnableHttp {
-		mlog.Debug("Loading HTTP module")
-		luaState.PreloadModule("http", gluahttp.NewHttpModule(management.NewHttpClient()).Loader)
-	}
+// bind prepares the state for the invocation: its deadline, arguments and
+// the northstar modules permitted to the snippet.
+func (s *State) bind(input *rtepub.Input) error {
+	luaState := s.LuaState
 
-	if EnableNSQL {
-		mlog.Debug("Loading nsQL module")
-		output.NSQL = nsQL.NewNSQLModule()
-		luaState.PreloadModule("nsQL", output.NSQL.Loader)
+	s.ctx, s.cancel = createContext(input.Timeout)
+	s.policy = input.Policy
+	s.accountId = input.AccountId
+	luaState.SetContext(s.ctx)
+	luaState.SetGlobal("context", luar.New(luaState, ExecutionContext{Args: input.Args}))
+
+	if EnableLibs {
+		s.Libraries = libraryCache
 	}
 
-	if EnableNSOutput {
-		mlog.Debug("Loading nsOutput module")
-		output.Output = nsOutput.NewNsOutputModule()
-		luaState.PreloadModule("nsOutput", output.Output.Loader)
+	if EnableHttp && s.permitted("http") {
+		mlog.Debug("Loading HTTP module")
+		httpClient := management.NewHttpClient()
+		if input.Policy != nil {
+			httpClient.Transport = input.Policy.Transport(httpClient.Transport)
+		}
+		luaState.PreloadModule("http", gluahttp.NewHttpModule(httpClient).Loader)
 	}
 
-	if EnableNSFTP {
-		mlog.Debug("Loading FTP module")
-		luaState.PreloadModule("nsFTP", nsFTP.NewNsFTPModule().Loader)
+	if EnableNSQL && s.permitted("nsQL") {
+		mlog.Debug("Loading nsQL module")
+		s.NSQL = nsQL.NewNSQLModule()
+		luaState.PreloadModule("nsQL", s.NSQL.Loader)
 	}
 
-	if EnableNSSFTP {
-		mlog.Debug("Loading SFTP module")
-		luaState.PreloadModule("nsSFTP", nsSFTP.NewNsSFTPModule().Loader)
+	if EnableNSOutput && s.permitted("nsOutput") {
+		mlog.Debug("Loading nsOutput module")
+		s.Output = nsOutput.NewNsOutputModule()
+		luaState.PreloadModule("nsOutput", s.Output.Loader)
 	}
 
-	if EnableNSObject {
+	// The files retrieved and synced by nsFTP and nsSFTP, and the tables
+	// exported by nsOutput, are stored into buckets with nsObject, when it
+	// is available to the snippet.
+	var objects remotefs.ObjectStore
+	var synced remotefs.SyncState
+	if EnableNSObject && s.permitted("nsObject") {
 		mlog.Debug("Loading nsObject module")
 		nsObjectModule, err := nsObject.NewNsObjectModule(input.AccountId)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		s.Object, objects = nsObjectModule, nsObjectModule
+		synced = remotefs.NewDataSyncState(input.AccountId)
+		luaState.PreloadModule("nsObject", s.Object.Loader)
+		if s.Output != nil {
+			s.Output.Objects = nsObjectModule
 		}
-		luaState.PreloadModule("nsObject", nsObjectModule.Loader)
 	}
 
-	if EnableNSKV {
+	if EnableNSFTP && s.permitted("nsFTP") {
+		mlog.Debug("Loading FTP module")
+		luaState.PreloadModule("nsFTP", nsFTP.NewNsFTPModule(input.Policy, objects, synced).Loader)
+	}
+
+	if EnableNSSFTP && s.permitted("nsSFTP") {
+		mlog.Debug("Loading SFTP module")
+		luaState.PreloadModule("nsSFTP", nsSFTP.NewNsSFTPModule(input.Policy, objects, synced).Loader)
+	}
+
+	if EnableNSKV && s.permitted("nsKV") {
 		mlog.Debug("Loading nsKV module")
 		redisCluster, err := pkgCfg.CreateRedisCluster()
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		options := &lualib.Options{KeyPrefix: input.AccountId}
 		luaState.PreloadModule("nsKV", lualib.NewRedisModuleWithOptions(redisCluster, options).Loader)
 	}
 
-	if EnableNSStream {
+	if EnableNSStream && s.permitted("nsStream") {
 		mlog.Debug("Loading nsStream module")
 		luaState.PreloadModule("nsStream", nsStream.NewNsStreamModule(input.AccountId,
 			input.InvocationId,
 			input.Memory).Loader)
 	}
 
-	if EnableNSUtil {
+	if EnableNSUtil && s.permitted("nsUtil") {
 		mlog.Debug("Loading nsUtil module")
 		luaState.PreloadModule("nsUtil", nsUtil.NewNsUtilModule().Loader)
 	}
 
-	return output, nil
+	if EnableNSLog && s.permitted("nsLog") {
+		mlog.Debug("Loading nsLog module")
+		nsLogModule, err := nsLog.NewNsLogModule(input.AccountId, input.InvocationId)
+		if err != nil {
+			return err
+		}
+		s.Log = nsLogModule
+		luaState.PreloadModule("nsLog", s.Log.Loader)
+	}
+
+	return nil
+}
+
+// permitted reports whether the policy of the snippet permits the module.
+// A module which is not permitted is preloaded with a loader raising the
+// violation, so require reports it instead of a missing module.
+func (s *State) permitted(name string) bool {
+	err := s.policy.CheckModule(name)
+	if err == nil {
+		return true
+	}
+
+	mlog.Debug("Not loading %s module: %v", name, err)
+	s.LuaState.PreloadModule(name, func(L *lua.LState) int {
+		L.RaiseError(err.Error())
+		return 0
+	})
+	return false
+}
+
+// SetLimits enforces the memory and instruction limits of the invocation on
+// the state. Exceeding them raises an error in the running snippet.
+func (s *State) SetLimits(limits *rlimit.Resources) {
+	if limits.Memory == 0 && limits.Instructions == 0 {
+		return
+	}
+
+	s.LuaState.SetContext(newBudgetContext(s.ctx, s.LuaState, limits))
 }
 
 func createContext(timeout int) (context.Context, context.CancelFunc) {
@@ -153,6 +244,7 @@ func createContext(timeout int) (context.Context, context.CancelFunc) {
 }
 
 func (s *State) Close() {
+	s.closed = true
 	s.Clean()
 	if s.cancel != nil {
 		mlog.Debug("Canceling context")
@@ -164,6 +256,24 @@ func (s *State) Close() {
 	}
 }
 
+// Reset returns the state to the one created by newState, so it can be
+// bound to another invocation: the globals, libraries and modules changed
+// by the snippet are restored.
+func (s *State) Reset() {
+	s.Clean()
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	for _, snapshot := range s.snapshots {
+		snapshot.restore()
+	}
+
+	s.LuaState.SetTop(0)
+	s.ctx, s.cancel, s.policy = nil, nil, nil
+	s.Output, s.NSQL, s.Log, s.Object, s.Libraries = nil, nil, nil, nil, nil
+}
+
 func (s *State) Clean() {
 	if s.Output != nil {
 		s.Output.Reset()
@@ -172,4 +282,12 @@ func (s *State) Clean() {
 	if s.NSQL != nil {
 		s.NSQL.Reset()
 	}
+
+	if s.Log != nil {
+		s.Log.Flush()
+	}
+
+	if s.Object != nil {
+		s.Object.Close()
+	}
 }
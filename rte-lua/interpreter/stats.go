@@ -24,4 +24,12 @@ var (
 	Lua       = stats.New("lua")
 	DoREPL    = Lua.NewCounter("DoREPL")
 	ErrDoREPL = Lua.NewCounter("ErrDoREPL")
+	PoolHit   = Lua.NewCounter("PoolHit")
+	PoolMiss  = Lua.NewCounter("PoolMiss")
+	CacheHit  = Lua.NewCounter("CacheHit")
+	CacheMiss = Lua.NewCounter("CacheMiss")
+
+	LibraryCacheHit  = Lua.NewCounter("LibraryCacheHit")
+	LibraryCacheMiss = Lua.NewCounter("LibraryCacheMiss")
+	ErrLoadLibrary   = Lua.NewCounter("ErrLoadLibrary")
 )
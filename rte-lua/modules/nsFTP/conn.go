@@ -0,0 +1,83 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsFTP
+
+import (
+	"bytes"
+	"path"
+
+	"github.com/jlaffaye/ftp"
+	"github.com/lavaorg/northstar/rte-lua/modules/remotefs"
+)
+
+// Conn is the connection held by the connection handle, implementing the
+// remote file system shared with nsSFTP.
+type Conn struct {
+	Server *ftp.ServerConn
+}
+
+func (c *Conn) List(dir string) ([]*remotefs.FileInfo, error) {
+	entries, err := c.Server.List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*remotefs.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Name == "." || entry.Name == ".." {
+			continue
+		}
+
+		files = append(files, &remotefs.FileInfo{Name: entry.Name,
+			Path:    path.Join(dir, entry.Name),
+			Size:    int64(entry.Size),
+			ModTime: entry.Time,
+			IsDir:   entry.Type == ftp.EntryTypeFolder})
+	}
+
+	return files, nil
+}
+
+func (c *Conn) Stat(p string) (*remotefs.FileInfo, error) {
+	return remotefs.StatFromList(c, p)
+}
+
+func (c *Conn) Retrieve(p string) ([]byte, error) {
+	resp, err := c.Server.Retr(p)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+
+	return remotefs.ReadAll(resp)
+}
+
+func (c *Conn) Store(p string, data []byte) error {
+	return c.Server.Stor(p, bytes.NewReader(data))
+}
+
+func (c *Conn) Mkdir(p string) error {
+	return c.Server.MakeDir(p)
+}
+
+func (c *Conn) Rename(from string, to string) error {
+	return c.Server.Rename(from, to)
+}
+
+func (c *Conn) Delete(p string) error {
+	return c.Server.Delete(p)
+}
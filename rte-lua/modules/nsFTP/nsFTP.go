@@ -21,6 +21,8 @@ import (
 	"github.com/jlaffaye/ftp"
 	"github.com/lavaorg/lrtx/config"
 	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/rte-lua/modules/remotefs"
+	"github.com/lavaorg/northstar/rte/rtepub"
 )
 
 const (
@@ -32,11 +34,22 @@ var (
 )
 
 type NsFTPModule struct {
-	Limit int
+	Limit   int
+	Policy  *rtepub.Policy
+	Objects remotefs.ObjectStore
+	Synced  remotefs.SyncState
 }
 
-func NewNsFTPModule() *NsFTPModule {
-	return &NsFTPModule{Limit: NsFTPConnectionLimit}
+// NewNsFTPModule creates the module. Files are retrieved and synced into the
+// objects, the synced files recorded in the sync state. Both are nil when
+// nsObject is not available.
+func NewNsFTPModule(policy *rtepub.Policy,
+	objects remotefs.ObjectStore,
+	synced remotefs.SyncState) *NsFTPModule {
+	return &NsFTPModule{Limit: NsFTPConnectionLimit,
+		Policy:  policy,
+		Objects: objects,
+		Synced:  synced}
 }
 
 func (nsFTP *NsFTPModule) Loader(L *lua.LState) int {
@@ -55,6 +68,12 @@ func (nsFTP *NsFTPModule) connect(L *lua.LState) int {
 	}
 
 	hostport := L.CheckString(1)
+	if err := nsFTP.Policy.CheckHost(hostport); err != nil {
+		ErrConnect.Incr()
+		L.RaiseError(nsFTP.makeErrorMessage(err.Error()))
+		return 0
+	}
+
 	conn, err := ftp.Connect(hostport)
 	if err != nil {
 		return nsFTP.error(L, err.Error(), nil, "connect")
@@ -70,11 +89,18 @@ func (nsFTP *NsFTPModule) connect(L *lua.LState) int {
 		"mkdir":      nsFTP.mkdir,
 		"store":      nsFTP.store,
 	}
+	shared := &remotefs.Methods{Objects: nsFTP.Objects,
+		Synced: nsFTP.Synced,
+		Error: func(L *lua.LState, err string, method string) int {
+			return nsFTP.error(L, err, nil, method)
+		},
+		Done: done}
+	shared.Register(methods)
 
 	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), methods))
 
 	connection := L.NewUserData()
-	connection.Value = conn
+	connection.Value = &Conn{Server: conn}
 	L.SetMetatable(connection, L.GetTypeMetatable(FTP_CONN_TYPE))
 
 	Connect.Incr()
@@ -84,8 +110,8 @@ func (nsFTP *NsFTPModule) connect(L *lua.LState) int {
 
 func (nsFTP *NsFTPModule) disconnect(L *lua.LState) int {
 	conn := L.CheckUserData(1)
-	if connection, ok := conn.Value.(*ftp.ServerConn); ok {
-		if err := connection.Quit(); err != nil {
+	if connection, ok := conn.Value.(*Conn); ok {
+		if err := connection.Server.Quit(); err != nil {
 			return nsFTP.error(L, err.Error(), nil, "disconnect")
 		}
 		nsFTP.Limit++
@@ -97,10 +123,10 @@ func (nsFTP *NsFTPModule) disconnect(L *lua.LState) int {
 
 func (nsFTP *NsFTPModule) login(L *lua.LState) int {
 	conn := L.CheckUserData(1)
-	if connection, ok := conn.Value.(*ftp.ServerConn); ok {
+	if connection, ok := conn.Value.(*Conn); ok {
 		user := L.CheckString(2)
 		password := L.CheckString(3)
-		if err := connection.Login(user, password); err != nil {
+		if err := connection.Server.Login(user, password); err != nil {
 			return nsFTP.error(L, "unable to login", nil, "login")
 		}
 		Login.Incr()
@@ -111,8 +137,8 @@ func (nsFTP *NsFTPModule) login(L *lua.LState) int {
 
 func (nsFTP *NsFTPModule) logout(L *lua.LState) int {
 	conn := L.CheckUserData(1)
-	if connection, ok := conn.Value.(*ftp.ServerConn); ok {
-		if err := connection.Logout(); err != nil {
+	if connection, ok := conn.Value.(*Conn); ok {
+		if err := connection.Server.Logout(); err != nil {
 			return nsFTP.error(L, err.Error(), nil, "logout")
 		}
 		Logout.Incr()
@@ -123,9 +149,9 @@ func (nsFTP *NsFTPModule) logout(L *lua.LState) int {
 
 func (nsFTP *NsFTPModule) mkdir(L *lua.LState) int {
 	conn := L.CheckUserData(1)
-	if connection, ok := conn.Value.(*ftp.ServerConn); ok {
+	if connection, ok := conn.Value.(*Conn); ok {
 		path := L.CheckString(2)
-		if err := connection.MakeDir(path); err != nil {
+		if err := connection.Server.MakeDir(path); err != nil {
 			return nsFTP.error(L, err.Error(), nil, "mkdir")
 		}
 		Mkdir.Incr()
@@ -137,10 +163,10 @@ func (nsFTP *NsFTPModule) mkdir(L *lua.LState) int {
 func (nsFTP *NsFTPModule) store(L *lua.LState) int {
 	timer := NsFTP.NewTimer("StoreTimer")
 	conn := L.CheckUserData(1)
-	if connection, ok := conn.Value.(*ftp.ServerConn); ok {
+	if connection, ok := conn.Value.(*Conn); ok {
 		filename := L.CheckString(2)
 		data := L.CheckString(3)
-		if err := connection.Stor(filename, bytes.NewBufferString(data)); err != nil {
+		if err := connection.Server.Stor(filename, bytes.NewBufferString(data)); err != nil {
 			return nsFTP.error(L, err.Error(), timer, "store")
 		}
 		Store.Incr()
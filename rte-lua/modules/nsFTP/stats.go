@@ -26,10 +26,24 @@ var (
 	Logout        = NsFTP.NewCounter("Logout")
 	Mkdir         = NsFTP.NewCounter("Mkdir")
 	Store         = NsFTP.NewCounter("Store")
+	List          = NsFTP.NewCounter("List")
+	Stat          = NsFTP.NewCounter("Stat")
+	Retrieve      = NsFTP.NewCounter("Retrieve")
+	Rename        = NsFTP.NewCounter("Rename")
+	Delete        = NsFTP.NewCounter("Delete")
+	Glob          = NsFTP.NewCounter("Glob")
+	Sync          = NsFTP.NewCounter("Sync")
 	ErrConnect    = NsFTP.NewCounter("ErrConnect")
 	ErrDisconnect = NsFTP.NewCounter("ErrDisconnect")
 	ErrLogin      = NsFTP.NewCounter("ErrLogin")
 	ErrLogout     = NsFTP.NewCounter("ErrLogout")
 	ErrMkdir      = NsFTP.NewCounter("ErrMkdir")
 	ErrStore      = NsFTP.NewCounter("ErrStore")
+	ErrList       = NsFTP.NewCounter("ErrList")
+	ErrStat       = NsFTP.NewCounter("ErrStat")
+	ErrRetrieve   = NsFTP.NewCounter("ErrRetrieve")
+	ErrRename     = NsFTP.NewCounter("ErrRename")
+	ErrDelete     = NsFTP.NewCounter("ErrDelete")
+	ErrGlob       = NsFTP.NewCounter("ErrGlob")
+	ErrSync       = NsFTP.NewCounter("ErrSync")
 )
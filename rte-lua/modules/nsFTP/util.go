@@ -19,6 +19,7 @@ package nsFTP
 import (
 	"github.com/lavaorg/lrtx/stats"
 	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/rte-lua/modules/remotefs"
 )
 
 const NS_FTP_ERROR = "nsFTP error: "
@@ -48,7 +49,41 @@ func (nsFtp *NsFTPModule) error(L *lua.LState, err string, timer *stats.Timer, c
 		ErrMkdir.Incr()
 	case "store":
 		ErrStore.Incr()
+	case remotefs.LIST:
+		ErrList.Incr()
+	case remotefs.STAT:
+		ErrStat.Incr()
+	case remotefs.RETRIEVE:
+		ErrRetrieve.Incr()
+	case remotefs.RENAME:
+		ErrRename.Incr()
+	case remotefs.DELETE:
+		ErrDelete.Incr()
+	case remotefs.GLOB:
+		ErrGlob.Incr()
+	case remotefs.SYNC:
+		ErrSync.Incr()
 	}
 
 	return 2
 }
+
+// done counts the success of the shared connection methods.
+func done(method string) {
+	switch method {
+	case remotefs.LIST:
+		List.Incr()
+	case remotefs.STAT:
+		Stat.Incr()
+	case remotefs.RETRIEVE:
+		Retrieve.Incr()
+	case remotefs.RENAME:
+		Rename.Incr()
+	case remotefs.DELETE:
+		Delete.Incr()
+	case remotefs.GLOB:
+		Glob.Incr()
+	case remotefs.SYNC:
+		Sync.Incr()
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsLog
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lavaorg/lrtx/config"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/data/invocations/client"
+	"github.com/lavaorg/northstar/data/invocations/model"
+)
+
+var (
+	NsLogBatchSize, _     = config.GetInt("NS_LOG_BATCH_SIZE", 100)
+	NsLogFlushInterval, _ = config.GetInt("NS_LOG_FLUSH_INTERVAL", 1000)
+	NsLogLimit, _         = config.GetInt("NS_LOG_LIMIT", 10000)
+)
+
+// NsLogModule writes structured entries to the log of the invocation. The
+// entries are buffered and appended in batches, when the batch is full or the
+// flush interval elapsed, and when the state is cleaned. Entries beyond the
+// limit are dropped and reported by a final warning.
+type NsLogModule struct {
+	Client       client.Client
+	AccountId    string
+	InvocationId string
+	Written      int
+	Dropped      int
+	buffer       []*model.LogEntry
+	flushed      time.Time
+}
+
+func NewNsLogModule(accountId string, invocationId string) (*NsLogModule, error) {
+	cli, err := client.NewInvocationClient()
+	if err != nil {
+		return nil, err
+	}
+	return &NsLogModule{Client: cli,
+		AccountId:    accountId,
+		InvocationId: invocationId,
+		flushed:      time.Now()}, nil
+}
+
+func (nsLog *NsLogModule) Loader(L *lua.LState) int {
+	api := map[string]lua.LGFunction{
+		model.LOG_DEBUG: nsLog.levelApi(model.LOG_DEBUG),
+		model.LOG_INFO:  nsLog.levelApi(model.LOG_INFO),
+		model.LOG_WARN:  nsLog.levelApi(model.LOG_WARN),
+		model.LOG_ERROR: nsLog.levelApi(model.LOG_ERROR),
+	}
+	t := L.NewTable()
+	L.SetFuncs(t, api)
+	L.Push(t)
+	return 1
+}
+
+// levelApi returns the function logging the message and the optional table
+// of fields at the level.
+func (nsLog *NsLogModule) levelApi(level string) lua.LGFunction {
+	return func(L *lua.LState) int {
+		entry := &model.LogEntry{Time: time.Now(),
+			Level:   level,
+			Message: L.CheckString(1),
+			Fields:  Fields(L.OptTable(2, nil))}
+		nsLog.Append(entry)
+		return 0
+	}
+}
+
+// Fields converts the table of fields to strings.
+func Fields(tbl *lua.LTable) map[string]string {
+	if tbl == nil {
+		return nil
+	}
+
+	fields := make(map[string]string)
+	tbl.ForEach(func(key lua.LValue, value lua.LValue) {
+		fields[key.String()] = value.String()
+	})
+	return fields
+}
+
+// Append buffers the entry, flushing the buffer when it is due.
+func (nsLog *NsLogModule) Append(entry *model.LogEntry) {
+	if nsLog.Written+len(nsLog.buffer) >= NsLogLimit {
+		nsLog.Dropped++
+		Dropped.Incr()
+		return
+	}
+
+	nsLog.buffer = append(nsLog.buffer, entry)
+	if len(nsLog.buffer) >= NsLogBatchSize ||
+		time.Since(nsLog.flushed) >= time.Duration(NsLogFlushInterval)*time.Millisecond {
+		nsLog.Flush()
+	}
+}
+
+// Flush appends the buffered entries to the log of the invocation. A failure
+// is logged and the entries discarded, so logging never fails the snippet.
+func (nsLog *NsLogModule) Flush() {
+	if nsLog.Dropped > 0 {
+		nsLog.buffer = append(nsLog.buffer, &model.LogEntry{Time: time.Now(),
+			Level:   model.LOG_WARN,
+			Message: fmt.Sprintf("%d log entries dropped, limit of %d reached", nsLog.Dropped, NsLogLimit)})
+		nsLog.Dropped = 0
+	}
+
+	nsLog.flushed = time.Now()
+	if len(nsLog.buffer) == 0 {
+		return
+	}
+
+	if mErr := nsLog.Client.AppendLogs(nsLog.AccountId, nsLog.InvocationId, nsLog.buffer); mErr != nil {
+		mlog.Error("Failed to append logs of invocation %s: %v", nsLog.InvocationId, mErr)
+		ErrFlush.Incr()
+	} else {
+		Flush.Incr()
+	}
+
+	nsLog.Written += len(nsLog.buffer)
+	nsLog.buffer = nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsLog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/data/invocations/client"
+	"github.com/lavaorg/northstar/data/invocations/model"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient records the batches of entries appended to the log.
+type fakeClient struct {
+	client.Client
+	batches [][]*model.LogEntry
+	err     *management.Error
+}
+
+func (c *fakeClient) AppendLogs(accountId string, invocationId string, entries []*model.LogEntry) *management.Error {
+	c.batches = append(c.batches, entries)
+	return c.err
+}
+
+// newModule returns a module writing to the fake client with the batch
+// size, flush interval in milliseconds and limit, restored by the returned
+// function.
+func newModule(batchSize int, flushInterval int, limit int) (*NsLogModule, *fakeClient, func()) {
+	oldBatchSize, oldFlushInterval, oldLimit := NsLogBatchSize, NsLogFlushInterval, NsLogLimit
+	NsLogBatchSize, NsLogFlushInterval, NsLogLimit = batchSize, flushInterval, limit
+
+	fake := &fakeClient{}
+	nsLog := &NsLogModule{Client: fake, AccountId: "account", InvocationId: "invocation", flushed: time.Now()}
+	return nsLog, fake, func() {
+		NsLogBatchSize, NsLogFlushInterval, NsLogLimit = oldBatchSize, oldFlushInterval, oldLimit
+	}
+}
+
+func entry(message string) *model.LogEntry {
+	return &model.LogEntry{Time: time.Now(), Level: model.LOG_INFO, Message: message}
+}
+
+func TestAppendBatches(t *testing.T) {
+	nsLog, fake, restore := newModule(2, 60000, 100)
+	defer restore()
+
+	for _, message := range []string{"1", "2", "3", "4", "5"} {
+		nsLog.Append(entry(message))
+	}
+	require.Len(t, fake.batches, 2)
+	require.Len(t, fake.batches[0], 2)
+	require.Equal(t, "3", fake.batches[1][0].Message)
+
+	nsLog.Flush()
+	require.Len(t, fake.batches, 3)
+	require.Equal(t, "5", fake.batches[2][0].Message)
+	require.Equal(t, 5, nsLog.Written)
+
+	// Nothing buffered, nothing appended.
+	nsLog.Flush()
+	require.Len(t, fake.batches, 3)
+}
+
+func TestAppendFlushInterval(t *testing.T) {
+	nsLog, fake, restore := newModule(100, 0, 100)
+	defer restore()
+
+	nsLog.Append(entry("1"))
+	nsLog.Append(entry("2"))
+	require.Len(t, fake.batches, 2)
+}
+
+func TestAppendLimit(t *testing.T) {
+	nsLog, fake, restore := newModule(2, 60000, 3)
+	defer restore()
+
+	for _, message := range []string{"1", "2", "3", "4", "5"} {
+		nsLog.Append(entry(message))
+	}
+	require.Equal(t, 2, nsLog.Dropped)
+
+	nsLog.Flush()
+	require.Len(t, fake.batches, 2)
+	require.Equal(t, []string{"3", "2 log entries dropped, limit of 3 reached"},
+		[]string{fake.batches[1][0].Message, fake.batches[1][1].Message})
+	require.Equal(t, model.LOG_WARN, fake.batches[1][1].Level)
+	require.Equal(t, 0, nsLog.Dropped)
+}
+
+func TestFlushError(t *testing.T) {
+	nsLog, fake, restore := newModule(100, 60000, 100)
+	defer restore()
+
+	fake.err = management.GetInternalError("unavailable")
+	nsLog.Append(entry("1"))
+	nsLog.Flush()
+	require.Len(t, fake.batches, 1)
+
+	// The failed batch is discarded, not appended again.
+	fake.err = nil
+	nsLog.Append(entry("2"))
+	nsLog.Flush()
+	require.Len(t, fake.batches, 2)
+	require.Len(t, fake.batches[1], 1)
+	require.Equal(t, "2", fake.batches[1][0].Message)
+}
+
+func TestLoader(t *testing.T) {
+	nsLog, fake, restore := newModule(100, 60000, 100)
+	defer restore()
+
+	L := lua.NewState()
+	defer L.Close()
+	L.PreloadModule("nsLog", nsLog.Loader)
+	require.Nil(t, L.DoString(`
+		local log = require("nsLog")
+		log.warn("disk", {free = 10})
+		log.debug("done")`))
+	nsLog.Flush()
+
+	require.Len(t, fake.batches, 1)
+	require.Equal(t, model.LOG_WARN, fake.batches[0][0].Level)
+	require.Equal(t, "disk", fake.batches[0][0].Message)
+	require.Equal(t, map[string]string{"free": "10"}, fake.batches[0][0].Fields)
+	require.Equal(t, model.LOG_DEBUG, fake.batches[0][1].Level)
+	require.Nil(t, fake.batches[0][1].Fields)
+}
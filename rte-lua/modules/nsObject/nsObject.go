@@ -22,13 +22,15 @@ import (
 	"github.com/lavaorg/lua"
 	"github.com/lavaorg/northstar/object/client"
 	"github.com/lavaorg/northstar/object/model"
+	"github.com/lavaorg/northstar/object/util"
 	"strconv"
 	"time"
 )
 
 type NsObjectModule struct {
-	Client    *client.ObjectClient
+	Client    client.Client
 	AccountId string
+	streams   *Streams
 }
 
 func NewNsObjectModule(accountid string) (*NsObjectModule, error) {
@@ -36,7 +38,14 @@ func NewNsObjectModule(accountid string) (*NsObjectModule, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &NsObjectModule{Client: cli, AccountId: accountid}, nil
+
+	nsObject := &NsObjectModule{Client: cli, AccountId: accountid}
+	nsObject.streams = &Streams{Store: nsObject,
+		Error: func(L *lua.LState, err string, context string) int {
+			return nsObject.error(L, err, nil, context)
+		},
+		Done: done}
+	return nsObject, nil
 }
 
 func (nsObject *NsObjectModule) Loader(L *lua.LState) int {
@@ -48,7 +57,9 @@ func (nsObject *NsObjectModule) Loader(L *lua.LState) int {
 		"downloadFile": nsObject.downloadFile,
 		"deleteFile":   nsObject.deleteFile,
 		"listFiles":    nsObject.listFiles,
+		"signedUrl":    nsObject.signedUrl,
 	}
+	nsObject.streams.Register(api)
 	t := L.NewTable()
 	L.SetFuncs(t, api)
 	L.Push(t)
@@ -144,6 +155,93 @@ func (nsObject *NsObjectModule) uploadFile(L *lua.LState) int {
 	return 0
 }
 
+// Upload stores the data as the file of the bucket, for the modules storing
+// files into the object store on behalf of the snippet.
+func (nsObject *NsObjectModule) Upload(bucket string, file string, data []byte, contentType string) error {
+	uploadData := &model.UploadData{FileName: file, Payload: data, ContentType: contentType}
+	if _, mErr := nsObject.Client.UploadFile(nsObject.AccountId, bucket, uploadData); mErr != nil {
+		mlog.Error(mErr.Error())
+		ErrUploadFile.Incr()
+		return fmt.Errorf("Failed to upload file %s", file)
+	}
+
+	UploadFile.Incr()
+	return nil
+}
+
+// Close aborts the uploads of the files the snippet left open.
+func (nsObject *NsObjectModule) Close() {
+	nsObject.streams.Close()
+}
+
+func (nsObject *NsObjectModule) Stat(bucket string, file string) (*model.Object, error) {
+	object, mErr := nsObject.Client.StatFile(nsObject.AccountId, bucket, file)
+	if mErr != nil {
+		mlog.Error(mErr.Error())
+		return nil, fmt.Errorf("Failed to stat file %s", file)
+	}
+	return object, nil
+}
+
+func (nsObject *NsObjectModule) ReadRange(bucket string, file string, offset int64, length int64) ([]byte, error) {
+	data, mErr := nsObject.Client.DownloadRange(nsObject.AccountId, bucket, file, offset, length)
+	if mErr != nil {
+		mlog.Error(mErr.Error())
+		return nil, fmt.Errorf("Failed to download file %s", file)
+	}
+	return data.Payload, nil
+}
+
+func (nsObject *NsObjectModule) CreateUpload(bucket string, file string, contentType string) (string, error) {
+	upload := &model.Upload{FileName: file, ContentType: contentType}
+	uploadId, mErr := nsObject.Client.CreateUpload(nsObject.AccountId, bucket, upload)
+	if mErr != nil {
+		mlog.Error(mErr.Error())
+		return "", fmt.Errorf("Failed to upload file %s", file)
+	}
+	return uploadId, nil
+}
+
+func (nsObject *NsObjectModule) UploadPart(bucket string,
+	file string,
+	uploadId string,
+	part int64,
+	data []byte) (*model.Part, error) {
+	partData := &model.PartData{FileName: file, PartNumber: part, Payload: data}
+	uploaded, mErr := nsObject.Client.UploadPart(nsObject.AccountId, bucket, uploadId, partData)
+	if mErr != nil {
+		mlog.Error(mErr.Error())
+		return nil, fmt.Errorf("Failed to upload file %s", file)
+	}
+	return uploaded, nil
+}
+
+func (nsObject *NsObjectModule) CompleteUpload(bucket string, file string, uploadId string, parts []model.Part) error {
+	upload := &model.Upload{UploadId: uploadId, FileName: file, Parts: parts}
+	if mErr := nsObject.Client.CompleteUpload(nsObject.AccountId, bucket, upload); mErr != nil {
+		mlog.Error(mErr.Error())
+		return fmt.Errorf("Failed to upload file %s", file)
+	}
+	return nil
+}
+
+func (nsObject *NsObjectModule) AbortUpload(bucket string, file string, uploadId string) error {
+	if mErr := nsObject.Client.AbortUpload(nsObject.AccountId, bucket, uploadId, file); mErr != nil {
+		mlog.Error(mErr.Error())
+		return fmt.Errorf("Failed to abort upload of file %s", file)
+	}
+	return nil
+}
+
+func (nsObject *NsObjectModule) Copy(bucket string, file string, toBucket string, toFile string, move bool) error {
+	data := &model.CopyData{FileName: file, ToBucket: toBucket, ToFileName: toFile, Move: move}
+	if mErr := nsObject.Client.CopyFile(nsObject.AccountId, bucket, data); mErr != nil {
+		mlog.Error(mErr.Error())
+		return fmt.Errorf("Failed to copy file %s", file)
+	}
+	return nil
+}
+
 func (nsObject *NsObjectModule) downloadFile(L *lua.LState) int {
 	bucketName := L.CheckString(1)
 	fileName := L.CheckString(2)
@@ -209,3 +307,24 @@ func (nsObject *NsObjectModule) listFiles(L *lua.LState) int {
 	ListFiles.Incr()
 	return 1
 }
+
+// signedUrl returns a url to download (GET) or upload (PUT) the file of the
+// bucket without credentials until it expires, after a number of seconds.
+func (nsObject *NsObjectModule) signedUrl(L *lua.LState) int {
+	bucketName := L.CheckString(1)
+	fileName := L.CheckString(2)
+	method := L.OptString(3, "GET")
+	expiresIn := L.OptInt64(4, util.DefaultSignedUrlExpiry)
+
+	signed, mErr := nsObject.Client.SignUrl(nsObject.AccountId, bucketName,
+		&model.SignRequest{FileName: fileName, Method: method, ExpiresIn: expiresIn})
+	if mErr != nil {
+		errM := fmt.Sprintf("Failed to sign url of file %s", fileName)
+		mlog.Error(mErr.Error())
+		return nsObject.error(L, errM, nil, "signedUrl")
+	}
+
+	L.Push(lua.LString(signed.Url))
+	SignedUrl.Incr()
+	return 1
+}
@@ -0,0 +1,76 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsObject
+
+import (
+	"testing"
+
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/object/client"
+	"github.com/lavaorg/northstar/object/model"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient records the requests to sign urls.
+type fakeClient struct {
+	client.Client
+	requests []*model.SignRequest
+	err      *management.Error
+}
+
+func (c *fakeClient) SignUrl(accountId, bucketName string,
+	request *model.SignRequest) (*model.SignedUrl, *management.Error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	c.requests = append(c.requests, request)
+	return &model.SignedUrl{Url: "http://object/" + accountId + "/" + bucketName + "/" + request.FileName,
+		Method: request.Method}, nil
+}
+
+func TestSignedUrl(t *testing.T) {
+	fake := &fakeClient{}
+	nsObject := &NsObjectModule{Client: fake, AccountId: "account"}
+
+	L := lua.NewState()
+	defer L.Close()
+	L.SetGlobal("signedUrl", L.NewFunction(nsObject.signedUrl))
+	require.Nil(t, L.DoString(`
+		get = signedUrl("results", "report.csv")
+		put = signedUrl("results", "upload.csv", "PUT", 60)`))
+
+	require.Equal(t, "http://object/account/results/report.csv", L.GetGlobal("get").String())
+	require.Equal(t, "http://object/account/results/upload.csv", L.GetGlobal("put").String())
+	require.Equal(t, []*model.SignRequest{
+		{FileName: "report.csv", Method: "GET", ExpiresIn: 3600},
+		{FileName: "upload.csv", Method: "PUT", ExpiresIn: 60}}, fake.requests)
+}
+
+func TestSignedUrlError(t *testing.T) {
+	fake := &fakeClient{err: management.GetNotFoundError("missing.csv")}
+	nsObject := &NsObjectModule{Client: fake, AccountId: "account"}
+
+	L := lua.NewState()
+	defer L.Close()
+	L.SetGlobal("signedUrl", L.NewFunction(nsObject.signedUrl))
+	require.Nil(t, L.DoString(`url, err = signedUrl("results", "missing.csv")`))
+
+	require.Equal(t, lua.LNil, L.GetGlobal("url"))
+	require.Equal(t, "nsObject error: Failed to sign url of file missing.csv", L.GetGlobal("err").String())
+}
@@ -34,4 +34,28 @@ var (
 	ErrDownloadFile = NsObject.NewCounter("ErrDownloadFile")
 	ErrDeleteFile   = NsObject.NewCounter("ErrDeleteFile")
 	ErrListFiles    = NsObject.NewCounter("ErrListFiles")
+	Stat            = NsObject.NewCounter("Stat")
+	ReadRange       = NsObject.NewCounter("ReadRange")
+	Open            = NsObject.NewCounter("Open")
+	Read            = NsObject.NewCounter("Read")
+	Seek            = NsObject.NewCounter("Seek")
+	Create          = NsObject.NewCounter("Create")
+	Write           = NsObject.NewCounter("Write")
+	Close           = NsObject.NewCounter("Close")
+	Abort           = NsObject.NewCounter("Abort")
+	CopyFile        = NsObject.NewCounter("CopyFile")
+	MoveFile        = NsObject.NewCounter("MoveFile")
+	ErrStat         = NsObject.NewCounter("ErrStat")
+	ErrReadRange    = NsObject.NewCounter("ErrReadRange")
+	ErrOpen         = NsObject.NewCounter("ErrOpen")
+	ErrRead         = NsObject.NewCounter("ErrRead")
+	ErrSeek         = NsObject.NewCounter("ErrSeek")
+	ErrCreate       = NsObject.NewCounter("ErrCreate")
+	ErrWrite        = NsObject.NewCounter("ErrWrite")
+	ErrClose        = NsObject.NewCounter("ErrClose")
+	ErrAbort        = NsObject.NewCounter("ErrAbort")
+	ErrCopyFile     = NsObject.NewCounter("ErrCopyFile")
+	ErrMoveFile     = NsObject.NewCounter("ErrMoveFile")
+	SignedUrl       = NsObject.NewCounter("SignedUrl")
+	ErrSignedUrl    = NsObject.NewCounter("ErrSignedUrl")
 )
@@ -0,0 +1,564 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsObject
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/lavaorg/lrtx/config"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/object/model"
+	"github.com/lavaorg/northstar/object/util"
+)
+
+var (
+	ChunkSize, _ = config.GetInt("NS_OBJECT_CHUNK_SIZE", 1024*1024)
+	PartSize, _  = config.GetInt("NS_OBJECT_PART_SIZE", 5*1024*1024)
+)
+
+const (
+	STAT       = "stat"
+	READ_RANGE = "readRange"
+	OPEN       = "open"
+	READ       = "read"
+	LINES      = "lines"
+	SEEK       = "seek"
+	SIZE       = "size"
+	CREATE     = "create"
+	WRITE      = "write"
+	CLOSE      = "close"
+	ABORT      = "abort"
+	COPY_FILE  = "copyFile"
+	MOVE_FILE  = "moveFile"
+
+	OBJECT_READER_TYPE = "nsObject.reader"
+	OBJECT_WRITER_TYPE = "nsObject.writer"
+)
+
+// Store is the access of the streaming functions to the files of the
+// account.
+type Store interface {
+	Stat(bucket string, file string) (*model.Object, error)
+	ReadRange(bucket string, file string, offset int64, length int64) ([]byte, error)
+	Upload(bucket string, file string, data []byte, contentType string) error
+	CreateUpload(bucket string, file string, contentType string) (string, error)
+	UploadPart(bucket string, file string, uploadId string, part int64, data []byte) (*model.Part, error)
+	CompleteUpload(bucket string, file string, uploadId string, parts []model.Part) error
+	AbortUpload(bucket string, file string, uploadId string) error
+	Copy(bucket string, file string, toBucket string, toFile string, move bool) error
+}
+
+// Streams are the functions of nsObject moving files through handles
+// which read and upload them in chunks, so a snippet can process files
+// larger than its memory limit.
+type Streams struct {
+	Store Store
+	// Error pushes the error of the function the way the module reports
+	// errors, Done counts its success.
+	Error func(L *lua.LState, err string, context string) int
+	Done  func(context string)
+
+	writers map[*Writer]bool
+}
+
+// Reader reads a file from its position, ChunkSize bytes at a time.
+type Reader struct {
+	bucket string
+	file   string
+	size   int64
+	pos    int64
+	// buf holds the bytes of the file read from pos.
+	buf    []byte
+	closed bool
+}
+
+// Writer uploads a file in parts of PartSize bytes as it is written. A
+// file smaller than a part is uploaded at once when the writer is closed.
+type Writer struct {
+	bucket      string
+	file        string
+	contentType string
+	uploadId    string
+	parts       []model.Part
+	buf         []byte
+	closed      bool
+}
+
+// Register adds the functions to the api of the module.
+func (s *Streams) Register(api map[string]lua.LGFunction) {
+	api[STAT] = s.stat
+	api[READ_RANGE] = s.readRange
+	api[OPEN] = s.open
+	api[CREATE] = s.create
+	api[COPY_FILE] = s.copyFile
+	api[MOVE_FILE] = s.moveFile
+}
+
+// Close aborts the uploads the snippet did not close.
+func (s *Streams) Close() {
+	for writer := range s.writers {
+		if writer.uploadId != "" {
+			if err := s.Store.AbortUpload(writer.bucket, writer.file, writer.uploadId); err != nil {
+				mlog.Error("Failed to abort upload of %s: %v", writer.file, err)
+			}
+		}
+	}
+	s.writers = nil
+}
+
+func (s *Streams) stat(L *lua.LState) int {
+	object, err := s.Store.Stat(L.CheckString(1), L.CheckString(2))
+	if err != nil {
+		return s.Error(L, err.Error(), STAT)
+	}
+
+	tbl := L.CreateTable(0, 4)
+	tbl.RawSetH(lua.LString("key"), lua.LString(object.Key))
+	tbl.RawSetH(lua.LString("size"), lua.LNumber(object.Size))
+	tbl.RawSetH(lua.LString("last_modified"), lua.LString(object.LastModified.String()))
+	tbl.RawSetH(lua.LString("etag"), lua.LString(object.Etag))
+
+	L.Push(tbl)
+	s.Done(STAT)
+	return 1
+}
+
+// readRange returns length bytes of the file from the offset as a string,
+// the rest of the file when length is 0.
+func (s *Streams) readRange(L *lua.LState) int {
+	offset, length := L.CheckInt64(3), L.OptInt64(4, 0)
+	if offset < 0 || length < 0 {
+		return s.Error(L, "invalid range", READ_RANGE)
+	}
+
+	data, err := s.Store.ReadRange(L.CheckString(1), L.CheckString(2), offset, length)
+	if err != nil {
+		return s.Error(L, err.Error(), READ_RANGE)
+	}
+
+	L.Push(lua.LString(data))
+	s.Done(READ_RANGE)
+	return 1
+}
+
+func (s *Streams) open(L *lua.LState) int {
+	bucket, file := L.CheckString(1), L.CheckString(2)
+	object, err := s.Store.Stat(bucket, file)
+	if err != nil {
+		return s.Error(L, err.Error(), OPEN)
+	}
+
+	mt := L.NewTypeMetatable(OBJECT_READER_TYPE)
+	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		READ:  s.read,
+		LINES: s.lines,
+		SEEK:  s.seek,
+		SIZE:  s.size,
+		CLOSE: s.closeReader,
+	}))
+
+	reader := L.NewUserData()
+	reader.Value = &Reader{bucket: bucket, file: file, size: object.Size}
+	L.SetMetatable(reader, mt)
+
+	L.Push(reader)
+	s.Done(OPEN)
+	return 1
+}
+
+func (s *Streams) reader(L *lua.LState) (*Reader, error) {
+	reader, ok := L.CheckUserData(1).Value.(*Reader)
+	if !ok {
+		return nil, errors.New("unknown file handle")
+	}
+
+	if reader.closed {
+		return nil, errors.New("file is closed")
+	}
+
+	return reader, nil
+}
+
+// read reads the file like io.read: a line with "l", the default, the rest
+// of the file with "a", or up to n bytes. nil is returned at the end of the
+// file.
+func (s *Streams) read(L *lua.LState) int {
+	reader, err := s.reader(L)
+	if err != nil {
+		return s.Error(L, err.Error(), READ)
+	}
+
+	var data []byte
+	var ok bool
+	switch format := L.Get(2).(type) {
+	case lua.LNumber:
+		if format <= 0 {
+			return s.Error(L, "invalid size", READ)
+		}
+		data, err = s.next(reader, int(format))
+		ok = len(data) > 0
+	case lua.LString, *lua.LNilType:
+		switch lua.LVAsString(format) {
+		case "", "l":
+			data, ok, err = s.line(reader)
+		case "a":
+			data, err = s.next(reader, int(reader.size-reader.pos))
+			ok = true
+		default:
+			return s.Error(L, "invalid format", READ)
+		}
+	default:
+		return s.Error(L, "invalid format", READ)
+	}
+
+	if err != nil {
+		return s.Error(L, err.Error(), READ)
+	}
+
+	if !ok {
+		L.Push(lua.LNil)
+	} else {
+		L.Push(lua.LString(data))
+	}
+	s.Done(READ)
+	return 1
+}
+
+// lines returns an iterator over the lines of the file. An error reading
+// the file is raised, since the iterator cannot return it.
+func (s *Streams) lines(L *lua.LState) int {
+	reader, err := s.reader(L)
+	if err != nil {
+		return s.Error(L, err.Error(), READ)
+	}
+
+	L.Push(L.NewFunction(func(L *lua.LState) int {
+		if reader.closed {
+			L.RaiseError(NS_OBJECT_ERROR + "file is closed")
+			return 0
+		}
+
+		line, ok, err := s.line(reader)
+		if err != nil {
+			L.RaiseError(NS_OBJECT_ERROR + err.Error())
+			return 0
+		}
+
+		if !ok {
+			L.Push(lua.LNil)
+		} else {
+			L.Push(lua.LString(line))
+		}
+		return 1
+	}))
+	s.Done(READ)
+	return 1
+}
+
+// seek moves the reader to the offset, when one is given, and returns its
+// position.
+func (s *Streams) seek(L *lua.LState) int {
+	reader, err := s.reader(L)
+	if err != nil {
+		return s.Error(L, err.Error(), SEEK)
+	}
+
+	if L.GetTop() >= 2 {
+		offset := L.CheckInt64(2)
+		if offset < 0 || offset > reader.size {
+			return s.Error(L, "offset out of range", SEEK)
+		}
+
+		if offset >= reader.pos && offset <= reader.pos+int64(len(reader.buf)) {
+			reader.buf = reader.buf[offset-reader.pos:]
+		} else {
+			reader.buf = nil
+		}
+		reader.pos = offset
+	}
+
+	L.Push(lua.LNumber(reader.pos))
+	s.Done(SEEK)
+	return 1
+}
+
+func (s *Streams) size(L *lua.LState) int {
+	reader, err := s.reader(L)
+	if err != nil {
+		return s.Error(L, err.Error(), SIZE)
+	}
+
+	L.Push(lua.LNumber(reader.size))
+	return 1
+}
+
+func (s *Streams) closeReader(L *lua.LState) int {
+	if reader, ok := L.CheckUserData(1).Value.(*Reader); ok {
+		reader.closed, reader.buf = true, nil
+	}
+	return 0
+}
+
+// fill reads the chunk of the file following the buffer, and reports
+// whether there was one.
+func (s *Streams) fill(reader *Reader) (bool, error) {
+	offset := reader.pos + int64(len(reader.buf))
+	if offset >= reader.size {
+		return false, nil
+	}
+
+	length := reader.size - offset
+	if length > int64(ChunkSize) {
+		length = int64(ChunkSize)
+	}
+
+	data, err := s.Store.ReadRange(reader.bucket, reader.file, offset, length)
+	if err != nil {
+		return false, err
+	}
+
+	reader.buf = append(reader.buf, data...)
+	return len(data) > 0, nil
+}
+
+// next returns up to n bytes from the position, fewer at the end of the
+// file.
+func (s *Streams) next(reader *Reader, n int) ([]byte, error) {
+	for len(reader.buf) < n {
+		more, err := s.fill(reader)
+		if err != nil {
+			return nil, err
+		}
+		if !more {
+			break
+		}
+	}
+
+	if n > len(reader.buf) {
+		n = len(reader.buf)
+	}
+
+	data := reader.buf[:n]
+	reader.buf = reader.buf[n:]
+	reader.pos += int64(n)
+	return data, nil
+}
+
+// line returns the next line without its end of line, and false at the end
+// of the file.
+func (s *Streams) line(reader *Reader) ([]byte, bool, error) {
+	searched := 0
+	for {
+		if i := bytes.IndexByte(reader.buf[searched:], '\n'); i >= 0 {
+			line, _ := s.next(reader, searched+i+1)
+			return bytes.TrimSuffix(line[:len(line)-1], []byte("\r")), true, nil
+		}
+
+		searched = len(reader.buf)
+		more, err := s.fill(reader)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if !more {
+			if len(reader.buf) == 0 {
+				return nil, false, nil
+			}
+			line, _ := s.next(reader, len(reader.buf))
+			return bytes.TrimSuffix(line, []byte("\r")), true, nil
+		}
+	}
+}
+
+func (s *Streams) create(L *lua.LState) int {
+	writer := &Writer{bucket: L.CheckString(1), file: L.CheckString(2), contentType: L.CheckString(3)}
+
+	mt := L.NewTypeMetatable(OBJECT_WRITER_TYPE)
+	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		WRITE: s.write,
+		CLOSE: s.closeWriter,
+		ABORT: s.abort,
+	}))
+
+	if s.writers == nil {
+		s.writers = make(map[*Writer]bool)
+	}
+	s.writers[writer] = true
+
+	handle := L.NewUserData()
+	handle.Value = writer
+	L.SetMetatable(handle, mt)
+
+	L.Push(handle)
+	s.Done(CREATE)
+	return 1
+}
+
+func (s *Streams) writer(L *lua.LState) (*Writer, error) {
+	writer, ok := L.CheckUserData(1).Value.(*Writer)
+	if !ok {
+		return nil, errors.New("unknown file handle")
+	}
+
+	if writer.closed {
+		return nil, errors.New("file is closed")
+	}
+
+	return writer, nil
+}
+
+// write appends the string or byte table to the file, uploading the parts
+// filled.
+func (s *Streams) write(L *lua.LState) int {
+	writer, err := s.writer(L)
+	if err != nil {
+		return s.Error(L, err.Error(), WRITE)
+	}
+
+	data, err := toData(L.CheckAny(2))
+	if err != nil {
+		return s.Error(L, err.Error(), WRITE)
+	}
+
+	writer.buf = append(writer.buf, data...)
+	for len(writer.buf) >= PartSize {
+		if err := s.flush(writer, PartSize); err != nil {
+			return s.Error(L, err.Error(), WRITE)
+		}
+	}
+
+	s.Done(WRITE)
+	return 0
+}
+
+func (s *Streams) closeWriter(L *lua.LState) int {
+	writer, err := s.writer(L)
+	if err != nil {
+		return s.Error(L, err.Error(), CLOSE)
+	}
+
+	if writer.uploadId == "" {
+		err = s.Store.Upload(writer.bucket, writer.file, writer.buf, writer.contentType)
+	} else {
+		if len(writer.buf) > 0 {
+			err = s.flush(writer, len(writer.buf))
+		}
+		if err == nil {
+			err = s.Store.CompleteUpload(writer.bucket, writer.file, writer.uploadId, writer.parts)
+		}
+	}
+
+	if err != nil {
+		return s.Error(L, err.Error(), CLOSE)
+	}
+
+	writer.closed, writer.buf = true, nil
+	delete(s.writers, writer)
+	s.Done(CLOSE)
+	return 0
+}
+
+func (s *Streams) abort(L *lua.LState) int {
+	writer, err := s.writer(L)
+	if err != nil {
+		return s.Error(L, err.Error(), ABORT)
+	}
+
+	writer.closed, writer.buf = true, nil
+	delete(s.writers, writer)
+	if writer.uploadId != "" {
+		if err := s.Store.AbortUpload(writer.bucket, writer.file, writer.uploadId); err != nil {
+			return s.Error(L, err.Error(), ABORT)
+		}
+	}
+
+	s.Done(ABORT)
+	return 0
+}
+
+// flush uploads the first size bytes of the buffer as the next part,
+// starting the multipart upload with the first part.
+func (s *Streams) flush(writer *Writer, size int) error {
+	if len(writer.parts) == util.MaxParts {
+		return fmt.Errorf("file %s exceeds %d parts", writer.file, util.MaxParts)
+	}
+
+	if writer.uploadId == "" {
+		uploadId, err := s.Store.CreateUpload(writer.bucket, writer.file, writer.contentType)
+		if err != nil {
+			return err
+		}
+		writer.uploadId = uploadId
+	}
+
+	part, err := s.Store.UploadPart(writer.bucket, writer.file, writer.uploadId,
+		int64(len(writer.parts)+1), writer.buf[:size])
+	if err != nil {
+		return err
+	}
+
+	writer.parts = append(writer.parts, *part)
+	writer.buf = append(writer.buf[:0], writer.buf[size:]...)
+	return nil
+}
+
+func (s *Streams) copyFile(L *lua.LState) int {
+	return s.copy(L, false, COPY_FILE)
+}
+
+func (s *Streams) moveFile(L *lua.LState) int {
+	return s.copy(L, true, MOVE_FILE)
+}
+
+// copy copies or moves the file of the bucket to the file of the other
+// bucket, the same file name when it is omitted.
+func (s *Streams) copy(L *lua.LState, move bool, context string) int {
+	bucket, file, toBucket := L.CheckString(1), L.CheckString(2), L.CheckString(3)
+	toFile := L.OptString(4, file)
+	if bucket == toBucket && file == toFile {
+		return s.Error(L, "source and destination are the same file", context)
+	}
+
+	if err := s.Store.Copy(bucket, file, toBucket, toFile, move); err != nil {
+		return s.Error(L, err.Error(), context)
+	}
+
+	s.Done(context)
+	return 0
+}
+
+// toData accepts the data written as a string or a byte table.
+func toData(value lua.LValue) ([]byte, error) {
+	switch v := value.(type) {
+	case lua.LString:
+		return []byte(string(v)), nil
+	case *lua.LTable:
+		data := make([]byte, v.MaxN())
+		for i := range data {
+			b, ok := v.RawGetInt(i + 1).(lua.LNumber)
+			if !ok {
+				return nil, errors.New("unexpected value in array, byte expected")
+			}
+			data[i] = byte(b)
+		}
+		return data, nil
+	}
+	return nil, errors.New("unexpected value, string or byte array expected")
+}
@@ -52,6 +52,39 @@ func (nsObject *NsObjectModule) error(L *lua.LState, err string, timer *stats.Ti
 	case "listFiles":
 		mode = 2
 		ErrListFiles.Incr()
+	case "signedUrl":
+		mode = 2
+		ErrSignedUrl.Incr()
+	case STAT:
+		mode = 2
+		ErrStat.Incr()
+	case READ_RANGE:
+		mode = 2
+		ErrReadRange.Incr()
+	case OPEN:
+		mode = 2
+		ErrOpen.Incr()
+	case READ:
+		mode = 2
+		ErrRead.Incr()
+	case SEEK:
+		mode = 2
+		ErrSeek.Incr()
+	case SIZE:
+		mode = 2
+	case CREATE:
+		mode = 2
+		ErrCreate.Incr()
+	case WRITE:
+		ErrWrite.Incr()
+	case CLOSE:
+		ErrClose.Incr()
+	case ABORT:
+		ErrAbort.Incr()
+	case COPY_FILE:
+		ErrCopyFile.Incr()
+	case MOVE_FILE:
+		ErrMoveFile.Incr()
 	}
 
 	if mode == 1 {
@@ -63,3 +96,30 @@ func (nsObject *NsObjectModule) error(L *lua.LState, err string, timer *stats.Ti
 		return 2
 	}
 }
+
+func done(context string) {
+	switch context {
+	case STAT:
+		Stat.Incr()
+	case READ_RANGE:
+		ReadRange.Incr()
+	case OPEN:
+		Open.Incr()
+	case READ:
+		Read.Incr()
+	case SEEK:
+		Seek.Incr()
+	case CREATE:
+		Create.Incr()
+	case WRITE:
+		Write.Incr()
+	case CLOSE:
+		Close.Incr()
+	case ABORT:
+		Abort.Incr()
+	case COPY_FILE:
+		CopyFile.Incr()
+	case MOVE_FILE:
+		MoveFile.Incr()
+	}
+}
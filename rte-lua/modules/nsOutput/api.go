@@ -19,6 +19,7 @@ package nsOutput
 import (
 	"github.com/lavaorg/lrtx/luaext/gluamapper"
 	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/rte-lua/modules/nsOutput/export"
 )
 
 func (nsOutput *NsOutputModule) printApi(L *lua.LState) int {
@@ -139,6 +140,28 @@ func (nsOutput *NsOutputModule) htmlDirectApi(L *lua.LState) int {
 	return 0
 }
 
+func (nsOutput *NsOutputModule) chartApi(L *lua.LState) int {
+	output, err := nsOutput.generateFromTable(L.CheckTable(1), &Chart{})
+	if err != nil {
+		return nsOutput.error(L, err.Error(), nil, _CHART, 2)
+	}
+
+	L.Push(lua.LString(output))
+	ChartCounter.Incr()
+	return 1
+}
+
+func (nsOutput *NsOutputModule) chartDirectApi(L *lua.LState) int {
+	output, err := nsOutput.generateFromTable(L.CheckTable(1), &Chart{})
+	if err != nil {
+		return nsOutput.error(L, err.Error(), nil, _CHART_DIRECT, 1)
+	}
+
+	nsOutput.Result = output
+	ChartDirectCounter.Incr()
+	return 0
+}
+
 func (nsOutput *NsOutputModule) tableToCsvApi(L *lua.LState) int {
 	var data string
 	var table Table
@@ -158,3 +181,43 @@ func (nsOutput *NsOutputModule) tableToCsvApi(L *lua.LState) int {
 	L.Push(lua.LString(data))
 	return 1
 }
+
+// exportApi writes a table, a table document or the result of an nsQL query
+// to a file of a bucket, as CSV, JSON Lines or Parquet. The format defaults
+// to the extension of the file.
+func (nsOutput *NsOutputModule) exportApi(L *lua.LState) int {
+	bucket := L.CheckString(2)
+	fileName := L.CheckString(3)
+	format := L.OptString(4, export.FormatOf(fileName))
+
+	if nsOutput.Objects == nil {
+		return nsOutput.error(L, "export requires nsObject", nil, _EXPORT, 1)
+	}
+
+	var table Table
+	var err error
+	switch value := L.CheckAny(1).(type) {
+	case *lua.LTable:
+		mapper := gluamapper.NewMapper(gluamapper.Option{NameFunc: func(str string) string { return str }})
+		err = mapper.Map(value, &table)
+	case lua.LString:
+		err = tableFromOutput(string(value), &table)
+	default:
+		L.ArgError(1, "table or string expected")
+	}
+	if err != nil {
+		return nsOutput.error(L, err.Error(), nil, _EXPORT, 1)
+	}
+
+	data, err := export.Export((*export.Table)(&table), format)
+	if err != nil {
+		return nsOutput.error(L, err.Error(), nil, _EXPORT, 1)
+	}
+
+	if err = nsOutput.Objects.Upload(bucket, fileName, data, export.ContentType(format)); err != nil {
+		return nsOutput.error(L, err.Error(), nil, _EXPORT, 1)
+	}
+
+	ExportCounter.Incr()
+	return 0
+}
@@ -0,0 +1,290 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsOutput
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	LINE_CHART      = "line"
+	BAR_CHART       = "bar"
+	SCATTER_CHART   = "scatter"
+	HISTOGRAM_CHART = "histogram"
+	HEATMAP_CHART   = "heatmap"
+
+	LINEAR_AXIS   = "linear"
+	LOG_AXIS      = "log"
+	CATEGORY_AXIS = "category"
+	TIME_AXIS     = "time"
+
+	// MaxChartSeries is the number of series a chart can have, and
+	// MaxChartBins the number of bins of a histogram.
+	MaxChartSeries = 50
+	MaxChartBins   = 1000
+)
+
+// Validate checks the chart can be rendered and is at most
+// NsOutputChartPointLimit points, and converts the values of its time axes
+// to RFC3339 strings.
+func (chart *Chart) Validate() error {
+	switch chart.Type {
+	case LINE_CHART, BAR_CHART, SCATTER_CHART, HISTOGRAM_CHART, HEATMAP_CHART:
+	default:
+		return fmt.Errorf("unknown chart type %q", chart.Type)
+	}
+
+	if err := chart.X.validate("x"); err != nil {
+		return err
+	}
+
+	if err := chart.Y.validate("y"); err != nil {
+		return err
+	}
+
+	if len(chart.Series) == 0 {
+		return errors.New("chart has no series")
+	}
+
+	if len(chart.Series) > MaxChartSeries {
+		return fmt.Errorf("chart has more than %d series", MaxChartSeries)
+	}
+
+	if chart.Type == HEATMAP_CHART && len(chart.Series) != 1 {
+		return errors.New("heatmap must have a single series")
+	}
+
+	points := 0
+	for i, series := range chart.Series {
+		if series == nil {
+			return fmt.Errorf("series %d is empty", i+1)
+		}
+
+		count, err := chart.validateSeries(series)
+		if err != nil {
+			return fmt.Errorf("series %d: %v", i+1, err)
+		}
+
+		if points += count; points > NsOutputChartPointLimit {
+			return fmt.Errorf("%d-point chart limit is exceeded", NsOutputChartPointLimit)
+		}
+	}
+
+	return nil
+}
+
+// validateSeries returns the number of points of the series.
+func (chart *Chart) validateSeries(series *Series) (int, error) {
+	var err error
+	switch chart.Type {
+	case HISTOGRAM_CHART:
+		if len(series.X) > 0 || len(series.Z) > 0 {
+			return 0, errors.New("histogram has only y values")
+		}
+
+		if series.Bins < 0 || series.Bins > MaxChartBins {
+			return 0, fmt.Errorf("bins must be between 0 and %d", MaxChartBins)
+		}
+
+		if err = numbers(series.Y, "y"); err != nil {
+			return 0, err
+		}
+
+		// The samples are binned along the x axis.
+		if err = chart.X.positive(series.Y, "y"); err != nil {
+			return 0, err
+		}
+
+		return len(series.Y), nil
+	case HEATMAP_CHART:
+		if len(series.Z) != len(series.Y) {
+			return 0, errors.New("heatmap must have a row of z values per y value")
+		}
+
+		for _, row := range series.Z {
+			if len(row) != len(series.X) {
+				return 0, errors.New("heatmap must have a z value per x value in each row")
+			}
+
+			if err = numbers(row, "z"); err != nil {
+				return 0, err
+			}
+		}
+
+		if series.X, err = chart.X.values(series.X, "x"); err != nil {
+			return 0, err
+		}
+
+		if series.Y, err = chart.Y.values(series.Y, "y"); err != nil {
+			return 0, err
+		}
+
+		return len(series.X) * len(series.Y), nil
+	}
+
+	if len(series.Z) > 0 || series.Bins != 0 {
+		return 0, fmt.Errorf("%s chart has only x and y values", chart.Type)
+	}
+
+	if len(series.X) > 0 && len(series.X) != len(series.Y) {
+		return 0, errors.New("x and y values have different lengths")
+	}
+
+	if series.X, err = chart.X.values(series.X, "x"); err != nil {
+		return 0, err
+	}
+
+	if err = numbers(series.Y, "y"); err != nil {
+		return 0, err
+	}
+
+	if err = chart.Y.positive(series.Y, "y"); err != nil {
+		return 0, err
+	}
+
+	return len(series.Y), nil
+}
+
+func (axis *Axis) validate(name string) error {
+	if axis == nil {
+		return nil
+	}
+
+	switch axis.Type {
+	case "", LINEAR_AXIS, LOG_AXIS, CATEGORY_AXIS, TIME_AXIS:
+	default:
+		return fmt.Errorf("unknown %s axis type %q", name, axis.Type)
+	}
+
+	if axis.Min != nil && axis.Max != nil && *axis.Min >= *axis.Max {
+		return fmt.Errorf("%s axis min must be less than its max", name)
+	}
+
+	if axis.Type == LOG_AXIS && (axis.Min != nil && *axis.Min <= 0 || axis.Max != nil && *axis.Max <= 0) {
+		return fmt.Errorf("%s axis min and max must be positive on a log axis", name)
+	}
+
+	return nil
+}
+
+// positive checks the numbers of a log axis are above zero, a log axis
+// can not place the others.
+func (axis *Axis) positive(values []interface{}, name string) error {
+	if axis == nil || axis.Type != LOG_AXIS {
+		return nil
+	}
+
+	for _, value := range values {
+		if n, ok := toNumber(value); !ok || n <= 0 {
+			return fmt.Errorf("%s value %v is not positive on a log axis", name, value)
+		}
+	}
+
+	return nil
+}
+
+// values checks the values of the axis: numbers, or strings on a category
+// axis. The values of a time axis are returned as RFC3339 strings.
+func (axis *Axis) values(values []interface{}, name string) ([]interface{}, error) {
+	axisType := ""
+	if axis != nil {
+		axisType = axis.Type
+	}
+
+	switch axisType {
+	case CATEGORY_AXIS:
+		for _, value := range values {
+			switch value.(type) {
+			case string, float64, int, int64:
+			default:
+				return nil, fmt.Errorf("%s value %v is not a category", name, value)
+			}
+		}
+		return values, nil
+	case TIME_AXIS:
+		times := make([]interface{}, len(values))
+		for i, value := range values {
+			t, err := toTime(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s value %v is not a time", name, value)
+			}
+			times[i] = t.UTC().Format(time.RFC3339)
+		}
+		return times, nil
+	}
+
+	// Without a type, strings make the axis a category axis.
+	if axisType == "" {
+		for _, value := range values {
+			if _, ok := value.(string); ok {
+				return values, nil
+			}
+		}
+	}
+
+	if err := numbers(values, name); err != nil {
+		return nil, err
+	}
+
+	return values, axis.positive(values, name)
+}
+
+func numbers(values []interface{}, name string) error {
+	for _, value := range values {
+		switch value.(type) {
+		case float64, int, int64:
+		default:
+			return fmt.Errorf("%s value %v is not a number", name, value)
+		}
+	}
+
+	return nil
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+
+	return 0, false
+}
+
+func toTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case float64:
+		return time.Unix(int64(v), 0), nil
+	case int:
+		return time.Unix(int64(v), 0), nil
+	case int64:
+		return time.Unix(v, 0), nil
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, nil
+		}
+		// The times of the tables.
+		return time.Parse("2006-01-02 15:04:05", v)
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %v", value)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsOutput
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChartValidate(t *testing.T) {
+	chart := &Chart{Type: LINE_CHART,
+		X: &Axis{Type: TIME_AXIS, Format: "%H:%M"},
+		Series: []*Series{
+			{Name: "cpu", X: []interface{}{float64(0), "1970-01-01T00:01:00Z"}, Y: []interface{}{0.5, 0.7}},
+			{Name: "memory", X: []interface{}{float64(0), "1970-01-01 00:01:00"}, Y: []interface{}{0.2, 0.3}}}}
+	assert.NoError(t, chart.Validate())
+	assert.Equal(t, []interface{}{"1970-01-01T00:00:00Z", "1970-01-01T00:01:00Z"}, chart.Series[1].X)
+
+	bar := &Chart{Type: BAR_CHART, Series: []*Series{{X: []interface{}{"a", "b"}, Y: []interface{}{1.0, 2.0}}}}
+	assert.NoError(t, bar.Validate())
+
+	heatmap := &Chart{Type: HEATMAP_CHART, Series: []*Series{{X: []interface{}{"mon", "tue"},
+		Y: []interface{}{"am", "pm"}, Z: [][]interface{}{{1.0, 2.0}, {3.0, 4.0}}}}}
+	assert.NoError(t, heatmap.Validate())
+
+	for name, invalid := range map[string]*Chart{
+		"unknown chart type": {Type: "pie", Series: []*Series{{Y: []interface{}{1.0}}}},
+		"no series":          {Type: LINE_CHART},
+		"different lengths":  {Type: LINE_CHART, Series: []*Series{{X: []interface{}{1.0}, Y: []interface{}{1.0, 2.0}}}},
+		"not a number":       {Type: SCATTER_CHART, Series: []*Series{{Y: []interface{}{"a"}}}},
+		"not a time": {Type: LINE_CHART, X: &Axis{Type: TIME_AXIS},
+			Series: []*Series{{X: []interface{}{"yesterday"}, Y: []interface{}{1.0}}}},
+		"min must be less": {Type: LINE_CHART, Y: &Axis{Min: float(2), Max: float(1)},
+			Series: []*Series{{Y: []interface{}{1.0}}}},
+		"bins must be":  {Type: HISTOGRAM_CHART, Series: []*Series{{Y: []interface{}{1.0}, Bins: -1}}},
+		"a z value per": {Type: HEATMAP_CHART, Series: []*Series{{X: []interface{}{"a"}, Y: []interface{}{"b"}, Z: [][]interface{}{{1.0, 2.0}}}}},
+		"single series": {Type: HEATMAP_CHART, Series: []*Series{{}, {}}},
+		"z value x is not a number": {Type: HEATMAP_CHART, Series: []*Series{{X: []interface{}{"a"}, Y: []interface{}{"b"},
+			Z: [][]interface{}{{"x"}}}}},
+		"y value 0 is not positive": {Type: LINE_CHART, Y: &Axis{Type: LOG_AXIS},
+			Series: []*Series{{Y: []interface{}{1.0, 0.0}}}},
+		"x value -1 is not positive": {Type: SCATTER_CHART, X: &Axis{Type: LOG_AXIS},
+			Series: []*Series{{X: []interface{}{-1.0}, Y: []interface{}{1.0}}}},
+		"y value 0 is not positive on a log axis": {Type: HISTOGRAM_CHART, X: &Axis{Type: LOG_AXIS},
+			Series: []*Series{{Y: []interface{}{0.0}}}},
+		"must be positive": {Type: LINE_CHART, Y: &Axis{Type: LOG_AXIS, Min: float(0)},
+			Series: []*Series{{Y: []interface{}{1.0}}}},
+	} {
+		err := invalid.Validate()
+		if assert.Error(t, err, name) {
+			assert.Contains(t, err.Error(), name)
+		}
+	}
+}
+
+func TestChartPointLimit(t *testing.T) {
+	limit := NsOutputChartPointLimit
+	NsOutputChartPointLimit = 3
+	defer func() { NsOutputChartPointLimit = limit }()
+
+	chart := &Chart{Type: HISTOGRAM_CHART, Series: []*Series{{Y: []interface{}{1.0, 2.0}}, {Y: []interface{}{3.0, 4.0}}}}
+	err := chart.Validate()
+	if assert.Error(t, err) {
+		assert.True(t, strings.HasPrefix(err.Error(), "3-point chart limit"), err.Error())
+	}
+}
+
+func float(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,370 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package export converts the tables of nsOutput and the results of nsQL
+// queries to CSV, JSON Lines or Parquet files.
+package export
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats of the exported files.
+const (
+	CSV     = "csv"
+	JSONL   = "jsonl"
+	PARQUET = "parquet"
+)
+
+var contentTypes = map[string]string{
+	CSV:     "text/csv",
+	JSONL:   "application/x-ndjson",
+	PARQUET: "application/vnd.apache.parquet",
+}
+
+// Table is a table of nsOutput or the result of an nsQL query. The values
+// of a row follow the columns, and are converted to the types of the
+// columns when they are set.
+type Table struct {
+	Columns []string        `json:"columns"`
+	Types   []string        `json:"types"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// FormatOf returns the format of a file from its extension, or an empty
+// string when it is not one of the formats.
+func FormatOf(fileName string) string {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".csv":
+		return CSV
+	case ".jsonl", ".ndjson":
+		return JSONL
+	case ".parquet":
+		return PARQUET
+	}
+
+	return ""
+}
+
+// ContentType returns the content type of the files of the format.
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// Export returns the table as a file of the format.
+func Export(table *Table, format string) ([]byte, error) {
+	if _, ok := contentTypes[format]; !ok {
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+
+	columns, err := toColumns(table)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case CSV:
+		return toCsv(columns, len(table.Rows))
+	case JSONL:
+		return toJsonl(columns, len(table.Rows))
+	}
+
+	return toParquet(columns, len(table.Rows))
+}
+
+type kind int
+
+const (
+	stringKind kind = iota
+	intKind
+	floatKind
+	boolKind
+	timeKind
+	blobKind
+)
+
+// column holds the values of a column converted to its kind: nil, string,
+// int64, float64, bool, time.Time or []byte.
+type column struct {
+	name   string
+	kind   kind
+	values []interface{}
+}
+
+func toColumns(table *Table) ([]*column, error) {
+	if len(table.Columns) == 0 {
+		return nil, errors.New("table has no columns")
+	}
+
+	if len(table.Types) != 0 && len(table.Types) != len(table.Columns) {
+		return nil, errors.New("table has not a type per column")
+	}
+
+	columns := make([]*column, len(table.Columns))
+	for i, name := range table.Columns {
+		columns[i] = &column{name: name, values: make([]interface{}, len(table.Rows))}
+		if len(table.Types) != 0 {
+			columns[i].kind = kindOf(table.Types[i])
+		}
+	}
+
+	for i, row := range table.Rows {
+		if len(row) > len(columns) {
+			return nil, fmt.Errorf("row %d has more values than columns", i+1)
+		}
+
+		for j, value := range row {
+			converted, err := convert(value, columns[j].kind)
+			if err != nil {
+				return nil, fmt.Errorf("row %d, column %s: %v", i+1, columns[j].name, err)
+			}
+			columns[j].values[i] = converted
+		}
+	}
+
+	return columns, nil
+}
+
+// kindOf returns the kind of the values of a type of nsQL.
+func kindOf(valueType string) kind {
+	switch strings.ToLower(valueType) {
+	case "int", "uint":
+		return intKind
+	case "float", "double":
+		return floatKind
+	case "bool":
+		return boolKind
+	case "time":
+		return timeKind
+	case "blob":
+		return blobKind
+	}
+
+	return stringKind
+}
+
+func convert(value interface{}, k kind) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	// The values of the results of nsQL are strings, empty when they are
+	// missing.
+	if s, ok := value.(string); ok && s == "" && k != stringKind && k != blobKind {
+		return nil, nil
+	}
+
+	switch k {
+	case intKind:
+		switch v := value.(type) {
+		case float64:
+			if v != math.Trunc(v) {
+				return nil, fmt.Errorf("%v is not an integer", v)
+			}
+			return int64(v), nil
+		case int:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		case string:
+			return strconv.ParseInt(v, 10, 64)
+		}
+	case floatKind:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case string:
+			return strconv.ParseFloat(v, 64)
+		}
+	case boolKind:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case timeKind:
+		switch v := value.(type) {
+		case time.Time:
+			return v.UTC(), nil
+		case float64:
+			return time.Unix(int64(v), 0).UTC(), nil
+		case int:
+			return time.Unix(int64(v), 0).UTC(), nil
+		case int64:
+			return time.Unix(v, 0).UTC(), nil
+		case string:
+			return parseTime(v)
+		}
+	case blobKind:
+		switch v := value.(type) {
+		case []byte:
+			return v, nil
+		case string:
+			return []byte(v), nil
+		case []interface{}:
+			blob := make([]byte, len(v))
+			for i, b := range v {
+				n, ok := b.(float64)
+				if !ok || n < 0 || n > 255 {
+					return nil, fmt.Errorf("%v is not a byte", b)
+				}
+				blob[i] = byte(n)
+			}
+			return blob, nil
+		}
+	default:
+		return toString(value)
+	}
+
+	return nil, fmt.Errorf("invalid value %v", value)
+}
+
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+
+	// The times of the tables and nsQL results.
+	return time.Parse("2006-01-02 15:04:05", value)
+}
+
+func toString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case map[interface{}]interface{}, map[string]interface{}, []interface{}:
+		encoded, err := json.Marshal(normalize(v))
+		return string(encoded), err
+	}
+
+	return fmt.Sprint(value), nil
+}
+
+// normalize converts the tables mapped from Lua to values which can be
+// encoded in JSON.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			m[fmt.Sprint(key)] = normalize(elem)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			m[key] = normalize(elem)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, elem := range v {
+			a[i] = normalize(elem)
+		}
+		return a
+	}
+
+	return value
+}
+
+// text formats a converted value in a CSV file.
+func text(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	}
+
+	return fmt.Sprint(value)
+}
+
+func toCsv(columns []*column, rows int) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	writer := csv.NewWriter(buf)
+
+	record := make([]string, len(columns))
+	for i, c := range columns {
+		record[i] = c.name
+	}
+	writer.Write(record)
+
+	for row := 0; row < rows; row++ {
+		for i, c := range columns {
+			record[i] = text(c.values[row])
+		}
+		writer.Write(record)
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// toJsonl returns a JSON object per row, its fields in the order of the
+// columns.
+func toJsonl(columns []*column, rows int) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	for row := 0; row < rows; row++ {
+		buf.WriteByte('{')
+		for i, c := range columns {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			name, _ := json.Marshal(c.name)
+			value, err := json.Marshal(c.values[row])
+			if err != nil {
+				return nil, err
+			}
+
+			buf.Write(name)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteString("}\n")
+	}
+
+	return buf.Bytes(), nil
+}
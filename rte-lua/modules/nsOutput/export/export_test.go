@@ -0,0 +1,191 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTable() *Table {
+	return &Table{Columns: []string{"device", "count", "temperature", "online", "seen"},
+		Types: []string{"string", "int", "double", "bool", "time"},
+		Rows: [][]interface{}{
+			{"a,1", "3", "21.5", "true", "2017-06-01 10:00:00"},
+			{"b", float64(4), nil, false, float64(1496311200)},
+			{"c"}}}
+}
+
+func TestFormatOf(t *testing.T) {
+	assert.Equal(t, CSV, FormatOf("results/today.CSV"))
+	assert.Equal(t, JSONL, FormatOf("today.jsonl"))
+	assert.Equal(t, PARQUET, FormatOf("today.parquet"))
+	assert.Equal(t, "", FormatOf("today.xlsx"))
+}
+
+func TestCsv(t *testing.T) {
+	data, err := Export(newTable(), CSV)
+	require.NoError(t, err)
+	assert.Equal(t, "device,count,temperature,online,seen\n"+
+		"\"a,1\",3,21.5,true,2017-06-01T10:00:00Z\n"+
+		"b,4,,false,2017-06-01T10:00:00Z\n"+
+		"c,,,,\n", string(data))
+}
+
+func TestJsonl(t *testing.T) {
+	data, err := Export(newTable(), JSONL)
+	require.NoError(t, err)
+	assert.Equal(t, `{"device":"a,1","count":3,"temperature":21.5,"online":true,"seen":"2017-06-01T10:00:00Z"}
+{"device":"b","count":4,"temperature":null,"online":false,"seen":"2017-06-01T10:00:00Z"}
+{"device":"c","count":null,"temperature":null,"online":null,"seen":null}
+`, string(data))
+}
+
+func TestInvalidTables(t *testing.T) {
+	for _, table := range []*Table{
+		{},
+		{Columns: []string{"a"}, Types: []string{"int", "int"}},
+		{Columns: []string{"a"}, Rows: [][]interface{}{{"1", "2"}}},
+		{Columns: []string{"a"}, Types: []string{"int"}, Rows: [][]interface{}{{1.5}}},
+		{Columns: []string{"a"}, Types: []string{"time"}, Rows: [][]interface{}{{"yesterday"}}},
+	} {
+		_, err := Export(table, CSV)
+		assert.Error(t, err, "%+v", table)
+	}
+
+	_, err := Export(newTable(), "xlsx")
+	assert.Error(t, err)
+}
+
+func TestParquet(t *testing.T) {
+	data, err := Export(newTable(), PARQUET)
+	require.NoError(t, err)
+	require.Equal(t, "PAR1", string(data[:4]))
+	require.Equal(t, "PAR1", string(data[len(data)-4:]))
+
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	meta := newCompactReader(data[len(data)-8-size : len(data)-8]).readStruct()
+	assert.Equal(t, int64(3), meta[3])
+
+	schema := meta[2].([]interface{})
+	require.Len(t, schema, 6)
+	assert.Equal(t, "schema", schema[0].(map[int16]interface{})[4])
+	assert.Equal(t, int64(5), schema[0].(map[int16]interface{})[5])
+	for i, name := range newTable().Columns {
+		element := schema[i+1].(map[int16]interface{})
+		assert.Equal(t, name, element[4])
+		assert.Equal(t, int64(parquetOptional), element[3])
+	}
+	assert.Equal(t, int64(parquetUtf8), schema[1].(map[int16]interface{})[6])
+	assert.Equal(t, int64(parquetInt64), schema[2].(map[int16]interface{})[1])
+	assert.Equal(t, int64(parquetTimestampMillis), schema[5].(map[int16]interface{})[6])
+
+	chunks := meta[4].([]interface{})[0].(map[int16]interface{})[1].([]interface{})
+	require.Len(t, chunks, 5)
+
+	// The page of the count column: two values set out of three.
+	chunk := chunks[1].(map[int16]interface{})[3].(map[int16]interface{})
+	assert.Equal(t, []interface{}{"count"}, chunk[3])
+	reader := newCompactReader(data[chunk[9].(int64):])
+	header := reader.readStruct()
+	assert.Equal(t, int64(3), header[5].(map[int16]interface{})[1])
+	body := reader.data[reader.pos : reader.pos+int(header[3].(int64))]
+
+	levels := int(binary.LittleEndian.Uint32(body))
+	assert.Equal(t, []byte{1<<1 | 1, 0x03}, body[4:4+levels])
+	values := body[4+levels:]
+	require.Len(t, values, 16)
+	assert.Equal(t, uint64(3), binary.LittleEndian.Uint64(values))
+	assert.Equal(t, uint64(4), binary.LittleEndian.Uint64(values[8:]))
+
+	// The page of the temperature column: a single value.
+	chunk = chunks[2].(map[int16]interface{})[3].(map[int16]interface{})
+	reader = newCompactReader(data[chunk[9].(int64):])
+	header = reader.readStruct()
+	body = reader.data[reader.pos : reader.pos+int(header[3].(int64))]
+	assert.Equal(t, 21.5, math.Float64frombits(binary.LittleEndian.Uint64(body[len(body)-8:])))
+}
+
+// compactReader reads the structs of the thrift compact protocol, their
+// fields keyed by id.
+type compactReader struct {
+	data []byte
+	pos  int
+}
+
+func newCompactReader(data []byte) *compactReader {
+	return &compactReader{data: data}
+}
+
+func (r *compactReader) readStruct() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var last int16
+	for {
+		b := r.data[r.pos]
+		r.pos++
+		if b == 0 {
+			return fields
+		}
+
+		id := last + int16(b>>4)
+		if b>>4 == 0 {
+			id = int16(r.readInt())
+		}
+		fields[id] = r.readValue(b & 0x0f)
+		last = id
+	}
+}
+
+func (r *compactReader) readValue(valueType byte) interface{} {
+	switch valueType {
+	case thriftI32, thriftI64:
+		return r.readInt()
+	case thriftBinary:
+		size, n := binary.Uvarint(r.data[r.pos:])
+		r.pos += n + int(size)
+		return string(r.data[r.pos-int(size) : r.pos])
+	case thriftList:
+		b := r.data[r.pos]
+		r.pos++
+		size := int(b >> 4)
+		if size == 15 {
+			s, n := binary.Uvarint(r.data[r.pos:])
+			r.pos += n
+			size = int(s)
+		}
+
+		values := make([]interface{}, size)
+		for i := range values {
+			values[i] = r.readValue(b & 0x0f)
+		}
+		return values
+	case thriftStruct:
+		return r.readStruct()
+	}
+
+	panic("unexpected type")
+}
+
+func (r *compactReader) readInt() int64 {
+	value, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return int64(value>>1) ^ -int64(value&1)
+}
@@ -0,0 +1,286 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// The Parquet files have a single row group, with a page per column. The
+// values are PLAIN encoded and not compressed; every column is optional.
+const (
+	parquetMagic   = "PAR1"
+	parquetCreator = "northstar"
+
+	// Physical types
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	// Converted types
+	parquetUtf8            = 0
+	parquetTimestampMillis = 9
+
+	parquetOptional     = 1
+	parquetPlain        = 0
+	parquetRle          = 3
+	parquetUncompressed = 0
+	parquetDataPage     = 0
+)
+
+// Types of the thrift compact protocol of the metadata.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+func toParquet(columns []*column, rows int) ([]byte, error) {
+	file := bytes.NewBufferString(parquetMagic)
+
+	// The column chunks are written with the pages, and added to the row
+	// group of the metadata.
+	chunks := new(compactWriter)
+	chunks.listHeader(thriftStruct, len(columns))
+	var total int64
+	for _, c := range columns {
+		offset := int64(file.Len())
+		page := c.page()
+		file.Write(page)
+		total += int64(len(page))
+
+		chunks.begin()
+		chunks.i64(2, offset)
+		chunks.beginStruct(3)
+		chunks.i32(1, c.physicalType())
+		chunks.list(2, thriftI32, 2)
+		chunks.varint(zigzag(parquetPlain))
+		chunks.varint(zigzag(parquetRle))
+		chunks.list(3, thriftBinary, 1)
+		chunks.string(c.name)
+		chunks.i32(4, parquetUncompressed)
+		chunks.i64(5, int64(rows))
+		chunks.i64(6, int64(len(page)))
+		chunks.i64(7, int64(len(page)))
+		chunks.i64(9, offset)
+		chunks.endStruct()
+		chunks.endStruct()
+	}
+
+	meta := new(compactWriter)
+	meta.begin()
+	meta.i32(1, 1)
+	meta.list(2, thriftStruct, len(columns)+1)
+	meta.begin()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(columns)))
+	meta.endStruct()
+	for _, c := range columns {
+		meta.begin()
+		meta.i32(1, c.physicalType())
+		meta.i32(3, parquetOptional)
+		meta.binary(4, c.name)
+		if convertedType, ok := c.convertedType(); ok {
+			meta.i32(6, convertedType)
+		}
+		meta.endStruct()
+	}
+	meta.i64(3, int64(rows))
+	meta.list(4, thriftStruct, 1)
+	meta.begin()
+	meta.field(1, thriftList)
+	meta.buf.Write(chunks.buf.Bytes())
+	meta.i64(2, total)
+	meta.i64(3, int64(rows))
+	meta.endStruct()
+	meta.binary(6, parquetCreator)
+	meta.endStruct()
+
+	file.Write(meta.buf.Bytes())
+	binary.Write(file, binary.LittleEndian, uint32(meta.buf.Len()))
+	file.WriteString(parquetMagic)
+	return file.Bytes(), nil
+}
+
+func (c *column) physicalType() int32 {
+	switch c.kind {
+	case intKind, timeKind:
+		return parquetInt64
+	case floatKind:
+		return parquetDouble
+	case boolKind:
+		return parquetBoolean
+	}
+
+	return parquetByteArray
+}
+
+func (c *column) convertedType() (int32, bool) {
+	switch c.kind {
+	case stringKind:
+		return parquetUtf8, true
+	case timeKind:
+		return parquetTimestampMillis, true
+	}
+
+	return 0, false
+}
+
+// page returns the data page of the column with its header: the definition
+// levels of the values, bit-packed, followed by the values which are set.
+func (c *column) page() []byte {
+	levels := make([]byte, (len(c.values)+7)/8)
+	for i, value := range c.values {
+		if value != nil {
+			levels[i/8] |= 1 << uint(i%8)
+		}
+	}
+
+	encoded := new(compactWriter)
+	encoded.varint(uint64(len(levels))<<1 | 1)
+	encoded.buf.Write(levels)
+
+	body := new(bytes.Buffer)
+	binary.Write(body, binary.LittleEndian, uint32(encoded.buf.Len()))
+	body.Write(encoded.buf.Bytes())
+
+	var bits []byte
+	var set int
+	for _, value := range c.values {
+		switch v := value.(type) {
+		case int64:
+			binary.Write(body, binary.LittleEndian, v)
+		case float64:
+			binary.Write(body, binary.LittleEndian, math.Float64bits(v))
+		case time.Time:
+			binary.Write(body, binary.LittleEndian, v.UnixNano()/int64(time.Millisecond))
+		case bool:
+			if set%8 == 0 {
+				bits = append(bits, 0)
+			}
+			if v {
+				bits[set/8] |= 1 << uint(set%8)
+			}
+			set++
+		case string:
+			binary.Write(body, binary.LittleEndian, uint32(len(v)))
+			body.WriteString(v)
+		case []byte:
+			binary.Write(body, binary.LittleEndian, uint32(len(v)))
+			body.Write(v)
+		}
+	}
+	body.Write(bits)
+
+	header := new(compactWriter)
+	header.begin()
+	header.i32(1, parquetDataPage)
+	header.i32(2, int32(body.Len()))
+	header.i32(3, int32(body.Len()))
+	header.beginStruct(5)
+	header.i32(1, int32(len(c.values)))
+	header.i32(2, parquetPlain)
+	header.i32(3, parquetRle)
+	header.i32(4, parquetRle)
+	header.endStruct()
+	header.endStruct()
+
+	return append(header.buf.Bytes(), body.Bytes()...)
+}
+
+// compactWriter writes structs with the thrift compact protocol.
+type compactWriter struct {
+	buf bytes.Buffer
+	// last holds the id of the last field of each struct being written.
+	last []int16
+}
+
+// begin starts a top-level struct, or an element of a list of structs.
+func (w *compactWriter) begin() {
+	w.last = append(w.last, 0)
+}
+
+func (w *compactWriter) beginStruct(id int16) {
+	w.field(id, thriftStruct)
+	w.begin()
+}
+
+func (w *compactWriter) endStruct() {
+	w.buf.WriteByte(0)
+	w.last = w.last[:len(w.last)-1]
+}
+
+func (w *compactWriter) field(id int16, fieldType byte) {
+	last := &w.last[len(w.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		w.buf.WriteByte(fieldType)
+		w.varint(zigzag(int64(id)))
+	}
+	*last = id
+}
+
+func (w *compactWriter) i32(id int16, value int32) {
+	w.field(id, thriftI32)
+	w.varint(zigzag(int64(value)))
+}
+
+func (w *compactWriter) i64(id int16, value int64) {
+	w.field(id, thriftI64)
+	w.varint(zigzag(value))
+}
+
+func (w *compactWriter) binary(id int16, value string) {
+	w.field(id, thriftBinary)
+	w.string(value)
+}
+
+func (w *compactWriter) string(value string) {
+	w.varint(uint64(len(value)))
+	w.buf.WriteString(value)
+}
+
+// list starts a list field, followed by its elements.
+func (w *compactWriter) list(id int16, elemType byte, size int) {
+	w.field(id, thriftList)
+	w.listHeader(elemType, size)
+}
+
+func (w *compactWriter) listHeader(elemType byte, size int) {
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		w.buf.WriteByte(0xf0 | elemType)
+		w.varint(uint64(size))
+	}
+}
+
+func (w *compactWriter) varint(value uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	w.buf.Write(buf[:binary.PutUvarint(buf[:], value)])
+}
+
+func zigzag(value int64) uint64 {
+	return uint64(value<<1) ^ uint64(value>>63)
+}
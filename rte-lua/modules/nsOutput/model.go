@@ -50,3 +50,34 @@ type Map struct {
 	Zoom   int       `json:"zoom"`
 	Items  []*Item   `json:"items"`
 }
+
+// Chart is a plot of one or more series. Line, bar and scatter series are
+// points (X, Y), X defaulting to their index; histogram series are the
+// samples Y, counted in Bins; a heatmap is a single series of the values Z,
+// one row per Y and one column per X.
+type Chart struct {
+	Type   string    `json:"type"`
+	Title  string    `json:"title,omitempty"`
+	X      *Axis     `json:"x,omitempty"`
+	Y      *Axis     `json:"y,omitempty"`
+	Series []*Series `json:"series"`
+}
+
+// Axis describes an axis of a chart. The values of a time axis are unix
+// times or RFC3339 strings, rendered with Format when it is set.
+type Axis struct {
+	Label  string   `json:"label,omitempty"`
+	Type   string   `json:"type,omitempty"`
+	Format string   `json:"format,omitempty"`
+	Min    *float64 `json:"min,omitempty"`
+	Max    *float64 `json:"max,omitempty"`
+}
+
+type Series struct {
+	Name  string          `json:"name,omitempty"`
+	Color string          `json:"color,omitempty"`
+	X     []interface{}   `json:"x,omitempty"`
+	Y     []interface{}   `json:"y,omitempty"`
+	Z     [][]interface{} `json:"z,omitempty"`
+	Bins  int             `json:"bins,omitempty"`
+}
@@ -32,11 +32,17 @@ const (
 	_MAP_DIRECT   = "mapDirect"
 	_HTML         = "html"
 	_HTML_DIRECT  = "htmlDirect"
+	_CHART        = "chart"
+	_CHART_DIRECT = "chartDirect"
+	_EXPORT       = "export"
 	_TABLE_TO_CSV = "tableToCsv"
 )
 
 var (
 	NsOutputPrintLimit, _ = config.GetInt("NS_OUTPUT_PRINT_LIMIT", 10000)
+	// NsOutputChartPointLimit is the number of points of all the series of
+	// a chart.
+	NsOutputChartPointLimit, _ = config.GetInt("NS_OUTPUT_CHART_POINT_LIMIT", 10000)
 )
 
 type NsOutputModule struct {
@@ -44,6 +50,15 @@ type NsOutputModule struct {
 	Rolling int
 	Stdout  []string
 	Result  string
+
+	// Objects stores the exported tables, when nsObject is available to
+	// the snippet.
+	Objects ObjectStore
+}
+
+// ObjectStore stores files in the object store of the account.
+type ObjectStore interface {
+	Upload(bucket string, file string, data []byte, contentType string) error
 }
 
 func NewNsOutputModule() *NsOutputModule {
@@ -65,6 +80,9 @@ func (nsOutput *NsOutputModule) Loader(L *lua.LState) int {
 		_HTML:         nsOutput.htmlApi,
 		_HTML_DIRECT:  nsOutput.htmlDirectApi,
 		_TABLE_TO_CSV: nsOutput.tableToCsvApi,
+		_CHART:        nsOutput.chartApi,
+		_CHART_DIRECT: nsOutput.chartDirectApi,
+		_EXPORT:       nsOutput.exportApi,
 	}
 	t := L.NewTable()
 	L.SetFuncs(t, api)
@@ -30,6 +30,9 @@ var (
 	MapDirectCounter   = nsOutput.NewCounter("MapDirect")
 	HTMLCounter        = nsOutput.NewCounter("HTML")
 	HTMLDirectCounter  = nsOutput.NewCounter("HTMLDirect")
+	ChartCounter       = nsOutput.NewCounter("Chart")
+	ChartDirectCounter = nsOutput.NewCounter("ChartDirect")
+	ExportCounter      = nsOutput.NewCounter("Export")
 	TableToCsv         = nsOutput.NewCounter("TableToCsv")
 	ErrTableToCsv      = nsOutput.NewCounter("ErrTableToCsv")
 	ErrPrint           = nsOutput.NewCounter("ErrPrint")
@@ -42,4 +45,7 @@ var (
 	ErrMapDirect       = nsOutput.NewCounter("ErrMapDirect")
 	ErrHTML            = nsOutput.NewCounter("ErrHTML")
 	ErrHTMLDirect      = nsOutput.NewCounter("ErrHTMLDirect")
+	ErrChart           = nsOutput.NewCounter("ErrChart")
+	ErrChartDirect     = nsOutput.NewCounter("ErrChartDirect")
+	ErrExport          = nsOutput.NewCounter("ErrExport")
 )
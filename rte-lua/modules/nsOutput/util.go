@@ -86,6 +86,11 @@ func (nsOutput *NsOutputModule) convertParameters(lParameters []interface{}) ([]
 }
 
 func (nsOutput *NsOutputModule) tableToCsv(table *Table) (string, error) {
+	return FormatTableCsv(table)
+}
+
+// FormatTableCsv formats a table as comma separated values with a header line.
+func FormatTableCsv(table *Table) (string, error) {
 	if table.Columns == nil || table.Rows == nil {
 		return "", errors.New("malformed input table")
 	}
@@ -109,12 +114,28 @@ func (nsOutput *NsOutputModule) generateFromTable(data *lua.LTable, value interf
 		return "", err
 	}
 
+	return Generate(value)
+}
+
+func (nsOutput *NsOutputModule) generateOutput(dataType string, data interface{}) (string, error) {
+	return GenerateOutput(dataType, data)
+}
+
+// Generate returns the output document of a *Value, *Map, *Table or
+// *Chart. It is shared by the snippet runtimes so they produce the same
+// documents.
+func Generate(value interface{}) (string, error) {
 	var dataType string
 	switch converted := value.(type) {
 	case *Value:
 		dataType = "application/vnd.vz.value"
 	case *Map:
 		dataType = "application/vnd.vz.map"
+	case *Chart:
+		dataType = "application/vnd.vz.chart"
+		if err := converted.Validate(); err != nil {
+			return "", err
+		}
 	case *Table:
 		dataType = "application/vnd.vz.table"
 		for i := 0; i < len(converted.Rows); i++ {
@@ -131,7 +152,7 @@ func (nsOutput *NsOutputModule) generateFromTable(data *lua.LTable, value interf
 		return "", fmt.Errorf("Unknown data type")
 	}
 
-	output, err := nsOutput.generateOutput(dataType, value)
+	output, err := GenerateOutput(dataType, value)
 	if err != nil {
 		return "", err
 	}
@@ -139,7 +160,8 @@ func (nsOutput *NsOutputModule) generateFromTable(data *lua.LTable, value interf
 	return output, nil
 }
 
-func (nsOutput *NsOutputModule) generateOutput(dataType string, data interface{}) (string, error) {
+// GenerateOutput returns the output document of the given type.
+func GenerateOutput(dataType string, data interface{}) (string, error) {
 	buf := new(bytes.Buffer)
 	enc := json.NewEncoder(buf)
 	enc.SetEscapeHTML(false)
@@ -151,6 +173,20 @@ func (nsOutput *NsOutputModule) generateOutput(dataType string, data interface{}
 	return buf.String(), nil
 }
 
+// tableFromOutput reads the table of a table document.
+func tableFromOutput(document string, table *Table) error {
+	output := &Output{Content: table}
+	if err := json.Unmarshal([]byte(document), output); err != nil {
+		return err
+	}
+
+	if output.Type != "application/vnd.vz.table" {
+		return fmt.Errorf("%s is not a table", output.Type)
+	}
+
+	return nil
+}
+
 func (nsOutput *NsOutputModule) makeErrorMessage(msg string) string {
 	return NS_OUTPUT_ERROR + msg
 }
@@ -181,6 +217,12 @@ func (nsOutput *NsOutputModule) recordErrorStats(timer *stats.Timer, context str
 		ErrHTML.Incr()
 	case _HTML_DIRECT:
 		ErrHTMLDirect.Incr()
+	case _CHART:
+		ErrChart.Incr()
+	case _CHART_DIRECT:
+		ErrChartDirect.Incr()
+	case _EXPORT:
+		ErrExport.Incr()
 	case _TABLE_TO_CSV:
 		ErrTableToCsv.Incr()
 	}
@@ -71,21 +71,7 @@ func (nsQL *NsQLModule) connect(L *lua.LState) int {
 		return nsQL.error(L, err.Error(), timer, CONNECT, 2)
 	}
 
-	processing := &compiler.Processing{
-		Backend: source.Backend,
-		DataSource: &compiler.DataSource{
-			Protocol: source.Protocol,
-			Connection: &compiler.Connection{
-				Host:     source.Host,
-				Port:     source.Port,
-				Username: source.Username,
-				Password: source.Password,
-				Version:  source.Version,
-			},
-		},
-	}
-
-	compiler, err := getCompiler(processing)
+	compiler, err := NewCompiler(&source)
 	if err != nil {
 		return nsQL.error(L, err.Error(), timer, CONNECT, 2)
 	}
@@ -165,21 +151,7 @@ func (nsQL *NsQLModule) queryDirect(L *lua.LState) int {
 		return nsQL.error(L, err.Error(), timer, QUERY_DIRECT, 2)
 	}
 
-	processing := &compiler.Processing{
-		Backend: source.Backend,
-		DataSource: &compiler.DataSource{
-			Protocol: source.Protocol,
-			Connection: &compiler.Connection{
-				Host:     source.Host,
-				Port:     source.Port,
-				Username: source.Username,
-				Password: source.Password,
-				Version:  source.Version,
-			},
-		},
-	}
-
-	comp, err := getCompiler(processing)
+	comp, err := NewCompiler(&source)
 	if err != nil {
 		return nsQL.error(L, err.Error(), timer, QUERY_DIRECT, 2)
 	}
@@ -26,6 +26,25 @@ import (
 	"github.com/lavaorg/northstar/rte-lua/modules/nsQL/constants"
 )
 
+// NewCompiler returns the compiler running queries against the given source.
+func NewCompiler(source *compiler.Source) (compiler.Compiler, error) {
+	processing := &compiler.Processing{
+		Backend: source.Backend,
+		DataSource: &compiler.DataSource{
+			Protocol: source.Protocol,
+			Connection: &compiler.Connection{
+				Host:     source.Host,
+				Port:     source.Port,
+				Username: source.Username,
+				Password: source.Password,
+				Version:  source.Version,
+			},
+		},
+	}
+
+	return getCompiler(processing)
+}
+
 func getCompiler(processing *compiler.Processing) (compiler.Compiler, error) {
 	if processing.DataSource.Protocol == "" || processing.DataSource.Connection.Host == "" ||
 		processing.DataSource.Connection.Port == "" {
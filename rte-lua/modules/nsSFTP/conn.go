@@ -16,6 +16,10 @@ limitations under the License.
 package nsSFTP
 
 import (
+	"os"
+	"path"
+
+	"github.com/lavaorg/northstar/rte-lua/modules/remotefs"
 	"github.com/pkg/sftp"
 	"golang.org/x/crypto/ssh"
 )
@@ -30,3 +34,64 @@ type Clients struct {
 	SSH  *ssh.Client
 	SFTP *sftp.Client
 }
+
+// The clients implement the remote file system shared with nsFTP.
+
+func (c *Clients) List(dir string) ([]*remotefs.FileInfo, error) {
+	infos, err := c.SFTP.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*remotefs.FileInfo, 0, len(infos))
+	for _, fi := range infos {
+		files = append(files, remotefs.FromFileInfo(path.Join(dir, fi.Name()), fi))
+	}
+
+	return files, nil
+}
+
+func (c *Clients) Stat(p string) (*remotefs.FileInfo, error) {
+	fi, err := c.SFTP.Stat(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, remotefs.ErrNotExist
+		}
+		return nil, err
+	}
+
+	return remotefs.FromFileInfo(p, fi), nil
+}
+
+func (c *Clients) Retrieve(p string) ([]byte, error) {
+	file, err := c.SFTP.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return remotefs.ReadAll(file)
+}
+
+func (c *Clients) Store(p string, data []byte) error {
+	file, err := c.SFTP.Create(p)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(data)
+	return err
+}
+
+func (c *Clients) Mkdir(p string) error {
+	return c.SFTP.Mkdir(p)
+}
+
+func (c *Clients) Rename(from string, to string) error {
+	return c.SFTP.Rename(from, to)
+}
+
+func (c *Clients) Delete(p string) error {
+	return c.SFTP.Remove(p)
+}
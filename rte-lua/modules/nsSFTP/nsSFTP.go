@@ -21,6 +21,8 @@ import (
 	"github.com/lavaorg/lrtx/luaext/gluamapper"
 	"github.com/lavaorg/lrtx/mlog"
 	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/rte-lua/modules/remotefs"
+	"github.com/lavaorg/northstar/rte/rtepub"
 	"github.com/pkg/sftp"
 	"golang.org/x/crypto/ssh"
 )
@@ -34,11 +36,22 @@ var (
 )
 
 type NsSFTPModule struct {
-	Limit int
+	Limit   int
+	Policy  *rtepub.Policy
+	Objects remotefs.ObjectStore
+	Synced  remotefs.SyncState
 }
 
-func NewNsSFTPModule() *NsSFTPModule {
-	return &NsSFTPModule{Limit: NsSFTPConnectionLimit}
+// NewNsSFTPModule creates the module. Files are retrieved and synced into the
+// objects, the synced files recorded in the sync state. Both are nil when
+// nsObject is not available.
+func NewNsSFTPModule(policy *rtepub.Policy,
+	objects remotefs.ObjectStore,
+	synced remotefs.SyncState) *NsSFTPModule {
+	return &NsSFTPModule{Limit: NsSFTPConnectionLimit,
+		Policy:  policy,
+		Objects: objects,
+		Synced:  synced}
 }
 
 func (nsSFTP *NsSFTPModule) Loader(L *lua.LState) int {
@@ -64,6 +77,12 @@ func (nsSFTP *NsSFTPModule) connect(L *lua.LState) int {
 		return nsSFTP.error(L, err.Error(), nil, "connect")
 	}
 
+	if err := nsSFTP.Policy.CheckHost(destination.HostPort); err != nil {
+		ErrConnect.Incr()
+		L.RaiseError(nsSFTP.makeErrorMessage(err.Error()))
+		return 0
+	}
+
 	config := &ssh.ClientConfig{
 		User:            destination.User,
 		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
@@ -91,6 +110,13 @@ func (nsSFTP *NsSFTPModule) connect(L *lua.LState) int {
 		"mkdir":      nsSFTP.mkdir,
 		"disconnect": nsSFTP.disconnect,
 	}
+	shared := &remotefs.Methods{Objects: nsSFTP.Objects,
+		Synced: nsSFTP.Synced,
+		Error: func(L *lua.LState, err string, method string) int {
+			return nsSFTP.error(L, err, nil, method)
+		},
+		Done: done}
+	shared.Register(methods)
 
 	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), methods))
 
@@ -24,8 +24,22 @@ var (
 	Disconnect    = NsSFTP.NewCounter("Disconnect")
 	Mkdir         = NsSFTP.NewCounter("Mkdir")
 	Store         = NsSFTP.NewCounter("Store")
+	List          = NsSFTP.NewCounter("List")
+	Stat          = NsSFTP.NewCounter("Stat")
+	Retrieve      = NsSFTP.NewCounter("Retrieve")
+	Rename        = NsSFTP.NewCounter("Rename")
+	Delete        = NsSFTP.NewCounter("Delete")
+	Glob          = NsSFTP.NewCounter("Glob")
+	Sync          = NsSFTP.NewCounter("Sync")
 	ErrConnect    = NsSFTP.NewCounter("ErrConnect")
 	ErrDisconnect = NsSFTP.NewCounter("ErrDisconnect")
 	ErrMkdir      = NsSFTP.NewCounter("ErrMkdir")
 	ErrStore      = NsSFTP.NewCounter("ErrStore")
+	ErrList       = NsSFTP.NewCounter("ErrList")
+	ErrStat       = NsSFTP.NewCounter("ErrStat")
+	ErrRetrieve   = NsSFTP.NewCounter("ErrRetrieve")
+	ErrRename     = NsSFTP.NewCounter("ErrRename")
+	ErrDelete     = NsSFTP.NewCounter("ErrDelete")
+	ErrGlob       = NsSFTP.NewCounter("ErrGlob")
+	ErrSync       = NsSFTP.NewCounter("ErrSync")
 )
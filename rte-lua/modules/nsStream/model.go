@@ -19,6 +19,16 @@ package nsStream
 type Source struct {
 	Name       string
 	Connection interface{}
+	Format     *Format
+}
+
+type Format struct {
+	Type      string
+	Schema    string
+	SchemaId  int
+	Message   string
+	Columns   []string
+	Delimiter string
 }
 
 type Function struct {
@@ -27,6 +37,13 @@ type Function struct {
 	Evaluator  interface{}
 }
 
+type Autoscale struct {
+	MinWorkers   int
+	MaxWorkers   int
+	ScaleUpLag   int64
+	ScaleDownLag int64
+}
+
 type StreamJob struct {
 	JobId        string
 	InvocationId string
@@ -34,4 +51,6 @@ type StreamJob struct {
 	Source       Source
 	Functions    []Function
 	Description  string
+	Autoscale    *Autoscale
+	Output       *Format
 }
@@ -33,11 +33,17 @@ const (
 	CREATE          = "create"
 	START           = "start"
 	STOP            = "stop"
+	PAUSE           = "pause"
+	RESUME          = "resume"
+	UPDATE          = "update"
 	LIMIT           = "limit"
 	FOREACH         = "foreach"
 	FILTER          = "filter"
 	MAP             = "map"
 	FOLD            = "fold"
+	AUTOSCALE       = "autoscale"
+	DECODE          = "decode"
+	ENCODE          = "encode"
 )
 
 type NsStreamModule struct {
@@ -70,13 +76,19 @@ func (nsStream *NsStreamModule) createApi(L *lua.LState) int {
 
 	mt := L.NewTypeMetatable(NS_STREAM_TYPE)
 	methods := map[string]lua.LGFunction{
-		START:   nsStream.startApi,
-		STOP:    nsStream.stopApi,
-		LIMIT:   nsStream.limitApi,
-		FOREACH: nsStream.foreachApi,
-		FILTER:  nsStream.filterApi,
-		MAP:     nsStream.mapApi,
-		FOLD:    nsStream.foldApi,
+		START:     nsStream.startApi,
+		STOP:      nsStream.stopApi,
+		PAUSE:     nsStream.pauseApi,
+		RESUME:    nsStream.resumeApi,
+		UPDATE:    nsStream.updateApi,
+		LIMIT:     nsStream.limitApi,
+		FOREACH:   nsStream.foreachApi,
+		FILTER:    nsStream.filterApi,
+		MAP:       nsStream.mapApi,
+		FOLD:      nsStream.foldApi,
+		AUTOSCALE: nsStream.autoscaleApi,
+		DECODE:    nsStream.decodeApi,
+		ENCODE:    nsStream.encodeApi,
 	}
 	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), methods))
 
@@ -102,13 +114,17 @@ func (nsStream *NsStreamModule) startApi(L *lua.LState) int {
 	}
 
 	externalStreamJob := &model.StreamJob{InvocationId: streamJob.InvocationId, Memory: streamJob.Memory,
-		Source: model.Source{Name: streamJob.Source.Name, Connection: streamJob.Source.Connection}}
-	for _, function := range streamJob.Functions {
-		externalStreamJob.Functions = append(externalStreamJob.Functions,
-			model.Function{Name: function.Name,
-				Parameters: function.Parameters,
-				Evaluator:  function.Evaluator})
+		Source: model.Source{Name: streamJob.Source.Name,
+			Connection: streamJob.Source.Connection,
+			Format:     toExternalFormat(streamJob.Source.Format)},
+		Output: toExternalFormat(streamJob.Output)}
+	if streamJob.Autoscale != nil {
+		externalStreamJob.Autoscale = &model.Autoscale{MinWorkers: streamJob.Autoscale.MinWorkers,
+			MaxWorkers:   streamJob.Autoscale.MaxWorkers,
+			ScaleUpLag:   streamJob.Autoscale.ScaleUpLag,
+			ScaleDownLag: streamJob.Autoscale.ScaleDownLag}
 	}
+	externalStreamJob.Functions = toExternalFunctions(streamJob.Functions)
 
 	streamClient, err := client.NewStreamClient()
 	if err != nil {
@@ -150,6 +166,85 @@ func (nsStream *NsStreamModule) stopApi(L *lua.LState) int {
 
 }
 
+// pauseApi stops the workers of a started stream from consuming, keeping
+// their position and state until resumed.
+func (nsStream *NsStreamModule) pauseApi(L *lua.LState) int {
+	_, streamJob, err := nsStream.getStream(L)
+	if err != nil {
+		return nsStream.error(L, err.Error(), nil, PAUSE, 1)
+	}
+
+	streamClient, err := client.NewStreamClient()
+	if err != nil {
+		return nsStream.error(L, err.Error(), nil, PAUSE, 1)
+	}
+
+	if mErr := streamClient.PauseJob(nsStream.AccountId, streamJob.JobId); mErr != nil {
+		mlog.Error("Failed to pause job %v: %v", streamJob.JobId, mErr.Error())
+		return nsStream.error(L, mErr.Error(), nil, PAUSE, 1)
+	}
+
+	Pause.Incr()
+	return 0
+}
+
+func (nsStream *NsStreamModule) resumeApi(L *lua.LState) int {
+	_, streamJob, err := nsStream.getStream(L)
+	if err != nil {
+		return nsStream.error(L, err.Error(), nil, RESUME, 1)
+	}
+
+	streamClient, err := client.NewStreamClient()
+	if err != nil {
+		return nsStream.error(L, err.Error(), nil, RESUME, 1)
+	}
+
+	if mErr := streamClient.ResumeJob(nsStream.AccountId, streamJob.JobId); mErr != nil {
+		mlog.Error("Failed to resume job %v: %v", streamJob.JobId, mErr.Error())
+		return nsStream.error(L, mErr.Error(), nil, RESUME, 1)
+	}
+
+	Resume.Incr()
+	return 0
+}
+
+// updateApi replaces the functions chain and output format of a started
+// stream in place. The chain is taken from the stream given as argument, or
+// from the stream itself when called as stream:update().
+func (nsStream *NsStreamModule) updateApi(L *lua.LState) int {
+	_, streamJob, err := nsStream.getStream(L)
+	if err != nil {
+		return nsStream.error(L, err.Error(), nil, UPDATE, 1)
+	}
+
+	chain := streamJob
+	if L.GetTop() > 1 {
+		other, ok := L.CheckUserData(2).Value.(*StreamJob)
+		if !ok {
+			return nsStream.error(L, "invalid stream", nil, UPDATE, 1)
+		}
+		chain = other
+	}
+
+	update := &model.JobUpdate{Functions: toExternalFunctions(chain.Functions),
+		Output: toExternalFormat(chain.Output)}
+
+	streamClient, err := client.NewStreamClient()
+	if err != nil {
+		return nsStream.error(L, err.Error(), nil, UPDATE, 1)
+	}
+
+	if mErr := streamClient.UpdateJob(nsStream.AccountId, streamJob.JobId, update); mErr != nil {
+		mlog.Error("Failed to update job %v: %v", streamJob.JobId, mErr.Error())
+		return nsStream.error(L, mErr.Error(), nil, UPDATE, 1)
+	}
+
+	streamJob.Functions = chain.Functions
+	streamJob.Output = chain.Output
+	Update.Incr()
+	return 0
+}
+
 func (nsStream *NsStreamModule) limitApi(L *lua.LState) int {
 	stream, streamJob, err := nsStream.getStream(L)
 	if err != nil {
@@ -205,6 +300,103 @@ func (nsStream *NsStreamModule) foldApi(L *lua.LState) int {
 	return 1
 }
 
+// autoscaleApi lets the stream run between min and max workers, optionally
+// with the per worker lag thresholds for scaling up and down.
+func (nsStream *NsStreamModule) autoscaleApi(L *lua.LState) int {
+	stream, streamJob, err := nsStream.getStream(L)
+	if err != nil {
+		nsStream.panic(err.Error(), nil, START)
+	}
+
+	autoscale := &Autoscale{MinWorkers: L.CheckInt(2),
+		MaxWorkers:   L.CheckInt(3),
+		ScaleUpLag:   int64(L.OptInt(4, 0)),
+		ScaleDownLag: int64(L.OptInt(5, 0))}
+	if autoscale.MinWorkers < 1 || autoscale.MaxWorkers < autoscale.MinWorkers {
+		nsStream.panic("invalid number of workers", nil, START)
+	}
+
+	streamJob.Autoscale = autoscale
+	stream.Value = streamJob
+	L.Push(stream)
+	return 1
+}
+
+// decodeApi sets the format of the source messages, e.g.
+// stream:decode({type = "csv", columns = {"id", "value"}}).
+func (nsStream *NsStreamModule) decodeApi(L *lua.LState) int {
+	stream, streamJob, err := nsStream.getStream(L)
+	if err != nil {
+		nsStream.panic(err.Error(), nil, START)
+	}
+
+	format, err := nsStream.checkFormat(L)
+	if err != nil {
+		nsStream.panic(err.Error(), nil, START)
+	}
+
+	streamJob.Source.Format = format
+	stream.Value = streamJob
+	L.Push(stream)
+	return 1
+}
+
+// encodeApi sets the format in which the value produced by the functions
+// chain is sent with the stream output.
+func (nsStream *NsStreamModule) encodeApi(L *lua.LState) int {
+	stream, streamJob, err := nsStream.getStream(L)
+	if err != nil {
+		nsStream.panic(err.Error(), nil, START)
+	}
+
+	format, err := nsStream.checkFormat(L)
+	if err != nil {
+		nsStream.panic(err.Error(), nil, START)
+	}
+
+	streamJob.Output = format
+	stream.Value = streamJob
+	L.Push(stream)
+	return 1
+}
+
+func (nsStream *NsStreamModule) checkFormat(L *lua.LState) (*Format, error) {
+	format := new(Format)
+	if err := gluamapper.Map(L.CheckTable(2), format); err != nil {
+		return nil, err
+	}
+
+	if err := toExternalFormat(format).Validate(); err != nil {
+		return nil, err
+	}
+
+	return format, nil
+}
+
+func toExternalFormat(format *Format) *model.Format {
+	if format == nil {
+		return nil
+	}
+
+	return &model.Format{Type: format.Type,
+		Schema:    format.Schema,
+		SchemaId:  format.SchemaId,
+		Message:   format.Message,
+		Columns:   format.Columns,
+		Delimiter: format.Delimiter}
+}
+
+func toExternalFunctions(functions []Function) []model.Function {
+	var external []model.Function
+	for _, function := range functions {
+		external = append(external, model.Function{Name: function.Name,
+			Parameters: function.Parameters,
+			Evaluator:  function.Evaluator})
+	}
+
+	return external
+}
+
 func (nsStream *NsStreamModule) getStream(L *lua.LState) (*lua.LUserData, *StreamJob, error) {
 	stream := L.CheckUserData(1)
 	sj, ok := stream.Value.(*StreamJob)
@@ -280,6 +472,12 @@ func (nsStream *NsStreamModule) recordErrorStats(timer *stats.Timer, context str
 		ErrStart.Incr()
 	case STOP:
 		ErrStop.Incr()
+	case PAUSE:
+		ErrPause.Incr()
+	case RESUME:
+		ErrResume.Incr()
+	case UPDATE:
+		ErrUpdate.Incr()
 	}
 }
 
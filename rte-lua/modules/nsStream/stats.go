@@ -24,8 +24,14 @@ var (
 	Destroy    = NsStream.NewCounter("Destroy")
 	Start      = NsStream.NewCounter("Start")
 	Stop       = NsStream.NewCounter("Stop")
+	Pause      = NsStream.NewCounter("Pause")
+	Resume     = NsStream.NewCounter("Resume")
+	Update     = NsStream.NewCounter("Update")
 	ErrCreate  = NsStream.NewCounter("ErrCreate")
 	ErrDestroy = NsStream.NewCounter("ErrDestroy")
 	ErrStart   = NsStream.NewCounter("ErrStart")
 	ErrStop    = NsStream.NewCounter("ErrStop")
+	ErrPause   = NsStream.NewCounter("ErrPause")
+	ErrResume  = NsStream.NewCounter("ErrResume")
+	ErrUpdate  = NsStream.NewCounter("ErrUpdate")
 )
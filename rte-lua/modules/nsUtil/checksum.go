@@ -0,0 +1,106 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsUtil
+
+import (
+	"fmt"
+	"hash/crc32"
+)
+
+// Checksum algorithms. The CRC parameters follow the usual catalogue names,
+// e.g. crc16-ccitt is CRC-16/CCITT-FALSE and crc16-modbus is CRC-16/MODBUS.
+const (
+	CRC8         = "crc8"
+	CRC16_CCITT  = "crc16-ccitt"
+	CRC16_XMODEM = "crc16-xmodem"
+	CRC16_MODBUS = "crc16-modbus"
+	CRC32        = "crc32"
+	SUM8         = "sum8"
+	XOR8         = "xor8"
+)
+
+func ComputeChecksum(algorithm string, data []byte) (uint64, error) {
+	switch algorithm {
+	case CRC8:
+		return uint64(crc8(data)), nil
+	case CRC16_CCITT:
+		return uint64(crc16(data, 0xFFFF)), nil
+	case CRC16_XMODEM:
+		return uint64(crc16(data, 0)), nil
+	case CRC16_MODBUS:
+		return uint64(crc16Modbus(data)), nil
+	case CRC32:
+		return uint64(crc32.ChecksumIEEE(data)), nil
+	case SUM8:
+		var sum byte
+		for _, b := range data {
+			sum += b
+		}
+		return uint64(sum), nil
+	case XOR8:
+		var sum byte
+		for _, b := range data {
+			sum ^= b
+		}
+		return uint64(sum), nil
+	}
+	return 0, fmt.Errorf("unknown checksum algorithm %s", algorithm)
+}
+
+func crc8(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func crc16(data []byte, crc uint16) uint16 {
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func crc16Modbus(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
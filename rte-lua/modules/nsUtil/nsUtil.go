@@ -19,6 +19,9 @@ package nsUtil
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/lavaorg/lrtx/luaext/gluamapper"
 	"github.com/lavaorg/lrtx/stats"
 	"github.com/lavaorg/lua"
 	"github.com/lavaorg/northstar/rte-lua/util"
@@ -28,6 +31,9 @@ import (
 const (
 	NS_UTIL_ERROR        = "nsUtil error: "
 	READ_FROM_BYTE_ARRAY = "readFromByteArray"
+	DECODE               = "decode"
+	ENCODE               = "encode"
+	CHECKSUM             = "checksum"
 )
 
 type NsUtilModule struct{}
@@ -39,6 +45,9 @@ func NewNsUtilModule() *NsUtilModule {
 func (nsUtil *NsUtilModule) Loader(L *lua.LState) int {
 	api := map[string]lua.LGFunction{
 		READ_FROM_BYTE_ARRAY: nsUtil.readFromByteArray,
+		DECODE:               nsUtil.decode,
+		ENCODE:               nsUtil.encode,
+		CHECKSUM:             nsUtil.checksum,
 	}
 	t := L.NewTable()
 	L.SetFuncs(t, api)
@@ -118,6 +127,124 @@ func (nsUtil *NsUtilModule) readFromByteArray(L *lua.LState) int {
 	return 1
 }
 
+func (nsUtil *NsUtilModule) decode(L *lua.LState) int {
+	var schema Schema
+	if err := gluamapper.Map(L.CheckTable(1), &schema); err != nil {
+		return nsUtil.error(L, err.Error(), nil, DECODE, 2)
+	}
+
+	data, err := byteData(L.Get(2))
+	if err != nil {
+		return nsUtil.error(L, err.Error(), nil, DECODE, 2)
+	}
+
+	record, offset, err := DecodeRecord(&schema, data, L.OptInt(3, 0))
+	if err != nil {
+		return nsUtil.error(L, err.Error(), nil, DECODE, 2)
+	}
+
+	result, err := util.ToLua(L, record)
+	if err != nil {
+		return nsUtil.error(L, err.Error(), nil, DECODE, 2)
+	}
+
+	Decode.Incr()
+	L.Push(result)
+	L.Push(lua.LNumber(offset))
+	return 2
+}
+
+func (nsUtil *NsUtilModule) encode(L *lua.LState) int {
+	var schema Schema
+	if err := gluamapper.Map(L.CheckTable(1), &schema); err != nil {
+		return nsUtil.error(L, err.Error(), nil, ENCODE, 2)
+	}
+
+	values, err := util.FromLua(L.CheckTable(2))
+	if err != nil {
+		return nsUtil.error(L, err.Error(), nil, ENCODE, 2)
+	}
+
+	record, ok := stringKeys(values).(map[string]interface{})
+	if !ok {
+		return nsUtil.error(L, "values must be a table of fields", nil, ENCODE, 2)
+	}
+
+	data, err := EncodeRecord(&schema, record)
+	if err != nil {
+		return nsUtil.error(L, err.Error(), nil, ENCODE, 2)
+	}
+
+	var result lua.LValue
+	switch L.OptString(3, "bytes") {
+	case "bytes":
+		if result, err = util.ToLua(L, data); err != nil {
+			return nsUtil.error(L, err.Error(), nil, ENCODE, 2)
+		}
+	case "string":
+		result = lua.LString(data)
+	default:
+		return nsUtil.error(L, "unknown output format", nil, ENCODE, 2)
+	}
+
+	Encode.Incr()
+	L.Push(result)
+	return 1
+}
+
+func (nsUtil *NsUtilModule) checksum(L *lua.LState) int {
+	data, err := byteData(L.Get(1))
+	if err != nil {
+		return nsUtil.error(L, err.Error(), nil, CHECKSUM, 2)
+	}
+
+	sum, err := ComputeChecksum(L.OptString(2, CRC32), data)
+	if err != nil {
+		return nsUtil.error(L, err.Error(), nil, CHECKSUM, 2)
+	}
+
+	Checksum.Incr()
+	L.Push(lua.LNumber(sum))
+	return 1
+}
+
+// byteData accepts binary data as a string or as a table of byte values.
+func byteData(value lua.LValue) ([]byte, error) {
+	switch converted := value.(type) {
+	case lua.LString:
+		return []byte(string(converted)), nil
+	case *lua.LTable:
+		data := make([]byte, converted.MaxN())
+		for i := range data {
+			b, ok := converted.RawGetInt(i + 1).(lua.LNumber)
+			if !ok || b < 0 || b > 255 || b != lua.LNumber(int(b)) {
+				return nil, errors.New("invalid data")
+			}
+			data[i] = byte(b)
+		}
+		return data, nil
+	}
+	return nil, errors.New("data must be a string or a table of bytes")
+}
+
+// stringKeys converts the tables returned by util.FromLua into records
+// keyed by field name.
+func stringKeys(value interface{}) interface{} {
+	switch converted := value.(type) {
+	case map[interface{}]interface{}:
+		record := make(map[string]interface{}, len(converted))
+		for k, v := range converted {
+			record[fmt.Sprint(k)] = stringKeys(v)
+		}
+		return record
+	case []interface{}:
+		for i, element := range converted {
+			converted[i] = stringKeys(element)
+		}
+	}
+	return value
+}
+
 func (nsUtil *NsUtilModule) makeErrorMessage(msg string) string {
 	return NS_UTIL_ERROR + msg
 }
@@ -130,6 +257,12 @@ func (nsUtil *NsUtilModule) recordErrorStats(timer *stats.Timer, context string)
 	switch context {
 	case READ_FROM_BYTE_ARRAY:
 		ErrReadFromByteArray.Incr()
+	case DECODE:
+		ErrDecode.Incr()
+	case ENCODE:
+		ErrEncode.Incr()
+	case CHECKSUM:
+		ErrChecksum.Incr()
 	}
 }
 
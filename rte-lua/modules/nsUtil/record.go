@@ -0,0 +1,752 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsUtil
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	STRUCT    = "struct"
+	STRING    = "string"
+	BYTES     = "bytes"
+	BITS      = "bits"
+	PAD       = "pad"
+	BOOL      = "bool"
+	REMAINING = "*"
+)
+
+var scalarSizes = map[string]int{
+	"uint8":   1,
+	"int8":    1,
+	"bool":    1,
+	"uint16":  2,
+	"int16":   2,
+	"uint32":  4,
+	"int32":   4,
+	"float32": 4,
+	"uint64":  8,
+	"int64":   8,
+	"float64": 8,
+}
+
+// Schema is the layout of a packed binary record. Endian is "big", the
+// default, or "little" and can be overridden by any field.
+type Schema struct {
+	Endian string
+	Fields []*Field
+}
+
+// Field is one member of a record. Count makes the field an array and
+// Length sizes string, bytes and pad fields; both take a fixed number, the
+// name of an earlier integer field or "*" for the rest of the data. Prefix
+// is the integer type holding the length of a length-prefixed string or
+// bytes field. A bits field reads an unsigned integer of Size bytes and
+// stores its Bits in the enclosing record.
+type Field struct {
+	Name   string
+	Type   string
+	Endian string
+	Count  interface{}
+	Length interface{}
+	Prefix string
+	Size   int
+	Bits   []*Bit
+	Fields []*Field
+}
+
+// Bit is a run of bits in a bits field, listed from the most significant
+// bit down. Type "bool" decodes the run as a boolean.
+type Bit struct {
+	Name  string
+	Width int
+	Type  string
+}
+
+func (s *Schema) Validate() error {
+	if _, err := byteOrder(s.Endian, nil); err != nil {
+		return err
+	}
+	return validateFields(s.Fields)
+}
+
+func validateFields(fields []*Field) error {
+	if len(fields) == 0 {
+		return errors.New("no fields")
+	}
+
+	for _, field := range fields {
+		if err := field.validate(); err != nil {
+			return fmt.Errorf("field %s: %v", field.name(), err)
+		}
+	}
+	return nil
+}
+
+func (f *Field) validate() error {
+	if _, err := byteOrder(f.Endian, nil); err != nil {
+		return err
+	}
+
+	if f.Name == "" && f.Type != BITS && f.Type != PAD {
+		return errors.New("missing name")
+	}
+
+	if err := validSize(f.Count); err != nil {
+		return fmt.Errorf("invalid count: %v", err)
+	}
+
+	switch f.Type {
+	case STRUCT:
+		return validateFields(f.Fields)
+	case STRING, BYTES:
+		if f.Prefix != "" {
+			if !isInteger(f.Prefix) {
+				return fmt.Errorf("invalid prefix type %s", f.Prefix)
+			}
+			if f.Length != nil {
+				return errors.New("length and prefix are exclusive")
+			}
+			return nil
+		}
+		if f.Length == nil {
+			return errors.New("missing length or prefix")
+		}
+		return validSize(f.Length)
+	case PAD:
+		if f.Count != nil {
+			return errors.New("pad cannot have a count")
+		}
+		if f.Length == nil || f.Length == REMAINING {
+			return errors.New("pad needs a length")
+		}
+		return validSize(f.Length)
+	case BITS:
+		if f.Count != nil {
+			return errors.New("bits cannot have a count")
+		}
+		size := f.bitsSize()
+		if size != 1 && size != 2 && size != 4 && size != 8 {
+			return fmt.Errorf("invalid bits size %d", size)
+		}
+		if len(f.Bits) == 0 {
+			return errors.New("no bits")
+		}
+		width := 0
+		for _, bit := range f.Bits {
+			if bit.Name == "" || bit.Width <= 0 {
+				return errors.New("bits need a name and a width")
+			}
+			if bit.Type != "" && bit.Type != BOOL {
+				return fmt.Errorf("invalid bit type %s", bit.Type)
+			}
+			width += bit.Width
+		}
+		if width > size*8 {
+			return fmt.Errorf("%d bits do not fit in %d bytes", width, size)
+		}
+		return nil
+	}
+
+	if _, ok := scalarSizes[f.Type]; !ok {
+		return fmt.Errorf("unknown type %s", f.Type)
+	}
+	return nil
+}
+
+func (f *Field) name() string {
+	if f.Name == "" {
+		return f.Type
+	}
+	return f.Name
+}
+
+func (f *Field) bitsSize() int {
+	if f.Size == 0 {
+		return 1
+	}
+	return f.Size
+}
+
+func validSize(size interface{}) error {
+	switch converted := size.(type) {
+	case nil:
+		return nil
+	case string:
+		if converted != "" {
+			return nil
+		}
+	case float64, int:
+		if _, ok := toInt(converted); ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("%v is not a size or a field name", size)
+}
+
+func isInteger(typ string) bool {
+	return typ != BOOL && !strings.HasPrefix(typ, "float") && scalarSizes[typ] > 0
+}
+
+func byteOrder(endian string, inherited binary.ByteOrder) (binary.ByteOrder, error) {
+	switch endian {
+	case "":
+		return inherited, nil
+	case "big":
+		return binary.BigEndian, nil
+	case "little":
+		return binary.LittleEndian, nil
+	}
+	return nil, fmt.Errorf("unknown byte order %s", endian)
+}
+
+// toInt converts a size, count or length to an int. Sizes above
+// math.MaxInt32 are refused, they do not fit any payload and would overflow
+// the offsets of the decoder.
+func toInt(value interface{}) (int, bool) {
+	n, ok := toFloat(value)
+	if !ok || n < 0 || n > math.MaxInt32 || n != math.Trunc(n) {
+		return 0, false
+	}
+	return int(n), true
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch converted := value.(type) {
+	case float64:
+		return converted, true
+	case int:
+		return float64(converted), true
+	case int64:
+		return float64(converted), true
+	case uint64:
+		return float64(converted), true
+	}
+	return 0, false
+}
+
+func mask(width int) uint64 {
+	if width >= 64 {
+		return math.MaxUint64
+	}
+	return 1<<uint(width) - 1
+}
+
+type decoder struct {
+	data   []byte
+	offset int
+}
+
+// DecodeRecord reads a record laid out by the schema from the data at the
+// offset and returns it with the offset of the first byte after it.
+// Trailing NUL padding is removed from strings.
+func DecodeRecord(schema *Schema, data []byte, offset int) (map[string]interface{}, int, error) {
+	if err := schema.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	if offset < 0 || offset > len(data) {
+		return nil, 0, fmt.Errorf("offset %d is out of range", offset)
+	}
+
+	order, _ := byteOrder(schema.Endian, binary.BigEndian)
+	d := &decoder{data: data, offset: offset}
+	record, err := d.record(schema.Fields, order)
+	if err != nil {
+		return nil, 0, err
+	}
+	return record, d.offset, nil
+}
+
+func (d *decoder) record(fields []*Field, order binary.ByteOrder) (map[string]interface{}, error) {
+	record := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		fieldOrder, _ := byteOrder(field.Endian, order)
+		if err := d.field(field, fieldOrder, record); err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.name(), err)
+		}
+	}
+	return record, nil
+}
+
+func (d *decoder) field(f *Field, order binary.ByteOrder, record map[string]interface{}) error {
+	switch f.Type {
+	case BITS:
+		return d.bits(f, order, record)
+	case PAD:
+		n, err := d.size(f.Length, record)
+		if err != nil {
+			return err
+		}
+		_, err = d.read(n)
+		return err
+	}
+
+	if f.Count == nil {
+		value, err := d.value(f, order, record)
+		if err != nil {
+			return err
+		}
+		record[f.Name] = value
+		return nil
+	}
+
+	elements := make([]interface{}, 0)
+	if f.Count == REMAINING {
+		for d.offset < len(d.data) {
+			offset := d.offset
+			element, err := d.value(f, order, record)
+			if err != nil {
+				return fmt.Errorf("element %d: %v", len(elements)+1, err)
+			}
+			if d.offset == offset {
+				return errors.New("elements of the array are empty")
+			}
+			elements = append(elements, element)
+		}
+	} else {
+		count, err := d.size(f.Count, record)
+		if err != nil {
+			return err
+		}
+		// Every element takes at least one byte, a larger count can only
+		// come from a corrupt payload.
+		if count > len(d.data)-d.offset {
+			return fmt.Errorf("count %d exceeds the %d remaining bytes", count, len(d.data)-d.offset)
+		}
+		for i := 0; i < count; i++ {
+			element, err := d.value(f, order, record)
+			if err != nil {
+				return fmt.Errorf("element %d: %v", i+1, err)
+			}
+			elements = append(elements, element)
+		}
+	}
+	record[f.Name] = elements
+	return nil
+}
+
+func (d *decoder) value(f *Field, order binary.ByteOrder, record map[string]interface{}) (interface{}, error) {
+	switch f.Type {
+	case STRUCT:
+		return d.record(f.Fields, order)
+	case STRING, BYTES:
+		var n int
+		var ok bool
+		if f.Prefix != "" {
+			prefix, err := d.scalar(f.Prefix, order)
+			if err != nil {
+				return nil, err
+			}
+			if n, ok = toInt(prefix); !ok {
+				return nil, fmt.Errorf("length %v is out of range", prefix)
+			}
+		} else {
+			size, err := d.size(f.Length, record)
+			if err != nil {
+				return nil, err
+			}
+			n = size
+		}
+
+		raw, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		if f.Type == STRING {
+			return strings.TrimRight(string(raw), "\x00"), nil
+		}
+		return append([]byte(nil), raw...), nil
+	}
+	return d.scalar(f.Type, order)
+}
+
+func (d *decoder) scalar(typ string, order binary.ByteOrder) (interface{}, error) {
+	raw, err := d.read(scalarSizes[typ])
+	if err != nil {
+		return nil, err
+	}
+
+	switch typ {
+	case "uint8":
+		return uint64(raw[0]), nil
+	case "int8":
+		return int64(int8(raw[0])), nil
+	case "bool":
+		return raw[0] != 0, nil
+	case "uint16":
+		return uint64(order.Uint16(raw)), nil
+	case "int16":
+		return int64(int16(order.Uint16(raw))), nil
+	case "uint32":
+		return uint64(order.Uint32(raw)), nil
+	case "int32":
+		return int64(int32(order.Uint32(raw))), nil
+	case "float32":
+		return float64(math.Float32frombits(order.Uint32(raw))), nil
+	case "uint64":
+		return order.Uint64(raw), nil
+	case "int64":
+		return int64(order.Uint64(raw)), nil
+	case "float64":
+		return math.Float64frombits(order.Uint64(raw)), nil
+	}
+	return nil, fmt.Errorf("unknown type %s", typ)
+}
+
+func (d *decoder) bits(f *Field, order binary.ByteOrder, record map[string]interface{}) error {
+	raw, err := d.read(f.bitsSize())
+	if err != nil {
+		return err
+	}
+
+	var word uint64
+	switch len(raw) {
+	case 1:
+		word = uint64(raw[0])
+	case 2:
+		word = uint64(order.Uint16(raw))
+	case 4:
+		word = uint64(order.Uint32(raw))
+	case 8:
+		word = order.Uint64(raw)
+	}
+
+	shift := uint(len(raw) * 8)
+	for _, bit := range f.Bits {
+		shift -= uint(bit.Width)
+		value := (word >> shift) & mask(bit.Width)
+		if bit.Type == BOOL {
+			record[bit.Name] = value != 0
+		} else {
+			record[bit.Name] = value
+		}
+	}
+	return nil
+}
+
+func (d *decoder) size(size interface{}, record map[string]interface{}) (int, error) {
+	if size == REMAINING {
+		return len(d.data) - d.offset, nil
+	}
+	return sizeOf(size, record)
+}
+
+func (d *decoder) read(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("invalid length %d at offset %d", n, d.offset)
+	}
+	if n > len(d.data)-d.offset {
+		return nil, fmt.Errorf("need %d bytes at offset %d, have %d", n, d.offset, len(d.data)-d.offset)
+	}
+	raw := d.data[d.offset : d.offset+n]
+	d.offset += n
+	return raw, nil
+}
+
+func sizeOf(size interface{}, record map[string]interface{}) (int, error) {
+	if name, ok := size.(string); ok {
+		n, ok := toInt(record[name])
+		if !ok {
+			return 0, fmt.Errorf("%s is not an earlier integer field or is out of range", name)
+		}
+		return n, nil
+	}
+	n, _ := toInt(size)
+	return n, nil
+}
+
+type encoder struct {
+	buf []byte
+}
+
+// EncodeRecord packs the values into the layout of the schema. Integer
+// fields that size a later array, string or bytes field are filled in when
+// missing, and missing bits are zero.
+func EncodeRecord(schema *Schema, values map[string]interface{}) ([]byte, error) {
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+
+	order, _ := byteOrder(schema.Endian, binary.BigEndian)
+	e := &encoder{buf: make([]byte, 0, 64)}
+	if err := e.record(schema.Fields, order, values); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+func (e *encoder) record(fields []*Field, order binary.ByteOrder, values map[string]interface{}) error {
+	record := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		record[name] = value
+	}
+
+	for _, field := range fields {
+		for _, size := range []interface{}{field.Count, field.Length} {
+			name, ok := size.(string)
+			if !ok || name == REMAINING {
+				continue
+			}
+			if _, ok := record[name]; ok {
+				continue
+			}
+			if n, ok := lengthOf(values[field.Name]); ok {
+				record[name] = n
+			}
+		}
+	}
+
+	for _, field := range fields {
+		fieldOrder, _ := byteOrder(field.Endian, order)
+		if err := e.field(field, fieldOrder, record); err != nil {
+			return fmt.Errorf("field %s: %v", field.name(), err)
+		}
+	}
+	return nil
+}
+
+func (e *encoder) field(f *Field, order binary.ByteOrder, record map[string]interface{}) error {
+	switch f.Type {
+	case BITS:
+		return e.bits(f, order, record)
+	case PAD:
+		n, err := sizeOf(f.Length, record)
+		if err != nil {
+			return err
+		}
+		e.buf = append(e.buf, make([]byte, n)...)
+		return nil
+	}
+
+	value, ok := record[f.Name]
+	if !ok {
+		return errors.New("missing value")
+	}
+
+	if f.Count == nil {
+		return e.value(f, order, record, value)
+	}
+
+	elements, ok := toArray(value)
+	if !ok {
+		return errors.New("expected an array")
+	}
+
+	if f.Count != REMAINING {
+		count, err := sizeOf(f.Count, record)
+		if err != nil {
+			return err
+		}
+		if count != len(elements) {
+			return fmt.Errorf("expected %d elements, got %d", count, len(elements))
+		}
+	}
+
+	for i, element := range elements {
+		if err := e.value(f, order, record, element); err != nil {
+			return fmt.Errorf("element %d: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (e *encoder) value(f *Field, order binary.ByteOrder, record map[string]interface{}, value interface{}) error {
+	switch f.Type {
+	case STRUCT:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return errors.New("expected a table")
+		}
+		return e.record(f.Fields, order, values)
+	case STRING, BYTES:
+		raw, ok := toBytes(value)
+		if !ok {
+			return fmt.Errorf("expected %s", f.Type)
+		}
+
+		if f.Prefix != "" {
+			if err := e.scalar(f.Prefix, order, len(raw)); err != nil {
+				return err
+			}
+			e.buf = append(e.buf, raw...)
+			return nil
+		}
+
+		if f.Length == REMAINING {
+			e.buf = append(e.buf, raw...)
+			return nil
+		}
+
+		n, err := sizeOf(f.Length, record)
+		if err != nil {
+			return err
+		}
+		if len(raw) > n {
+			return fmt.Errorf("%d bytes exceed the length %d", len(raw), n)
+		}
+		e.buf = append(e.buf, raw...)
+		e.buf = append(e.buf, make([]byte, n-len(raw))...)
+		return nil
+	}
+	return e.scalar(f.Type, order, value)
+}
+
+func (e *encoder) scalar(typ string, order binary.ByteOrder, value interface{}) error {
+	raw := make([]byte, scalarSizes[typ])
+	switch typ {
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return errors.New("expected a boolean")
+		}
+		if b {
+			raw[0] = 1
+		}
+	case "float32":
+		f, ok := toFloat(value)
+		if !ok {
+			return errors.New("expected a number")
+		}
+		order.PutUint32(raw, math.Float32bits(float32(f)))
+	case "float64":
+		f, ok := toFloat(value)
+		if !ok {
+			return errors.New("expected a number")
+		}
+		order.PutUint64(raw, math.Float64bits(f))
+	default:
+		n, ok := toFloat(value)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Errorf("%v is not an integer", value)
+		}
+
+		bits := len(raw) * 8
+		min, limit := 0.0, math.Ldexp(1, bits)
+		if strings.HasPrefix(typ, "int") {
+			min, limit = -math.Ldexp(1, bits-1), math.Ldexp(1, bits-1)
+		}
+		if n < min || n >= limit {
+			return fmt.Errorf("%v is out of range for %s", value, typ)
+		}
+
+		var word uint64
+		if n < 0 {
+			word = uint64(int64(n))
+		} else {
+			word = uint64(n)
+		}
+		putUint(raw, order, word)
+	}
+	e.buf = append(e.buf, raw...)
+	return nil
+}
+
+func (e *encoder) bits(f *Field, order binary.ByteOrder, record map[string]interface{}) error {
+	raw := make([]byte, f.bitsSize())
+	shift := uint(len(raw) * 8)
+
+	var word uint64
+	for _, bit := range f.Bits {
+		shift -= uint(bit.Width)
+
+		var n uint64
+		switch value := record[bit.Name].(type) {
+		case nil:
+		case bool:
+			if value {
+				n = 1
+			}
+		default:
+			converted, ok := toFloat(value)
+			if !ok || converted < 0 || converted != math.Trunc(converted) || uint64(converted) > mask(bit.Width) {
+				return fmt.Errorf("bit %s: %v does not fit in %d bits", bit.Name, value, bit.Width)
+			}
+			n = uint64(converted)
+		}
+		word |= n << shift
+	}
+
+	putUint(raw, order, word)
+	e.buf = append(e.buf, raw...)
+	return nil
+}
+
+func putUint(raw []byte, order binary.ByteOrder, word uint64) {
+	switch len(raw) {
+	case 1:
+		raw[0] = byte(word)
+	case 2:
+		order.PutUint16(raw, uint16(word))
+	case 4:
+		order.PutUint32(raw, uint32(word))
+	case 8:
+		order.PutUint64(raw, word)
+	}
+}
+
+func lengthOf(value interface{}) (int, bool) {
+	if raw, ok := toBytes(value); ok {
+		return len(raw), true
+	}
+	if elements, ok := toArray(value); ok {
+		return len(elements), true
+	}
+	return 0, false
+}
+
+// toArray accepts an empty table as an empty array, since Lua cannot tell
+// the two apart.
+func toArray(value interface{}) ([]interface{}, bool) {
+	switch converted := value.(type) {
+	case []interface{}:
+		return converted, true
+	case map[string]interface{}:
+		if len(converted) == 0 {
+			return []interface{}{}, true
+		}
+	}
+	return nil, false
+}
+
+func toBytes(value interface{}) ([]byte, bool) {
+	switch converted := value.(type) {
+	case string:
+		return []byte(converted), true
+	case []byte:
+		return converted, true
+	case []interface{}:
+		raw := make([]byte, len(converted))
+		for i, element := range converted {
+			n, ok := toFloat(element)
+			if !ok || n < 0 || n > 255 || n != math.Trunc(n) {
+				return nil, false
+			}
+			raw[i] = byte(n)
+		}
+		return raw, true
+	case map[string]interface{}:
+		if len(converted) == 0 {
+			return []byte{}, true
+		}
+	}
+	return nil, false
+}
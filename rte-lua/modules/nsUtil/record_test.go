@@ -0,0 +1,148 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsUtil
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var reading = &Schema{
+	Endian: "big",
+	Fields: []*Field{
+		{Name: "version", Type: "uint8"},
+		{Type: "bits", Bits: []*Bit{
+			{Name: "alarm", Width: 1, Type: "bool"},
+			{Name: "mode", Width: 3},
+		}},
+		{Name: "temperature", Type: "int16", Endian: "little"},
+		{Name: "device", Type: "string", Length: 6.0},
+		{Name: "count", Type: "uint8"},
+		{Name: "samples", Type: "uint16", Count: "count"},
+		{Type: "pad", Length: 1.0},
+		{Name: "label", Type: "string", Prefix: "uint8"},
+		{Name: "position", Type: "struct", Fields: []*Field{
+			{Name: "lat", Type: "float32"},
+			{Name: "lon", Type: "float32"},
+		}},
+		{Name: "trailer", Type: "bytes", Length: "*"},
+	},
+}
+
+var payload = []byte{
+	0x02,
+	0xB0,
+	0x2E, 0xFF,
+	'n', 's', '-', '1', 0, 0,
+	0x02,
+	0x01, 0x00, 0x00, 0x10,
+	0x00,
+	0x03, 'a', 'b', 'c',
+	0x40, 0x20, 0x00, 0x00,
+	0xC0, 0x20, 0x00, 0x00,
+	0xAA, 0x55,
+}
+
+func TestDecodeRecord(t *testing.T) {
+	record, offset, err := DecodeRecord(reading, payload, 0)
+	require.Nil(t, err)
+	require.Equal(t, len(payload), offset)
+	require.Equal(t, uint64(2), record["version"])
+	require.Equal(t, true, record["alarm"])
+	require.Equal(t, uint64(3), record["mode"])
+	require.Equal(t, int64(-210), record["temperature"])
+	require.Equal(t, "ns-1", record["device"])
+	require.Equal(t, []interface{}{uint64(256), uint64(16)}, record["samples"])
+	require.Equal(t, "abc", record["label"])
+	require.Equal(t, map[string]interface{}{"lat": 2.5, "lon": -2.5}, record["position"])
+	require.Equal(t, []byte{0xAA, 0x55}, record["trailer"])
+}
+
+func TestDecodeRecordShort(t *testing.T) {
+	_, _, err := DecodeRecord(reading, payload[:12], 0)
+	require.EqualError(t, err, "field samples: count 2 exceeds the 1 remaining bytes")
+}
+
+func TestDecodeRecordOutOfRange(t *testing.T) {
+	prefixed := &Schema{Fields: []*Field{{Name: "s", Type: "string", Prefix: "uint64"}}}
+	_, _, err := DecodeRecord(prefixed, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 'a'}, 0)
+	require.EqualError(t, err, "field s: length 18446744073709551615 is out of range")
+
+	sized := &Schema{Fields: []*Field{
+		{Name: "n", Type: "uint64"},
+		{Name: "s", Type: "bytes", Length: "n"},
+	}}
+	_, _, err = DecodeRecord(sized, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 'a'}, 0)
+	require.EqualError(t, err, "field s: n is not an earlier integer field or is out of range")
+
+	counted := &Schema{Fields: []*Field{
+		{Name: "n", Type: "uint16"},
+		{Name: "s", Type: "bytes", Length: 0.0, Count: "n"},
+	}}
+	_, _, err = DecodeRecord(counted, []byte{0xff, 0xff, 'a'}, 0)
+	require.EqualError(t, err, "field s: count 65535 exceeds the 1 remaining bytes")
+
+	huge := &Schema{Fields: []*Field{{Name: "s", Type: "bytes", Length: 1e19}}}
+	require.EqualError(t, huge.Validate(), "field s: 1e+19 is not a size or a field name")
+}
+
+func TestEncodeRecord(t *testing.T) {
+	data, err := EncodeRecord(reading, map[string]interface{}{
+		"version":     2.0,
+		"alarm":       true,
+		"mode":        3.0,
+		"temperature": -210.0,
+		"device":      "ns-1",
+		"samples":     []interface{}{256.0, 16.0},
+		"label":       "abc",
+		"position":    map[string]interface{}{"lat": 2.5, "lon": -2.5},
+		"trailer":     []interface{}{170.0, 85.0},
+	})
+	require.Nil(t, err)
+	require.Equal(t, payload, data)
+}
+
+func TestEncodeRecordRange(t *testing.T) {
+	schema := &Schema{Fields: []*Field{{Name: "value", Type: "int8"}}}
+	_, err := EncodeRecord(schema, map[string]interface{}{"value": 128.0})
+	require.EqualError(t, err, "field value: 128 is out of range for int8")
+}
+
+func TestValidateSchema(t *testing.T) {
+	schema := &Schema{Fields: []*Field{{Name: "name", Type: "string"}}}
+	require.EqualError(t, schema.Validate(), "field name: missing length or prefix")
+
+	schema = &Schema{Fields: []*Field{{Type: "bits", Bits: []*Bit{{Name: "a", Width: 9}}}}}
+	require.EqualError(t, schema.Validate(), "field bits: 9 bits do not fit in 1 bytes")
+}
+
+func TestComputeChecksum(t *testing.T) {
+	check := []byte("123456789")
+	for algorithm, expected := range map[string]uint64{
+		CRC8:         0xF4,
+		CRC16_CCITT:  0x29B1,
+		CRC16_XMODEM: 0x31C3,
+		CRC16_MODBUS: 0x4B37,
+		CRC32:        0xCBF43926,
+		SUM8:         0xDD,
+		XOR8:         0x31,
+	} {
+		sum, err := ComputeChecksum(algorithm, check)
+		require.Nil(t, err)
+		require.Equal(t, expected, sum, algorithm)
+	}
+}
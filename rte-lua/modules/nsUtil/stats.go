@@ -22,4 +22,10 @@ var (
 	NsStream             = stats.New("nsUtil")
 	ReadFromByteArray    = NsStream.NewCounter("ReadFromByteArray")
 	ErrReadFromByteArray = NsStream.NewCounter("ErrReadFromByteArray")
+	Decode               = NsStream.NewCounter("Decode")
+	ErrDecode            = NsStream.NewCounter("ErrDecode")
+	Encode               = NsStream.NewCounter("Encode")
+	ErrEncode            = NsStream.NewCounter("ErrEncode")
+	Checksum             = NsStream.NewCounter("Checksum")
+	ErrChecksum          = NsStream.NewCounter("ErrChecksum")
 )
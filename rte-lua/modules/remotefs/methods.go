@@ -0,0 +1,283 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotefs
+
+import (
+	"sort"
+
+	"github.com/lavaorg/lrtx/luaext/gluamapper"
+	"github.com/lavaorg/lua"
+)
+
+const (
+	LIST     = "list"
+	STAT     = "stat"
+	RETRIEVE = "retrieve"
+	RENAME   = "rename"
+	DELETE   = "delete"
+	GLOB     = "glob"
+	SYNC     = "sync"
+
+	AS_STRING = "string"
+	AS_BYTES  = "bytes"
+)
+
+// Methods are the connection methods shared by the FTP and SFTP modules,
+// called on a userdata holding the FileSystem of the connection.
+type Methods struct {
+	// Objects stores the files retrieved into a bucket, nil when nsObject
+	// is not available to the snippet.
+	Objects ObjectStore
+	// Synced records the files copied by the syncs, nil with Objects.
+	Synced SyncState
+	// Error pushes the error of the method the way the module reports
+	// errors, Done counts its success.
+	Error func(L *lua.LState, err string, method string) int
+	Done  func(method string)
+}
+
+// Register adds the methods to the methods of the connection.
+func (m *Methods) Register(methods map[string]lua.LGFunction) {
+	methods[LIST] = m.list
+	methods[STAT] = m.stat
+	methods[RETRIEVE] = m.retrieve
+	methods[RENAME] = m.rename
+	methods[DELETE] = m.delete
+	methods[GLOB] = m.glob
+	methods[SYNC] = m.sync
+}
+
+func (m *Methods) fileSystem(L *lua.LState) (FileSystem, bool) {
+	fs, ok := L.CheckUserData(1).Value.(FileSystem)
+	return fs, ok
+}
+
+func (m *Methods) list(L *lua.LState) int {
+	fs, ok := m.fileSystem(L)
+	if !ok {
+		return m.Error(L, "unknown connection handle", LIST)
+	}
+
+	files, err := fs.List(L.OptString(2, "."))
+	if err != nil {
+		return m.Error(L, err.Error(), LIST)
+	}
+
+	L.Push(filesTable(L, files))
+	m.Done(LIST)
+	return 1
+}
+
+func (m *Methods) stat(L *lua.LState) int {
+	fs, ok := m.fileSystem(L)
+	if !ok {
+		return m.Error(L, "unknown connection handle", STAT)
+	}
+
+	file, err := fs.Stat(L.CheckString(2))
+	if err != nil {
+		return m.Error(L, err.Error(), STAT)
+	}
+
+	L.Push(fileTable(L, file))
+	m.Done(STAT)
+	return 1
+}
+
+// retrieve returns the content of the file as a string, or as a byte table
+// with "bytes" as second argument. With a table {bucket, file, contentType}
+// the file is stored in the bucket instead and its size returned.
+func (m *Methods) retrieve(L *lua.LState) int {
+	fs, ok := m.fileSystem(L)
+	if !ok {
+		return m.Error(L, "unknown connection handle", RETRIEVE)
+	}
+
+	p := L.CheckString(2)
+	var object *lua.LTable
+	as := AS_STRING
+	switch v := L.Get(3).(type) {
+	case lua.LString:
+		as = string(v)
+		if as != AS_STRING && as != AS_BYTES {
+			return m.Error(L, "unexpected format "+as+", string or bytes expected", RETRIEVE)
+		}
+	case *lua.LTable:
+		if m.Objects == nil {
+			return m.Error(L, "nsObject is not available", RETRIEVE)
+		}
+		object = v
+	default:
+		if v != lua.LNil {
+			return m.Error(L, "unexpected value, format or object expected", RETRIEVE)
+		}
+	}
+
+	data, err := fs.Retrieve(p)
+	if err != nil {
+		return m.Error(L, err.Error(), RETRIEVE)
+	}
+
+	switch {
+	case object != nil:
+		bucket := lua.LVAsString(object.RawGetH(lua.LString("bucket")))
+		file := lua.LVAsString(object.RawGetH(lua.LString("file")))
+		contentType := lua.LVAsString(object.RawGetH(lua.LString("contentType")))
+		if bucket == "" || file == "" {
+			return m.Error(L, "bucket and file of the object expected", RETRIEVE)
+		}
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		if err := m.Objects.Upload(bucket, file, data, contentType); err != nil {
+			return m.Error(L, err.Error(), RETRIEVE)
+		}
+		L.Push(lua.LNumber(len(data)))
+	case as == AS_BYTES:
+		bytes := L.CreateTable(len(data), 0)
+		for _, b := range data {
+			bytes.Append(lua.LNumber(b))
+		}
+		L.Push(bytes)
+	default:
+		L.Push(lua.LString(data))
+	}
+
+	m.Done(RETRIEVE)
+	return 1
+}
+
+func (m *Methods) rename(L *lua.LState) int {
+	fs, ok := m.fileSystem(L)
+	if !ok {
+		return m.Error(L, "unknown connection handle", RENAME)
+	}
+
+	if err := fs.Rename(L.CheckString(2), L.CheckString(3)); err != nil {
+		return m.Error(L, err.Error(), RENAME)
+	}
+
+	m.Done(RENAME)
+	return 0
+}
+
+func (m *Methods) delete(L *lua.LState) int {
+	fs, ok := m.fileSystem(L)
+	if !ok {
+		return m.Error(L, "unknown connection handle", DELETE)
+	}
+
+	if err := fs.Delete(L.CheckString(2)); err != nil {
+		return m.Error(L, err.Error(), DELETE)
+	}
+
+	m.Done(DELETE)
+	return 0
+}
+
+func (m *Methods) glob(L *lua.LState) int {
+	fs, ok := m.fileSystem(L)
+	if !ok {
+		return m.Error(L, "unknown connection handle", GLOB)
+	}
+
+	files, err := Glob(fs, L.CheckString(2))
+	if err != nil {
+		return m.Error(L, err.Error(), GLOB)
+	}
+
+	L.Push(filesTable(L, files))
+	m.Done(GLOB)
+	return 1
+}
+
+// sync copies the new and changed files of a directory into a bucket, see
+// Sync, and returns the report {id, transferred, skipped, pending, failed}.
+func (m *Methods) sync(L *lua.LState) int {
+	fs, ok := m.fileSystem(L)
+	if !ok {
+		return m.Error(L, "unknown connection handle", SYNC)
+	}
+
+	var options SyncOptions
+	if err := gluamapper.Map(L.CheckTable(2), &options); err != nil {
+		return m.Error(L, err.Error(), SYNC)
+	}
+
+	if m.Objects == nil || m.Synced == nil {
+		return m.Error(L, "nsObject is not available", SYNC)
+	}
+
+	report, err := Sync(fs, m.Objects, m.Synced, &options)
+	if err != nil {
+		return m.Error(L, err.Error(), SYNC)
+	}
+
+	transferred := L.CreateTable(len(report.Transferred), 0)
+	for _, file := range report.Transferred {
+		tbl := L.CreateTable(0, 4)
+		tbl.RawSetH(lua.LString("path"), lua.LString(file.Path))
+		tbl.RawSetH(lua.LString("bucket"), lua.LString(file.Bucket))
+		tbl.RawSetH(lua.LString("object"), lua.LString(file.Object))
+		tbl.RawSetH(lua.LString("size"), lua.LNumber(file.Size))
+		transferred.Append(tbl)
+	}
+
+	paths := make([]string, 0, len(report.Failed))
+	for p := range report.Failed {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	failed := L.CreateTable(len(paths), 0)
+	for _, p := range paths {
+		tbl := L.CreateTable(0, 2)
+		tbl.RawSetH(lua.LString("path"), lua.LString(p))
+		tbl.RawSetH(lua.LString("error"), lua.LString(report.Failed[p]))
+		failed.Append(tbl)
+	}
+
+	tbl := L.CreateTable(0, 5)
+	tbl.RawSetH(lua.LString("id"), lua.LString(report.Id))
+	tbl.RawSetH(lua.LString("transferred"), transferred)
+	tbl.RawSetH(lua.LString("skipped"), lua.LNumber(report.Skipped))
+	tbl.RawSetH(lua.LString("pending"), lua.LNumber(report.Pending))
+	tbl.RawSetH(lua.LString("failed"), failed)
+	L.Push(tbl)
+	m.Done(SYNC)
+	return 1
+}
+
+func filesTable(L *lua.LState, files []*FileInfo) *lua.LTable {
+	arr := L.CreateTable(len(files), 0)
+	for _, file := range files {
+		arr.Append(fileTable(L, file))
+	}
+	return arr
+}
+
+// fileTable describes the file to the snippet, its modification time in
+// seconds since the epoch.
+func fileTable(L *lua.LState, file *FileInfo) *lua.LTable {
+	tbl := L.CreateTable(0, 5)
+	tbl.RawSetH(lua.LString("name"), lua.LString(file.Name))
+	tbl.RawSetH(lua.LString("path"), lua.LString(file.Path))
+	tbl.RawSetH(lua.LString("size"), lua.LNumber(file.Size))
+	tbl.RawSetH(lua.LString("mtime"), lua.LNumber(file.ModTime.Unix()))
+	tbl.RawSetH(lua.LString("isDir"), lua.LBool(file.IsDir))
+	return tbl
+}
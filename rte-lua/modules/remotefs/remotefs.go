@@ -0,0 +1,175 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotefs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lavaorg/lrtx/config"
+)
+
+var (
+	RetrieveLimit, _ = config.GetInt("NS_REMOTE_RETRIEVE_LIMIT", 64*1024*1024)
+)
+
+// ErrNotExist is returned when the remote file does not exist.
+var ErrNotExist = errors.New("file does not exist")
+
+// FileInfo describes a remote file.
+type FileInfo struct {
+	Name    string
+	Path    string
+	Size    int64
+	ModTime time.Time
+	IsDir   bool
+}
+
+// FileSystem is the set of file operations of a connection to a remote
+// server. The FTP and SFTP connections implement it, so both modules expose
+// the same methods and snippets can switch protocols.
+type FileSystem interface {
+	List(dir string) ([]*FileInfo, error)
+	Stat(path string) (*FileInfo, error)
+	Retrieve(path string) ([]byte, error)
+	Store(path string, data []byte) error
+	Mkdir(path string) error
+	Rename(from string, to string) error
+	Delete(path string) error
+}
+
+// ObjectStore stores files in the object store of the account.
+type ObjectStore interface {
+	Upload(bucket string, file string, data []byte, contentType string) error
+}
+
+// FromFileInfo describes the file at the path.
+func FromFileInfo(p string, fi os.FileInfo) *FileInfo {
+	return &FileInfo{Name: fi.Name(),
+		Path:    p,
+		Size:    fi.Size(),
+		ModTime: fi.ModTime(),
+		IsDir:   fi.IsDir()}
+}
+
+// StatFromList describes the file by listing its directory, for servers
+// without a command describing a single file.
+func StatFromList(fs FileSystem, p string) (*FileInfo, error) {
+	dir, name := path.Split(path.Clean(p))
+	if dir == "" {
+		dir = "."
+	}
+
+	files, err := fs.List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		if file.Name == name {
+			return file, nil
+		}
+	}
+
+	return nil, ErrNotExist
+}
+
+// ReadAll reads the retrieved file, failing when it is larger than the
+// retrieve limit.
+func ReadAll(r io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, int64(RetrieveLimit)+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > RetrieveLimit {
+		return nil, fmt.Errorf("file exceeds the retrieve limit of %d bytes", RetrieveLimit)
+	}
+
+	return data, nil
+}
+
+// Glob returns the files matching the pattern, in the syntax of path.Match.
+// Any element of the pattern may hold meta characters.
+func Glob(fs FileSystem, pattern string) ([]*FileInfo, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	dir, name := path.Split(pattern)
+	dir = strings.TrimSuffix(dir, "/")
+	if !hasMeta(pattern) {
+		file, err := fs.Stat(pattern)
+		if err == ErrNotExist {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []*FileInfo{file}, nil
+	}
+
+	var dirs []string
+	switch {
+	case dir == "" && strings.HasPrefix(pattern, "/"):
+		dirs = []string{"/"}
+	case dir == "":
+		dirs = []string{"."}
+	case hasMeta(dir):
+		parents, err := Glob(fs, dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			if parent.IsDir {
+				dirs = append(dirs, parent.Path)
+			}
+		}
+	default:
+		dirs = []string{dir}
+	}
+
+	var matches []*FileInfo
+	for _, d := range dirs {
+		files, err := fs.List(d)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			if matched, _ := path.Match(name, file.Name); matched {
+				matches = append(matches, file)
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Path < matches[j].Path
+	})
+	return matches, nil
+}
+
+func hasMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
@@ -0,0 +1,309 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotefs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lavaorg/lrtx/config"
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/northstar/data/syncs/client"
+	"github.com/lavaorg/northstar/data/syncs/model"
+)
+
+var (
+	SyncLimit, _       = config.GetInt("NS_REMOTE_SYNC_LIMIT", 1000)
+	SyncRecordBatch, _ = config.GetInt("NS_REMOTE_SYNC_RECORD_BATCH", 50)
+)
+
+var syncIdRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// SyncState records the remote files copied by the syncs of an account.
+type SyncState interface {
+	Synced(syncId string) ([]*model.SyncedFile, error)
+	Record(syncId string, files []*model.SyncedFile) error
+}
+
+// SyncOptions select the remote files copied into the bucket. Include and
+// exclude patterns are matched against the file name, or against the path
+// relative to the directory when they contain a slash.
+type SyncOptions struct {
+	Id          string
+	Dir         string
+	Bucket      string
+	Prefix      string
+	Include     []string
+	Exclude     []string
+	Recursive   bool
+	Checksum    bool
+	ContentType string
+	Limit       int
+}
+
+// SyncReport tells what a sync copied. Skipped files were unchanged, pending
+// ones are left for the next sync once the limit was reached.
+type SyncReport struct {
+	Id          string
+	Transferred []*model.SyncedFile
+	Skipped     int
+	Pending     int
+	Failed      map[string]string
+}
+
+// Sync copies the files of the remote directory which are new or changed
+// since the last sync with the same id into the bucket, named by the prefix
+// followed by their path relative to the directory. Files are compared by
+// size and modification time and, with checksums, a changed file whose
+// content is unchanged is not copied again.
+func Sync(fs FileSystem, objects ObjectStore, state SyncState, options *SyncOptions) (*SyncReport, error) {
+	if err := options.defaults(); err != nil {
+		return nil, err
+	}
+
+	previous, err := state.Synced(options.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	synced := make(map[string]*model.SyncedFile, len(previous))
+	for _, file := range previous {
+		synced[file.Path] = file
+	}
+
+	files, err := walk(fs, options.Dir, options.Recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SyncReport{Id: options.Id, Failed: make(map[string]string)}
+	var records []*model.SyncedFile
+	for _, file := range files {
+		rel := strings.TrimPrefix(strings.TrimPrefix(file.Path, options.Dir), "/")
+		if !options.selects(rel) {
+			continue
+		}
+
+		candidate := &model.SyncedFile{Path: file.Path, Size: file.Size, ModTime: file.ModTime}
+		if !candidate.Changed(synced[file.Path]) {
+			report.Skipped++
+			continue
+		}
+
+		if len(report.Transferred) >= options.Limit {
+			report.Pending++
+			continue
+		}
+
+		data, err := fs.Retrieve(file.Path)
+		if err != nil {
+			report.Failed[file.Path] = err.Error()
+			continue
+		}
+
+		if options.Checksum {
+			sum := sha256.Sum256(data)
+			candidate.Checksum = hex.EncodeToString(sum[:])
+		}
+
+		if prev := synced[file.Path]; prev != nil && candidate.Checksum != "" && candidate.Checksum == prev.Checksum {
+			candidate.Bucket, candidate.Object = prev.Bucket, prev.Object
+			report.Skipped++
+		} else {
+			candidate.Bucket, candidate.Object = options.Bucket, options.Prefix+rel
+			if err := objects.Upload(candidate.Bucket, candidate.Object, data, options.contentType(rel)); err != nil {
+				report.Failed[file.Path] = err.Error()
+				continue
+			}
+			report.Transferred = append(report.Transferred, candidate)
+		}
+
+		records = append(records, candidate)
+		if len(records) >= SyncRecordBatch {
+			if err := state.Record(options.Id, records); err != nil {
+				return report, err
+			}
+			records = nil
+		}
+	}
+
+	if len(records) > 0 {
+		if err := state.Record(options.Id, records); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// defaults validates the options and fills the ones not set. The default id
+// identifies the directory and the bucket it is copied into.
+func (options *SyncOptions) defaults() error {
+	if options.Bucket == "" {
+		return fmt.Errorf("bucket of the sync expected")
+	}
+
+	if options.Dir == "" {
+		options.Dir = "."
+	}
+	options.Dir = path.Clean(options.Dir)
+
+	if options.Id == "" {
+		sum := sha256.Sum256([]byte(options.Dir + "\x00" + options.Bucket + "\x00" + options.Prefix))
+		options.Id = hex.EncodeToString(sum[:8])
+	}
+
+	if !syncIdRegexp.MatchString(options.Id) {
+		return fmt.Errorf("invalid sync id: %q", options.Id)
+	}
+
+	for _, pattern := range append(options.Include, options.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+	}
+
+	if options.Limit <= 0 || options.Limit > SyncLimit {
+		options.Limit = SyncLimit
+	}
+
+	return nil
+}
+
+// selects reports whether the file, by its path relative to the directory,
+// is included and not excluded.
+func (options *SyncOptions) selects(rel string) bool {
+	match := func(pattern string) bool {
+		name := path.Base(rel)
+		if strings.Contains(pattern, "/") {
+			name = rel
+		}
+		matched, _ := path.Match(pattern, name)
+		return matched
+	}
+
+	included := len(options.Include) == 0
+	for _, pattern := range options.Include {
+		included = included || match(pattern)
+	}
+
+	for _, pattern := range options.Exclude {
+		if match(pattern) {
+			return false
+		}
+	}
+
+	return included
+}
+
+func (options *SyncOptions) contentType(name string) string {
+	if options.ContentType != "" {
+		return options.ContentType
+	}
+
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		return contentType
+	}
+
+	return "application/octet-stream"
+}
+
+// walk returns the files of the directory, and of its subdirectories when
+// recursive, ordered by path.
+func walk(fs FileSystem, dir string, recursive bool) ([]*FileInfo, error) {
+	entries, err := fs.List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*FileInfo
+	for _, entry := range entries {
+		if !entry.IsDir {
+			files = append(files, entry)
+			continue
+		}
+
+		if recursive {
+			sub, err := walk(fs, entry.Path, recursive)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, sub...)
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files, nil
+}
+
+// DataSyncState keeps the state of the syncs of the account in the data
+// service, creating the client on first use.
+type DataSyncState struct {
+	AccountId string
+	once      sync.Once
+	client    *client.SyncsClient
+	err       error
+}
+
+func NewDataSyncState(accountId string) *DataSyncState {
+	return &DataSyncState{AccountId: accountId}
+}
+
+func (d *DataSyncState) Synced(syncId string) ([]*model.SyncedFile, error) {
+	if err := d.connect(); err != nil {
+		return nil, err
+	}
+
+	files, mErr := d.client.GetSynced(d.AccountId, syncId)
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	return files, nil
+}
+
+func (d *DataSyncState) Record(syncId string, files []*model.SyncedFile) error {
+	if err := d.connect(); err != nil {
+		return err
+	}
+
+	if mErr := d.client.AddSynced(d.AccountId, syncId, files); mErr != nil {
+		return mErr
+	}
+
+	return nil
+}
+
+func (d *DataSyncState) connect() error {
+	d.once.Do(func() {
+		d.client, d.err = client.NewSyncsClient()
+	})
+	if d.err != nil {
+		return management.GetInternalError(d.err.Error())
+	}
+
+	return nil
+}
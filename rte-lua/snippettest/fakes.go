@@ -0,0 +1,555 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snippettest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lavaorg/lrtx/luaext/gluamapper"
+	"github.com/lavaorg/lrtx/management"
+	"github.com/lavaorg/lua"
+	invocations "github.com/lavaorg/northstar/data/invocations/model"
+	libraries "github.com/lavaorg/northstar/data/libraries/model"
+	syncs "github.com/lavaorg/northstar/data/syncs/model"
+	"github.com/lavaorg/northstar/rte-lua/modules/nsLog"
+	"github.com/lavaorg/northstar/rte-lua/modules/nsObject"
+	"github.com/lavaorg/northstar/rte-lua/modules/nsSFTP"
+	"github.com/lavaorg/northstar/rte-lua/modules/remotefs"
+	"github.com/lavaorg/northstar/rte-lua/util"
+)
+
+// Fakes are in-memory implementations of the northstar modules reaching
+// outside the rte. They serve the fixtures of a case and record what the
+// snippet stored, so the case can assert on it.
+type Fakes struct {
+	queries   []*QueryFixture
+	http      []*HttpFixture
+	libraries map[string]string
+	syncs     map[string]map[string]*syncs.SyncedFile
+
+	// Recorder, when set, sends the http requests without a fixture and
+	// records their responses in Recorded.
+	Recorder *http.Client
+	Recorded []*HttpFixture
+
+	KV      map[string]string
+	Objects map[string]map[string]Object
+	// Files are the files stored with nsFTP and nsSFTP, keyed by the
+	// host and port of the server followed by the path of the file.
+	Files map[string]string
+	// Logs are the entries written with nsLog.
+	Logs []*invocations.LogEntry
+}
+
+func NewFakes(fixtures *Fixtures) *Fakes {
+	fakes := &Fakes{queries: fixtures.Queries,
+		http:      fixtures.Http,
+		libraries: fixtures.Libraries,
+		syncs:     make(map[string]map[string]*syncs.SyncedFile),
+		KV:        make(map[string]string),
+		Objects:   make(map[string]map[string]Object),
+		Files:     make(map[string]string)}
+
+	for key, value := range fixtures.KV {
+		fakes.KV[key] = value
+	}
+
+	for name, data := range fixtures.Files {
+		fakes.Files[name] = data
+	}
+
+	for bucket, files := range fixtures.Objects {
+		fakes.Objects[bucket] = make(map[string]Object)
+		for name, object := range files {
+			fakes.Objects[bucket][name] = object
+		}
+	}
+
+	return fakes
+}
+
+// Preload replaces the modules of the state with the fakes.
+func (f *Fakes) Preload(L *lua.LState) {
+	L.PreloadModule("nsQL", f.nsQL)
+	L.PreloadModule("nsKV", f.nsKV)
+	L.PreloadModule("nsObject", f.nsObject)
+	L.PreloadModule("nsFTP", f.nsFTP)
+	L.PreloadModule("nsSFTP", f.nsSFTP)
+	L.PreloadModule("nsLog", f.nsLog)
+	L.PreloadModule("http", f.httpModule)
+}
+
+// GetLibrary serves the library fixtures, the highest version of the
+// library when the latest one is required.
+func (f *Fakes) GetLibrary(accountId string, name string, version int) (*libraries.LibraryData, *management.Error) {
+	if version == libraries.LATEST {
+		for key := range f.libraries {
+			i := strings.LastIndex(key, "@")
+			if v, err := strconv.Atoi(key[i+1:]); i >= 0 && err == nil && key[:i] == name && v > version {
+				version = v
+			}
+		}
+	}
+
+	code, ok := f.libraries[fmt.Sprintf("%s@%d", name, version)]
+	if !ok {
+		return nil, management.GetNotFoundError("Library not found")
+	}
+
+	return &libraries.LibraryData{Name: name, Version: version, Code: code}, nil
+}
+
+func (f *Fakes) nsQL(L *lua.LState) int {
+	L.Push(L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"connect": func(L *lua.LState) int {
+			L.CheckTable(1)
+			L.Push(L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+				"query": func(L *lua.LState) int {
+					return f.query(L, L.CheckString(2))
+				},
+				"disconnect": func(L *lua.LState) int {
+					return 0
+				},
+			}))
+			return 1
+		},
+		"query": func(L *lua.LState) int {
+			L.CheckTable(2)
+			return f.query(L, L.CheckString(1))
+		},
+	}))
+	return 1
+}
+
+func (f *Fakes) query(L *lua.LState, query string) int {
+	normalized := strings.Join(strings.Fields(query), " ")
+	for _, fixture := range f.queries {
+		if strings.Join(strings.Fields(fixture.Query), " ") != normalized {
+			continue
+		}
+
+		if fixture.Error != "" {
+			return failure(L, "nsQL error: "+fixture.Error)
+		}
+
+		response, err := util.ToLua(L, fixture.Response)
+		if err != nil {
+			return failure(L, "nsQL error: "+err.Error())
+		}
+
+		L.Push(response)
+		return 1
+	}
+
+	return failure(L, "nsQL error: no fixture for query: "+normalized)
+}
+
+func (f *Fakes) nsKV(L *lua.LState) int {
+	L.Push(L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"get": func(L *lua.LState) int {
+			if value, ok := f.KV[L.CheckString(1)]; ok {
+				L.Push(lua.LString(value))
+			} else {
+				L.Push(lua.LNil)
+			}
+			return 1
+		},
+		"set": func(L *lua.LState) int {
+			f.KV[L.CheckString(1)] = lua.LVAsString(L.CheckAny(2))
+			return 0
+		},
+		"del": func(L *lua.LState) int {
+			delete(f.KV, L.CheckString(1))
+			return 0
+		},
+	}))
+	return 1
+}
+
+func (f *Fakes) nsLog(L *lua.LState) int {
+	api := make(map[string]lua.LGFunction)
+	for _, level := range []string{invocations.LOG_DEBUG, invocations.LOG_INFO, invocations.LOG_WARN, invocations.LOG_ERROR} {
+		level := level
+		api[level] = func(L *lua.LState) int {
+			f.Logs = append(f.Logs, &invocations.LogEntry{Time: time.Now(),
+				Level:   level,
+				Message: L.CheckString(1),
+				Fields:  nsLog.Fields(L.OptTable(2, nil))})
+			return 0
+		}
+	}
+
+	L.Push(L.SetFuncs(L.NewTable(), api))
+	return 1
+}
+
+func (f *Fakes) nsObject(L *lua.LState) int {
+	api := map[string]lua.LGFunction{
+		"createBucket": func(L *lua.LState) int {
+			name := L.CheckString(1)
+			if _, ok := f.Objects[name]; !ok {
+				f.Objects[name] = make(map[string]Object)
+			}
+			return 0
+		},
+		"deleteBucket": func(L *lua.LState) int {
+			name := L.CheckString(1)
+			if _, ok := f.Objects[name]; !ok {
+				L.Push(lua.LString("nsObject error: Failed to delete bucket: " + name))
+				return 1
+			}
+			delete(f.Objects, name)
+			return 0
+		},
+		"listBuckets": func(L *lua.LState) int {
+			arr := L.NewTable()
+			var names []string
+			for name := range f.Objects {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				tbl := L.NewTable()
+				tbl.RawSetH(lua.LString("name"), lua.LString(name))
+				tbl.RawSetH(lua.LString("date"), lua.LString(time.Time{}.String()))
+				arr.Append(tbl)
+			}
+			L.Push(arr)
+			return 1
+		},
+		"uploadFile": func(L *lua.LState) int {
+			bucket, name := L.CheckString(1), L.CheckString(2)
+			payload, err := toBytes(L.CheckAny(3))
+			if err != nil {
+				L.Push(lua.LString("nsObject error: " + err.Error()))
+				return 1
+			}
+
+			files, ok := f.Objects[bucket]
+			if !ok {
+				L.Push(lua.LString("nsObject error: Failed to upload file " + name))
+				return 1
+			}
+			files[name] = Object{Payload: string(payload), ContentType: L.CheckString(4)}
+			return 0
+		},
+		"downloadFile": func(L *lua.LState) int {
+			bucket, name := L.CheckString(1), L.CheckString(2)
+			object, ok := f.Objects[bucket][name]
+			if !ok {
+				return failure(L, "nsObject error: Failed to download file "+name)
+			}
+
+			payload := L.CreateTable(len(object.Payload), 0)
+			for _, b := range []byte(object.Payload) {
+				payload.Append(lua.LNumber(b))
+			}
+
+			output := L.NewTable()
+			output.RawSetH(lua.LString("Payload"), payload)
+			output.RawSetH(lua.LString("ContentType"), lua.LString(object.ContentType))
+			L.Push(output)
+			return 1
+		},
+		"deleteFile": func(L *lua.LState) int {
+			bucket, name := L.CheckString(1), L.CheckString(2)
+			if _, ok := f.Objects[bucket][name]; !ok {
+				L.Push(lua.LString("nsObject error: Failed to delete file " + name))
+				return 1
+			}
+			delete(f.Objects[bucket], name)
+			return 0
+		},
+		"listFiles": func(L *lua.LState) int {
+			files, ok := f.Objects[L.CheckString(1)]
+			if !ok {
+				return failure(L, "nsObject error: Failed to list files")
+			}
+
+			arr := L.NewTable()
+			var names []string
+			for name := range files {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				tbl := L.NewTable()
+				tbl.RawSetH(lua.LString("key"), lua.LString(name))
+				tbl.RawSetH(lua.LString("size"), lua.LString(fmt.Sprint(len(files[name].Payload))))
+				arr.Append(tbl)
+			}
+			L.Push(arr)
+			return 1
+		},
+		"signedUrl": func(L *lua.LState) int {
+			bucket, name := L.CheckString(1), L.CheckString(2)
+			method, expiresIn := strings.ToUpper(L.OptString(3, "GET")), L.OptInt64(4, 3600)
+			files, ok := f.Objects[bucket]
+			_, exists := files[name]
+			if !ok || (method == "GET" && !exists) || (method != "GET" && method != "PUT") {
+				return failure(L, "nsObject error: Failed to sign url of file "+name)
+			}
+
+			// The fake url is deterministic, so cases can expect it.
+			L.Push(lua.LString(fmt.Sprintf("/object/v1/signed/%s/%s?method=%s&expiresIn=%d",
+				bucket, name, method, expiresIn)))
+			return 1
+		},
+	}
+
+	streams := &nsObject.Streams{Store: &objectStore{fakes: f, uploads: make(map[string]*pendingUpload)},
+		Error: func(L *lua.LState, err string, context string) int {
+			switch context {
+			case nsObject.WRITE, nsObject.CLOSE, nsObject.ABORT, nsObject.COPY_FILE, nsObject.MOVE_FILE:
+				L.Push(lua.LString("nsObject error: " + err))
+				return 1
+			}
+			return failure(L, "nsObject error: "+err)
+		},
+		Done: func(context string) {}}
+	streams.Register(api)
+
+	L.Push(L.SetFuncs(L.NewTable(), api))
+	return 1
+}
+
+func (f *Fakes) nsFTP(L *lua.LState) int {
+	L.Push(L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"connect": func(L *lua.LState) int {
+			L.Push(f.connection(L, "nsFTP", L.CheckString(1)))
+			return 1
+		},
+	}))
+	return 1
+}
+
+func (f *Fakes) nsSFTP(L *lua.LState) int {
+	L.Push(L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"connect": func(L *lua.LState) int {
+			var destination nsSFTP.Destination
+			if err := gluamapper.Map(L.CheckTable(1), &destination); err != nil {
+				return failure(L, "nsSFTP error: "+err.Error())
+			}
+			L.Push(f.connection(L, "nsSFTP", destination.HostPort))
+			return 1
+		},
+	}))
+	return 1
+}
+
+// connection is the fake of a connection of the FTP or SFTP module to the
+// server, its files kept in Files.
+func (f *Fakes) connection(L *lua.LState, module string, hostport string) *lua.LUserData {
+	noop := func(L *lua.LState) int {
+		return 0
+	}
+
+	methods := map[string]lua.LGFunction{
+		"login":      noop,
+		"logout":     noop,
+		"disconnect": noop,
+		"mkdir":      noop,
+		"store": func(L *lua.LState) int {
+			fs := L.CheckUserData(1).Value.(*remoteFiles)
+			fs.Store(L.CheckString(2), []byte(L.CheckString(3)))
+			return 0
+		},
+	}
+	shared := &remotefs.Methods{Objects: f,
+		Synced: f,
+		Error: func(L *lua.LState, err string, method string) int {
+			return failure(L, module+" error: "+err)
+		},
+		Done: func(method string) {}}
+	shared.Register(methods)
+
+	mt := L.NewTable()
+	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), methods))
+	connection := L.NewUserData()
+	connection.Value = &remoteFiles{files: f.Files, hostport: hostport}
+	L.SetMetatable(connection, mt)
+	return connection
+}
+
+// Synced returns the files synced by nsFTP and nsSFTP during the case.
+func (f *Fakes) Synced(syncId string) ([]*syncs.SyncedFile, error) {
+	var files []*syncs.SyncedFile
+	for _, file := range f.syncs[syncId] {
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func (f *Fakes) Record(syncId string, files []*syncs.SyncedFile) error {
+	if _, ok := f.syncs[syncId]; !ok {
+		f.syncs[syncId] = make(map[string]*syncs.SyncedFile)
+	}
+
+	for _, file := range files {
+		f.syncs[syncId][file.Path] = file
+	}
+	return nil
+}
+
+// Upload stores the files retrieved and synced by nsFTP and nsSFTP into
+// Objects.
+func (f *Fakes) Upload(bucket string, file string, data []byte, contentType string) error {
+	if _, ok := f.Objects[bucket]; !ok {
+		return fmt.Errorf("Failed to upload file %s", file)
+	}
+
+	f.Objects[bucket][file] = Object{Payload: string(data), ContentType: contentType}
+	return nil
+}
+
+func (f *Fakes) httpModule(L *lua.LState) int {
+	api := map[string]lua.LGFunction{
+		"request": func(L *lua.LState) int {
+			return f.request(L, L.CheckString(1), L.CheckString(2), L.OptTable(3, L.NewTable()))
+		},
+	}
+
+	for _, method := range []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD"} {
+		method := method
+		api[strings.ToLower(method)] = func(L *lua.LState) int {
+			return f.request(L, method, L.CheckString(1), L.OptTable(2, L.NewTable()))
+		}
+	}
+
+	L.Push(L.SetFuncs(L.NewTable(), api))
+	return 1
+}
+
+func (f *Fakes) request(L *lua.LState, method string, rawURL string, options *lua.LTable) int {
+	method = strings.ToUpper(method)
+	if query, ok := options.RawGetString("query").(lua.LString); ok {
+		rawURL += "?" + string(query)
+	}
+
+	fixture := f.fixture(method, rawURL)
+	if fixture == nil && f.Recorder != nil {
+		var err error
+		if fixture, err = f.record(method, rawURL, options); err != nil {
+			return failure(L, err.Error())
+		}
+	}
+
+	if fixture == nil {
+		return failure(L, fmt.Sprintf("no fixture for %s %s", method, rawURL))
+	}
+
+	headers := L.NewTable()
+	for name, value := range fixture.Headers {
+		headers.RawSetString(name, lua.LString(value))
+	}
+
+	response := L.NewTable()
+	response.RawSetString("body", lua.LString(fixture.Body))
+	response.RawSetString("body_size", lua.LNumber(len(fixture.Body)))
+	response.RawSetString("headers", headers)
+	response.RawSetString("cookies", L.NewTable())
+	response.RawSetString("status_code", lua.LNumber(fixture.Status))
+	response.RawSetString("url", lua.LString(rawURL))
+	L.Push(response)
+	return 1
+}
+
+func (f *Fakes) fixture(method string, rawURL string) *HttpFixture {
+	for _, fixture := range f.http {
+		if strings.ToUpper(fixture.Method) == method && fixture.URL == rawURL {
+			return fixture
+		}
+	}
+
+	return nil
+}
+
+// record sends the request and keeps the response as a fixture.
+func (f *Fakes) record(method string, rawURL string, options *lua.LTable) (*HttpFixture, error) {
+	body := ""
+	if value, ok := options.RawGetString("body").(lua.LString); ok {
+		body = string(value)
+	}
+
+	request, err := http.NewRequest(method, rawURL, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if headers, ok := options.RawGetString("headers").(*lua.LTable); ok {
+		headers.ForEach(func(name lua.LValue, value lua.LValue) {
+			request.Header.Set(name.String(), value.String())
+		})
+	}
+
+	response, err := f.Recorder.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &HttpFixture{Method: method,
+		URL:     rawURL,
+		Status:  response.StatusCode,
+		Headers: make(map[string]string),
+		Body:    string(data)}
+	for name := range response.Header {
+		fixture.Headers[name] = response.Header.Get(name)
+	}
+
+	f.http = append(f.http, fixture)
+	f.Recorded = append(f.Recorded, fixture)
+	return fixture, nil
+}
+
+func failure(L *lua.LState, err string) int {
+	L.Push(lua.LNil)
+	L.Push(lua.LString(err))
+	return 2
+}
+
+func toBytes(value lua.LValue) ([]byte, error) {
+	switch v := value.(type) {
+	case lua.LString:
+		return []byte(string(v)), nil
+	case *lua.LTable:
+		var data []byte
+		for i := 1; i <= v.MaxN(); i++ {
+			b, ok := v.RawGetInt(i).(lua.LNumber)
+			if !ok {
+				return nil, fmt.Errorf("unexpected value in array, byte expected")
+			}
+			data = append(data, byte(b))
+		}
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("unexpected value, string or byte array expected")
+}
@@ -0,0 +1,151 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snippettest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// TEST_FILE_SUFFIX is appended to the name of the snippet, without its
+	// extension, to find the test file colocated with it, e.g. the tests of
+	// transform.lua are in transform_test.json.
+	TEST_FILE_SUFFIX = "_test.json"
+	SNIPPET_EXT      = ".lua"
+)
+
+// Suite is the content of a test file: the cases run against one snippet.
+type Suite struct {
+	MainFn string  `json:"mainfn,omitempty"`
+	Cases  []*Case `json:"cases"`
+}
+
+// Case runs the snippet once with the arguments and fixtures and asserts on
+// its output and on the effects recorded by the fake modules.
+type Case struct {
+	Name     string                 `json:"name"`
+	Args     map[string]interface{} `json:"args,omitempty"`
+	Timeout  int                    `json:"timeout,omitempty"`
+	Fixtures Fixtures               `json:"fixtures"`
+	Expect   Expect                 `json:"expect"`
+}
+
+// Fixtures are the data served by the fake modules. Files are the files on
+// the FTP and SFTP servers, keyed like the files expected. Libraries are the
+// code of the libraries the snippet requires, keyed by <name>@<version>.
+type Fixtures struct {
+	Queries   []*QueryFixture              `json:"nsQL,omitempty"`
+	KV        map[string]string            `json:"nsKV,omitempty"`
+	Objects   map[string]map[string]Object `json:"nsObject,omitempty"`
+	Files     map[string]string            `json:"files,omitempty"`
+	Http      []*HttpFixture               `json:"http,omitempty"`
+	Libraries map[string]string            `json:"libraries,omitempty"`
+}
+
+// QueryFixture is the response of nsQL to the query.
+type QueryFixture struct {
+	Query    string      `json:"query"`
+	Response interface{} `json:"response,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// HttpFixture is a recorded response of the http module to the request.
+type HttpFixture struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// Object is a file of a bucket served by nsObject.
+type Object struct {
+	Payload     string `json:"payload"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// Expect are the assertions of the case. Empty fields are not asserted.
+// Result is compared as a string when it is one, otherwise as the JSON
+// document the snippet returned, e.g. an nsOutput table.
+type Expect struct {
+	Status  string                       `json:"status,omitempty"`
+	Error   string                       `json:"error,omitempty"`
+	Result  interface{}                  `json:"result,omitempty"`
+	Stdout  *string                      `json:"stdout,omitempty"`
+	KV      map[string]string            `json:"nsKV,omitempty"`
+	Objects map[string]map[string]string `json:"nsObject,omitempty"`
+	Files   map[string]string            `json:"files,omitempty"`
+}
+
+// TestFile returns the path of the test file colocated with the snippet.
+func TestFile(snippet string) string {
+	return strings.TrimSuffix(snippet, filepath.Ext(snippet)) + TEST_FILE_SUFFIX
+}
+
+// Discover returns the snippets under the directory which have a test file.
+func Discover(dir string) ([]string, error) {
+	var snippets []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || filepath.Ext(path) != SNIPPET_EXT {
+			return nil
+		}
+
+		if _, err := os.Stat(TestFile(path)); err == nil {
+			snippets = append(snippets, path)
+		}
+		return nil
+	})
+
+	return snippets, err
+}
+
+// LoadSuite reads the test file.
+func LoadSuite(path string) (*Suite, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var suite Suite
+	if err := json.Unmarshal(data, &suite); err != nil {
+		return nil, err
+	}
+
+	if suite.MainFn == "" {
+		suite.MainFn = "main"
+	}
+
+	return &suite, nil
+}
+
+// Save writes the suite to the test file, e.g. after recording fixtures.
+func (s *Suite) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
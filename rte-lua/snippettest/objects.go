@@ -0,0 +1,147 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snippettest
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lavaorg/northstar/object/model"
+)
+
+// objectStore serves the streaming functions of the fake nsObject from the
+// objects of the fakes. Multipart uploads are kept aside until they are
+// completed.
+type objectStore struct {
+	fakes   *Fakes
+	uploads map[string]*pendingUpload
+	next    int
+}
+
+type pendingUpload struct {
+	bucket      string
+	file        string
+	contentType string
+	parts       map[int64][]byte
+}
+
+func (s *objectStore) object(bucket string, file string) (Object, error) {
+	object, ok := s.fakes.Objects[bucket][file]
+	if !ok {
+		return Object{}, fmt.Errorf("Failed to stat file %s", file)
+	}
+	return object, nil
+}
+
+func (s *objectStore) Stat(bucket string, file string) (*model.Object, error) {
+	object, err := s.object(bucket, file)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Object{Key: file, Size: int64(len(object.Payload))}, nil
+}
+
+func (s *objectStore) ReadRange(bucket string, file string, offset int64, length int64) ([]byte, error) {
+	object, err := s.object(bucket, file)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to download file %s", file)
+	}
+
+	size := int64(len(object.Payload))
+	if offset > size {
+		return nil, fmt.Errorf("Failed to download file %s", file)
+	}
+
+	end := size
+	if length > 0 && offset+length < size {
+		end = offset + length
+	}
+	return []byte(object.Payload[offset:end]), nil
+}
+
+func (s *objectStore) Upload(bucket string, file string, data []byte, contentType string) error {
+	return s.fakes.Upload(bucket, file, data, contentType)
+}
+
+func (s *objectStore) CreateUpload(bucket string, file string, contentType string) (string, error) {
+	if _, ok := s.fakes.Objects[bucket]; !ok {
+		return "", fmt.Errorf("Failed to upload file %s", file)
+	}
+
+	s.next++
+	uploadId := fmt.Sprintf("upload-%d", s.next)
+	s.uploads[uploadId] = &pendingUpload{bucket: bucket,
+		file:        file,
+		contentType: contentType,
+		parts:       make(map[int64][]byte)}
+	return uploadId, nil
+}
+
+func (s *objectStore) UploadPart(bucket string,
+	file string,
+	uploadId string,
+	part int64,
+	data []byte) (*model.Part, error) {
+	upload, ok := s.uploads[uploadId]
+	if !ok {
+		return nil, fmt.Errorf("Failed to upload file %s", file)
+	}
+
+	upload.parts[part] = append([]byte(nil), data...)
+	return &model.Part{PartNumber: part, Etag: fmt.Sprintf("%s-%d", uploadId, part)}, nil
+}
+
+func (s *objectStore) CompleteUpload(bucket string, file string, uploadId string, parts []model.Part) error {
+	upload, ok := s.uploads[uploadId]
+	if !ok {
+		return fmt.Errorf("Failed to upload file %s", file)
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+
+	var data []byte
+	for _, part := range parts {
+		data = append(data, upload.parts[part.PartNumber]...)
+	}
+
+	delete(s.uploads, uploadId)
+	return s.fakes.Upload(upload.bucket, upload.file, data, upload.contentType)
+}
+
+func (s *objectStore) AbortUpload(bucket string, file string, uploadId string) error {
+	delete(s.uploads, uploadId)
+	return nil
+}
+
+func (s *objectStore) Copy(bucket string, file string, toBucket string, toFile string, move bool) error {
+	object, err := s.object(bucket, file)
+	if err != nil {
+		return fmt.Errorf("Failed to copy file %s", file)
+	}
+
+	if _, ok := s.fakes.Objects[toBucket]; !ok {
+		return fmt.Errorf("Failed to copy file %s", file)
+	}
+
+	s.fakes.Objects[toBucket][toFile] = object
+	if move {
+		delete(s.fakes.Objects[bucket], file)
+	}
+	return nil
+}
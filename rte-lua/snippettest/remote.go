@@ -0,0 +1,132 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snippettest
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/lavaorg/northstar/rte-lua/modules/remotefs"
+)
+
+// remoteFiles is the file system of a fake FTP or SFTP server, its files
+// kept in the files of the fakes under the host and port of the server.
+// Directories exist as long as they hold files.
+type remoteFiles struct {
+	files    map[string]string
+	hostport string
+}
+
+func (fs *remoteFiles) key(p string) string {
+	return path.Join(fs.hostport, p)
+}
+
+func (fs *remoteFiles) List(dir string) ([]*remotefs.FileInfo, error) {
+	prefix := fs.key(dir) + "/"
+	seen := make(map[string]bool)
+	var files []*remotefs.FileInfo
+	for key, data := range fs.files {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(key, prefix)
+		isDir := strings.Contains(name, "/")
+		if isDir {
+			name = name[:strings.Index(name, "/")]
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		file := &remotefs.FileInfo{Name: name, Path: path.Join(dir, name), IsDir: isDir}
+		if !isDir {
+			file.Size = int64(len(data))
+		}
+		files = append(files, file)
+	}
+
+	if len(files) == 0 {
+		if _, err := fs.Stat(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	return files, nil
+}
+
+func (fs *remoteFiles) Stat(p string) (*remotefs.FileInfo, error) {
+	key := fs.key(p)
+	if key == fs.hostport {
+		return &remotefs.FileInfo{Name: "/", Path: p, IsDir: true}, nil
+	}
+
+	if data, ok := fs.files[key]; ok {
+		return &remotefs.FileInfo{Name: path.Base(key), Path: p, Size: int64(len(data))}, nil
+	}
+
+	for name := range fs.files {
+		if strings.HasPrefix(name, key+"/") {
+			return &remotefs.FileInfo{Name: path.Base(key), Path: p, IsDir: true}, nil
+		}
+	}
+
+	return nil, remotefs.ErrNotExist
+}
+
+func (fs *remoteFiles) Retrieve(p string) ([]byte, error) {
+	data, ok := fs.files[fs.key(p)]
+	if !ok {
+		return nil, remotefs.ErrNotExist
+	}
+
+	return []byte(data), nil
+}
+
+func (fs *remoteFiles) Store(p string, data []byte) error {
+	fs.files[fs.key(p)] = string(data)
+	return nil
+}
+
+func (fs *remoteFiles) Mkdir(p string) error {
+	return nil
+}
+
+func (fs *remoteFiles) Rename(from string, to string) error {
+	data, ok := fs.files[fs.key(from)]
+	if !ok {
+		return remotefs.ErrNotExist
+	}
+
+	delete(fs.files, fs.key(from))
+	fs.files[fs.key(to)] = data
+	return nil
+}
+
+func (fs *remoteFiles) Delete(p string) error {
+	if _, ok := fs.files[fs.key(p)]; !ok {
+		return remotefs.ErrNotExist
+	}
+
+	delete(fs.files, fs.key(p))
+	return nil
+}
@@ -0,0 +1,227 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snippettest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lavaorg/lua"
+	"github.com/lavaorg/northstar/rte-lua/interpreter"
+	"github.com/lavaorg/northstar/rte/rtepub"
+)
+
+const (
+	TEST_ACCOUNT_ID = "snippettest"
+	DEFAULT_TIMEOUT = 5000
+)
+
+// Result is the outcome of a case.
+type Result struct {
+	Name     string
+	Output   *rtepub.Output
+	Failures []string
+}
+
+func (r *Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// Runner runs the cases of a suite against the code of a snippet.
+type Runner struct {
+	// Recorder, when set, sends the http requests of the snippet which have
+	// no fixture. Their responses are added to the fixtures of the case.
+	Recorder *http.Client
+}
+
+func NewRunner() *Runner {
+	return &Runner{}
+}
+
+// Run runs the cases of the suite in order.
+func (r *Runner) Run(code string, suite *Suite) []*Result {
+	results := make([]*Result, 0, len(suite.Cases))
+	for _, c := range suite.Cases {
+		results = append(results, r.RunCase(code, suite.MainFn, c))
+	}
+
+	return results
+}
+
+// RunCase runs the snippet in a state created by the interpreter, with the
+// modules reaching outside the rte replaced by fakes serving the fixtures of
+// the case.
+func (r *Runner) RunCase(code string, mainFn string, c *Case) *Result {
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = DEFAULT_TIMEOUT
+	}
+
+	input := &rtepub.Input{AccountId: TEST_ACCOUNT_ID,
+		InvocationId: c.Name,
+		MainFn:       mainFn,
+		Code:         code,
+		Timeout:      timeout,
+		Args:         c.Args}
+
+	result := &Result{Name: c.Name}
+	state, err := interpreter.CreateState(input)
+	if err != nil {
+		result.Output = &rtepub.Output{Status: rtepub.STATE_CREATE_FAILED, ErrorDescr: err.Error()}
+		result.Failures = []string{"failed to create state: " + err.Error()}
+		return result
+	}
+	defer state.Close()
+
+	fakes := NewFakes(&c.Fixtures)
+	fakes.Recorder = r.Recorder
+	fakes.Preload(state.LuaState)
+	if state.Output != nil {
+		state.Output.Objects = fakes
+	}
+	state.Libraries = interpreter.NewLibraryCache(fakes, 0, 0)
+
+	result.Output = run(state, input)
+	c.Fixtures.Http = append(c.Fixtures.Http, fakes.Recorded...)
+	result.Failures = check(&c.Expect, result.Output, fakes)
+	return result
+}
+
+func run(state *interpreter.State, input *rtepub.Input) *rtepub.Output {
+	startedOn := time.Now()
+	if err := state.LuaState.DoString(input.Code); err != nil {
+		execError := rtepub.GetExecutionError(err, nil)
+		return &rtepub.Output{StartedOn: startedOn,
+			Status:     execError.Status,
+			ErrorDescr: execError.Description}
+	}
+
+	err := state.LuaState.CallByParam(lua.P{
+		Fn:      state.LuaState.GetGlobal(input.MainFn),
+		NRet:    1,
+		Protect: true,
+	})
+
+	output := &rtepub.Output{StartedOn: startedOn, FinishedOn: time.Now()}
+	output.ElapsedTime = output.FinishedOn.Sub(startedOn)
+	if state.Output != nil {
+		output.Stdout = strings.Join(state.Output.Stdout, "")
+	}
+
+	if err != nil {
+		execError := rtepub.GetExecutionError(err, nil)
+		output.Status = execError.Status
+		output.ErrorDescr = execError.Description
+		return output
+	}
+
+	output.Result = lua.LVAsString(state.LuaState.Get(-1))
+	state.LuaState.Pop(1)
+	if state.Output != nil && state.Output.Result != "" {
+		output.Result = state.Output.Result
+	}
+
+	output.Status = rtepub.SNIPPET_RUN_FINISHED
+	return output
+}
+
+// check returns the expectations of the case the run did not meet.
+func check(expect *Expect, output *rtepub.Output, fakes *Fakes) []string {
+	var failures []string
+
+	status := expect.Status
+	if status == "" {
+		status = rtepub.SNIPPET_RUN_FINISHED
+	}
+
+	if output.Status != status {
+		failures = append(failures, fmt.Sprintf("status: expected %s, got %s %s",
+			status, output.Status, output.ErrorDescr))
+	}
+
+	if expect.Error != "" && !strings.Contains(output.ErrorDescr, expect.Error) {
+		failures = append(failures, fmt.Sprintf("error: expected %q in %q", expect.Error, output.ErrorDescr))
+	}
+
+	if expect.Stdout != nil && *expect.Stdout != output.Stdout {
+		failures = append(failures, fmt.Sprintf("stdout: expected %q, got %q", *expect.Stdout, output.Stdout))
+	}
+
+	if expect.Result != nil && !equalResult(expect.Result, output.Result) {
+		failures = append(failures, fmt.Sprintf("result: expected %v, got %s", expect.Result, output.Result))
+	}
+
+	for _, key := range keys(expect.KV) {
+		if value, ok := fakes.KV[key]; !ok || value != expect.KV[key] {
+			failures = append(failures, fmt.Sprintf("nsKV %s: expected %q, got %q", key, expect.KV[key], value))
+		}
+	}
+
+	buckets := make([]string, 0, len(expect.Objects))
+	for bucket := range expect.Objects {
+		buckets = append(buckets, bucket)
+	}
+	sort.Strings(buckets)
+
+	for _, bucket := range buckets {
+		files := expect.Objects[bucket]
+		for _, name := range keys(files) {
+			if object, ok := fakes.Objects[bucket][name]; !ok || object.Payload != files[name] {
+				failures = append(failures, fmt.Sprintf("nsObject %s/%s: expected %q, got %q",
+					bucket, name, files[name], object.Payload))
+			}
+		}
+	}
+
+	for _, name := range keys(expect.Files) {
+		if data, ok := fakes.Files[name]; !ok || data != expect.Files[name] {
+			failures = append(failures, fmt.Sprintf("file %s: expected %q, got %q", name, expect.Files[name], data))
+		}
+	}
+
+	return failures
+}
+
+// equalResult compares the result as a string when the expectation is one,
+// otherwise as the JSON document the snippet returned.
+func equalResult(expected interface{}, result string) bool {
+	if str, ok := expected.(string); ok {
+		return str == result
+	}
+
+	var actual interface{}
+	if err := json.Unmarshal([]byte(result), &actual); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(expected, actual)
+}
+
+func keys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
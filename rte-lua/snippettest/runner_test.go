@@ -0,0 +1,191 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snippettest
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/lavaorg/northstar/rte-lua/modules/nsObject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColocatedSuite(t *testing.T) {
+	snippets, err := Discover("testdata")
+	require.NoError(t, err)
+	require.Equal(t, []string{"testdata/transform.lua"}, snippets)
+
+	code, err := ioutil.ReadFile(snippets[0])
+	require.NoError(t, err)
+
+	suite, err := LoadSuite(TestFile(snippets[0]))
+	require.NoError(t, err)
+
+	for _, result := range NewRunner().Run(string(code), suite) {
+		assert.True(t, result.Passed(), "%s: %v", result.Name, result.Failures)
+	}
+}
+
+func TestFailedExpectations(t *testing.T) {
+	code := `
+		local nsKV = require("nsKV")
+		local http = require("http")
+		function main()
+			nsKV.set("counter", "1")
+			local response, err = http.get("http://example.com")
+			return err
+		end
+	`
+	stdout := "hello"
+	c := &Case{Name: "unmet", Expect: Expect{Result: "ok",
+		Stdout: &stdout,
+		KV:     map[string]string{"counter": "2"}}}
+
+	result := NewRunner().RunCase(code, "main", c)
+	require.False(t, result.Passed())
+	assert.Equal(t, "no fixture for GET http://example.com", result.Output.Result)
+	assert.Len(t, result.Failures, 3)
+}
+
+func TestRemoteFiles(t *testing.T) {
+	code := `
+		local nsSFTP = require("nsSFTP")
+		function main()
+			local conn = nsSFTP.connect({HostPort = "sftp.example.com:22"})
+			local names = {}
+			for _, file in ipairs(conn:glob("/in/*/*.csv")) do
+				table.insert(names, file.path .. ":" .. file.size)
+			end
+
+			local data = conn:retrieve("/in/a/1.csv")
+			local bytes = conn:retrieve("/in/a/1.csv", "bytes")
+			conn:retrieve("/in/b/2.csv", {bucket = "imports", file = "2.csv", contentType = "text/csv"})
+			conn:rename("/in/a/1.csv", "/done/1.csv")
+			conn:delete("/in/b/2.csv")
+			local _, err = conn:stat("/in/b/2.csv")
+			return table.concat(names, ",") .. ";" .. data .. ";" .. #bytes .. ";" .. err
+		end
+	`
+	c := &Case{Name: "remote",
+		Fixtures: Fixtures{Files: map[string]string{
+			"sftp.example.com:22/in/a/1.csv": "a,b",
+			"sftp.example.com:22/in/b/2.csv": "c",
+			"sftp.example.com:22/in/b/3.txt": "d",
+			"sftp.example.com:22/in/readme":  "e"},
+			Objects: map[string]map[string]Object{"imports": {}}},
+		Expect: Expect{Result: "/in/a/1.csv:3,/in/b/2.csv:1;a,b;3;nsSFTP error: file does not exist",
+			Objects: map[string]map[string]string{"imports": {"2.csv": "c"}},
+			Files: map[string]string{"sftp.example.com:22/done/1.csv": "a,b",
+				"sftp.example.com:22/in/b/3.txt": "d"}}}
+
+	result := NewRunner().RunCase(code, "main", c)
+	assert.True(t, result.Passed(), "%v %v", result.Output, result.Failures)
+}
+
+func TestRemoteSync(t *testing.T) {
+	code := `
+		local nsFTP = require("nsFTP")
+		function main()
+			local conn = nsFTP.connect("ftp.example.com:21")
+			local options = {dir = "/drop", bucket = "imports", prefix = "drop/",
+				recursive = true, include = {"*.csv"}, exclude = {"tmp/*"}}
+			local first = conn:sync(options)
+			conn:store("/drop/b.csv", "changed")
+			local second = conn:sync(options)
+			return #first.transferred .. "," .. #second.transferred .. "," ..
+				second.transferred[1].object .. "," .. second.skipped
+		end
+	`
+	c := &Case{Name: "sync",
+		Fixtures: Fixtures{Files: map[string]string{
+			"ftp.example.com:21/drop/a.csv":     "a",
+			"ftp.example.com:21/drop/b.csv":     "b",
+			"ftp.example.com:21/drop/sub/c.csv": "c",
+			"ftp.example.com:21/drop/tmp/d.csv": "d",
+			"ftp.example.com:21/drop/e.txt":     "e"},
+			Objects: map[string]map[string]Object{"imports": {}}},
+		Expect: Expect{Result: "3,1,drop/b.csv,2",
+			Objects: map[string]map[string]string{"imports": {
+				"drop/a.csv":     "a",
+				"drop/b.csv":     "changed",
+				"drop/sub/c.csv": "c"}}}}
+
+	result := NewRunner().RunCase(code, "main", c)
+	assert.True(t, result.Passed(), "%v %v", result.Output, result.Failures)
+}
+
+func TestObjectStreams(t *testing.T) {
+	chunkSize, partSize := nsObject.ChunkSize, nsObject.PartSize
+	nsObject.ChunkSize, nsObject.PartSize = 4, 8
+	defer func() {
+		nsObject.ChunkSize, nsObject.PartSize = chunkSize, partSize
+	}()
+
+	code := `
+		local nsObject = require("nsObject")
+		function main()
+			local reader = nsObject.open("logs", "app.log")
+			local writer = nsObject.create("logs", "errors.log", "text/plain")
+			local count = 0
+			for line in reader:lines() do
+				count = count + 1
+				if line:find("ERROR") then
+					writer:write(line .. "\n")
+				end
+			end
+			reader:close()
+			writer:close()
+
+			nsObject.moveFile("logs", "errors.log", "archive")
+			return count .. "," .. nsObject.readRange("logs", "app.log", 5, 5)
+		end
+	`
+	c := &Case{Name: "streams",
+		Fixtures: Fixtures{Objects: map[string]map[string]Object{
+			"logs":    {"app.log": {Payload: "INFO start\r\nERROR disk full\nINFO retry\nERROR disk still full"}},
+			"archive": {}}},
+		Expect: Expect{Result: "4,start",
+			Objects: map[string]map[string]string{"archive": {
+				"errors.log": "ERROR disk full\nERROR disk still full\n"}}}}
+
+	result := NewRunner().RunCase(code, "main", c)
+	assert.True(t, result.Passed(), "%v %v", result.Output, result.Failures)
+}
+
+func TestExport(t *testing.T) {
+	code := `
+		local nsOutput = require("nsOutput")
+		function main()
+			local table = {columns = {"device", "count"}, types = {"string", "int"},
+				rows = {{"a", 1}, {"b", 2}}}
+			local err = nsOutput.export(table, "results", "counts.csv")
+			if err ~= nil then
+				return err
+			end
+			return nsOutput.export(nsOutput.table(table), "results", "counts", "jsonl")
+		end
+	`
+	c := &Case{Name: "export",
+		Fixtures: Fixtures{Objects: map[string]map[string]Object{"results": {}}},
+		Expect: Expect{Objects: map[string]map[string]string{"results": {
+			"counts.csv": "device,count\na,1\nb,2\n",
+			"counts":     "{\"device\":\"a\",\"count\":1}\n{\"device\":\"b\",\"count\":2}\n"}}}}
+
+	result := NewRunner().RunCase(code, "main", c)
+	assert.True(t, result.Passed(), "%v %v", result.Output, result.Failures)
+}
@@ -0,0 +1,39 @@
+local nsQL = require("nsQL")
+local nsKV = require("nsKV")
+local nsObject = require("nsObject")
+local nsFTP = require("nsFTP")
+local http = require("http")
+local output = require("nsOutput")
+
+function main()
+    local source = { Protocol = "cassandra", Connection = { Keyspace = "devices" } }
+    local rows, err = nsQL.query("SELECT id, value FROM readings", source)
+    if err ~= nil then
+        error(err)
+    end
+
+    local response, err = http.get("http://thresholds.example.com/limit")
+    if err ~= nil then
+        error(err)
+    end
+    local limit = tonumber(response.body)
+
+    local report = ""
+    for _, row in ipairs(rows) do
+        if row.value > limit then
+            report = report .. row.id .. "\n"
+        end
+    end
+
+    nsKV.set("last-report", tostring(#rows))
+    nsObject.createBucket("reports")
+    nsObject.uploadFile("reports", "over-limit.txt", report, "text/plain")
+
+    local conn = nsFTP.connect("ftp.example.com:21")
+    conn:login("user", "password")
+    conn:store("/reports/over-limit.txt", report)
+    conn:disconnect()
+
+    output.printf("%v readings\n", #rows)
+    return report
+end
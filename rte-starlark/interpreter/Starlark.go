@@ -0,0 +1,168 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/rte/config"
+	"github.com/lavaorg/northstar/rte/rlimit"
+	"github.com/lavaorg/northstar/rte/rtepub"
+	"go.starlark.net/starlark"
+	"strings"
+	"time"
+)
+
+const TOO_MANY_STEPS = "too many steps"
+
+var errTerminated = errors.New("snippet terminated")
+
+type StarlarkInterpreter struct {
+	State  *State
+	rLimit rlimit.ResourceLimit
+}
+
+func NewStarlarkInterpreter(rLimit rlimit.ResourceLimit) rtepub.Interpreter {
+	return &StarlarkInterpreter{rLimit: rLimit}
+}
+
+func (i *StarlarkInterpreter) DoREPL(input *rtepub.Input) *rtepub.Output {
+	timer := Starlark.NewTimer("DoREPLTimer")
+	startedOn := time.Now()
+	mlog.Debug("Running main: %s, code: %s, args: %s, timeout: %d, memory: %v, accountId: %s, "+
+		"invocationId: %s", input.MainFn, input.Code, input.Args, input.Timeout, input.Memory,
+		input.AccountId, input.InvocationId)
+
+	state, err := CreateState(input)
+	if err != nil {
+		mlog.Error("Failed to create state: %v", err.Error())
+		timer.Stop()
+		ErrDoREPL.Incr()
+		return &rtepub.Output{StartedOn: startedOn,
+			Status:     rtepub.STATE_CREATE_FAILED,
+			ErrorDescr: err.Error()}
+	}
+
+	i.State = state
+	defer state.Close()
+
+	if config.EnableRLimit {
+		limits, err := i.rLimit.Reserve(&rlimit.Resources{Memory: input.Memory})
+		if err != nil {
+			mlog.Error("Failed to reserve resources: %v", err.Error())
+			timer.Stop()
+			ErrDoREPL.Incr()
+			return &rtepub.Output{StartedOn: startedOn,
+				Status:     rtepub.START_MONITORING_FAILED,
+				ErrorDescr: err.Error()}
+		}
+		defer i.rLimit.Release(limits)
+
+		if limits.Instructions > 0 {
+			state.Thread.SetMaxExecutionSteps(limits.Instructions)
+		}
+
+		if limits.Memory > 0 {
+			stop := rlimit.WatchHeap(limits.Memory, func() {
+				state.Thread.Cancel(rlimit.ERR_OUT_OF_MEMORY)
+			})
+			defer close(stop)
+		}
+	}
+
+	if input.Timeout > 0 {
+		deadline := time.AfterFunc(time.Duration(input.Timeout)*time.Millisecond, func() {
+			state.Thread.Cancel(rtepub.CONTEXT_DEADLINE_EXCEEDED)
+		})
+		defer deadline.Stop()
+	}
+
+	value, err := i.run(state, input)
+	finishedOn := time.Now()
+
+	var stdout string
+	if state.Output != nil {
+		stdout = strings.Join(state.Output.Stdout, "")
+	}
+
+	if err != nil {
+		mlog.Error("Run error: %v", err)
+		timer.Stop()
+		ErrDoREPL.Incr()
+		if strings.Contains(err.Error(), TOO_MANY_STEPS) {
+			rlimit.InstructionLimit.Incr()
+			err = rlimit.ErrInstructionLimit
+		}
+
+		execError := rtepub.GetExecutionError(err, nil)
+		return &rtepub.Output{StartedOn: startedOn,
+			FinishedOn: finishedOn,
+			Stdout:     stdout,
+			Status:     execError.Status,
+			ErrorDescr: execError.Description}
+	}
+
+	var result string
+	switch v := value.(type) {
+	case nil, starlark.NoneType:
+	case starlark.String:
+		result = string(v)
+	default:
+		result = v.String()
+	}
+	if state.Output != nil && state.Output.Result != "" {
+		result = state.Output.Result
+	}
+
+	timer.Stop()
+	DoREPL.Incr()
+
+	output := &rtepub.Output{
+		StartedOn:   startedOn,
+		FinishedOn:  finishedOn,
+		ElapsedTime: finishedOn.Sub(startedOn),
+		Stdout:      stdout,
+		Result:      result,
+		Status:      rtepub.SNIPPET_RUN_FINISHED,
+		ErrorDescr:  ""}
+	mlog.Debug("REPL output: %v", output)
+	return output
+}
+
+// run executes the snippet and calls its main function.
+func (i *StarlarkInterpreter) run(state *State, input *rtepub.Input) (starlark.Value, error) {
+	globals, err := starlark.ExecFile(state.Thread, input.MainFn+".star", input.Code, state.Predeclared())
+	if err != nil {
+		return nil, err
+	}
+
+	main, ok := globals[input.MainFn].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("function %s is not defined", input.MainFn)
+	}
+
+	return starlark.Call(state.Thread, main, nil, nil)
+}
+
+func (i *StarlarkInterpreter) Terminate() {
+	mlog.Debug("Terminating")
+
+	if i.State != nil {
+		i.State.Close()
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpreter
+
+import (
+	"errors"
+	"github.com/lavaorg/lrtx/config"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/rte-starlark/modules/nsKV"
+	"github.com/lavaorg/northstar/rte-starlark/modules/nsObject"
+	"github.com/lavaorg/northstar/rte-starlark/modules/nsOutput"
+	"github.com/lavaorg/northstar/rte-starlark/modules/nsQL"
+	"github.com/lavaorg/northstar/rte-starlark/util"
+	pkgCfg "github.com/lavaorg/northstar/rte/config"
+	"github.com/lavaorg/northstar/rte/rtepub"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+var (
+	EnableNSQL, _     = config.GetBool("ENABLE_NSQL", false)
+	EnableNSOutput, _ = config.GetBool("ENABLE_NSOUTPUT", true)
+	EnableNSObject, _ = config.GetBool("ENABLE_NSOBJECT", false)
+	EnableNSKV, _     = config.GetBool("ENABLE_NSKV", false)
+)
+
+// Loader creates the module value of a northstar module.
+type Loader func() *starlarkstruct.Module
+
+type State struct {
+	Thread  *starlark.Thread
+	Output  *nsOutput.NsOutputModule
+	NSQL    *nsQL.NsQLModule
+	policy  *rtepub.Policy
+	denied  map[string]error
+	modules map[string]Loader
+	loaded  map[string]*starlarkstruct.Module
+	context starlark.Value
+}
+
+// CreateState creates the thread running a snippet. The northstar modules
+// are predeclared as builtins and can also be loaded with
+// load("nsOutput", "printf"). No clock or random source is exposed so runs
+// are deterministic for the same input.
+func CreateState(input *rtepub.Input) (*State, error) {
+	args := util.ToStarlark(input.Args)
+	if input.Args == nil {
+		args = starlark.NewDict(0)
+	}
+
+	output := &State{policy: input.Policy,
+		denied:  make(map[string]error),
+		modules: make(map[string]Loader),
+		loaded:  make(map[string]*starlarkstruct.Module),
+		context: starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{"Args": args})}
+	output.Thread = &starlark.Thread{Name: input.InvocationId,
+		Load:  output.load,
+		Print: output.print}
+
+	if EnableNSQL && output.permitted("nsQL") {
+		mlog.Debug("Loading nsQL module")
+		output.NSQL = nsQL.NewNSQLModule()
+		output.PreloadModule("nsQL", output.NSQL.Loader)
+	}
+
+	if EnableNSOutput && output.permitted("nsOutput") {
+		mlog.Debug("Loading nsOutput module")
+		output.Output = nsOutput.NewNsOutputModule()
+		output.PreloadModule("nsOutput", output.Output.Loader)
+	}
+
+	if EnableNSObject && output.permitted("nsObject") {
+		mlog.Debug("Loading nsObject module")
+		nsObjectModule, err := nsObject.NewNsObjectModule(input.AccountId)
+		if err != nil {
+			return nil, err
+		}
+		output.PreloadModule("nsObject", nsObjectModule.Loader)
+	}
+
+	if EnableNSKV && output.permitted("nsKV") {
+		mlog.Debug("Loading nsKV module")
+		redisCluster, err := pkgCfg.CreateRedisCluster()
+		if err != nil {
+			return nil, err
+		}
+
+		output.PreloadModule("nsKV", nsKV.NewNsKVModule(redisCluster, input.AccountId).Loader)
+	}
+
+	return output, nil
+}
+
+// PreloadModule registers a northstar module available to the snippet.
+func (s *State) PreloadModule(name string, loader Loader) {
+	s.modules[name] = loader
+}
+
+// Predeclared returns the builtins of the snippet: the execution context
+// and the registered modules.
+func (s *State) Predeclared() starlark.StringDict {
+	predeclared := starlark.StringDict{"context": s.context}
+	for name := range s.modules {
+		module, err := s.module(name)
+		if err != nil {
+			continue
+		}
+		predeclared[name] = module
+	}
+
+	return predeclared
+}
+
+// permitted reports whether the policy of the snippet permits the module.
+// Loading a module which is not permitted reports the violation instead of
+// a missing module.
+func (s *State) permitted(name string) bool {
+	err := s.policy.CheckModule(name)
+	if err == nil {
+		return true
+	}
+
+	mlog.Debug("Not loading %s module: %v", name, err)
+	s.denied[name] = err
+	return false
+}
+
+func (s *State) module(name string) (*starlarkstruct.Module, error) {
+	if module, ok := s.loaded[name]; ok {
+		return module, nil
+	}
+
+	if err, ok := s.denied[name]; ok {
+		return nil, err
+	}
+
+	loader, ok := s.modules[name]
+	if !ok {
+		return nil, errors.New("module " + name + " not found")
+	}
+
+	module := loader()
+	s.loaded[name] = module
+	return module, nil
+}
+
+func (s *State) load(thread *starlark.Thread, name string) (starlark.StringDict, error) {
+	module, err := s.module(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return module.Members, nil
+}
+
+// print sends the output of the print builtin to the snippet stdout.
+func (s *State) print(thread *starlark.Thread, msg string) {
+	if s.Output == nil {
+		return
+	}
+
+	if err := s.Output.Write(msg + "\n"); err != nil {
+		thread.Cancel(nsOutput.NS_OUTPUT_ERROR + err.Error())
+	}
+}
+
+func (s *State) Close() {
+	s.Clean()
+	if s.Thread != nil {
+		s.Thread.Cancel(errTerminated.Error())
+	}
+}
+
+func (s *State) Clean() {
+	if s.Output != nil {
+		s.Output.Reset()
+	}
+
+	if s.NSQL != nil {
+		s.NSQL.Reset()
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsKV
+
+import (
+	"errors"
+	"github.com/lavaorg/northstar/rte-starlark/util"
+	"github.com/lavaorg/northstar/rte/rtepub"
+	"go.starlark.net/starlarkstruct"
+	"time"
+)
+
+const (
+	NS_KV_ERROR = "nsKV error: "
+	GET         = "get"
+	SET         = "set"
+	DEL         = "del"
+)
+
+// NsKVModule is the Starlark counterpart of the Lua nsKV module. Keys are
+// prefixed with the account id so accounts do not see each other's keys.
+type NsKVModule struct {
+	store     rtepub.KVStore
+	keyPrefix string
+}
+
+func NewNsKVModule(store rtepub.KVStore, keyPrefix string) *NsKVModule {
+	return &NsKVModule{store: store, keyPrefix: keyPrefix}
+}
+
+func (nsKV *NsKVModule) Loader() *starlarkstruct.Module {
+	return util.NewModule("nsKV", NS_KV_ERROR, map[string]util.Function{
+		GET: func(args []interface{}) (interface{}, error) {
+			key, err := util.String(args, 0)
+			if err != nil {
+				ErrGet.Incr()
+				return nil, err
+			}
+
+			value, err := nsKV.store.Get(nsKV.keyPrefix + key)
+			if err != nil {
+				ErrGet.Incr()
+				return nil, err
+			}
+
+			Get.Incr()
+			return value, nil
+		},
+		// set(key, value[, ttl]) stores the value, optionally expiring
+		// after ttl seconds.
+		SET: func(args []interface{}) (interface{}, error) {
+			if err := nsKV.set(args); err != nil {
+				ErrSet.Incr()
+				return nil, err
+			}
+
+			Set.Incr()
+			return nil, nil
+		},
+		DEL: func(args []interface{}) (interface{}, error) {
+			key, err := util.String(args, 0)
+			if err != nil {
+				ErrDel.Incr()
+				return nil, err
+			}
+
+			if err := nsKV.store.Del(nsKV.keyPrefix + key); err != nil {
+				ErrDel.Incr()
+				return nil, err
+			}
+
+			Del.Incr()
+			return nil, nil
+		},
+	})
+}
+
+func (nsKV *NsKVModule) set(args []interface{}) error {
+	key, err := util.String(args, 0)
+	if err != nil {
+		return err
+	}
+
+	value, err := util.String(args, 1)
+	if err != nil {
+		return err
+	}
+
+	var ttl int64
+	if arg := util.Optional(args, 2); arg != nil {
+		var ok bool
+		if ttl, ok = arg.(int64); !ok {
+			return errors.New("argument #3 must be an integer")
+		}
+	}
+
+	return nsKV.store.Set(nsKV.keyPrefix+key, value, time.Duration(ttl)*time.Second)
+}
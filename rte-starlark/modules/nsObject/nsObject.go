@@ -0,0 +1,226 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsObject
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/object/client"
+	"github.com/lavaorg/northstar/object/model"
+	"github.com/lavaorg/northstar/rte-starlark/util"
+	"go.starlark.net/starlarkstruct"
+	"strconv"
+	"time"
+)
+
+const NS_OBJECT_ERROR = "nsObject error: "
+
+// NsObjectModule is the Starlark counterpart of the Lua nsObject module.
+// File contents are exchanged as strings or bytes.
+type NsObjectModule struct {
+	Client    *client.ObjectClient
+	AccountId string
+}
+
+func NewNsObjectModule(accountId string) (*NsObjectModule, error) {
+	cli, err := client.NewObjectClient()
+	if err != nil {
+		return nil, err
+	}
+	return &NsObjectModule{Client: cli, AccountId: accountId}, nil
+}
+
+func (nsObject *NsObjectModule) Loader() *starlarkstruct.Module {
+	return util.NewModule("nsObject", NS_OBJECT_ERROR, map[string]util.Function{
+		"createBucket": nsObject.createBucket,
+		"deleteBucket": nsObject.deleteBucket,
+		"listBuckets":  nsObject.listBuckets,
+		"uploadFile":   nsObject.uploadFile,
+		"downloadFile": nsObject.downloadFile,
+		"deleteFile":   nsObject.deleteFile,
+		"listFiles":    nsObject.listFiles,
+	})
+}
+
+func (nsObject *NsObjectModule) createBucket(args []interface{}) (interface{}, error) {
+	bucketName, err := util.String(args, 0)
+	if err != nil {
+		ErrCreateBucket.Incr()
+		return nil, err
+	}
+
+	bucket := &model.Bucket{Name: bucketName, CreationDate: time.Now()}
+	if _, mErr := nsObject.Client.CreateBucket(nsObject.AccountId, bucket); mErr != nil {
+		mlog.Error(mErr.Error())
+		ErrCreateBucket.Incr()
+		return nil, fmt.Errorf("Failed to create bucket: %s", bucketName)
+	}
+
+	CreateBucket.Incr()
+	return nil, nil
+}
+
+func (nsObject *NsObjectModule) deleteBucket(args []interface{}) (interface{}, error) {
+	bucketName, err := util.String(args, 0)
+	if err != nil {
+		ErrDeleteBucket.Incr()
+		return nil, err
+	}
+
+	if mErr := nsObject.Client.DeleteBucket(nsObject.AccountId, bucketName); mErr != nil {
+		mlog.Error(mErr.Error())
+		ErrDeleteBucket.Incr()
+		return nil, fmt.Errorf("Failed to delete bucket: %s", bucketName)
+	}
+
+	DeleteBucket.Incr()
+	return nil, nil
+}
+
+func (nsObject *NsObjectModule) listBuckets(args []interface{}) (interface{}, error) {
+	buckets, mErr := nsObject.Client.ListBuckets(nsObject.AccountId)
+	if mErr != nil {
+		mlog.Error(mErr.Error())
+		ErrListBuckets.Incr()
+		return nil, errors.New("Failed to list buckets")
+	}
+
+	list := []map[string]interface{}{}
+	for _, bucket := range buckets {
+		list = append(list, map[string]interface{}{"name": bucket.Name,
+			"date": bucket.CreationDate.String()})
+	}
+
+	ListBuckets.Incr()
+	return list, nil
+}
+
+func (nsObject *NsObjectModule) uploadFile(args []interface{}) (interface{}, error) {
+	bucketName, err := util.String(args, 0)
+	if err != nil {
+		ErrUploadFile.Incr()
+		return nil, err
+	}
+
+	fileName, err := util.String(args, 1)
+	if err != nil {
+		ErrUploadFile.Incr()
+		return nil, err
+	}
+
+	contentType, err := util.String(args, 3)
+	if err != nil {
+		ErrUploadFile.Incr()
+		return nil, err
+	}
+
+	var data []byte
+	switch input := util.Optional(args, 2).(type) {
+	case string:
+		data = []byte(input)
+	case []byte:
+		data = input
+	default:
+		ErrUploadFile.Incr()
+		return nil, errors.New("unexpected value, string or bytes expected")
+	}
+
+	uploadData := &model.UploadData{FileName: fileName, Payload: data, ContentType: contentType}
+	if _, mErr := nsObject.Client.UploadFile(nsObject.AccountId, bucketName, uploadData); mErr != nil {
+		mlog.Error(mErr.Error())
+		ErrUploadFile.Incr()
+		return nil, fmt.Errorf("Failed to upload file %s", fileName)
+	}
+
+	UploadFile.Incr()
+	return nil, nil
+}
+
+func (nsObject *NsObjectModule) downloadFile(args []interface{}) (interface{}, error) {
+	bucketName, err := util.String(args, 0)
+	if err != nil {
+		ErrDownloadFile.Incr()
+		return nil, err
+	}
+
+	fileName, err := util.String(args, 1)
+	if err != nil {
+		ErrDownloadFile.Incr()
+		return nil, err
+	}
+
+	data, mErr := nsObject.Client.DownloadFile(nsObject.AccountId, bucketName, fileName)
+	if mErr != nil {
+		mlog.Error(mErr.Error())
+		ErrDownloadFile.Incr()
+		return nil, fmt.Errorf("Failed to download file %s", fileName)
+	}
+
+	DownloadFile.Incr()
+	return map[string]interface{}{"Payload": data.Payload, "ContentType": data.ContentType}, nil
+}
+
+func (nsObject *NsObjectModule) deleteFile(args []interface{}) (interface{}, error) {
+	bucketName, err := util.String(args, 0)
+	if err != nil {
+		ErrDeleteFile.Incr()
+		return nil, err
+	}
+
+	fileName, err := util.String(args, 1)
+	if err != nil {
+		ErrDeleteFile.Incr()
+		return nil, err
+	}
+
+	if mErr := nsObject.Client.DeleteFile(nsObject.AccountId, bucketName, fileName); mErr != nil {
+		mlog.Error(mErr.Error())
+		ErrDeleteFile.Incr()
+		return nil, fmt.Errorf("Failed to delete file %s", fileName)
+	}
+
+	DeleteFile.Incr()
+	return nil, nil
+}
+
+func (nsObject *NsObjectModule) listFiles(args []interface{}) (interface{}, error) {
+	bucketName, err := util.String(args, 0)
+	if err != nil {
+		ErrListFiles.Incr()
+		return nil, err
+	}
+
+	objects, mErr := nsObject.Client.ListFiles(nsObject.AccountId, bucketName)
+	if mErr != nil {
+		mlog.Error(mErr.Error())
+		ErrListFiles.Incr()
+		return nil, errors.New("Failed to list files")
+	}
+
+	list := []map[string]interface{}{}
+	for _, object := range objects {
+		list = append(list, map[string]interface{}{"key": object.Key,
+			"last_modified": object.LastModified.String(),
+			"size":          strconv.FormatInt(object.Size, 10),
+			"etag":          object.Etag,
+			"storage_class": object.StorageClass})
+	}
+
+	ListFiles.Incr()
+	return list, nil
+}
@@ -0,0 +1,236 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsOutput
+
+import (
+	"fmt"
+	"github.com/lavaorg/lrtx/config"
+	luaOutput "github.com/lavaorg/northstar/rte-lua/modules/nsOutput"
+	"github.com/lavaorg/northstar/rte-starlark/util"
+	"go.starlark.net/starlarkstruct"
+)
+
+const (
+	NS_OUTPUT_ERROR = "nsOutput error: "
+
+	_PRINT        = "print"
+	_PRINTF       = "printf"
+	_VALUE        = "value"
+	_VALUE_DIRECT = "valueDirect"
+	_TABLE        = "table"
+	_TABLE_DIRECT = "tableDirect"
+	_MAP          = "map"
+	_MAP_DIRECT   = "mapDirect"
+	_HTML         = "html"
+	_HTML_DIRECT  = "htmlDirect"
+	_CHART        = "chart"
+	_CHART_DIRECT = "chartDirect"
+	_TABLE_TO_CSV = "tableToCsv"
+)
+
+var (
+	NsOutputPrintLimit, _ = config.GetInt("NS_OUTPUT_PRINT_LIMIT", 10000)
+)
+
+// NsOutputModule is the Starlark counterpart of the Lua nsOutput module,
+// producing the same output documents.
+type NsOutputModule struct {
+	Limit   int
+	Rolling int
+	Stdout  []string
+	Result  string
+}
+
+func NewNsOutputModule() *NsOutputModule {
+	return &NsOutputModule{Limit: NsOutputPrintLimit,
+		Rolling: NsOutputPrintLimit,
+		Stdout:  []string{}}
+}
+
+func (nsOutput *NsOutputModule) Loader() *starlarkstruct.Module {
+	return util.NewModule("nsOutput", NS_OUTPUT_ERROR, map[string]util.Function{
+		_PRINT: func(args []interface{}) (interface{}, error) {
+			if err := nsOutput.Write(fmt.Sprint(args...)); err != nil {
+				ErrPrint.Incr()
+				return nil, err
+			}
+
+			Print.Incr()
+			return nil, nil
+		},
+		_PRINTF: func(args []interface{}) (interface{}, error) {
+			format, err := util.String(args, 0)
+			if err != nil {
+				ErrPrintf.Incr()
+				return nil, err
+			}
+
+			if err := nsOutput.Write(fmt.Sprintf(format, args[1:]...)); err != nil {
+				ErrPrintf.Incr()
+				return nil, err
+			}
+
+			Printf.Incr()
+			return nil, nil
+		},
+		_VALUE:        nsOutput.generator(_VALUE, false, func() interface{} { return &luaOutput.Value{} }),
+		_VALUE_DIRECT: nsOutput.generator(_VALUE_DIRECT, true, func() interface{} { return &luaOutput.Value{} }),
+		_TABLE:        nsOutput.generator(_TABLE, false, func() interface{} { return &luaOutput.Table{} }),
+		_TABLE_DIRECT: nsOutput.generator(_TABLE_DIRECT, true, func() interface{} { return &luaOutput.Table{} }),
+		_MAP:          nsOutput.generator(_MAP, false, func() interface{} { return &luaOutput.Map{} }),
+		_MAP_DIRECT:   nsOutput.generator(_MAP_DIRECT, true, func() interface{} { return &luaOutput.Map{} }),
+		_CHART:        nsOutput.generator(_CHART, false, func() interface{} { return &luaOutput.Chart{} }),
+		_CHART_DIRECT: nsOutput.generator(_CHART_DIRECT, true, func() interface{} { return &luaOutput.Chart{} }),
+		_HTML:         nsOutput.htmlGenerator(_HTML, false),
+		_HTML_DIRECT:  nsOutput.htmlGenerator(_HTML_DIRECT, true),
+		_TABLE_TO_CSV: func(args []interface{}) (interface{}, error) {
+			var table luaOutput.Table
+			if err := util.Map(util.Optional(args, 0), &table); err != nil {
+				ErrTableToCsv.Incr()
+				return nil, err
+			}
+
+			data, err := luaOutput.FormatTableCsv(&table)
+			if err != nil {
+				ErrTableToCsv.Incr()
+				return nil, err
+			}
+
+			TableToCsv.Incr()
+			return data, nil
+		},
+	})
+}
+
+func (nsOutput *NsOutputModule) Reset() {
+	nsOutput.Rolling = NsOutputPrintLimit
+	nsOutput.Stdout = nil
+	nsOutput.Result = ""
+}
+
+// Write appends to the stdout of the snippet. It is also used for the
+// Starlark print builtin.
+func (nsOutput *NsOutputModule) Write(out string) error {
+	if nsOutput.Rolling-len([]byte(out)) < 0 {
+		return fmt.Errorf("%d-byte stdout limit is exceeded", nsOutput.Limit)
+	}
+
+	nsOutput.Rolling -= len([]byte(out))
+	nsOutput.Stdout = append(nsOutput.Stdout, out)
+	return nil
+}
+
+// generator returns a function building the output document of the given
+// type from its argument. Direct functions set the result of the snippet
+// instead of returning the document.
+func (nsOutput *NsOutputModule) generator(context string,
+	direct bool,
+	newValue func() interface{}) util.Function {
+	return func(args []interface{}) (interface{}, error) {
+		value := newValue()
+		if err := util.Map(util.Optional(args, 0), value); err != nil {
+			recordErrorStats(context)
+			return nil, err
+		}
+
+		output, err := luaOutput.Generate(value)
+		if err != nil {
+			recordErrorStats(context)
+			return nil, err
+		}
+
+		return nsOutput.result(context, direct, output), nil
+	}
+}
+
+func (nsOutput *NsOutputModule) htmlGenerator(context string, direct bool) util.Function {
+	return func(args []interface{}) (interface{}, error) {
+		html, err := util.String(args, 0)
+		if err != nil {
+			recordErrorStats(context)
+			return nil, err
+		}
+
+		output, err := luaOutput.GenerateOutput("text/html", html)
+		if err != nil {
+			recordErrorStats(context)
+			return nil, err
+		}
+
+		return nsOutput.result(context, direct, output), nil
+	}
+}
+
+func (nsOutput *NsOutputModule) result(context string, direct bool, output string) interface{} {
+	recordStats(context)
+	if direct {
+		nsOutput.Result = output
+		return nil
+	}
+
+	return output
+}
+
+func recordStats(context string) {
+	switch context {
+	case _VALUE:
+		ValueCounter.Incr()
+	case _VALUE_DIRECT:
+		ValueDirectCounter.Incr()
+	case _TABLE:
+		TableCounter.Incr()
+	case _TABLE_DIRECT:
+		TableDirectCounter.Incr()
+	case _MAP:
+		MapCounter.Incr()
+	case _MAP_DIRECT:
+		MapDirectCounter.Incr()
+	case _HTML:
+		HTMLCounter.Incr()
+	case _HTML_DIRECT:
+		HTMLDirectCounter.Incr()
+	case _CHART:
+		ChartCounter.Incr()
+	case _CHART_DIRECT:
+		ChartDirectCounter.Incr()
+	}
+}
+
+func recordErrorStats(context string) {
+	switch context {
+	case _VALUE:
+		ErrValue.Incr()
+	case _VALUE_DIRECT:
+		ErrValueDirect.Incr()
+	case _TABLE:
+		ErrTable.Incr()
+	case _TABLE_DIRECT:
+		ErrTableDirect.Incr()
+	case _MAP:
+		ErrMap.Incr()
+	case _MAP_DIRECT:
+		ErrMapDirect.Incr()
+	case _HTML:
+		ErrHTML.Incr()
+	case _HTML_DIRECT:
+		ErrHTMLDirect.Incr()
+	case _CHART:
+		ErrChart.Incr()
+	case _CHART_DIRECT:
+		ErrChartDirect.Incr()
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nsQL
+
+import (
+	"errors"
+	luaQL "github.com/lavaorg/northstar/rte-lua/modules/nsQL"
+	"github.com/lavaorg/northstar/rte-lua/modules/nsQL/compiler"
+	"github.com/lavaorg/northstar/rte-lua/modules/nsQL/compiler/cassandra"
+	"github.com/lavaorg/northstar/rte-starlark/util"
+	"go.starlark.net/starlarkstruct"
+)
+
+const (
+	NSQL_ERROR = "nsQL error: "
+	CONNECT    = "connect"
+	DISCONNECT = "disconnect"
+	QUERY      = "query"
+)
+
+// NsQLModule is the Starlark counterpart of the Lua nsQL module. It runs
+// queries with the same compilers.
+type NsQLModule struct {
+	compilers []*cassandra.CassandraCompiler
+}
+
+func NewNSQLModule() *NsQLModule {
+	return &NsQLModule{}
+}
+
+func (nsQL *NsQLModule) Loader() *starlarkstruct.Module {
+	return util.NewModule("nsQL", NSQL_ERROR, map[string]util.Function{
+		CONNECT: nsQL.connect,
+		QUERY:   nsQL.queryDirect,
+	})
+}
+
+// Reset disconnects the sessions opened by the snippet.
+func (nsQL *NsQLModule) Reset() {
+	for _, compiler := range nsQL.compilers {
+		compiler.Disconnect()
+	}
+
+	nsQL.compilers = nil
+}
+
+func (nsQL *NsQLModule) connect(args []interface{}) (interface{}, error) {
+	timer := NsQL.NewTimer("ConnectTimer")
+	defer timer.Stop()
+
+	var source compiler.Source
+	if err := util.Map(util.Optional(args, 0), &source); err != nil {
+		ErrConnect.Incr()
+		return nil, err
+	}
+
+	comp, err := luaQL.NewCompiler(&source)
+	if err != nil {
+		ErrConnect.Incr()
+		return nil, err
+	}
+
+	cassandraCompiler, ok := comp.(*cassandra.CassandraCompiler)
+	if !ok {
+		ErrConnect.Incr()
+		return nil, errors.New("invalid backend or protocol")
+	}
+
+	cassandraCompiler.Connect()
+	nsQL.compilers = append(nsQL.compilers, cassandraCompiler)
+
+	members := util.NewMembers(NSQL_ERROR, map[string]util.Function{
+		QUERY: func(args []interface{}) (interface{}, error) {
+			timer := NsQL.NewTimer("QueryTimer")
+			defer timer.Stop()
+
+			response, err := run(cassandraCompiler, args, 1)
+			if err != nil {
+				ErrQuery.Incr()
+				return nil, err
+			}
+
+			Query.Incr()
+			return response, nil
+		},
+		DISCONNECT: func(args []interface{}) (interface{}, error) {
+			cassandraCompiler.Disconnect()
+			Disconnect.Incr()
+			return nil, nil
+		},
+	})
+
+	Connect.Incr()
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, members), nil
+}
+
+func (nsQL *NsQLModule) queryDirect(args []interface{}) (interface{}, error) {
+	timer := NsQL.NewTimer("QueryDirectTimer")
+	defer timer.Stop()
+
+	var source compiler.Source
+	if err := util.Map(util.Optional(args, 1), &source); err != nil {
+		ErrQuery.Incr()
+		return nil, err
+	}
+
+	comp, err := luaQL.NewCompiler(&source)
+	if err != nil {
+		ErrQuery.Incr()
+		return nil, err
+	}
+
+	response, err := run(comp, args, 2)
+	if err != nil {
+		ErrQuery.Incr()
+		return nil, err
+	}
+
+	QueryDirect.Incr()
+	return response, nil
+}
+
+// run executes the query given as first argument with the optional options
+// found at the given position.
+func run(comp compiler.Compiler, args []interface{}, optionsIndex int) (interface{}, error) {
+	query, err := util.String(args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var options compiler.Options
+	if value := util.Optional(args, optionsIndex); value != nil {
+		if err := util.Map(value, &options); err != nil {
+			return nil, err
+		}
+	}
+
+	return comp.Run(query, &options)
+}
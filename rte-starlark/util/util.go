@@ -0,0 +1,228 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Function is a module function working on Go values.
+type Function func(args []interface{}) (interface{}, error)
+
+// NewModule creates a Starlark module calling the given functions with their
+// positional arguments converted to Go values. Errors are reported with the
+// given prefix.
+func NewModule(name string, prefix string, functions map[string]Function) *starlarkstruct.Module {
+	return &starlarkstruct.Module{Name: name, Members: NewMembers(prefix, functions)}
+}
+
+// NewMembers wraps the functions as Starlark builtins.
+func NewMembers(prefix string, functions map[string]Function) starlark.StringDict {
+	members := make(starlark.StringDict, len(functions))
+	for name, function := range functions {
+		members[name] = NewBuiltin(name, prefix, function)
+	}
+
+	return members
+}
+
+// NewBuiltin wraps a single function as a Starlark builtin.
+func NewBuiltin(name string, prefix string, function Function) *starlark.Builtin {
+	return starlark.NewBuiltin(name, func(thread *starlark.Thread,
+		b *starlark.Builtin,
+		args starlark.Tuple,
+		kwargs []starlark.Tuple) (starlark.Value, error) {
+		if len(kwargs) > 0 {
+			return nil, fmt.Errorf("%s%s: unexpected keyword arguments", prefix, name)
+		}
+
+		var converted []interface{}
+		for _, arg := range args {
+			value, err := ToGo(arg)
+			if err != nil {
+				return nil, errors.New(prefix + err.Error())
+			}
+			converted = append(converted, value)
+		}
+
+		result, err := function(converted)
+		if err != nil {
+			return nil, errors.New(prefix + err.Error())
+		}
+
+		if value, ok := result.(starlark.Value); ok {
+			return value, nil
+		}
+
+		return ToStarlark(result), nil
+	})
+}
+
+// ToGo converts a Starlark value to strings, int64, float64, bools, slices
+// and string keyed maps.
+func ToGo(value starlark.Value) (interface{}, error) {
+	switch v := value.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.String:
+		return string(v), nil
+	case starlark.Bytes:
+		return []byte(v), nil
+	case starlark.Int:
+		if i, ok := v.Int64(); ok {
+			return i, nil
+		}
+		return nil, fmt.Errorf("integer %s out of range", v.String())
+	case starlark.Float:
+		return float64(v), nil
+	case *starlark.List:
+		return iterableToGo(v)
+	case starlark.Tuple:
+		return iterableToGo(v)
+	case *starlark.Dict:
+		converted := make(map[string]interface{}, v.Len())
+		for _, item := range v.Items() {
+			key, ok := starlark.AsString(item[0])
+			if !ok {
+				key = item[0].String()
+			}
+
+			element, err := ToGo(item[1])
+			if err != nil {
+				return nil, err
+			}
+			converted[key] = element
+		}
+		return converted, nil
+	default:
+		return nil, fmt.Errorf("unsupported value of type %s", value.Type())
+	}
+}
+
+func iterableToGo(iterable starlark.Iterable) ([]interface{}, error) {
+	converted := []interface{}{}
+
+	iterator := iterable.Iterate()
+	defer iterator.Done()
+
+	var element starlark.Value
+	for iterator.Next(&element) {
+		value, err := ToGo(element)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, value)
+	}
+
+	return converted, nil
+}
+
+// ToStarlark converts a Go value to its Starlark counterpart. Values of
+// unknown types are converted to their string representation.
+func ToStarlark(value interface{}) starlark.Value {
+	switch v := value.(type) {
+	case nil:
+		return starlark.None
+	case starlark.Value:
+		return v
+	case string:
+		return starlark.String(v)
+	case []byte:
+		return starlark.Bytes(v)
+	case bool:
+		return starlark.Bool(v)
+	case time.Time:
+		return starlark.String(v.String())
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return starlark.MakeInt64(reflected.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return starlark.MakeUint64(reflected.Uint())
+	case reflect.Float32, reflect.Float64:
+		return starlark.Float(reflected.Float())
+	case reflect.Ptr, reflect.Interface:
+		if reflected.IsNil() {
+			return starlark.None
+		}
+		return ToStarlark(reflected.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		elements := make([]starlark.Value, reflected.Len())
+		for i := 0; i < reflected.Len(); i++ {
+			elements[i] = ToStarlark(reflected.Index(i).Interface())
+		}
+		return starlark.NewList(elements)
+	case reflect.Map:
+		keys := reflected.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		dict := starlark.NewDict(len(keys))
+		for _, key := range keys {
+			dict.SetKey(ToStarlark(key.Interface()), ToStarlark(reflected.MapIndex(key).Interface()))
+		}
+		return dict
+	default:
+		return starlark.String(fmt.Sprint(value))
+	}
+}
+
+// Map decodes a converted Starlark value into the target structure.
+func Map(value interface{}, target interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, target)
+}
+
+// String returns the argument at the given position as a string.
+func String(args []interface{}, index int) (string, error) {
+	if index >= len(args) {
+		return "", fmt.Errorf("missing argument #%d", index+1)
+	}
+
+	value, ok := args[index].(string)
+	if !ok {
+		return "", fmt.Errorf("argument #%d must be a string", index+1)
+	}
+
+	return value, nil
+}
+
+// Optional returns the argument at the given position or nil if it is not
+// present.
+func Optional(args []interface{}, index int) interface{} {
+	if index >= len(args) {
+		return nil
+	}
+
+	return args[index]
+}
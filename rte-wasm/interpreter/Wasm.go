@@ -0,0 +1,203 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpreter
+
+import (
+	"context"
+	"fmt"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/rte/config"
+	"github.com/lavaorg/northstar/rte/rlimit"
+	"github.com/lavaorg/northstar/rte/rtepub"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PAGE_SIZE is the size of a WebAssembly memory page.
+const PAGE_SIZE = 65536
+
+// WasmInterpreter runs snippets compiled to WebAssembly. The code is the
+// binary module, Input.MainFn an exported function without parameters. The
+// module runs in a WASI sandbox without file system, environment or
+// network access, the host functions of the northstar module give access to
+// the arguments, the result, the output, the object store and KV.
+type WasmInterpreter struct {
+	rLimit rlimit.ResourceLimit
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+}
+
+func NewWasmInterpreter(rLimit rlimit.ResourceLimit) rtepub.Interpreter {
+	return &WasmInterpreter{rLimit: rLimit}
+}
+
+func (i *WasmInterpreter) DoREPL(input *rtepub.Input) *rtepub.Output {
+	timer := Wasm.NewTimer("DoREPLTimer")
+	startedOn := time.Now()
+	mlog.Debug("Running main: %s, code: %d bytes, args: %s, timeout: %d, memory: %v, accountId: %s, "+
+		"invocationId: %s", input.MainFn, len(input.Code), input.Args, input.Timeout, input.Memory,
+		input.AccountId, input.InvocationId)
+
+	runtimeConfig := wazero.NewRuntimeConfig().WithCloseOnContextDone(true)
+	if config.EnableRLimit {
+		limits, err := i.rLimit.Reserve(&rlimit.Resources{Memory: input.Memory})
+		if err != nil {
+			mlog.Error("Failed to reserve resources: %v", err.Error())
+			timer.Stop()
+			ErrDoREPL.Incr()
+			return &rtepub.Output{StartedOn: startedOn,
+				Status:     rtepub.START_MONITORING_FAILED,
+				ErrorDescr: err.Error()}
+		}
+		defer i.rLimit.Release(limits)
+
+		// The memory of the module is capped, wazero has no instruction
+		// counter so only the timeout bounds the execution.
+		if limits.Memory > 0 {
+			runtimeConfig = runtimeConfig.WithMemoryLimitPages(pages(limits.Memory))
+		}
+	}
+
+	// The host functions are created once the resources are reserved, a
+	// refused invocation does not connect to the object store or KV.
+	host, err := NewHost(input)
+	if err != nil {
+		mlog.Error("Failed to create state: %v", err.Error())
+		timer.Stop()
+		ErrDoREPL.Incr()
+		return &rtepub.Output{StartedOn: startedOn,
+			Status:     rtepub.STATE_CREATE_FAILED,
+			ErrorDescr: err.Error()}
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if input.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Duration(input.Timeout)*time.Millisecond)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	i.setCancel(cancel)
+	defer i.setCancel(nil)
+	defer cancel()
+
+	err = i.run(ctx, runtimeConfig, host, input)
+	finishedOn := time.Now()
+	stdout := strings.Join(host.Stdout, "")
+
+	if err != nil {
+		mlog.Error("Run error: %v", err)
+		timer.Stop()
+		ErrDoREPL.Incr()
+		execError := rtepub.GetExecutionError(err, nil)
+		return &rtepub.Output{StartedOn: startedOn,
+			FinishedOn: finishedOn,
+			Stdout:     stdout,
+			Status:     execError.Status,
+			ErrorDescr: execError.Description}
+	}
+
+	timer.Stop()
+	DoREPL.Incr()
+
+	output := &rtepub.Output{
+		StartedOn:   startedOn,
+		FinishedOn:  finishedOn,
+		ElapsedTime: finishedOn.Sub(startedOn),
+		Stdout:      stdout,
+		Result:      host.Result,
+		Status:      rtepub.SNIPPET_RUN_FINISHED,
+		ErrorDescr:  ""}
+	mlog.Debug("REPL output: %v", output)
+	return output
+}
+
+// run instantiates the module and calls its main function. Reactor modules
+// are initialized first, the _start function of command modules is not
+// called.
+func (i *WasmInterpreter) run(ctx context.Context,
+	runtimeConfig wazero.RuntimeConfig,
+	host *Host,
+	input *rtepub.Input) error {
+	runtime := wazero.NewRuntimeWithConfig(ctx, runtimeConfig)
+	defer runtime.Close(context.Background())
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		return err
+	}
+
+	if err := host.Instantiate(ctx, runtime); err != nil {
+		return err
+	}
+
+	compiled, err := runtime.CompileModule(ctx, []byte(input.Code))
+	if err != nil {
+		return err
+	}
+
+	moduleConfig := wazero.NewModuleConfig().
+		WithName(input.InvocationId).
+		WithStdout(host).
+		WithStderr(host).
+		WithStartFunctions("_initialize")
+	module, err := runtime.InstantiateModule(ctx, compiled, moduleConfig)
+	if err != nil {
+		return err
+	}
+
+	main := module.ExportedFunction(input.MainFn)
+	if main == nil {
+		return fmt.Errorf("function %s is not exported", input.MainFn)
+	}
+
+	_, err = main.Call(ctx)
+	return err
+}
+
+func (i *WasmInterpreter) Terminate() {
+	mlog.Debug("Terminating")
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.cancel != nil {
+		i.cancel()
+	}
+}
+
+func (i *WasmInterpreter) setCancel(cancel context.CancelFunc) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.cancel = cancel
+}
+
+// pages returns the number of memory pages fitting in the given memory.
+func pages(memory uint64) uint32 {
+	if memory/PAGE_SIZE > 65536 {
+		return 65536
+	}
+
+	if memory < PAGE_SIZE {
+		return 1
+	}
+
+	return uint32(memory / PAGE_SIZE)
+}
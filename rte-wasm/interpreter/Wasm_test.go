@@ -0,0 +1,76 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpreter
+
+import (
+	"github.com/lavaorg/northstar/rte/rlimit"
+	"github.com/lavaorg/northstar/rte/rtepub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// resultModule exports main, which passes "42" to northstar.result.
+var resultModule = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x09, 0x02, 0x60, 0x02, 0x7f, 0x7f, 0x00, 0x60, 0x00, 0x00,
+	0x02, 0x14, 0x01, 0x09, 0x6e, 0x6f, 0x72, 0x74, 0x68, 0x73, 0x74, 0x61, 0x72,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x00, 0x00,
+	0x03, 0x02, 0x01, 0x01,
+	0x05, 0x03, 0x01, 0x00, 0x01,
+	0x07, 0x11, 0x02, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x02, 0x00,
+	0x04, 0x6d, 0x61, 0x69, 0x6e, 0x00, 0x01,
+	0x0a, 0x0a, 0x01, 0x08, 0x00, 0x41, 0x00, 0x41, 0x02, 0x10, 0x00, 0x0b,
+	0x0b, 0x08, 0x01, 0x00, 0x41, 0x00, 0x0b, 0x02, 0x34, 0x32}
+
+// loopModule exports main, which never returns.
+var loopModule = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x04, 0x01, 0x60, 0x00, 0x00,
+	0x03, 0x02, 0x01, 0x00,
+	0x07, 0x08, 0x01, 0x04, 0x6d, 0x61, 0x69, 0x6e, 0x00, 0x00,
+	0x0a, 0x09, 0x01, 0x07, 0x00, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x0b}
+
+func runSnippet(code []byte, mainFn string, timeout int) *rtepub.Output {
+	interpreter := NewWasmInterpreter(rlimit.MockResourceLimit{})
+	input := &rtepub.Input{AccountId: "610140f2-2633-6e25-ef47-deda1f752cb3",
+		MainFn:  mainFn,
+		Code:    string(code),
+		Timeout: timeout,
+		Args:    map[string]interface{}{"param1": "test"}}
+	return interpreter.DoREPL(input)
+}
+
+func TestResult(t *testing.T) {
+	output := runSnippet(resultModule, "main", 1000)
+	require.Equal(t, rtepub.SNIPPET_RUN_FINISHED, output.Status, output.ErrorDescr)
+	assert.Equal(t, "42", output.Result)
+}
+
+func TestSlowSnippet(t *testing.T) {
+	output := runSnippet(loopModule, "main", 1000)
+	require.Equal(t, rtepub.SNIPPET_RUN_TIMEDOUT, output.Status, output.ErrorDescr)
+}
+
+func TestMissingFunction(t *testing.T) {
+	output := runSnippet(resultModule, "transform", 1000)
+	assert.Equal(t, rtepub.SNIPPET_REPL_FAILED, output.Status)
+	assert.Contains(t, output.ErrorDescr, "function transform is not exported")
+}
+
+func TestInvalidModule(t *testing.T) {
+	output := runSnippet([]byte("function main() end"), "main", 1000)
+	assert.Equal(t, rtepub.SNIPPET_REPL_FAILED, output.Status)
+}
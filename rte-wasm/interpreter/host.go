@@ -0,0 +1,303 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpreter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lavaorg/lrtx/config"
+	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/object/client"
+	"github.com/lavaorg/northstar/object/model"
+	pkgCfg "github.com/lavaorg/northstar/rte/config"
+	"github.com/lavaorg/northstar/rte/rtepub"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"time"
+)
+
+const (
+	// HOST_MODULE is the module the snippet imports the host functions
+	// from.
+	HOST_MODULE = "northstar"
+
+	// CALL_FAILED is returned by the host functions on error. The error
+	// message can be read from the buffer.
+	CALL_FAILED = -1
+)
+
+var (
+	EnableNSObject, _     = config.GetBool("ENABLE_NSOBJECT", false)
+	EnableNSKV, _         = config.GetBool("ENABLE_NSKV", false)
+	NsOutputPrintLimit, _ = config.GetInt("NS_OUTPUT_PRINT_LIMIT", 10000)
+
+	errNotEnabled = errors.New("module not enabled")
+)
+
+// Host holds the state shared between a snippet and the host functions.
+//
+// Host functions returning data place it in a buffer and return its length,
+// the snippet then copies it into its memory with buffer_read. This way the
+// snippet does not need to export an allocator.
+type Host struct {
+	args      []byte
+	buffer    []byte
+	rolling   int
+	Stdout    []string
+	Result    string
+	accountId string
+	objects   *client.ObjectClient
+	store     rtepub.KVStore
+
+	// Errors returned while the object store or KV are unavailable.
+	objectsErr error
+	storeErr   error
+}
+
+func NewHost(input *rtepub.Input) (*Host, error) {
+	args, err := json.Marshal(input.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	host := &Host{args: args,
+		rolling:    NsOutputPrintLimit,
+		accountId:  input.AccountId,
+		objectsErr: errNotEnabled,
+		storeErr:   errNotEnabled}
+
+	if EnableNSObject && host.permitted(input.Policy, "nsObject", &host.objectsErr) {
+		mlog.Debug("Enabling object host functions")
+		if host.objects, err = client.NewObjectClient(); err != nil {
+			return nil, err
+		}
+	}
+
+	if EnableNSKV && host.permitted(input.Policy, "nsKV", &host.storeErr) {
+		mlog.Debug("Enabling KV host functions")
+		redisCluster, err := pkgCfg.CreateRedisCluster()
+		if err != nil {
+			return nil, err
+		}
+
+		host.store = redisCluster
+	}
+
+	return host, nil
+}
+
+// permitted reports whether the policy of the snippet permits the module
+// backing some host functions. Otherwise these functions report the
+// violation.
+func (host *Host) permitted(policy *rtepub.Policy, name string, hostErr *error) bool {
+	if err := policy.CheckModule(name); err != nil {
+		mlog.Debug("Not enabling %s host functions: %v", name, err)
+		*hostErr = err
+		return false
+	}
+
+	return true
+}
+
+// Instantiate registers the host functions with the runtime. Strings and
+// bytes are passed as pointer and length into the memory of the snippet.
+func (host *Host) Instantiate(ctx context.Context, runtime wazero.Runtime) error {
+	builder := runtime.NewHostModuleBuilder(HOST_MODULE)
+	builder.NewFunctionBuilder().WithFunc(host.readArgs).Export("args")
+	builder.NewFunctionBuilder().WithFunc(host.readBuffer).Export("buffer_read")
+	builder.NewFunctionBuilder().WithFunc(host.setResult).Export("result")
+	builder.NewFunctionBuilder().WithFunc(host.print).Export("print")
+	builder.NewFunctionBuilder().WithFunc(host.objectGet).Export("object_get")
+	builder.NewFunctionBuilder().WithFunc(host.objectPut).Export("object_put")
+	builder.NewFunctionBuilder().WithFunc(host.kvGet).Export("kv_get")
+	builder.NewFunctionBuilder().WithFunc(host.kvSet).Export("kv_set")
+	builder.NewFunctionBuilder().WithFunc(host.kvDel).Export("kv_del")
+
+	_, err := builder.Instantiate(ctx)
+	return err
+}
+
+// args() returns the length of the JSON encoded arguments.
+func (host *Host) readArgs() int32 {
+	return host.fill(host.args, nil)
+}
+
+// buffer_read(ptr) copies the buffer to the snippet memory.
+func (host *Host) readBuffer(ctx context.Context, m api.Module, ptr uint32) {
+	if !m.Memory().Write(ptr, host.buffer) {
+		panic(fmt.Errorf("buffer_read: %d bytes at %d out of range", len(host.buffer), ptr))
+	}
+}
+
+// result(ptr, len) sets the JSON encoded result of the snippet.
+func (host *Host) setResult(ctx context.Context, m api.Module, ptr, size uint32) {
+	host.Result = string(read(m, ptr, size))
+}
+
+// print(ptr, len) writes to the stdout of the snippet.
+func (host *Host) print(ctx context.Context, m api.Module, ptr, size uint32) int32 {
+	_, err := host.Write(read(m, ptr, size))
+	return host.fill(nil, err)
+}
+
+// object_get(bucket, file) returns the length of the file content.
+func (host *Host) objectGet(ctx context.Context, m api.Module,
+	bucketPtr, bucketSize, filePtr, fileSize uint32) int32 {
+	return host.fill(host.downloadFile(string(read(m, bucketPtr, bucketSize)),
+		string(read(m, filePtr, fileSize))))
+}
+
+// object_put(bucket, file, data, contentType) uploads the file.
+func (host *Host) objectPut(ctx context.Context, m api.Module,
+	bucketPtr, bucketSize, filePtr, fileSize, dataPtr, dataSize, typePtr, typeSize uint32) int32 {
+	return host.fill(nil, host.uploadFile(string(read(m, bucketPtr, bucketSize)),
+		string(read(m, filePtr, fileSize)),
+		read(m, dataPtr, dataSize),
+		string(read(m, typePtr, typeSize))))
+}
+
+// kv_get(key) returns the length of the value.
+func (host *Host) kvGet(ctx context.Context, m api.Module, keyPtr, keySize uint32) int32 {
+	return host.fill(host.get(string(read(m, keyPtr, keySize))))
+}
+
+// kv_set(key, value, ttl) stores the value, optionally expiring after ttl
+// seconds.
+func (host *Host) kvSet(ctx context.Context, m api.Module,
+	keyPtr, keySize, valuePtr, valueSize uint32, ttl int64) int32 {
+	return host.fill(nil, host.set(string(read(m, keyPtr, keySize)),
+		string(read(m, valuePtr, valueSize)),
+		time.Duration(ttl)*time.Second))
+}
+
+// kv_del(key) deletes the key.
+func (host *Host) kvDel(ctx context.Context, m api.Module, keyPtr, keySize uint32) int32 {
+	return host.fill(nil, host.del(string(read(m, keyPtr, keySize))))
+}
+
+// Write appends to the stdout of the snippet. It also receives the WASI
+// stdout and stderr.
+func (host *Host) Write(out []byte) (int, error) {
+	if host.rolling-len(out) < 0 {
+		ErrPrint.Incr()
+		return 0, fmt.Errorf("%d-byte stdout limit is exceeded", NsOutputPrintLimit)
+	}
+
+	host.rolling -= len(out)
+	host.Stdout = append(host.Stdout, string(out))
+	Print.Incr()
+	return len(out), nil
+}
+
+// fill places the data, or the message of the error, in the buffer.
+func (host *Host) fill(data []byte, err error) int32 {
+	if err != nil {
+		host.buffer = []byte(err.Error())
+		return CALL_FAILED
+	}
+
+	host.buffer = data
+	return int32(len(data))
+}
+
+func (host *Host) downloadFile(bucketName string, fileName string) ([]byte, error) {
+	if host.objects == nil {
+		return nil, host.objectsErr
+	}
+
+	data, mErr := host.objects.DownloadFile(host.accountId, bucketName, fileName)
+	if mErr != nil {
+		mlog.Error(mErr.Error())
+		ErrObjectGet.Incr()
+		return nil, fmt.Errorf("Failed to download file %s", fileName)
+	}
+
+	ObjectGet.Incr()
+	return data.Payload, nil
+}
+
+func (host *Host) uploadFile(bucketName string, fileName string, data []byte, contentType string) error {
+	if host.objects == nil {
+		return host.objectsErr
+	}
+
+	uploadData := &model.UploadData{FileName: fileName, Payload: data, ContentType: contentType}
+	if _, mErr := host.objects.UploadFile(host.accountId, bucketName, uploadData); mErr != nil {
+		mlog.Error(mErr.Error())
+		ErrObjectPut.Incr()
+		return fmt.Errorf("Failed to upload file %s", fileName)
+	}
+
+	ObjectPut.Incr()
+	return nil
+}
+
+func (host *Host) get(key string) ([]byte, error) {
+	if host.store == nil {
+		return nil, host.storeErr
+	}
+
+	value, err := host.store.Get(host.accountId + key)
+	if err != nil {
+		ErrKVGet.Incr()
+		return nil, err
+	}
+
+	KVGet.Incr()
+	return []byte(value), nil
+}
+
+func (host *Host) set(key string, value string, expiration time.Duration) error {
+	if host.store == nil {
+		return host.storeErr
+	}
+
+	if err := host.store.Set(host.accountId+key, value, expiration); err != nil {
+		ErrKVSet.Incr()
+		return err
+	}
+
+	KVSet.Incr()
+	return nil
+}
+
+func (host *Host) del(key string) error {
+	if host.store == nil {
+		return host.storeErr
+	}
+
+	if err := host.store.Del(host.accountId + key); err != nil {
+		ErrKVDel.Incr()
+		return err
+	}
+
+	KVDel.Incr()
+	return nil
+}
+
+// read returns a copy of the given range of the snippet memory. An invalid
+// range aborts the snippet.
+func read(m api.Module, ptr uint32, size uint32) []byte {
+	data, ok := m.Memory().Read(ptr, size)
+	if !ok {
+		panic(fmt.Errorf("%d bytes at %d out of range", size, ptr))
+	}
+
+	return append([]byte(nil), data...)
+}
@@ -31,6 +31,35 @@ var (
 	WorkerQueueCapacity, _ = config.GetInt("RTE_WORKER_QUEUE_CAPACITY", 10)
 	EnableRLimit, _        = config.GetBool("RTE_ENABLE_RLIMIT", true)
 	GoMaxProcs, _          = config.GetInt("GOMAXPROCS", 1)
+
+	// Number of VM instructions a snippet may execute, 0 for unlimited, and
+	// how often in instructions the memory held by a snippet is measured.
+	InstructionLimit, _     = config.GetInt("RTE_INSTRUCTION_LIMIT", 0)
+	MemorySampleInterval, _ = config.GetInt("RTE_MEMORY_SAMPLE_INTERVAL", 10000)
+
+	// Number of invocations the free container memory is split between
+	// when they do not request memory. The rte runs one invocation at a
+	// time, so it gets all of it by default.
+	MemoryShares, _ = config.GetInt("RTE_MEMORY_SHARES", 1)
+
+	// Number of warm interpreter states kept between invocations, 0 to
+	// create a state per invocation, and number of compiled snippets kept.
+	StatePoolSize, _ = config.GetInt("RTE_STATE_POOL_SIZE", 4)
+	CodeCacheSize, _ = config.GetInt("RTE_CODE_CACHE_SIZE", 256)
+
+	// Number of library versions kept and seconds after which the latest
+	// version of a library is fetched again.
+	LibraryCacheSize, _ = config.GetInt("RTE_LIBRARY_CACHE_SIZE", 256)
+	LibraryCacheTTL, _  = config.GetInt("RTE_LIBRARY_CACHE_TTL", 60)
+
+	// Seconds a paused debugger waits for a command of the client before
+	// polling its endpoint again.
+	DebugPollTimeout, _ = config.GetInt("RTE_DEBUG_POLL_TIMEOUT", 30)
+
+	// Base url of the debugger of the portal, e.g.
+	// http://portal:8080/internal/v1/connections. A debug session only
+	// connects to endpoints under it; debug mode is refused when not set.
+	DebugBaseUrl, _ = config.GetString("RTE_DEBUG_BASE_URL", "")
 )
 
 const (
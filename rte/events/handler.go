@@ -22,7 +22,10 @@ import (
 	"github.com/lavaorg/lrtx/kafka"
 	"github.com/lavaorg/lrtx/mlog"
 	"github.com/lavaorg/lrtx/service_master"
+	jsInterpreter "github.com/lavaorg/northstar/rte-js/interpreter"
 	"github.com/lavaorg/northstar/rte-lua/interpreter"
+	starlarkInterpreter "github.com/lavaorg/northstar/rte-starlark/interpreter"
+	wasmInterpreter "github.com/lavaorg/northstar/rte-wasm/interpreter"
 	"github.com/lavaorg/northstar/rte/config"
 	"github.com/lavaorg/northstar/rte/rlimit"
 	"github.com/lavaorg/northstar/rte/rtepub"
@@ -186,6 +189,12 @@ func initInterpreter(runtime string) (rtepub.Interpreter, error) {
 	switch runtime {
 	case rtepub.Lua:
 		return interpreter.NewLuaInterpreter(rlimit.NewLuaResourceLimit()), nil
+	case rtepub.JavaScript:
+		return jsInterpreter.NewJsInterpreter(rlimit.NewLuaResourceLimit()), nil
+	case rtepub.Starlark:
+		return starlarkInterpreter.NewStarlarkInterpreter(rlimit.NewLuaResourceLimit()), nil
+	case rtepub.WebAssembly:
+		return wasmInterpreter.NewWasmInterpreter(rlimit.NewLuaResourceLimit()), nil
 	default:
 		return nil, fmt.Errorf("Unknown runtime received: %v", runtime)
 	}
@@ -24,9 +24,12 @@ import (
 	"github.com/lavaorg/lrtx/msgq"
 	"github.com/lavaorg/northstar/data/invocations/client"
 	"github.com/lavaorg/northstar/data/invocations/model"
+	policiesClient "github.com/lavaorg/northstar/data/policies/client"
+	policiesModel "github.com/lavaorg/northstar/data/policies/model"
 	"github.com/lavaorg/northstar/rte/config"
 	"github.com/lavaorg/northstar/rte/rtepub"
 	"github.com/lavaorg/northstar/rte/stats"
+	"net/http"
 )
 
 type SnippetManager interface {
@@ -34,6 +37,7 @@ type SnippetManager interface {
 	SnippetStop(accountId string, partition int, stop *SnippetStopEvent) error
 	SnippetOutput(accountId string, start *SnippetStartEvent, output *rtepub.Output) error
 	UpdateInvocation(accountId string, invocationId string, partition int32, status string) error
+	GetPolicy(accountId string, snippetId string) (*rtepub.Policy, error)
 }
 
 type SnippetManagerService struct {
@@ -44,6 +48,7 @@ type SnippetManagerService struct {
 	httpEventsProducer *HttpEventsProducer
 	eventsCreator      *EventsCreator
 	invocationClient   client.Client
+	policiesClient     policiesClient.Client
 }
 
 func NewSnippetManagerService(serviceName string, topicName string) (SnippetManager, error) {
@@ -75,13 +80,19 @@ func NewSnippetManagerService(serviceName string, topicName string) (SnippetMana
 		return nil, err
 	}
 
+	policies, err := policiesClient.NewPoliciesClient()
+	if err != nil {
+		return nil, err
+	}
+
 	return &SnippetManagerService{topicName: topicName,
 		rteId:              rteId,
 		msgQ:               msgQ,
 		kafkaProducer:      kafkaProducer,
 		httpEventsProducer: httpEventsProducer,
 		eventsCreator:      NewEventsCreator(),
-		invocationClient:   invocationClient}, nil
+		invocationClient:   invocationClient,
+		policiesClient:     policies}, nil
 }
 
 func (n *SnippetManagerService) SnippetStart(accountId string,
@@ -314,3 +325,31 @@ func (event *SnippetManagerService) UpdateInvocation(accountId string,
 		invocationId, partition, status)
 	return nil
 }
+
+// GetPolicy returns the policy of the snippet: its own policy if set,
+// otherwise the policy of its account. Nil is returned when neither is set.
+func (n *SnippetManagerService) GetPolicy(accountId string, snippetId string) (*rtepub.Policy, error) {
+	if snippetId != "" {
+		policy, err := n.getPolicy(accountId, snippetId)
+		if err != nil || policy != nil {
+			return policy, err
+		}
+	}
+
+	return n.getPolicy(accountId, policiesModel.ACCOUNT_POLICY)
+}
+
+func (n *SnippetManagerService) getPolicy(accountId string, snippetId string) (*rtepub.Policy, error) {
+	data, mErr := n.policiesClient.GetPolicy(accountId, snippetId)
+	if mErr != nil {
+		if mErr.HttpStatus == http.StatusNotFound {
+			return nil, nil
+		}
+
+		mlog.Error("Failed to get policy of snippet %s: %v", snippetId, mErr)
+		stats.ErrGetPolicy.Incr()
+		return nil, errors.New(mErr.Error())
+	}
+
+	return &rtepub.Policy{Modules: data.Modules, Hosts: data.Hosts}, nil
+}
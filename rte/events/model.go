@@ -17,6 +17,7 @@ limitations under the License.
 package events
 
 import (
+	"github.com/lavaorg/northstar/rte/rtepub"
 	"time"
 )
 
@@ -38,6 +39,7 @@ type SnippetStartEvent struct {
 	Callback     string                 `json:"callback,omitempty"`
 	Memory       uint64                 `json:"memory,omitempty"`
 	Args         map[string]interface{} `json:"args,omitempty"`
+	Debug        *rtepub.Debug          `json:"debug,omitempty"`
 }
 
 type SnippetStopEvent struct {
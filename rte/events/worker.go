@@ -22,7 +22,6 @@ import (
 	"github.com/lavaorg/northstar/rte/rtepub"
 	"github.com/lavaorg/northstar/rte/util"
 	"github.com/orcaman/concurrent-map"
-	"os"
 	"time"
 )
 
@@ -64,12 +63,18 @@ func (worker *SnippetRunWorker) Run(workRoutine int) error {
 		return err
 	}
 
-	code, err := util.GetSnippetCode(worker.startEvent.URL, worker.startEvent.Code)
+	code, err := util.GetSnippetCode(worker.accountId, worker.startEvent.URL, worker.startEvent.Code)
 	if err != nil {
 		mlog.Error("Failed to get snippet: %v", err.Error())
 		return err
 	}
 
+	policy, err := worker.snippetManager.GetPolicy(worker.accountId, worker.startEvent.SnippetId)
+	if err != nil {
+		mlog.Error("Failed to get snippet policy: %v", err.Error())
+		return err
+	}
+
 	runSnippet := rtepub.Input{AccountId: worker.accountId,
 		Id:           worker.startEvent.SnippetId,
 		InvocationId: worker.startEvent.InvocationId,
@@ -79,7 +84,9 @@ func (worker *SnippetRunWorker) Run(workRoutine int) error {
 		Timeout:      worker.startEvent.Timeout,
 		Callback:     worker.startEvent.Callback,
 		Memory:       worker.startEvent.Memory,
-		Args:         worker.startEvent.Args}
+		Args:         worker.startEvent.Args,
+		Policy:       policy,
+		Debug:        worker.startEvent.Debug}
 
 	output := worker.interpreter.DoREPL(&runSnippet)
 	err = worker.snippetManager.SnippetOutput(worker.accountId, worker.startEvent, output)
@@ -116,10 +123,5 @@ func (worker *SnippetRunWorker) cleanup(status string) {
 
 	worker.workers.Remove(worker.startEvent.InvocationId)
 
-	if status == rtepub.SNIPPET_OUT_OF_MEMORY {
-		mlog.Debug("Terminating process because of status: %v", status)
-		os.Exit(1)
-	}
-
 	mlog.Debug("Worker cleanup complete")
 }
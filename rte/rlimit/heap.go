@@ -0,0 +1,75 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rlimit
+
+import (
+	"github.com/lavaorg/lrtx/mlog"
+	"runtime"
+	"time"
+)
+
+const HEAP_CHECK_INTERVAL = time.Second
+
+// WatchHeap calls exceeded once the process heap grew by more than the given
+// number of bytes. It is used by interpreters which cannot account the
+// memory of a single invocation; the rte runs one invocation at a time so
+// the growth is attributed to it. The limit is approximate: the heap is
+// checked every HEAP_CHECK_INTERVAL, and when it grew over the limit a
+// collection is run first so only live memory counts against it. The
+// watcher stops when the returned channel is closed.
+func WatchHeap(memory uint64, exceeded func()) chan struct{} {
+	stop := make(chan struct{})
+
+	var initial runtime.MemStats
+	runtime.ReadMemStats(&initial)
+
+	go func() {
+		ticker := time.NewTicker(HEAP_CHECK_INTERVAL)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if !heapExceeded(initial.HeapAlloc, memory) {
+					continue
+				}
+
+				// The heap includes garbage, collect it so only the live
+				// objects are compared.
+				runtime.GC()
+				if heapExceeded(initial.HeapAlloc, memory) {
+					mlog.Debug("Heap grew over the memory limit of %v", memory)
+					OutOfMemory.Incr()
+					exceeded()
+					return
+				}
+			}
+		}
+	}()
+
+	return stop
+}
+
+// heapExceeded reports whether the allocated heap grew by more than memory
+// bytes since it held initial bytes.
+func heapExceeded(initial uint64, memory uint64) bool {
+	var current runtime.MemStats
+	runtime.ReadMemStats(&current)
+	return current.HeapAlloc > initial && current.HeapAlloc-initial > memory
+}
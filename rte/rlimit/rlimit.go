@@ -17,30 +17,53 @@ limitations under the License.
 package rlimit
 
 import (
+	"errors"
 	"fmt"
 	"github.com/lavaorg/lrtx/mlog"
 	"github.com/lavaorg/northstar/rte/config"
 	"io/ioutil"
+	"os"
 	"runtime"
 	"strconv"
 	"strings"
-	"time"
+	"sync"
 )
 
 const (
 	ERR_OUT_OF_MEMORY        = "out of memory"
+	ERR_INSTRUCTION_LIMIT    = "instruction limit exceeded"
 	MEMORY_BUFFER_PERCENTAGE = 20
-	MEMORY_LIMIT_IN_BYTES    = "/sys/fs/cgroup/memory/memory.limit_in_bytes"
-	MEMORY_USAGE_IN_BYTES    = "/sys/fs/cgroup/memory/memory.usage_in_bytes"
+
+	// cgroup v1 memory controller.
+	MEMORY_LIMIT_IN_BYTES = "/sys/fs/cgroup/memory/memory.limit_in_bytes"
+	MEMORY_USAGE_IN_BYTES = "/sys/fs/cgroup/memory/memory.usage_in_bytes"
+
+	// cgroup v2 unified hierarchy.
+	CGROUP_CONTROLLERS = "/sys/fs/cgroup/cgroup.controllers"
+	MEMORY_MAX         = "/sys/fs/cgroup/memory.max"
+	MEMORY_CURRENT     = "/sys/fs/cgroup/memory.current"
+	MEMORY_UNLIMITED   = "max"
 )
 
+var (
+	ErrOutOfMemory      = errors.New(ERR_OUT_OF_MEMORY)
+	ErrInstructionLimit = errors.New(ERR_INSTRUCTION_LIMIT)
+)
+
+// ResourceLimit admits snippet invocations against the memory of the
+// container. The limits returned by Reserve are enforced by the interpreter
+// on the state of the invocation itself, so concurrent invocations do not
+// affect each other.
 type ResourceLimit interface {
-	StartMonitoring(resources *Resources) (chan error, error)
-	StopMonitoring()
+	Reserve(resources *Resources) (*Resources, error)
+	Release(resources *Resources)
 }
 
+// Resources of a single invocation. Memory is in bytes and Instructions is
+// the number of VM instructions, zero means unlimited.
 type Resources struct {
-	Memory uint64
+	Memory       uint64
+	Instructions uint64
 }
 
 type CGroupMemoryStats struct {
@@ -49,97 +72,85 @@ type CGroupMemoryStats struct {
 }
 
 type LuaResourceLimit struct {
-	Done chan bool
-	Msg  chan error
+	lock     sync.Mutex
+	reserved uint64
 }
 
 func NewLuaResourceLimit() *LuaResourceLimit {
-	return &LuaResourceLimit{Done: make(chan bool),
-		Msg: make(chan error)}
+	setNumberOfThreads(config.GoMaxProcs)
+	return &LuaResourceLimit{}
 }
 
-func (r *LuaResourceLimit) StartMonitoring(resources *Resources) (chan error, error) {
-	setNumberOfThreads(config.GoMaxProcs)
-	err := r.enforceMemoryLimit(resources)
+// Reserve returns the limits of an invocation requesting the given
+// resources. Invocations without a memory request get the free container
+// memory, split in RTE_MEMORY_SHARES. The reservation has to be given back
+// with Release once the invocation finished.
+func (r *LuaResourceLimit) Reserve(resources *Resources) (*Resources, error) {
+	memStats, err := getCGroupMemoryStats()
 	if err != nil {
 		return nil, err
 	}
 
-	return r.Msg, nil
-}
+	limits := &Resources{Memory: resources.Memory, Instructions: resources.Instructions}
+	if limits.Instructions == 0 {
+		limits.Instructions = uint64(config.InstructionLimit)
+	}
 
-func (r *LuaResourceLimit) StopMonitoring() {
-	if config.EnableRLimit {
-		mlog.Debug("Sent stop message")
-		r.Done <- true
-		mlog.Debug("Stop message sent")
+	updatePerformanceCounters()
+
+	// Without a cgroup limit only the requested memory is enforced.
+	if memStats.Limit == 0 {
+		return limits, nil
 	}
+
+	available := memStats.Limit - getPercentage(memStats.Limit, MEMORY_BUFFER_PERCENTAGE)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if limits.Memory == 0 && available > memStats.Usage {
+		limits.Memory = (available - memStats.Usage) / uint64(max(config.MemoryShares, 1))
+	}
+
+	mlog.Debug("Memory requested %v, reserved %v, available %v", limits.Memory, r.reserved, available)
+	if limits.Memory == 0 || limits.Memory+r.reserved > available {
+		return nil, fmt.Errorf("not enough memory available")
+	}
+
+	r.reserved += limits.Memory
+	MemReserved.Set(int64(r.reserved))
+	return limits, nil
 }
 
-func (r *LuaResourceLimit) enforceMemoryLimit(resources *Resources) error {
-	var memLimit uint64
-	if resources.Memory > 0 {
-		ok, err := isEnoughMemoryAvailable(resources.Memory)
-		if !ok {
-			return err
-		}
-		memLimit = resources.Memory
+func (r *LuaResourceLimit) Release(limits *Resources) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if limits.Memory > r.reserved {
+		r.reserved = 0
 	} else {
-		memStats, err := getCGroupMemoryStats()
-		if err != nil {
-			return err
-		}
-
-		buffer := getPercentage(memStats.Limit, MEMORY_BUFFER_PERCENTAGE)
-		memLimit = memStats.Limit - buffer - memStats.Usage
-		mlog.Debug("System memory limit: %v, buffer: %v, usage %v",
-			memStats.Limit, buffer, memStats.Usage)
+		r.reserved -= limits.Memory
 	}
 
-	r.monitorMemory(memLimit)
-	return nil
+	MemReserved.Set(int64(r.reserved))
+	updatePerformanceCounters()
 }
 
-func (r *LuaResourceLimit) monitorMemory(memory uint64) {
-	mlog.Debug("Enforcing memory limit of %v", memory)
-	MemLimit.Set(int64(memory))
-	var initialStats runtime.MemStats
-	runtime.ReadMemStats(&initialStats)
-
-	go func() {
-	L:
-		for {
-			select {
-			case <-r.Done:
-				mlog.Debug("Received monitoring stop signal")
-				break L
-			default:
-				var memStats runtime.MemStats
-				runtime.ReadMemStats(&memStats)
-				updatePerformanceCounters(&memStats)
-				allocated := getAllocatedMemory(memStats.Alloc, initialStats.Alloc)
-				mlog.Debug("Allocated memory: %v, full memory stats: %v", allocated, memStats)
-				if allocated >= memory {
-					mlog.Debug("Sending out of memory message to channel")
-					r.Msg <- fmt.Errorf(ERR_OUT_OF_MEMORY)
-				}
-				time.Sleep(100 * time.Millisecond)
-			}
-		}
-
-		mlog.Debug("Memory monitoring stopped")
-	}()
+// MockResourceLimit admits every invocation with the requested memory and
+// the given instruction limit.
+type MockResourceLimit struct {
+	Instructions uint64
 }
 
-func getAllocatedMemory(alloc, init uint64) uint64 {
-	if alloc > init {
-		return alloc - init
-	}
-
-	return alloc
+func (m MockResourceLimit) Reserve(resources *Resources) (*Resources, error) {
+	return &Resources{Memory: resources.Memory, Instructions: m.Instructions}, nil
 }
 
-func updatePerformanceCounters(mem *runtime.MemStats) {
+func (m MockResourceLimit) Release(resources *Resources) {}
+
+func updatePerformanceCounters() {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
 	MemSys.Set(int64(mem.Sys))
 	MemAlloc.Set(int64(mem.Alloc))
 	MemTotalAlloc.Set(int64(mem.TotalAlloc))
@@ -150,33 +161,25 @@ func updatePerformanceCounters(mem *runtime.MemStats) {
 	MemNumGC.Set(int64(mem.NumGC))
 }
 
-func isEnoughMemoryAvailable(memory uint64) (bool, error) {
-	memStats, err := getCGroupMemoryStats()
-	if err != nil {
-		return false, err
-	}
-
-	mlog.Debug("Memory requested %v, used %v, limit %v", memory, memStats.Usage, memStats.Limit)
-
-	reqMemory := memory + memStats.Usage
-	if reqMemory > memStats.Limit {
-		return false, fmt.Errorf("not enough memory available")
+// getCGroupMemoryStats reads the memory controller of either cgroup v2 or
+// v1. A zero limit means the container memory is not limited.
+func getCGroupMemoryStats() (*CGroupMemoryStats, error) {
+	usagePath, limitPath := MEMORY_USAGE_IN_BYTES, MEMORY_LIMIT_IN_BYTES
+	if _, err := os.Stat(CGROUP_CONTROLLERS); err == nil {
+		usagePath, limitPath = MEMORY_CURRENT, MEMORY_MAX
 	}
 
-	return true, nil
-}
-
-func getCGroupMemoryStats() (*CGroupMemoryStats, error) {
-	usage, err := getValue(MEMORY_USAGE_IN_BYTES)
+	usage, err := getValue(usagePath)
 	if err != nil {
 		return nil, err
 	}
 
-	limit, err := getValue(MEMORY_LIMIT_IN_BYTES)
+	limit, err := getValue(limitPath)
 	if err != nil {
 		return nil, err
 	}
 
+	MemLimit.Set(int64(limit))
 	return &CGroupMemoryStats{Usage: usage, Limit: limit}, nil
 }
 
@@ -190,8 +193,12 @@ func getValue(path string) (uint64, error) {
 		return 0, err
 	}
 
-	memStr := strings.Split(string(contents), "\n")
-	memory, err := strconv.ParseUint(memStr[0], 0, 64)
+	memStr := strings.TrimSpace(strings.Split(string(contents), "\n")[0])
+	if memStr == MEMORY_UNLIMITED {
+		return 0, nil
+	}
+
+	memory, err := strconv.ParseUint(memStr, 0, 64)
 	if err != nil {
 		return 0, err
 	}
@@ -199,6 +206,14 @@ func getValue(path string) (uint64, error) {
 	return memory, nil
 }
 
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
 func setNumberOfThreads(threads int) {
 	mlog.Debug("Number of threads set to %d", threads)
 	runtime.GOMAXPROCS(threads)
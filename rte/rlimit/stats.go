@@ -23,6 +23,7 @@ import (
 var (
 	rLimit          = stats.New("rLimit")
 	MemLimit        = rLimit.NewSet("MemLimit")
+	MemReserved     = rLimit.NewSet("MemReserved")
 	MemSys          = rLimit.NewSet("MemSys")
 	MemAlloc        = rLimit.NewSet("MemAlloc")
 	MemTotalAlloc   = rLimit.NewSet("MemTotalAlloc")
@@ -31,4 +32,7 @@ var (
 	MemHeapObjects  = rLimit.NewSet("MemHeapObjects")
 	MemHeapReleased = rLimit.NewSet("MemHeapReleased")
 	MemNumGC        = rLimit.NewSet("MemNumGC")
+
+	OutOfMemory      = rLimit.NewCounter("OutOfMemory")
+	InstructionLimit = rLimit.NewCounter("InstructionLimit")
 )
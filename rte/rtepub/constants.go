@@ -7,19 +7,25 @@ const (
 	SNIPPET_OUTPUT_EVENT  = "SNIPPET_OUTPUT"
 )
 const (
-	Lua = "lua"
-	R   = "r"
+	Lua         = "lua"
+	R           = "r"
+	JavaScript  = "javascript"
+	Starlark    = "starlark"
+	WebAssembly = "wasm"
 )
 
 const (
-	STATE_CREATE_FAILED     = "STATE_CREATE_FAILED"
-	SNIPPET_OUT_OF_MEMORY   = "OUT_OF_MEMORY"
-	SNIPPET_CODE_GET_FAILED = "CODE_GET_FAILED"
-	SNIPPET_REPL_FAILED     = "REPL_FAILED"
-	SNIPPET_RUN_FINISHED    = "FINISHED"
-	SNIPPET_RUN_TIMEDOUT    = "TIMED_OUT"
-	START_MONITORING_FAILED = "START_MONITORING_FAILED"
+	STATE_CREATE_FAILED       = "STATE_CREATE_FAILED"
+	SNIPPET_OUT_OF_MEMORY     = "OUT_OF_MEMORY"
+	SNIPPET_INSTRUCTION_LIMIT = "INSTRUCTION_LIMIT_EXCEEDED"
+	SNIPPET_NOT_PERMITTED     = "NOT_PERMITTED"
+	SNIPPET_CODE_GET_FAILED   = "CODE_GET_FAILED"
+	SNIPPET_REPL_FAILED       = "REPL_FAILED"
+	SNIPPET_RUN_FINISHED      = "FINISHED"
+	SNIPPET_RUN_TIMEDOUT      = "TIMED_OUT"
+	START_MONITORING_FAILED   = "START_MONITORING_FAILED"
 
-	SNIPPET_RUN_TIMEDOUT_DESCR  = "snippet execution deadline exceeded"
-	SNIPPET_OUT_OF_MEMORY_DESCR = "snippet has run out of memory"
+	SNIPPET_RUN_TIMEDOUT_DESCR      = "snippet execution deadline exceeded"
+	SNIPPET_OUT_OF_MEMORY_DESCR     = "snippet has run out of memory"
+	SNIPPET_INSTRUCTION_LIMIT_DESCR = "snippet has exceeded its instruction limit"
 )
@@ -0,0 +1,174 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rtepub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// Commands a paused debugger accepts.
+const (
+	DEBUG_CONTINUE        = "continue"
+	DEBUG_STEP_OVER       = "stepOver"
+	DEBUG_STEP_INTO       = "stepInto"
+	DEBUG_STEP_OUT        = "stepOut"
+	DEBUG_SET_BREAKPOINTS = "setBreakpoints"
+	DEBUG_STOP            = "stop"
+)
+
+// Reasons a debugger pauses.
+const (
+	DEBUG_PAUSED_ENTRY      = "entry"
+	DEBUG_PAUSED_BREAKPOINT = "breakpoint"
+	DEBUG_PAUSED_STEP       = "step"
+)
+
+// ERR_DEBUG_STOPPED is raised in the snippet when the client stops it.
+const ERR_DEBUG_STOPPED = "stopped by the debugger"
+
+// ERR_DEBUG_ENDPOINT is returned when the endpoint of a debug session is
+// not under the configured debugger base url.
+const ERR_DEBUG_ENDPOINT = "debug endpoint is not permitted"
+
+// Debug runs the snippet in debug mode. The debugger pauses on the
+// breakpoints, given as lines of the snippet code, and reports the stack to
+// the endpoint, from which it then reads the commands of the client.
+type Debug struct {
+	Endpoint    string `json:"endpoint,omitempty"`
+	Breakpoints []int  `json:"breakpoints,omitempty"`
+	StopOnEntry bool   `json:"stopOnEntry,omitempty"`
+}
+
+// DebugCommand is a command of the client to a paused debugger.
+type DebugCommand struct {
+	Command     string `json:"command"`
+	Breakpoints []int  `json:"breakpoints,omitempty"`
+}
+
+// DebugFrame is an active function call of the paused snippet. Values are
+// rendered as strings, tables to a limited depth.
+type DebugFrame struct {
+	Function string            `json:"function,omitempty"`
+	Source   string            `json:"source,omitempty"`
+	Line     int               `json:"line"`
+	Locals   map[string]string `json:"locals,omitempty"`
+	Upvalues map[string]string `json:"upvalues,omitempty"`
+}
+
+// DebugPaused reports where the snippet is paused, innermost frame first.
+type DebugPaused struct {
+	InvocationId string       `json:"invocationId,omitempty"`
+	Reason       string       `json:"reason"`
+	Line         int          `json:"line"`
+	Stack        []DebugFrame `json:"stack"`
+}
+
+// DebugSession connects the debugger of an invocation to its client.
+type DebugSession interface {
+	// Paused reports the paused snippet to the client.
+	Paused(paused *DebugPaused) error
+	// Command waits for the next command of the client. It returns nil
+	// when the client sent none before the endpoint timed out.
+	Command() (*DebugCommand, error)
+}
+
+// HttpDebugSession is the session with a client behind an HTTP endpoint:
+// pauses are posted to <endpoint>/paused and commands are long polled from
+// <endpoint>/commands, which answers no content when there is none.
+type HttpDebugSession struct {
+	endpoint string
+	client   *http.Client
+}
+
+func NewHttpDebugSession(endpoint string, pollTimeout time.Duration) *HttpDebugSession {
+	return &HttpDebugSession{endpoint: endpoint, client: &http.Client{Timeout: pollTimeout}}
+}
+
+// CheckDebugEndpoint returns an error unless the endpoint is under the base
+// url, e.g. the internal debugger url of the portal. The session sends the
+// state of the snippet to the endpoint and runs the commands it returns, so
+// no other endpoint is accepted and debugging is disabled without a base.
+func CheckDebugEndpoint(endpoint string, base string) error {
+	baseUrl, err := url.Parse(base)
+	if base == "" || err != nil {
+		return fmt.Errorf("%s: no debugger base url is configured", ERR_DEBUG_ENDPOINT)
+	}
+
+	endpointUrl, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("%s: %v", ERR_DEBUG_ENDPOINT, err)
+	}
+
+	basePath := strings.TrimSuffix(baseUrl.Path, "/") + "/"
+	endpointPath := path.Clean("/" + endpointUrl.Path)
+	if endpointUrl.Scheme != baseUrl.Scheme || endpointUrl.User != nil ||
+		!strings.EqualFold(endpointUrl.Host, baseUrl.Host) ||
+		!strings.HasPrefix(endpointPath+"/", basePath) ||
+		endpointUrl.RawQuery != "" || endpointUrl.Fragment != "" {
+		return fmt.Errorf("%s: %s", ERR_DEBUG_ENDPOINT, endpoint)
+	}
+
+	return nil
+}
+
+func (s *HttpDebugSession) Paused(paused *DebugPaused) error {
+	data, err := json.Marshal(paused)
+	if err != nil {
+		return err
+	}
+
+	response, err := s.client.Post(s.endpoint+"/paused", "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("debug endpoint returned status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *HttpDebugSession) Command() (*DebugCommand, error) {
+	response, err := s.client.Get(s.endpoint + "/commands")
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusNoContent:
+		return nil, nil
+	case response.StatusCode >= http.StatusBadRequest:
+		return nil, fmt.Errorf("debug endpoint returned status %d", response.StatusCode)
+	}
+
+	var command DebugCommand
+	if err := json.NewDecoder(response.Body).Decode(&command); err != nil {
+		return nil, err
+	}
+
+	return &command, nil
+}
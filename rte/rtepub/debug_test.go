@@ -0,0 +1,48 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rtepub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDebugEndpoint(t *testing.T) {
+	base := "http://portal:8080/internal/v1/connections"
+	tests := []struct {
+		endpoint string
+		base     string
+		allowed  bool
+	}{
+		{"http://portal:8080/internal/v1/connections/1/events/2/debugger", base, true},
+		{"http://PORTAL:8080/internal/v1/connections/1/events/2/debugger", base + "/", true},
+		{"http://portal:8080/internal/v1/connections/1/events/2/debugger", "", false},
+		{"http://evil:8080/internal/v1/connections/1/events/2/debugger", base, false},
+		{"http://portal:8080.evil/internal/v1/connections/1", base, false},
+		{"https://portal:8080/internal/v1/connections/1", base, false},
+		{"http://user@portal:8080/internal/v1/connections/1", base, false},
+		{"http://portal:8080/internal/v1/connections-other/1", base, false},
+		{"http://portal:8080/internal/v1/connections/../../../api/ns/v1", base, false},
+		{"http://portal:8080/internal/v1/connections/1?redirect=http://evil", base, false},
+	}
+
+	for _, test := range tests {
+		err := CheckDebugEndpoint(test.endpoint, test.base)
+		assert.Equal(t, test.allowed, err == nil, "endpoint %s, base %s: %v", test.endpoint, test.base, err)
+	}
+}
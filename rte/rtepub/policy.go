@@ -0,0 +1,103 @@
+/*
+Copyright (C) 2017 Verizon. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rtepub
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ERR_NOT_PERMITTED is part of the errors raised when a snippet uses a
+// module or host its policy does not allow.
+const ERR_NOT_PERMITTED = "not permitted by the policy"
+
+// Policy lists the capabilities of a snippet: the modules it may require
+// and the hosts the network modules (http, nsFTP, nsSFTP) may connect to.
+// A nil policy, or an empty module list, puts no restriction beyond the
+// modules enabled for the runtime. Hosts are matched by name, e.g. "ftp.example.com", with or
+// without port, or by domain, e.g. "*.example.com". An empty host list
+// allows any host.
+type Policy struct {
+	Modules []string `json:"modules,omitempty"`
+	Hosts   []string `json:"hosts,omitempty"`
+}
+
+// CheckModule returns an error if the module is not permitted.
+func (policy *Policy) CheckModule(name string) error {
+	if policy == nil || len(policy.Modules) == 0 {
+		return nil
+	}
+
+	for _, module := range policy.Modules {
+		if module == name {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("module %s is %s", name, ERR_NOT_PERMITTED)
+}
+
+// CheckHost returns an error if connecting to the host, given as host or
+// host:port, is not permitted.
+func (policy *Policy) CheckHost(hostport string) error {
+	if policy == nil || len(policy.Hosts) == 0 {
+		return nil
+	}
+
+	hostport = strings.ToLower(hostport)
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+
+	for _, allowed := range policy.Hosts {
+		allowed = strings.ToLower(allowed)
+		switch {
+		case allowed == host || allowed == hostport:
+			return nil
+		case strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:]):
+			return nil
+		}
+	}
+
+	return fmt.Errorf("host %s is %s", hostport, ERR_NOT_PERMITTED)
+}
+
+// Transport returns a round tripper rejecting requests to hosts which are
+// not permitted.
+func (policy *Policy) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &policyTransport{policy: policy, base: base}
+}
+
+type policyTransport struct {
+	policy *Policy
+	base   http.RoundTripper
+}
+
+func (transport *policyTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if err := transport.policy.CheckHost(request.URL.Host); err != nil {
+		return nil, err
+	}
+
+	return transport.base.RoundTrip(request)
+}
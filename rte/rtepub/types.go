@@ -22,6 +22,8 @@ type Input struct {
 	Callback     string                 `json:"callback,omitempty"`
 	Memory       uint64                 `json:"memory,omitempty"`
 	Args         map[string]interface{} `json:"args,omitempty"`
+	Policy       *Policy                `json:"policy,omitempty"`
+	Debug        *Debug                 `json:"debug,omitempty"`
 }
 
 type Output struct {
@@ -39,6 +41,15 @@ type Interpreter interface {
 	Terminate()
 }
 
+// KVStore is the key value store backing the nsKV module of the JavaScript,
+// Starlark and Wasm runtimes. It is implemented by the Redis cluster used by
+// the Lua nsKV module.
+type KVStore interface {
+	Get(key string) (string, error)
+	Set(key string, value string, expiration time.Duration) error
+	Del(key string) error
+}
+
 type Error struct {
 	Status      string `json:"status,omitempty"`
 	Description string `json:"description,omitempty"`
@@ -48,9 +59,19 @@ func GetExecutionError(exec error, rErr error) *Error {
 	mlog.Debug("Execution error: %v, rErr: %v", exec, rErr)
 
 	if rErr != nil {
-		if strings.Contains(rErr.Error(), rlimit.ERR_OUT_OF_MEMORY) {
-			return NewError(SNIPPET_OUT_OF_MEMORY, SNIPPET_OUT_OF_MEMORY_DESCR)
-		}
+		exec = rErr
+	}
+
+	if strings.Contains(exec.Error(), rlimit.ERR_OUT_OF_MEMORY) {
+		return NewError(SNIPPET_OUT_OF_MEMORY, SNIPPET_OUT_OF_MEMORY_DESCR)
+	}
+
+	if strings.Contains(exec.Error(), rlimit.ERR_INSTRUCTION_LIMIT) {
+		return NewError(SNIPPET_INSTRUCTION_LIMIT, SNIPPET_INSTRUCTION_LIMIT_DESCR)
+	}
+
+	if strings.Contains(exec.Error(), ERR_NOT_PERMITTED) {
+		return NewError(SNIPPET_NOT_PERMITTED, exec.Error())
 	}
 
 	if strings.Contains(exec.Error(), CONTEXT_DEADLINE_EXCEEDED) {
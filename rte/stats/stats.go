@@ -37,4 +37,5 @@ var (
 	ErrSnippetStart           = RTE.NewCounter("ErrSnippetStart")
 	ErrSnippetStop            = RTE.NewCounter("ErrSnippetStop")
 	ErrSnippetOutput          = RTE.NewCounter("ErrSnippetOutput")
+	ErrGetPolicy              = RTE.NewCounter("ErrGetPolicy")
 )
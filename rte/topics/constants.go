@@ -17,7 +17,10 @@ limitations under the License.
 package topics
 
 const (
-	RTE_OUTPUT_TOPIC   = "rte-output"
-	RTE_R_CTRL_TOPIC   = "rte-r-ctrl"
-	RTE_LUA_CTRL_TOPIC = "rte-lua-ctrl"
+	RTE_OUTPUT_TOPIC        = "rte-output"
+	RTE_R_CTRL_TOPIC        = "rte-r-ctrl"
+	RTE_LUA_CTRL_TOPIC      = "rte-lua-ctrl"
+	RTE_JS_CTRL_TOPIC       = "rte-js-ctrl"
+	RTE_STARLARK_CTRL_TOPIC = "rte-starlark-ctrl"
+	RTE_WASM_CTRL_TOPIC     = "rte-wasm-ctrl"
 )
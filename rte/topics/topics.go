@@ -27,6 +27,12 @@ func GetCtrlTopicByType(rteType string) (string, error) {
 		return RTE_R_CTRL_TOPIC, nil
 	case rtepub.Lua:
 		return RTE_LUA_CTRL_TOPIC, nil
+	case rtepub.JavaScript:
+		return RTE_JS_CTRL_TOPIC, nil
+	case rtepub.Starlark:
+		return RTE_STARLARK_CTRL_TOPIC, nil
+	case rtepub.WebAssembly:
+		return RTE_WASM_CTRL_TOPIC, nil
 	default:
 		return "", fmt.Errorf("Wrong RTE type specified: %s", rteType)
 	}
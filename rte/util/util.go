@@ -21,11 +21,13 @@ import (
 	"github.com/lavaorg/lrtx/b64"
 	"github.com/lavaorg/lrtx/management"
 	"github.com/lavaorg/lrtx/mlog"
+	"github.com/lavaorg/northstar/object/client"
 	"github.com/satori/go.uuid"
 	"io/ioutil"
 	"net/url"
 	"os"
 	"os/exec"
+	"strings"
 )
 
 const (
@@ -47,7 +49,7 @@ func ExecCmd(cmd string, args string) error {
 	return nil
 }
 
-func GetSnippetCode(fullUrl string, code string) (string, error) {
+func GetSnippetCode(accountId string, fullUrl string, code string) (string, error) {
 	if fullUrl == "" || code == "" {
 		return "", errors.New("URL or code empty")
 	}
@@ -64,7 +66,7 @@ func GetSnippetCode(fullUrl string, code string) (string, error) {
 		return b64.DecodeBase64ToString(code)
 	case "s3":
 		mlog.Debug("S3 source detected")
-		return "", errors.New("S3 schema not supported!")
+		return getObjectCode(accountId, parsed)
 	case "http":
 		mlog.Debug("HTTP schema detected")
 		vuuid, err := uuid.NewV4()
@@ -94,3 +96,21 @@ func GetSnippetCode(fullUrl string, code string) (string, error) {
 
 	return "", nil
 }
+
+// getObjectCode downloads the code stored in the object store of the account,
+// e.g. s3://bucket/transform.wasm. The content is returned as is, which
+// allows binary code such as WebAssembly modules.
+func getObjectCode(accountId string, parsed *url.URL) (string, error) {
+	cli, err := client.NewObjectClient()
+	if err != nil {
+		return "", err
+	}
+
+	data, mErr := cli.DownloadFile(accountId, parsed.Host, strings.TrimPrefix(parsed.Path, "/"))
+	if mErr != nil {
+		mlog.Error("Failed to download object: %v", mErr.Error())
+		return "", errors.New(mErr.Error())
+	}
+
+	return string(data.Payload), nil
+}